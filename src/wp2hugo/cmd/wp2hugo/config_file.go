@@ -0,0 +1,100 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// The config file can't point to another one
+const _configFlagName = "config"
+
+// applyConfigFile sets the flags which are not set on the command line from the config file,
+// a YAML or TOML (.toml) file keyed by the flag names, e.g. `download-media: true`.
+// It returns the keys which are not flags, the caller warns about them once the logging is set up.
+func applyConfigFile(flags *flag.FlagSet, filePath string) ([]string, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading config file: %w", err)
+	}
+	var config map[string]any
+	if strings.EqualFold(filepath.Ext(filePath), ".toml") {
+		err = toml.Unmarshal(data, &config)
+	} else {
+		err = yaml.Unmarshal(data, &config)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error parsing config file '%s': %w", filePath, err)
+	}
+
+	setOnCommandLine := make(map[string]bool)
+	flags.Visit(func(f *flag.Flag) {
+		setOnCommandLine[f.Name] = true
+	})
+	var unknownKeys []string
+	for _, key := range slices.Sorted(maps.Keys(config)) {
+		if key == _configFlagName || flags.Lookup(key) == nil {
+			unknownKeys = append(unknownKeys, key)
+			continue
+		}
+		if setOnCommandLine[key] {
+			continue
+		}
+		value, err := getConfigValue(config[key])
+		if err != nil {
+			return nil, fmt.Errorf("config file '%s', key %q: %w", filePath, key, err)
+		}
+		if err := flags.Set(key, value); err != nil {
+			return nil, fmt.Errorf("config file '%s', key %q: %w", filePath, key, err)
+		}
+	}
+	return unknownKeys, nil
+}
+
+// getConfigValue returns the flag value of the config value, the lists are joined like the CSV flags,
+// e.g. `authors: [alice, bob]`, and the maps like --taxonomy-keys, e.g. `taxonomy-keys: {tags: keywords}`
+func getConfigValue(value any) (string, error) {
+	switch value := value.(type) {
+	case []any:
+		values := make([]string, 0, len(value))
+		for _, item := range value {
+			itemValue, err := getScalarConfigValue(item)
+			if err != nil {
+				return "", err
+			}
+			values = append(values, itemValue)
+		}
+		return strings.Join(values, ","), nil
+	case map[string]any:
+		pairs := make([]string, 0, len(value))
+		for _, key := range slices.Sorted(maps.Keys(value)) {
+			itemValue, err := getScalarConfigValue(value[key])
+			if err != nil {
+				return "", err
+			}
+			pairs = append(pairs, key+"="+itemValue)
+		}
+		return strings.Join(pairs, ","), nil
+	default:
+		return getScalarConfigValue(value)
+	}
+}
+
+func getScalarConfigValue(value any) (string, error) {
+	switch value := value.(type) {
+	case string, bool, int, int64, float64:
+		return fmt.Sprint(value), nil
+	case nil:
+		return "", errors.New("missing value")
+	default:
+		return "", fmt.Errorf("unsupported value %v", value)
+	}
+}
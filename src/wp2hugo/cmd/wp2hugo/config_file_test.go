@@ -0,0 +1,66 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestFlagSet() (*flag.FlagSet, *bool, *string, *string, *int) {
+	flags := flag.NewFlagSet("wp2hugo", flag.ContinueOnError)
+	downloadMedia := flags.Bool("download-media", false, "")
+	authors := flags.String("authors", "", "")
+	taxonomyKeys := flags.String("taxonomy-keys", "", "")
+	webpQuality := flags.Int("webp-quality", 80, "")
+	flags.String(_configFlagName, "", "")
+	return flags, downloadMedia, authors, taxonomyKeys, webpQuality
+}
+
+func TestApplyConfigFile(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	for fileName, config := range map[string]string{
+		"wp2hugo.yaml": "download-media: true\nauthors: [alice, bob]\ntaxonomy-keys:\n  tags: keywords\n  categories: category\n" +
+			"webp-quality: 90\nunknown: 1\n",
+		"wp2hugo.toml": "download-media = true\nauthors = [\"alice\", \"bob\"]\nwebp-quality = 90\nunknown = 1\n\n" +
+			"[taxonomy-keys]\ntags = \"keywords\"\ncategories = \"category\"\n",
+	} {
+		filePath := filepath.Join(dir, fileName)
+		require.NoError(t, os.WriteFile(filePath, []byte(config), 0o600))
+
+		flags, downloadMedia, authors, taxonomyKeys, webpQuality := newTestFlagSet()
+		// The command line takes precedence
+		require.NoError(t, flags.Parse([]string{"--webp-quality", "70"}))
+		unknownKeys, err := applyConfigFile(flags, filePath)
+		require.NoError(t, err, fileName)
+		require.Equal(t, []string{"unknown"}, unknownKeys, fileName)
+		require.True(t, *downloadMedia, fileName)
+		require.Equal(t, "alice,bob", *authors, fileName)
+		require.Equal(t, "categories=category,tags=keywords", *taxonomyKeys, fileName)
+		require.Equal(t, 70, *webpQuality, fileName)
+	}
+}
+
+func TestApplyInvalidConfigFile(t *testing.T) {
+	t.Parallel()
+	filePath := filepath.Join(t.TempDir(), "wp2hugo.yaml")
+	for _, config := range []string{"webp-quality: high\n", "authors: [[alice]]\n", "download-media:\n", "- download-media\n"} {
+		require.NoError(t, os.WriteFile(filePath, []byte(config), 0o600))
+		flags, _, _, _, _ := newTestFlagSet()
+		_, err := applyConfigFile(flags, filePath)
+		require.Error(t, err, config)
+	}
+}
+
+func TestListFlag(t *testing.T) {
+	t.Parallel()
+	flags := flag.NewFlagSet("wp2hugo", flag.ContinueOnError)
+	var onlyTypes listFlag
+	flags.Var(&onlyTypes, "only-type", "")
+	require.NoError(t, flags.Parse([]string{"--only-type", "product", "--only-type", "recipe, page", "--only-type", ""}))
+	require.Equal(t, listFlag{"product", "recipe", "page"}, onlyTypes)
+	require.Equal(t, "product,recipe,page", onlyTypes.String())
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"flag"
+	"strings"
+)
+
+// listFlag is a repeatable flag, each value may also be a CSV list, like the lists of the config file
+type listFlag []string
+
+func newListFlag(name string, usage string) *listFlag {
+	values := &listFlag{}
+	flag.Var(values, name, usage)
+	return values
+}
+
+func (l *listFlag) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *listFlag) Set(value string) error {
+	for item := range strings.SplitSeq(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*l = append(*l, item)
+		}
+	}
+	return nil
+}
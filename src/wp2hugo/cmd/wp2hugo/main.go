@@ -2,83 +2,471 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"flag"
+	"fmt"
+	"io"
 	"os"
+	"os/signal"
 	"path"
-	"slices"
 	"strings"
+	"syscall"
 
+	"github.com/ashishb/wp2hugo/src/wp2hugo"
 	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/hugogenerator"
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/hugogenerator/hugopage"
 	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/logger"
-	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/mediacache"
-	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 )
 
 var (
-	sourceFile                     = flag.String("source", "", "file path to the source WordPress XML file")
-	outputDir                      = flag.String("output", "/tmp", "dir path to write the Hugo-generated data to")
+	configFile                     = flag.String("config", "", "file path to a YAML or TOML (.toml) config file setting the flags, keyed by their names, e.g. \"download-media: true\", the command line flags take precedence")
+	sourceFile                     = flag.String("source", "", "file path to the source WordPress XML file, which may be gzipped, dir path to the files of a split export, or URL of a live WordPress site, e.g. https://example.com, read from its REST API")
+	restAPIUsername                = flag.String("rest-api-username", "", "with a site URL as --source, username authenticating the REST API requests, to read the drafts, the private content and the raw content")
+	restAPIPassword                = flag.String("rest-api-password", "", "with --rest-api-username, application password of the user, e.g. set in the --config file rather than on the command line")
+	outputDir                      = flag.String("output", "/tmp", "dir path to write the Hugo-generated data to, created with its parents if missing")
+	commentThreads                 = flag.String("comment-threads", "", "file path to a .json file written after the conversion, the approved comments of each content keyed by its URL path, with the discussion term of the pathname mapping of Giscus and Utterances, e.g. for seeding their discussions")
+	index                          = flag.String("index", "", "file path to a .csv or .json index written after the conversion, a row per converted content with its original URL, new path, status, word and media counts and aliases, e.g. for spot-checking")
+	outputZip                      = flag.String("output-zip", "", "file path to a zip archive to write the Hugo site into, instead of a dir under --output, e.g. for a single downloadable artifact")
+	singleFile                     = flag.String("single-file", "", "file path to a Markdown document to write all the content into instead of a Hugo site, a section per post with its front matter summarized below its heading, e.g. for reading or grepping a whole blog, the media are not downloaded")
+	singleFileTOC                  = flag.Bool("single-file-toc", false, "with --single-file, start the document with a table of contents linking to the sections")
+	inspect                        = flag.String("inspect", "", "only parse the export and print its structure as \"text\" or \"json\" instead of converting it: the counts per post type, status, taxonomy and author, the date range and the plugins detected from the postmeta and shortcodes")
+	alwaysEmitSlug                 = flag.Bool("always-emit-slug", false, "emit the WordPress slug, the post_name, as the slug front matter of every page, even when the filename is the slug, so that the URLs don't depend on the filenames")
+	maxFileNameLength              = flag.Int("max-filename-length", 200, "truncate the content filenames longer than this, keeping a hash suffix, the original slug is emitted in the front matter")
+	incremental                    = flag.Bool("incremental", false, "with --site-name, only rewrite the content which changed since the previous run into the same site, and remove the content which is not in the export anymore")
+	siteName                       = flag.String("site-name", "", "name of the Hugo site dir created under --output, defaults to \"generated-<timestamp>\", set it for reproducible output paths")
 	downloadMedia                  = flag.Bool("download-media", false, "download media files embedded in the WordPress content")
 	downloadAll                    = flag.Bool("download-all", false, "download all media from WordPress library, whether used in content or not")
+	rewriteHosts                   = newListFlag("rewrite-host", "rewrite the host of the URLs of the content and the front matter, e.g. \"example.org=cdn.example.net\" for the media moved to a CDN, without downloading them, repeatable, applied in order, the number of URLs rewritten per rule is in the report")
+	noMedia                        = flag.Bool("no-media", false, "content-only mode which never fetches any media, keeping the media links absolute, pointing to the WordPress site, e.g. when the media stay there or move to a CDN separately")
+	failFast                       = flag.Bool("fail-fast", false, "abort on the first item which fails to parse or convert, e.g. for CI, by default the item is skipped and listed with its error at the end")
 	continueOnMediaDownloadFailure = flag.Bool("continue-on-media-download-error", false, "continue processing even if one or more media downloads fail")
+	brokenImages                   = flag.String("broken-images", "keep", "with --continue-on-media-download-error, what becomes of the content images which failed to download: \"keep\" the original link, link a \"placeholder\" image or \"remove\" them")
+	convertToWebP                  = flag.Bool("webp", false, "with --download-media, convert the downloaded JPEG and PNG images to WebP and rewrite their links, requires a build with -tags webp")
+	webpQuality                    = flag.Int("webp-quality", 80, "quality of the WebP images generated by --webp, between 1 and 100")
+	keepOriginalImages             = flag.Bool("keep-original-images", false, "with --webp, keep the original JPEG and PNG images next to the WebP ones")
+	svgImages                      = flag.String("svg-images", "download", "with --download-media, what becomes of the SVG images of the content: \"download\" them as files or \"inline\" the ones up to --svg-inline-max-bytes into the content, e.g. icons, once sanitized")
+	svgInlineMaxBytes              = flag.Int64("svg-inline-max-bytes", hugogenerator.DefaultSVGInlineMaxBytes, "with --svg-images inline, size of the largest SVG images inlined, the larger ones are downloaded")
+	keepInlineImages               = flag.Bool("keep-inline-images", false, "with --download-media, leave base64-embedded images inline instead of writing them out as files")
+	assetsDir                      = flag.String("assets-dir", "", "with --download-media, download the images of the content into this dir of the site, e.g. \"assets\", instead of the static dir, for processing them with Hugo's asset pipeline")
+	assetReferences                = flag.String("asset-references", "path", "with --assets-dir, how the content references the images: \"path\" (resolved by Hugo's image render hook) or \"shortcode\" (resource shortcode)")
 	generateNgnixConfig            = flag.Bool("generate-nginx-config", true, "generate Nginx configuration for the generated Hugo website for redirecting WordPress GUIDs to Hugo URLs")
 	authors                        = flag.String("authors", "", "CSV list of author name(s), if provided, only posts by these authors will be processed")
+	includePostIDs                 = newListFlag("include-post-id", "only convert the post, page or custom post of this WordPress post ID, e.g. \"42\", repeatable, combined with the other filters, e.g. --only-type, for re-converting a single post")
+	excludePostIDs                 = newListFlag("exclude-post-id", "leave out the post, page or custom post of this WordPress post ID, e.g. \"42\", repeatable, combined with the other filters, e.g. --only-type")
+	onlyTypes                      = newListFlag("only-type", "only convert the content of this WordPress post type, e.g. \"product\", repeatable, imported even if not in --custom-post-types, the post types in the export are listed in the report")
 	// This is useful for repeated executions of the tool to avoid downloading the media files again
 	// Mostly for development and not for the production use
 	mediaCacheDir = flag.String("media-cache-dir", path.Join("/tmp/wp2hugo-cache"), "dir path to cache the downloaded media files")
 	// Custom font for Hugo's papermod theme
 	font           = flag.String("font", "Lexend", "custom font for the output website")
 	colorLogOutput = flag.Bool("color-log-output", true, "enable colored log output, set false to structured JSON log")
+	logLevel       = flag.String("log-level", "", "log level: trace, debug, info, warn or error, defaults to the LOG_LEVEL environment variable, or debug")
+	verbose        = flag.Bool("verbose", false, "log everything, shortcut for --log-level trace")
+	quiet          = flag.Bool("quiet", false, "only log warnings and errors, shortcut for --log-level warn")
+	logFormat      = flag.String("log-format", "", "log output format: console (pretty) or json, defaults to console unless --color-log-output=false")
 
-	customPostTypes = flag.String("custom-post-types", "", "CSV list of custom post types to import")
-)
+	customPostTypes   = flag.String("custom-post-types", "", "CSV list of custom post types to import")
+	lastModTolerance  = flag.Duration("lastmod-tolerance", 0, "do not emit lastmod when the post was last modified within this duration after its publish date, e.g. 1h")
+	dateSource        = flag.String("date-source", "gmt", "publish date driving the date front matter and --date-path: \"gmt\", emitted in UTC, or \"local\", as displayed by WordPress in the timezone of the site, e.g. for date archives grouped by the local day")
+	missingDate       = flag.String("missing-date", "omit", "date to emit for content without a publish date: \"omit\", \"lastmod\" (last modification date) or \"post-id\" (derived from the closest post by ID)")
+	urlPrefix         = flag.String("url-prefix", "", "namespace the generated content and URLs under this path, e.g. \"/blog\", when migrating into a subpath of a larger Hugo site")
+	privateContentDir = flag.String("private-content-dir", "", "write the private, password-protected, draft and pending content into this dir under content/, e.g. \"_private\", instead of mixing it with the published content")
+	draftsDir         = flag.String("drafts-dir", "", "write the content of --draft-statuses into this dir under content/, e.g. \"_drafts\", for a separate review workflow, keeping its draft: true front matter, takes precedence over --private-content-dir")
+	draftStatuses     = flag.String("draft-statuses", "draft,pending", "with --drafts-dir, CSV list of the statuses routed into it: draft, pending, future or private")
+	contentFormats    = flag.String("format", "markdown", "CSV list of the formats the content is emitted in: \"markdown\", converted, and \"html\", the HTML as exported, written into a parallel content-html/ tree with the same front matter and media, e.g. for phased migrations")
+	pathOverrides     = flag.String("path-overrides", "", "file path to a YAML file mapping post IDs to the output path of their content under content/, e.g. \"42\": about/index.md, taking precedence over the _wp2hugo_path postmeta")
+	sectionCascade    = flag.String("section-cascade", "", "file path to a YAML file mapping content sections, e.g. \"posts\", to the front matter cascaded to all their pages, written to the section _index.md")
+	replacements      = flag.String("replacements", "", "file path to a YAML file listing regex replacement rules applied in order to the converted content, e.g. renaming a shortcode or fixing a hardcoded domain")
+	datePath          = flag.String("date-path", "", "organize posts in sub-directories derived from their publish date, e.g. \":year/:month\" (tokens: :year, :month, :monthname, :day)")
 
-var _defaultCustomPosts = []string{"avada_portfolio", "avada_faq", "product", "product_variation"}
+	emitCommentStatus = flag.Bool("emit-comment-status", false, "emit comments: true/false from the WordPress comment status, and the comment_count of the approved comments, e.g. for rendering a comment widget")
+	detectLanguages   = flag.Bool("detect-language", false, "heuristically detect the language of the content without one, e.g. the posts in French of an English blog, and emit it as the lang front matter when it differs from the language of the site, the short texts and the detections which are not confident are skipped, the detected languages are in the report")
+	emitWPID          = flag.Bool("emit-wp-id", false, "emit the WordPress post ID in the front matter, for correlating the migrated content with external systems")
+	wpIDKey           = flag.String("wp-id-key", "wordpress_id", "front matter key used by --emit-wp-id")
+	acfFields         = flag.Bool("acf-fields", false, "decode Advanced Custom Fields postmeta into front matter params, instead of emitting the raw postmeta")
+	authorMap         = flag.String("author-map", "", "file path to a YAML file mapping the author logins or display names to their target author, e.g. former-intern: jdoe, to rename or consolidate the authors in the author front matter and data/authors.yaml")
+	allAuthors        = flag.Bool("all-authors", false, "list all the users of the export in data/authors.yaml, including the ones who authored none of the content, e.g. the editors")
+	authorLogins      = flag.Bool("author-logins", false, "emit the WordPress login as the author front matter, like the previous versions, instead of the author slug, which keys data/authors.yaml")
+	ogImages          = flag.Bool("og-images", true, "emit the featured image in the images front matter, read by Hugo's Open Graph and Twitter Cards templates")
+	ogContentImage    = flag.Bool("og-content-image", false, "with --og-images, also emit the first image of the content")
+	noIndexExclusion  = flag.String("noindex-exclusion", "sitemap", "how the content noindexed with the SEO plugins is excluded from the site: \"none\", from the \"sitemap\", \"unlisted\" from the lists and feeds too, or \"unrendered\"")
+	seoTitleSeparator = flag.String("seo-title-separator", hugopage.DefaultSEOTitleSeparator, "title separator of the Yoast SEO settings, replacing the %%sep%% variable of the SEO titles, which are not in the export")
+	excerptFormat     = flag.String("excerpt-format", "plain", "format of the manual excerpts emitted as the summary: \"plain\" text, safe for the meta description, \"markdown\" like the content, or \"html\" as written")
+	expiryDateMeta    = flag.String("expiry-date-meta", strings.Join(hugopage.DefaultExpiryDateMetaKeys, ","), "CSV list of the postmeta keys of the unpublish date, e.g. of Post Expirator, emitted as the expiryDate front matter, set empty to emit them as plain postmeta")
+	expiredAsDraft    = flag.Bool("expired-as-draft", false, "emit the content whose unpublish date has already passed as a draft, instead of with an expiryDate in the past")
+	seriesTaxonomy    = flag.String("series-taxonomy", hugopage.DefaultSeriesTaxonomy, "custom taxonomy of the series, e.g. of the Organize Series plugin, emitted as the series front matter with the series_weight of the post, set empty to emit it as a plain taxonomy")
+	sourceIsMarkdown  = flag.Bool("source-is-markdown", false, "treat the WordPress content as Markdown, e.g. stored by Jetpack Markdown or WP-Markdown, only rewriting the shortcodes and links instead of converting it from HTML")
+	stripShortcodes   = flag.String("strip-shortcodes", "none", "remove the shortcodes, keeping the text they enclose: \"none\", \"unhandled\" (not converted by wp2hugo, e.g. [su_note]) or \"all\" (including e.g. [caption] and [gallery])")
+	stripEmptyParas   = flag.Bool("strip-empty-paragraphs", false, "remove the paragraphs made of non-breaking spaces only, e.g. \"&nbsp;\" spacers, which are left in the content written in Markdown and in the raw HTML")
+	attachmentPages   = flag.String("attachment-pages", "none", "what becomes of the attachment pages WordPress generates for the media, e.g. /summit/ for summit.jpg: \"none\", left out, \"redirect\"ed to the content they are attached to, or else to their media file, or written as minimal \"page\"s for the media of the written content")
+	nextPage          = flag.String("nextpage", "collapse", "what becomes of the content paginated with <!--nextpage--> tags: \"collapse\" into one page with a horizontal rule between the pages, or \"split\" into one Hugo page per page, linked with page links")
+	galleryOutput     = flag.String("gallery-output", "shortcode", "how the galleries are emitted: inline as \"shortcode\"s, or as structured data for the theme, e.g. a lightbox gallery, in the galleries \"front-matter\" or a \"data\" file, data/galleries/<post ID>.yaml, a gallery-data shortcode in their place")
+	playlistShortcode = flag.String("playlist-shortcode", "", "Hugo shortcode the [playlist] shortcodes are emitted as, e.g. \"playlist\", with a nested <name>-track shortcode per track, instead of an HTML5 playlist of <audio> or <video> elements")
+	linkAttributes    = flag.Bool("preserve-link-attributes", false, "keep the links with a meaningful rel or target attribute, e.g. the affiliate links with rel=\"sponsored\" or the links opened in a new tab, as raw HTML links instead of Markdown links, which have no attributes")
+	rawHTMLShortcode  = flag.Bool("raw-html-shortcode", false, "wrap Custom HTML blocks in the rawhtml shortcode, needed if Goldmark's unsafe rendering is disabled in the Hugo config")
+	taxonomyKeys      = flag.String("taxonomy-keys", "", "CSV list of taxonomy=key pairs renaming the taxonomy front matter keys to match the Hugo taxonomies config, e.g. \"categories=category,tags=keywords\"")
+	taxonomyPaths     = flag.String("taxonomy-paths", "", "CSV list of taxonomy=path pairs setting the URL path of the taxonomy and term pages with the permalinks of the Hugo config, e.g. \"categories=topics\" for /topics/news/, defaulting to the front matter key of the taxonomy")
+	taxonomyWeights   = flag.Bool("taxonomy-weights", false, "emit the order of the taxonomy terms, from their term meta or else the export, as the weight of their term pages, so that Hugo lists them in the WordPress order")
+	faviconParam      = flag.String("favicon-param", hugogenerator.DefaultFaviconParam, "param of the Hugo config, as a dotted path under params, which the site icon is emitted as, e.g. \"favicon\" for themes other than PaperMod")
+	logoParam         = flag.String("logo-param", hugogenerator.DefaultLogoParam, "param of the Hugo config, as a dotted path under params, which the custom logo of the theme is emitted as, e.g. \"logo\" for themes other than PaperMod")
+	termMeta          = flag.Bool("term-meta", false, "emit the term meta, e.g. a category color, into the front matter of the term pages, and the term image as their cover")
+	termCollisions    = flag.String("term-collisions", "separate", "what becomes of the terms named the same in the categories and the tags, e.g. a \"Go\" category and tag: kept \"separate\", \"merge\"d into the term of --term-collision-target, or renamed with a \"suffix\", e.g. go-tag")
+	termTarget        = flag.String("term-collision-target", "categories", "taxonomy keeping its terms as is with --term-collisions: \"categories\" or \"tags\"")
+	titles            = flag.String("title-normalization", "keep", "clean up the titles: \"keep\" them as exported, \"clean\" to trim them and collapse their whitespace, or \"title-case\" to title-case them as well, keeping the original as the original_title front matter")
+	typography        = flag.String("typography", "keep", "style of the quotes, dashes and ellipses of the content: \"keep\" them as exported, \"straight\" for Goldmark's typographer to curl them, or \"curly\" like WordPress renders them")
+	wooCommerce       = flag.Bool("woocommerce", false, "emit the price, SKU, gallery, attributes and variations of the WooCommerce products in their front matter")
+	annotateIssues    = flag.Bool("annotate-issues", false, "insert <!-- wp2hugo: ... --> comments in the content where the conversion degraded it, e.g. unhandled shortcodes or media which failed to download")
+)
 
 func main() {
 	flag.Parse()
+	var unknownConfigKeys []string
+	if *configFile != "" {
+		var err error
+		if unknownConfigKeys, err = applyConfigFile(flag.CommandLine, *configFile); err != nil {
+			log.Fatal().Msgf("Error: %s", err)
+		}
+	}
 
 	// Set log level
-	logger.ConfigureLogging(*colorLogOutput)
+	if err := configureLogging(); err != nil {
+		log.Fatal().Msgf("Error: %s", err)
+	}
+	for _, key := range unknownConfigKeys {
+		log.Warn().
+			Str("key", key).
+			Str("config", *configFile).
+			Msg("Unknown config key, ignoring it")
+	}
 	if len(*sourceFile) == 0 {
 		log.Fatal().Msg("Source file is required")
 	}
 	if len(*outputDir) == 0 {
 		log.Fatal().Msg("Output directory is required")
 	}
-	err := handle(context.Background(), *sourceFile)
+	// Ctrl-C cancels the conversion, the manifest of --incremental is still written for the next run to resume
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err := handle(ctx, *sourceFile)
+	cancelled := ctx.Err() != nil
+	stop()
 	if err != nil {
+		if cancelled {
+			log.Warn().Msg("Conversion cancelled")
+			os.Exit(130)
+		}
 		log.Fatal().Msgf("Error: %s", err)
 	}
 }
 
-func handle(ctx context.Context, filePath string) error {
-	log.Debug().
-		Str("source", filePath).
-		Msg("Reading website export")
-	websiteInfo, err := getWebsiteInfo(filePath)
+func configureLogging() error {
+	level, err := getLogLevel()
 	if err != nil {
 		return err
 	}
-	return generate(ctx, *websiteInfo, *outputDir)
+	format := logger.LogFormatConsole
+	if !*colorLogOutput {
+		format = logger.LogFormatJSON
+	}
+	if *logFormat != "" {
+		if format, err = logger.ParseLogFormat(*logFormat); err != nil {
+			return err
+		}
+	}
+	logger.Configure(level, format)
+	return nil
+}
+
+func getLogLevel() (zerolog.Level, error) {
+	numLevelFlags := 0
+	for _, set := range []bool{*verbose, *quiet, *logLevel != ""} {
+		if set {
+			numLevelFlags++
+		}
+	}
+	switch {
+	case numLevelFlags > 1:
+		return zerolog.NoLevel, errors.New("--log-level, --verbose and --quiet are mutually exclusive")
+	case *verbose:
+		return zerolog.TraceLevel, nil
+	case *quiet:
+		return zerolog.WarnLevel, nil
+	case *logLevel != "":
+		return logger.ParseLogLevel(*logLevel)
+	default:
+		return logger.GetDefaultLogLevel()
+	}
 }
 
-func getWebsiteInfo(filePath string) (*wpparser.WebsiteInfo, error) {
-	parser := wpparser.NewParser()
-	file, err := os.OpenFile(filePath, os.O_RDONLY, 0o644)
+func handle(ctx context.Context, sourcePath string) error {
+	options, err := getOptions()
 	if err != nil {
-		return nil, err
+		return err
+	}
+	if wp2hugo.IsSiteURL(sourcePath) {
+		return handleSite(ctx, sourcePath, *options)
+	}
+	stat, err := os.Stat(sourcePath)
+	if err != nil {
+		return err
+	}
+	if *inspect != "" {
+		return handleInspect(ctx, sourcePath, stat.IsDir(), *options)
+	}
+	if stat.IsDir() {
+		_, err = wp2hugo.ConvertDir(ctx, sourcePath, *outputDir, *options)
+	} else {
+		_, err = wp2hugo.ConvertFile(ctx, sourcePath, *outputDir, *options)
+	}
+	return err
+}
+
+// handleSite converts, or inspects, the live site read from its REST API
+func handleSite(ctx context.Context, siteURL string, options wp2hugo.Options) error {
+	if *inspect == "" {
+		_, err := wp2hugo.ConvertSite(ctx, siteURL, *outputDir, options)
+		return err
+	}
+	if *inspect != "text" && *inspect != "json" {
+		return fmt.Errorf("unknown inspect format %q, expected text or json", *inspect)
+	}
+	summary, err := wp2hugo.InspectSite(ctx, siteURL, options)
+	if err != nil {
+		return err
 	}
+	return writeExportSummary(os.Stdout, *summary, *inspect)
+}
 
-	defaultCustomPosts := slices.Clone(_defaultCustomPosts)
-	defaultCustomPosts = append(defaultCustomPosts, strings.Split(*customPostTypes, ",")...)
+func handleInspect(ctx context.Context, sourcePath string, isDir bool, options wp2hugo.Options) error {
+	if *inspect != "text" && *inspect != "json" {
+		return fmt.Errorf("unknown inspect format %q, expected text or json", *inspect)
+	}
+	var summary *wp2hugo.ExportSummary
+	var err error
+	if isDir {
+		summary, err = wp2hugo.InspectDir(ctx, sourcePath, options)
+	} else {
+		summary, err = wp2hugo.InspectFile(ctx, sourcePath, options)
+	}
+	if err != nil {
+		return err
+	}
+	return writeExportSummary(os.Stdout, *summary, *inspect)
+}
 
-	return parser.Parse(file, strings.Split(*authors, ","), defaultCustomPosts)
+// writeExportSummary writes the summary in the format of --inspect, the logs go to the standard error
+func writeExportSummary(w io.Writer, summary wp2hugo.ExportSummary, format string) error {
+	if format == "text" {
+		return summary.WriteText(w)
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(summary); err != nil {
+		return fmt.Errorf("error writing the export summary: %w", err)
+	}
+	return nil
 }
 
-func generate(ctx context.Context, info wpparser.WebsiteInfo, outputDirPath string) error {
-	log.Debug().Msgf("Output: %s", outputDirPath)
-	generator := hugogenerator.NewGenerator(outputDirPath, *font, mediacache.New(*mediaCacheDir),
-		*downloadMedia, *downloadAll, *continueOnMediaDownloadFailure, *generateNgnixConfig, info)
-	return generator.Generate(ctx)
+func getOptions() (*wp2hugo.Options, error) {
+	missingDatePolicy, err := hugogenerator.ParseMissingDatePolicy(*missingDate)
+	if err != nil {
+		return nil, err
+	}
+	publishDateSource, err := hugogenerator.ParseDateSource(*dateSource)
+	if err != nil {
+		return nil, err
+	}
+	attachmentPagePolicy, err := hugogenerator.ParseAttachmentPagePolicy(*attachmentPages)
+	if err != nil {
+		return nil, err
+	}
+	nextPagePolicy, err := hugogenerator.ParseNextPagePolicy(*nextPage)
+	if err != nil {
+		return nil, err
+	}
+	termCollisionPolicy, err := hugogenerator.ParseTermCollisionPolicy(*termCollisions)
+	if err != nil {
+		return nil, err
+	}
+	assetReferenceStyle, err := hugogenerator.ParseAssetReferenceStyle(*assetReferences)
+	if err != nil {
+		return nil, err
+	}
+	shortcodeStripping, err := hugopage.ParseShortcodeStripping(*stripShortcodes)
+	if err != nil {
+		return nil, err
+	}
+	noIndexContentExclusion, err := hugopage.ParseNoIndexExclusion(*noIndexExclusion)
+	if err != nil {
+		return nil, err
+	}
+	contentTypography, err := hugopage.ParseTypography(*typography)
+	if err != nil {
+		return nil, err
+	}
+	contentExcerptFormat, err := hugopage.ParseExcerptFormat(*excerptFormat)
+	if err != nil {
+		return nil, err
+	}
+	titleNormalization, err := hugopage.ParseTitleNormalization(*titles)
+	if err != nil {
+		return nil, err
+	}
+	galleries, err := hugopage.ParseGalleryOutput(*galleryOutput)
+	if err != nil {
+		return nil, err
+	}
+	draftContentStatuses, err := hugogenerator.ParseDraftStatuses(strings.Split(*draftStatuses, ","))
+	if err != nil {
+		return nil, err
+	}
+	formats, err := hugogenerator.ParseContentFormats(strings.Split(*contentFormats, ","))
+	if err != nil {
+		return nil, err
+	}
+	var expiryDateMetaKeys listFlag
+	if err := expiryDateMetaKeys.Set(*expiryDateMeta); err != nil {
+		return nil, err
+	}
+	brokenImagePolicy, err := hugogenerator.ParseBrokenImagePolicy(*brokenImages)
+	if err != nil {
+		return nil, err
+	}
+	svgImagePolicy, err := hugogenerator.ParseSVGImagePolicy(*svgImages)
+	if err != nil {
+		return nil, err
+	}
+	taxonomyKeyMapping, err := hugogenerator.ParseTaxonomyKeys(*taxonomyKeys)
+	if err != nil {
+		return nil, err
+	}
+	taxonomyPathMapping, err := hugogenerator.ParseTaxonomyPaths(*taxonomyPaths)
+	if err != nil {
+		return nil, err
+	}
+	var pathOverrideMapping map[string]string
+	if *pathOverrides != "" {
+		if pathOverrideMapping, err = hugogenerator.ReadPathOverrides(*pathOverrides); err != nil {
+			return nil, err
+		}
+	}
+	var authorMapping map[string]string
+	if *authorMap != "" {
+		if authorMapping, err = hugogenerator.ReadAuthorMap(*authorMap); err != nil {
+			return nil, err
+		}
+	}
+	var sectionCascades map[string]map[string]any
+	if *sectionCascade != "" {
+		if sectionCascades, err = hugogenerator.ReadSectionCascades(*sectionCascade); err != nil {
+			return nil, err
+		}
+	}
+	hostRewrites, err := hugogenerator.ParseHostRewrites(*rewriteHosts)
+	if err != nil {
+		return nil, err
+	}
+	var contentReplacements []wp2hugo.ContentReplacement
+	if *replacements != "" {
+		if contentReplacements, err = hugogenerator.ReadContentReplacements(*replacements); err != nil {
+			return nil, err
+		}
+	}
+	return &wp2hugo.Options{
+		GeneratorOptions: wp2hugo.GeneratorOptions{
+			PageOptions: wp2hugo.PageOptions{
+				WrapCustomHTMLInShortcode: *rawHTMLShortcode,
+				WordPressIDKey:            getWordPressIDKey(),
+				ExtractACFFields:          *acfFields,
+				LastModTolerance:          *lastModTolerance,
+				SEOTitleSeparator:         *seoTitleSeparator,
+				SeriesTaxonomy:            *seriesTaxonomy,
+				ExpiryDateMetaKeys:        expiryDateMetaKeys,
+				ExpiredAsDraft:            *expiredAsDraft,
+				NoIndexExclusion:          noIndexContentExclusion,
+				URLPrefix:                 *urlPrefix,
+				OmitOpenGraphImages:       !*ogImages,
+				OpenGraphContentImage:     *ogContentImage,
+				AnnotateIssues:            *annotateIssues,
+				SourceIsMarkdown:          *sourceIsMarkdown,
+				StripShortcodes:           shortcodeStripping,
+				StripEmptyParagraphs:      *stripEmptyParas,
+				TaxonomyKeys:              taxonomyKeyMapping,
+				Typography:                contentTypography,
+				ExcerptFormat:             contentExcerptFormat,
+				TitleNormalization:        titleNormalization,
+				PreserveLinkAttributes:    *linkAttributes,
+				PlaylistShortcode:         *playlistShortcode,
+				GalleryOutput:             galleries,
+			},
+			KeepInlineImages:    *keepInlineImages,
+			ConvertImagesToWebP: *convertToWebP,
+			WebPQuality:         *webpQuality,
+			KeepOriginalImages:  *keepOriginalImages,
+			DatePath:            *datePath,
+			DateSource:          publishDateSource,
+			MissingDatePolicy:   missingDatePolicy,
+			AuthorLogins:        *authorLogins,
+			AllAuthors:          *allAuthors,
+			AuthorMap:           authorMapping,
+			PrivateContentDir:   *privateContentDir,
+			DraftsDir:           *draftsDir,
+			DraftStatuses:       draftContentStatuses,
+			Formats:             formats,
+			PathOverrides:       pathOverrideMapping,
+			SectionCascades:     sectionCascades,
+			ContentReplacements: contentReplacements,
+			NextPage:            nextPagePolicy,
+			AttachmentPages:     attachmentPagePolicy,
+			TaxonomyWeights:     *taxonomyWeights,
+			TaxonomyPaths:       taxonomyPathMapping,
+			TermMeta:            *termMeta,
+			FaviconParam:        *faviconParam,
+			LogoParam:           *logoParam,
+			TermCollisions:      termCollisionPolicy,
+			TermCollisionTarget: *termTarget,
+			AssetsDir:           *assetsDir,
+			AssetReferences:     assetReferenceStyle,
+			BrokenImages:        brokenImagePolicy,
+			SVGImages:           svgImagePolicy,
+			SVGInlineMaxBytes:   *svgInlineMaxBytes,
+			SiteName:            *siteName,
+			Incremental:         *incremental,
+			OutputZip:           *outputZip,
+			Index:               *index,
+			CommentThreads:      *commentThreads,
+			SingleFile:          *singleFile,
+			SingleFileTOC:       *singleFileTOC,
+			NoMedia:             *noMedia,
+			HostRewrites:        hostRewrites,
+			MaxFileNameLength:   *maxFileNameLength,
+			AlwaysEmitSlug:      *alwaysEmitSlug,
+			WooCommerce:         *wooCommerce,
+			EmitCommentStatus:   *emitCommentStatus,
+			DetectLanguages:     *detectLanguages,
+			FailFast:            *failFast,
+		},
+		Authors:                        strings.Split(*authors, ","),
+		CustomPostTypes:                strings.Split(*customPostTypes, ","),
+		OnlyTypes:                      *onlyTypes,
+		IncludePostIDs:                 *includePostIDs,
+		ExcludePostIDs:                 *excludePostIDs,
+		Font:                           *font,
+		DownloadMedia:                  *downloadMedia,
+		DownloadAll:                    *downloadAll,
+		ContinueOnMediaDownloadFailure: *continueOnMediaDownloadFailure,
+		MediaCacheDir:                  *mediaCacheDir,
+		GenerateNginxConfig:            *generateNgnixConfig,
+		RESTAPIUsername:                *restAPIUsername,
+		RESTAPIApplicationPassword:     *restAPIPassword,
+	}, nil
+}
+
+func getWordPressIDKey() string {
+	if !*emitWPID {
+		return ""
+	}
+	return strings.TrimSpace(*wpIDKey)
 }
@@ -0,0 +1,187 @@
+package hugogenerator
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/hugogenerator/hugopage"
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/utils"
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/rs/zerolog/log"
+)
+
+// AttachmentPagePolicy decides what becomes of the attachment pages WordPress generates for the media,
+// e.g. /summit/ for summit.jpg, which are indexed and linked
+type AttachmentPagePolicy string
+
+const (
+	// AttachmentPagesNone leaves the attachment pages out, their URLs are not found on the Hugo site
+	AttachmentPagesNone AttachmentPagePolicy = "none"
+	// AttachmentPagesRedirect redirects the attachment pages to the content they are attached to, their post_parent,
+	// with the aliases of its front matter, or else to their media file
+	AttachmentPagesRedirect AttachmentPagePolicy = "redirect"
+	// AttachmentPagesPage writes a minimal page at the URL of the attachment pages of the written content,
+	// with their media and a link to the content
+	AttachmentPagesPage AttachmentPagePolicy = "page"
+)
+
+func ParseAttachmentPagePolicy(policy string) (AttachmentPagePolicy, error) {
+	switch AttachmentPagePolicy(policy) {
+	case AttachmentPagesNone, AttachmentPagesRedirect, AttachmentPagesPage:
+		return AttachmentPagePolicy(policy), nil
+	case "":
+		return AttachmentPagesNone, nil
+	default:
+		return "", fmt.Errorf("unknown attachment pages policy %q, expected one of %s, %s, %s",
+			policy, AttachmentPagesNone, AttachmentPagesRedirect, AttachmentPagesPage)
+	}
+}
+
+// Like the alias pages of Hugo, which can't point to a file
+const _attachmentRedirectTemplate = `<!DOCTYPE html>
+<html>
+  <head>
+    <title>%[1]s</title>
+    <link rel="canonical" href="%[1]s">
+    <meta name="robots" content="noindex">
+    <meta charset="utf-8">
+    <meta http-equiv="refresh" content="0; url=%[1]s">
+  </head>
+</html>
+`
+
+// getAttachmentPagePath returns the URL path of the attachment page, e.g. /summit/, or false when it has none
+// a Hugo page can be at, e.g. /?attachment_id=11
+func (g Generator) getAttachmentPagePath(attachment wpparser.AttachmentInfo) (string, bool) {
+	pageURL, err := url.Parse(attachment.Link)
+	if err != nil || pageURL.RawQuery != "" || strings.Trim(pageURL.Path, "/") == "" {
+		log.Debug().
+			Str("link", attachment.Link).
+			Msg("Attachment page without a path, skipped")
+		return "", false
+	}
+	return g.options.URLPrefix + pageURL.Path, true
+}
+
+// getAttachmentAliases returns the URL paths of the attachment pages of the content, redirected to its first page
+// with AttachmentPagesRedirect
+func (g Generator) getAttachmentAliases(part pagePart) []string {
+	if g.options.AttachmentPages != AttachmentPagesRedirect || part.number > 1 {
+		return nil
+	}
+	var aliases []string
+	for _, attachment := range g.wpInfo.GetAttachmentsForPost(part.page.PostID) {
+		if attachmentPath, ok := g.getAttachmentPagePath(attachment); ok {
+			aliases = append(aliases, attachmentPath)
+		}
+	}
+	return aliases
+}
+
+// writeAttachmentPages writes the attachment pages of the written content with AttachmentPagesPage,
+// or the redirects to their media file of the attachments of no written content with AttachmentPagesRedirect
+func (g Generator) writeAttachmentPages(ctx context.Context, siteDir string, info wpparser.WebsiteInfo) error {
+	parents := make(map[string]wpparser.CommonFields)
+	for _, content := range g.getWrittenContent(info) {
+		parents[content.PostID] = content
+	}
+	for _, attachment := range info.Attachments() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		attachmentPath, ok := g.getAttachmentPagePath(attachment)
+		if !ok || attachment.GetAttachmentURL() == nil {
+			continue
+		}
+		var parent *wpparser.CommonFields
+		if attachment.PostParentID != nil {
+			if content, ok := parents[*attachment.PostParentID]; ok {
+				parent = &content
+			}
+		}
+		switch {
+		case g.options.AttachmentPages == AttachmentPagesPage && parent != nil:
+			if err := g.writeAttachmentPage(ctx, siteDir, info, attachment, attachmentPath, *parent); err != nil {
+				return err
+			}
+		case g.options.AttachmentPages == AttachmentPagesRedirect && parent == nil:
+			if err := g.writeAttachmentRedirect(ctx, siteDir, info, attachment, attachmentPath); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writeAttachmentPage writes the attachment page into the attachments dir next to the content it is attached to,
+// left out of the lists of pages
+func (g Generator) writeAttachmentPage(ctx context.Context, siteDir string, info wpparser.WebsiteInfo,
+	attachment wpparser.AttachmentInfo, attachmentPath string, parent wpparser.CommonFields,
+) error {
+	link, err := g.getMediaLink(ctx, siteDir, info, *attachment.GetAttachmentURL())
+	if err != nil {
+		return err
+	}
+	metadata := map[string]any{
+		"title":          attachment.Title,
+		"url":            attachmentPath,
+		"parent_post_id": parent.PostID,
+		"images":         []string{link},
+		"build":          map[string]string{"list": "never"},
+	}
+	if attachment.PublishDate != nil {
+		metadata["date"] = attachment.PublishDate.Format(hugopage.DateFormat)
+	}
+	frontMatter, err := utils.GetYAML(metadata)
+	if err != nil {
+		return err
+	}
+	body := fmt.Sprintf("![%s](%s)\n", escapeLinkText(attachment.Title), link)
+	if caption := strings.TrimSpace(attachment.Excerpt); caption != "" {
+		body += "\n" + caption + "\n"
+	}
+	if parentURL, err := url.Parse(parent.Link); err == nil {
+		body += fmt.Sprintf("\n[%s](%s)\n", escapeLinkText(parent.Title), g.options.URLPrefix+parentURL.Path)
+	}
+
+	attachmentsDir := path.Join(g.contentDir(siteDir, parent), "attachments")
+	if err := os.MkdirAll(attachmentsDir, 0o755); err != nil {
+		return fmt.Errorf("error creating attachments dir: %w", err)
+	}
+	pagePath := getFilePath(attachmentsDir, g.getFileInfo(attachment.CommonFields).FileNameWithLanguage())
+	if err := writeFile(pagePath, fmt.Appendf(nil, "---\n%s---\n%s", frontMatter, body)); err != nil {
+		return err
+	}
+	log.Debug().Msgf("Attachment page written: %s", pagePath)
+	return nil
+}
+
+// writeAttachmentRedirect writes a page redirecting the attachment page to its media file into the static dir
+func (g Generator) writeAttachmentRedirect(ctx context.Context, siteDir string, info wpparser.WebsiteInfo,
+	attachment wpparser.AttachmentInfo, attachmentPath string,
+) error {
+	link, err := g.getMediaLink(ctx, siteDir, info, *attachment.GetAttachmentURL())
+	if err != nil {
+		return err
+	}
+	redirectPath := path.Join(siteDir, _staticDir, attachmentPath, "index.html")
+	if utils.FileExists(redirectPath) {
+		log.Warn().
+			Str("redirectPath", redirectPath).
+			Msg("Attachment page already exists, not redirected")
+		return nil
+	}
+	if err := os.MkdirAll(path.Dir(redirectPath), 0o755); err != nil {
+		return fmt.Errorf("error creating attachment redirect dir: %w", err)
+	}
+	if err := writeFile(redirectPath, fmt.Appendf(nil, _attachmentRedirectTemplate, html.EscapeString(link))); err != nil {
+		return err
+	}
+	log.Debug().Msgf("Attachment redirect written: %s", redirectPath)
+	return nil
+}
@@ -0,0 +1,49 @@
+package hugogenerator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttachmentPagesRedirect(t *testing.T) {
+	t.Parallel()
+	siteDir := generateFixtureSite(t, integrationFixture{name: "classic"}, Options{AttachmentPages: AttachmentPagesRedirect})
+
+	content, err := os.ReadFile(filepath.Join(siteDir, "content", "posts", "a-trip-to-the-mountains.md"))
+	require.NoError(t, err)
+	require.Contains(t, string(content), "\naliases:\n  - /summit/\n  - /valley/\n")
+
+	// The products are not converted, their media pages redirect to the media files
+	siteDir = generateFixtureSite(t, integrationFixture{name: "woocommerce"}, Options{AttachmentPages: AttachmentPagesRedirect})
+	redirect, err := os.ReadFile(filepath.Join(siteDir, "static", "shop", "blue-mug", "mug-blue", "index.html"))
+	require.NoError(t, err)
+	require.Contains(t, string(redirect), `<meta http-equiv="refresh" content="0; url=/wp-content/uploads/2024/03/mug-blue.jpg">`)
+}
+
+func TestAttachmentPagesPage(t *testing.T) {
+	t.Parallel()
+	siteDir := generateFixtureSite(t, integrationFixture{name: "classic"}, Options{AttachmentPages: AttachmentPagesPage})
+
+	content, err := os.ReadFile(filepath.Join(siteDir, "content", "attachments", "summit.md"))
+	require.NoError(t, err)
+	require.Contains(t, string(content), "\nurl: /summit/\n")
+	require.Contains(t, string(content), "\nparent_post_id: \"10\"\n")
+	require.Contains(t, string(content), "---\n![Summit](/wp-content/uploads/2024/03/summit.jpg)\n")
+	require.Contains(t, string(content), "\n[A trip to the mountains](/2024/03/05/a-trip-to-the-mountains/)\n")
+
+	post, err := os.ReadFile(filepath.Join(siteDir, "content", "posts", "a-trip-to-the-mountains.md"))
+	require.NoError(t, err)
+	require.NotContains(t, string(post), "aliases:")
+}
+
+func TestParseAttachmentPagePolicy(t *testing.T) {
+	t.Parallel()
+	policy, err := ParseAttachmentPagePolicy("")
+	require.NoError(t, err)
+	require.Equal(t, AttachmentPagesNone, policy)
+	_, err = ParseAttachmentPagePolicy("delete")
+	require.Error(t, err)
+}
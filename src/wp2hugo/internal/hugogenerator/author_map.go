@@ -0,0 +1,71 @@
+package hugogenerator
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"gopkg.in/yaml.v3"
+)
+
+// ReadAuthorMap reads a YAML file mapping the login or the display name of the authors to their target author,
+// e.g. `former-intern: jdoe` to consolidate the content of an author into another one
+func ReadAuthorMap(filePath string) (map[string]string, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading author map: %w", err)
+	}
+	var authorMap map[string]string
+	if err := yaml.Unmarshal(data, &authorMap); err != nil {
+		return nil, fmt.Errorf("error parsing author map '%s': %w", filePath, err)
+	}
+	return authorMap, nil
+}
+
+func validateAuthorMap(authorMap map[string]string) error {
+	for source, target := range authorMap {
+		if strings.TrimSpace(target) == "" {
+			return fmt.Errorf("author map: %q is mapped to an empty author", source)
+		}
+	}
+	return nil
+}
+
+// mappedAuthor is the author an author of the export becomes with Options.AuthorMap
+type mappedAuthor struct {
+	// Another author of the export the author is consolidated into, or else nil, and the author is renamed to name
+	author *wpparser.AuthorInfo
+	name   string
+}
+
+// getMappedAuthor returns the author the login is mapped to by Options.AuthorMap, matching its login or else its display name.
+// The target is an author of the export if it matches its login, display name or slug.
+func getMappedAuthor(info wpparser.WebsiteInfo, authorMap map[string]string, login string) (mappedAuthor, bool) {
+	target, ok := authorMap[login]
+	if !ok {
+		for _, author := range info.Authors() {
+			if author.Login == login && author.DisplayName != "" {
+				target, ok = authorMap[author.DisplayName]
+				break
+			}
+		}
+	}
+	if !ok {
+		return mappedAuthor{}, false
+	}
+	target = strings.TrimSpace(target)
+	authors := info.Authors()
+	for _, match := range []func(wpparser.AuthorInfo) bool{
+		func(a wpparser.AuthorInfo) bool { return a.Login == target },
+		func(a wpparser.AuthorInfo) bool { return a.DisplayName == target },
+		func(a wpparser.AuthorInfo) bool { return a.Slug == target },
+	} {
+		for i := range authors {
+			if match(authors[i]) {
+				return mappedAuthor{author: &authors[i]}, true
+			}
+		}
+	}
+	return mappedAuthor{name: target}, true
+}
@@ -0,0 +1,78 @@
+package hugogenerator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthorMap(t *testing.T) {
+	t.Parallel()
+
+	jdoe := "<wp:author><wp:author_id>1</wp:author_id>"
+	intern := "<wp:author><wp:author_id>2</wp:author_id><wp:author_login><![CDATA[intern]]></wp:author_login>" +
+		"<wp:author_display_name><![CDATA[Summer Intern]]></wp:author_display_name></wp:author>\n"
+	guest := "<wp:author><wp:author_id>3</wp:author_id><wp:author_login><![CDATA[guest]]></wp:author_login>" +
+		"<wp:author_display_name><![CDATA[Guest]]></wp:author_display_name></wp:author>\n"
+	websiteInfo := parseFixture(t, integrationFixture{name: "classic", replacements: []string{jdoe, intern + guest + jdoe}})
+
+	authorMap := map[string]string{"Summer Intern": "jdoe", "guest": "Guest Writers"}
+	mapped, ok := getMappedAuthor(*websiteInfo, authorMap, "intern")
+	require.True(t, ok)
+	require.NotNil(t, mapped.author)
+	require.Equal(t, "jdoe", mapped.author.Login)
+	mapped, ok = getMappedAuthor(*websiteInfo, authorMap, "guest")
+	require.True(t, ok)
+	require.Nil(t, mapped.author)
+	require.Equal(t, "Guest Writers", mapped.name)
+	_, ok = getMappedAuthor(*websiteInfo, authorMap, "jdoe")
+	require.False(t, ok)
+
+	siteDir := t.TempDir()
+	// The guest and the intern authored none of the content
+	require.NoError(t, setupAuthorsData(siteDir, *websiteInfo, authorMap, true))
+	authors, err := os.ReadFile(filepath.Join(siteDir, "data", "authors.yaml"))
+	require.NoError(t, err)
+	require.Contains(t, string(authors), "jdoe:\n")
+	require.Contains(t, string(authors), "Guest Writers:\n")
+	require.NotContains(t, string(authors), "intern")
+	require.NotContains(t, string(authors), "\nguest:\n")
+
+	require.Error(t, validateAuthorMap(map[string]string{"intern": " "}))
+}
+
+func TestReadAuthorMap(t *testing.T) {
+	t.Parallel()
+
+	filePath := filepath.Join(t.TempDir(), "authors.yaml")
+	require.NoError(t, os.WriteFile(filePath, []byte("intern: jdoe\n\"Summer Intern\": jdoe\n"), 0o600))
+	authorMap, err := ReadAuthorMap(filePath)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"intern": "jdoe", "Summer Intern": "jdoe"}, authorMap)
+
+	require.NoError(t, os.WriteFile(filePath, []byte("- jdoe\n"), 0o600))
+	_, err = ReadAuthorMap(filePath)
+	require.Error(t, err)
+}
+
+func TestAuthorSlugs(t *testing.T) {
+	t.Parallel()
+
+	jdoe := "<wp:author><wp:author_id>1</wp:author_id>"
+	intern := "<wp:author><wp:author_id>2</wp:author_id><wp:author_login><![CDATA[intern]]></wp:author_login>" +
+		"<wp:author_display_name><![CDATA[Summer Intern]]></wp:author_display_name></wp:author>\n"
+	websiteInfo := parseFixture(t, integrationFixture{name: "classic", replacements: []string{jdoe, intern + jdoe}})
+	page := wpparser.CommonFields{Author: "intern", Title: "Internship"}
+
+	// The slug keys data/authors.yaml
+	generator := NewGenerator(t.TempDir(), "", nil, false, false, false, false, *websiteInfo, Options{})
+	require.Equal(t, "summer-intern", generator.getAuthor(page))
+	// Not in the export, the login is kept
+	require.Equal(t, "ghost", generator.getAuthor(wpparser.CommonFields{Author: "ghost"}))
+
+	generator = NewGenerator(t.TempDir(), "", nil, false, false, false, false, *websiteInfo, Options{AuthorLogins: true})
+	require.Equal(t, "intern", generator.getAuthor(page))
+}
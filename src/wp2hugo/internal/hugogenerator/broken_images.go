@@ -0,0 +1,89 @@
+package hugogenerator
+
+import (
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/hugogenerator/hugopage"
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/utils"
+	"github.com/rs/zerolog/log"
+)
+
+// BrokenImagePolicy decides what becomes of the content images which failed to download,
+// e.g. media deleted from WordPress but still referenced by old posts
+type BrokenImagePolicy string
+
+const (
+	// BrokenImagesKeep leaves the original link untouched
+	BrokenImagesKeep BrokenImagePolicy = "keep"
+	// BrokenImagesPlaceholder links a placeholder image instead, written by wp2hugo
+	BrokenImagesPlaceholder BrokenImagePolicy = "placeholder"
+	// BrokenImagesRemove removes the images and figures from the content
+	BrokenImagesRemove BrokenImagePolicy = "remove"
+)
+
+// Written with the media of the content, relative to the media dir of the site like the downloaded images,
+// so the link resolves the same way as theirs, including from the assets dir
+const _brokenImagePlaceholderLink = "/wp-content/uploads/wp2hugo-missing-image.svg"
+
+const _brokenImagePlaceholder = `<svg xmlns="http://www.w3.org/2000/svg" width="640" height="360" viewBox="0 0 640 360">
+<rect width="640" height="360" fill="#e5e7eb"/>
+<text x="320" y="180" fill="#6b7280" font-family="sans-serif" font-size="24" text-anchor="middle" dominant-baseline="middle">Image not found</text>
+</svg>
+`
+
+// BrokenImage is a content image which failed to download, see Options.BrokenImages
+type BrokenImage struct {
+	// URL of the WordPress page
+	Page  string
+	Link  string
+	Issue string
+}
+
+func ParseBrokenImagePolicy(policy string) (BrokenImagePolicy, error) {
+	switch BrokenImagePolicy(policy) {
+	case BrokenImagesKeep, BrokenImagesPlaceholder, BrokenImagesRemove:
+		return BrokenImagePolicy(policy), nil
+	case "":
+		return BrokenImagesKeep, nil
+	default:
+		return "", fmt.Errorf("unknown broken image policy %q, expected one of %s, %s, %s",
+			policy, BrokenImagesKeep, BrokenImagesPlaceholder, BrokenImagesRemove)
+	}
+}
+
+// handleBrokenImages applies Options.BrokenImages to the images of the page which failed to download
+func (g Generator) handleBrokenImages(outputMediaDirPath string, p *hugopage.Page, brokenLinks []string,
+	urlReplacements map[string]string,
+) error {
+	for _, link := range brokenLinks {
+		switch g.options.BrokenImages {
+		case BrokenImagesPlaceholder:
+			filePath := path.Join(outputMediaDirPath, g.getImageMediaDir(_brokenImagePlaceholderLink), _brokenImagePlaceholderLink)
+			if err := writeBrokenImagePlaceholder(filePath); err != nil {
+				return err
+			}
+			urlReplacements[link] = _brokenImagePlaceholderLink
+		case BrokenImagesRemove:
+			p.RemoveImage(link)
+		default:
+			continue
+		}
+		log.Debug().
+			Str("link", link).
+			Str("policy", string(g.options.BrokenImages)).
+			Msg("Broken image handled")
+	}
+	return nil
+}
+
+func writeBrokenImagePlaceholder(filePath string) error {
+	if utils.FileExists(filePath) {
+		return nil
+	}
+	if err := os.MkdirAll(path.Dir(filePath), 0o755); err != nil {
+		return fmt.Errorf("error creating placeholder image dir: %w", err)
+	}
+	return writeFile(filePath, []byte(_brokenImagePlaceholder))
+}
@@ -0,0 +1,69 @@
+package hugogenerator
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type notFoundMediaProvider struct{}
+
+func (notFoundMediaProvider) GetReader(_ context.Context, url string) (io.Reader, error) {
+	return nil, errors.New("404 Not Found: " + url)
+}
+
+func TestBrokenImages(t *testing.T) {
+	t.Parallel()
+	const summitLink = "/wp-content/uploads/2024/03/summit-640x480.jpg"
+	generate := func(policy BrokenImagePolicy, assetsDir string) (string, string, Report) {
+		info := parseFixture(t, integrationFixture{name: "classic"})
+		siteDir := t.TempDir()
+		generator := NewGenerator(siteDir, "", notFoundMediaProvider{}, true, false, true, false, *info,
+			Options{BrokenImages: policy, AssetsDir: assetsDir})
+		require.NoError(t, generator.writeContent(context.Background(), siteDir, *info))
+		content, err := os.ReadFile(filepath.Join(siteDir, "content", "posts", "a-trip-to-the-mountains.md"))
+		require.NoError(t, err)
+		return siteDir, string(content), generator.Report()
+	}
+
+	_, content, report := generate(BrokenImagesKeep, "")
+	require.Contains(t, content, `src="`+summitLink+`"`)
+	// The figure and the gallery images, the audio is not an image
+	require.Len(t, report.BrokenImages, 3)
+	require.Equal(t, BrokenImage{
+		Page:  "https://example.org/2024/03/05/a-trip-to-the-mountains/",
+		Link:  summitLink,
+		Issue: "media download failed",
+	}, report.BrokenImages[0])
+
+	siteDir, content, _ := generate(BrokenImagesPlaceholder, "")
+	require.Contains(t, content, `src="`+_brokenImagePlaceholderLink+`"`)
+	require.FileExists(t, filepath.Join(siteDir, "static", _brokenImagePlaceholderLink))
+
+	// The link resolves from the dir the placeholder is written to, like the other images
+	siteDir, content, _ = generate(BrokenImagesPlaceholder, "assets")
+	require.Contains(t, content, `src="`+_brokenImagePlaceholderLink+`"`)
+	require.FileExists(t, filepath.Join(siteDir, "assets", _brokenImagePlaceholderLink))
+	require.NoFileExists(t, filepath.Join(siteDir, "static", _brokenImagePlaceholderLink))
+
+	_, content, _ = generate(BrokenImagesRemove, "")
+	require.NotContains(t, content, summitLink)
+	require.NotContains(t, content, "{{< figure")
+}
+
+func TestParseBrokenImagePolicy(t *testing.T) {
+	t.Parallel()
+	policy, err := ParseBrokenImagePolicy("remove")
+	require.NoError(t, err)
+	require.Equal(t, BrokenImagesRemove, policy)
+	policy, err = ParseBrokenImagePolicy("")
+	require.NoError(t, err)
+	require.Equal(t, BrokenImagesKeep, policy)
+	_, err = ParseBrokenImagePolicy("hide")
+	require.Error(t, err)
+}
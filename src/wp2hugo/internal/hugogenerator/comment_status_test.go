@@ -0,0 +1,30 @@
+package hugogenerator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmitCommentStatus(t *testing.T) {
+	t.Parallel()
+	siteDir := generateFixtureSite(t, integrationFixture{name: "messy_html"}, Options{EmitCommentStatus: true})
+	content, err := os.ReadFile(filepath.Join(siteDir, "content", "posts", "tag-soup.md"))
+	require.NoError(t, err)
+	require.Contains(t, string(content), "\ncomments: true\n")
+	require.Contains(t, string(content), "\ncomment_count: 0\n")
+
+	siteDir = generateFixtureSite(t, integrationFixture{name: "woocommerce", customPostTypes: []string{"product"}},
+		Options{EmitCommentStatus: true})
+	content, err = os.ReadFile(filepath.Join(siteDir, "content", "products", "mug", "index.md"))
+	require.NoError(t, err)
+	require.Contains(t, string(content), "\ncomments: false\n")
+
+	// The key is omitted without the option
+	siteDir = generateFixtureSite(t, integrationFixture{name: "messy_html"}, Options{})
+	content, err = os.ReadFile(filepath.Join(siteDir, "content", "posts", "tag-soup.md"))
+	require.NoError(t, err)
+	require.NotContains(t, string(content), "comments:")
+}
@@ -0,0 +1,107 @@
+package hugogenerator
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/rs/zerolog/log"
+)
+
+// commentThread is the thread of comments of a content, for seeding a comment system, see Options.CommentThreads
+type commentThread struct {
+	PostID string `json:"post_id"`
+	Title  string `json:"title"`
+	// Discussion term of the content with the pathname mapping of Giscus and Utterances, e.g. "2024/03/a-trip/"
+	Term     string          `json:"term"`
+	Comments []threadComment `json:"comments"`
+}
+
+// threadComment is a comment of a commentThread, without the email of its author, which the comment systems publish
+type threadComment struct {
+	ID         string     `json:"id"`
+	ParentID   string     `json:"parent_id"`
+	AuthorName string     `json:"author_name"`
+	AuthorURL  string     `json:"author_url"`
+	Published  *time.Time `json:"published"`
+	Content    string     `json:"content"`
+}
+
+// The extension is dropped from the pathname, like Giscus and Utterances do, e.g. "about.html" is "about"
+var _pathnameExtensionRegEx = regexp.MustCompile(`\.\w+$`)
+
+func validateCommentThreadsPath(threadsPath string) error {
+	if strings.ToLower(filepath.Ext(threadsPath)) != ".json" {
+		return fmt.Errorf("unknown comment threads format %q, expected a .json file", filepath.Ext(threadsPath))
+	}
+	return nil
+}
+
+// getDiscussionTerm returns the term Giscus and Utterances look the discussion of the page at urlPath up with,
+// with their pathname mapping
+func getDiscussionTerm(urlPath string) string {
+	if len(urlPath) < 2 {
+		return "index"
+	}
+	return _pathnameExtensionRegEx.ReplaceAllString(strings.TrimPrefix(urlPath, "/"), "")
+}
+
+// getCommentThreads returns the threads of the content with comments, keyed by their URL path
+func (g Generator) getCommentThreads(contents []wpparser.CommonFields) map[string]commentThread {
+	threads := make(map[string]commentThread)
+	for _, content := range contents {
+		if len(content.Comments) == 0 {
+			continue
+		}
+		pageURL, err := url.Parse(content.Link)
+		if err != nil {
+			log.Warn().
+				Err(err).
+				Str("link", content.Link).
+				Msg("Skipping the comment thread of the content with an invalid link")
+			continue
+		}
+		urlPath := g.options.URLPrefix + pageURL.Path
+		thread := commentThread{
+			PostID:   content.PostID,
+			Title:    content.Title,
+			Term:     getDiscussionTerm(urlPath),
+			Comments: make([]threadComment, 0, len(content.Comments)),
+		}
+		for _, comment := range content.Comments {
+			thread.Comments = append(thread.Comments, threadComment{
+				ID:         comment.ID,
+				ParentID:   comment.ParentID,
+				AuthorName: comment.AuthorName,
+				AuthorURL:  comment.AuthorURL,
+				Published:  comment.PublishDate,
+				Content:    comment.Content,
+			})
+		}
+		threads[urlPath] = thread
+	}
+	return threads
+}
+
+// writeCommentThreads writes the comment threads of the content, keyed by their URL path, into Options.CommentThreads
+func (g Generator) writeCommentThreads(info wpparser.WebsiteInfo) error {
+	threads := g.getCommentThreads(g.getWrittenContent(info))
+	data, err := json.MarshalIndent(threads, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshalling comment threads: %w", err)
+	}
+	if err := os.WriteFile(g.options.CommentThreads, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("error writing comment threads: %w", err)
+	}
+	log.Info().
+		Str("location", g.options.CommentThreads).
+		Int("threads", len(threads)).
+		Msg("Comment threads written")
+	return nil
+}
@@ -0,0 +1,45 @@
+package hugogenerator
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/hugogenerator/hugopage"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommentThreads(t *testing.T) {
+	t.Parallel()
+	websiteInfo := parseFixture(t, integrationFixture{name: "classic"})
+	siteDir := t.TempDir()
+	threadsPath := filepath.Join(t.TempDir(), "threads.json")
+	generator := NewGenerator(siteDir, "", nil, false, false, false, false, *websiteInfo,
+		Options{CommentThreads: threadsPath, PageOptions: hugopage.PageOptions{URLPrefix: "/blog"}})
+	require.NoError(t, generator.writeContent(context.Background(), siteDir, *websiteInfo))
+
+	data, err := os.ReadFile(threadsPath)
+	require.NoError(t, err)
+	var threads map[string]commentThread
+	require.NoError(t, json.Unmarshal(data, &threads))
+	require.Len(t, threads, 1)
+	thread := threads["/blog/2024/03/05/a-trip-to-the-mountains/"]
+	require.Equal(t, "10", thread.PostID)
+	require.Equal(t, "A trip to the mountains", thread.Title)
+	require.Equal(t, "blog/2024/03/05/a-trip-to-the-mountains/", thread.Term)
+	require.Len(t, thread.Comments, 1)
+	require.Equal(t, "7", thread.Comments[0].ID)
+	require.Equal(t, "Lovely pictures!", thread.Comments[0].Content)
+	require.NotContains(t, string(data), "reader@example.com")
+
+	require.Error(t, validateCommentThreadsPath("threads.yaml"))
+}
+
+func TestDiscussionTerm(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, "index", getDiscussionTerm("/"))
+	require.Equal(t, "2024/03/a-trip/", getDiscussionTerm("/2024/03/a-trip/"))
+	require.Equal(t, "about", getDiscussionTerm("/about.html"))
+}
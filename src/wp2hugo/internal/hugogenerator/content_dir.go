@@ -0,0 +1,33 @@
+package hugogenerator
+
+import (
+	"path"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+)
+
+// contentDir returns the directory the WordPress content is written to,
+// under the URL prefix if any.
+// Non-public content goes to a separate tree when Options.PrivateContentDir is set,
+// and the drafts to theirs when Options.DraftsDir is set, even if they are not public.
+func (g Generator) contentDir(outputDirPath string, page wpparser.CommonFields) string {
+	if g.isDraftsDirContent(page) {
+		return path.Join(outputDirPath, "content", g.options.DraftsDir, g.options.URLPrefix)
+	}
+	if g.options.PrivateContentDir != "" && !page.IsPublic() {
+		return path.Join(outputDirPath, "content", g.options.PrivateContentDir, g.options.URLPrefix)
+	}
+	return path.Join(outputDirPath, "content", g.options.URLPrefix)
+}
+
+// contentDirs returns all the directories the WordPress content may be written to
+func (g Generator) contentDirs(outputDirPath string) []string {
+	contentDirs := []string{path.Join(outputDirPath, "content", g.options.URLPrefix)}
+	if g.options.PrivateContentDir != "" {
+		contentDirs = append(contentDirs, path.Join(outputDirPath, "content", g.options.PrivateContentDir, g.options.URLPrefix))
+	}
+	if g.options.DraftsDir != "" {
+		contentDirs = append(contentDirs, path.Join(outputDirPath, "content", g.options.DraftsDir, g.options.URLPrefix))
+	}
+	return contentDirs
+}
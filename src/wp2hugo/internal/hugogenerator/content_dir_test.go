@@ -0,0 +1,25 @@
+package hugogenerator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrivateContentDir(t *testing.T) {
+	t.Parallel()
+	siteDir := generateFixtureSite(t, integrationFixture{name: "classic"}, Options{PrivateContentDir: "/_private/"})
+
+	_, err := os.Stat(filepath.Join(siteDir, "content", "posts", "a-trip-to-the-mountains.md"))
+	require.NoError(t, err)
+	_, err = os.Stat(filepath.Join(siteDir, "content", "pages", "about", "_index.md"))
+	require.NoError(t, err)
+
+	// The draft is segregated
+	_, err = os.Stat(filepath.Join(siteDir, "content", "_private", "posts", "unfinished-thoughts.md"))
+	require.NoError(t, err)
+	_, err = os.Stat(filepath.Join(siteDir, "content", "posts", "unfinished-thoughts.md"))
+	require.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,107 @@
+package hugogenerator
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/hugogenerator/hugopage"
+	"github.com/rs/zerolog/log"
+)
+
+// ContentFormat is a format the WordPress content is emitted in
+type ContentFormat string
+
+const (
+	// ContentFormatMarkdown is the content converted to Markdown, which the Hugo site is built from
+	ContentFormatMarkdown ContentFormat = "markdown"
+	// ContentFormatHTML is the HTML content as exported, e.g. for an archive of the WordPress site,
+	// written as Hugo content files in the HTML format into the HTMLContentDir tree
+	ContentFormatHTML ContentFormat = "html"
+)
+
+// HTMLContentDir is the dir of the site the content in the HTML format is written to, in parallel to the content dir,
+// e.g. "content-html/posts/slug.html" for "content/posts/slug.md"
+const HTMLContentDir = "content-html"
+
+// DefaultContentFormats are the formats the content is emitted in by default
+var DefaultContentFormats = []ContentFormat{ContentFormatMarkdown}
+
+// ParseContentFormats parses the formats the content is emitted in, e.g. "markdown" and "html",
+// empty for DefaultContentFormats. The Markdown is required, the Hugo site is built from it.
+func ParseContentFormats(formats []string) ([]ContentFormat, error) {
+	parsed := make([]ContentFormat, 0, len(formats))
+	for _, format := range formats {
+		format = strings.TrimSpace(format)
+		switch ContentFormat(format) {
+		case "":
+			continue
+		case ContentFormatMarkdown, ContentFormatHTML:
+			if !slices.Contains(parsed, ContentFormat(format)) {
+				parsed = append(parsed, ContentFormat(format))
+			}
+		default:
+			return nil, fmt.Errorf("unknown content format %q, expected %s or %s", format, ContentFormatMarkdown, ContentFormatHTML)
+		}
+	}
+	if len(parsed) == 0 {
+		return DefaultContentFormats, nil
+	}
+	if !slices.Contains(parsed, ContentFormatMarkdown) {
+		return nil, fmt.Errorf("content formats %v don't include %s, which the Hugo site is built from", parsed, ContentFormatMarkdown)
+	}
+	return parsed, nil
+}
+
+func (g Generator) emitsHTML() bool {
+	return slices.Contains(g.options.Formats, ContentFormatHTML)
+}
+
+// getHTMLPagePath returns the path of the page in the HTML format, under HTMLContentDir, for the path of its Markdown
+func getHTMLPagePath(siteDir string, pagePath string) (string, error) {
+	relativePath, err := filepath.Rel(path.Join(siteDir, "content"), pagePath)
+	if err != nil {
+		return "", fmt.Errorf("error getting the path of %s in the content dir: %w", pagePath, err)
+	}
+	return path.Join(siteDir, HTMLContentDir, strings.TrimSuffix(filepath.ToSlash(relativePath), ".md")+".html"), nil
+}
+
+// writeHTMLPageFile writes the page in the HTML format, with the same front matter and media as its Markdown
+func writeHTMLPageFile(siteDir string, pagePath string, p *hugopage.Page) error {
+	htmlPagePath, err := getHTMLPagePath(siteDir, pagePath)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(path.Dir(htmlPagePath), 0o755); err != nil {
+		return fmt.Errorf("error creating the dir of %s: %w", htmlPagePath, err)
+	}
+	w, err := _siteFiles.Create(htmlPagePath)
+	if err != nil {
+		return fmt.Errorf("error opening page file: %w", err)
+	}
+	if err = p.WriteHTML(w); err != nil {
+		_ = w.Close()
+		return fmt.Errorf("error writing page file: %w", err)
+	}
+	if err = w.Close(); err != nil {
+		return fmt.Errorf("error closing page file: %w", err)
+	}
+	log.Info().Msgf("Page written: %s", htmlPagePath)
+	return nil
+}
+
+// pageBundleDirs returns the content dirs whose page bundles are sanitized, in the HTMLContentDir tree too
+func (g Generator) pageBundleDirs(siteDir string) []string {
+	contentDirs := g.contentDirs(siteDir)
+	if !g.emitsHTML() {
+		return contentDirs
+	}
+	dirs := slices.Clone(contentDirs)
+	for _, contentDir := range contentDirs {
+		dirs = append(dirs, path.Join(siteDir, HTMLContentDir, strings.TrimPrefix(contentDir, path.Join(siteDir, "content"))))
+	}
+	return dirs
+}
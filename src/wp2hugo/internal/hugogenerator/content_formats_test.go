@@ -0,0 +1,114 @@
+package hugogenerator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTMLContentFormat(t *testing.T) {
+	t.Parallel()
+	websiteInfo := parseFixture(t, integrationFixture{name: "classic"})
+	siteDir := t.TempDir()
+	generator := NewGenerator(siteDir, "", nil, false, false, false, false, *websiteInfo,
+		Options{Formats: []ContentFormat{ContentFormatMarkdown, ContentFormatHTML}})
+	require.NoError(t, generator.writeContent(context.Background(), siteDir, *websiteInfo))
+
+	markdown, err := os.ReadFile(filepath.Join(siteDir, "content", "posts", "a-trip-to-the-mountains.md"))
+	require.NoError(t, err)
+	html, err := os.ReadFile(filepath.Join(siteDir, HTMLContentDir, "posts", "a-trip-to-the-mountains.html"))
+	require.NoError(t, err)
+	// The same front matter, followed by the HTML as exported, with its links rewritten like the ones of the Markdown
+	frontMatter := markdown[:strings.Index(string(markdown), "\n---\n")+len("\n---\n")]
+	require.True(t, strings.HasPrefix(string(html), string(frontMatter)))
+	require.Contains(t, string(html), `took a few <a href="/2024/01/02/gear/">pictures</a>.</p>`)
+	require.Contains(t, string(html), `<pre class="lang:sh decode:true">echo "hello"</pre>`+"\n")
+
+	// The page bundles of both trees are named alike
+	require.FileExists(t, filepath.Join(siteDir, HTMLContentDir, "pages", "about", "_index.html"))
+	require.FileExists(t, filepath.Join(siteDir, "content", "pages", "about", "_index.md"))
+}
+
+func TestHTMLContentFormatRewrites(t *testing.T) {
+	t.Parallel()
+	formats := []ContentFormat{ContentFormatMarkdown, ContentFormatHTML}
+	readHTML := func(siteDir string) string {
+		html, err := os.ReadFile(filepath.Join(siteDir, HTMLContentDir, "posts", "a-trip-to-the-mountains.html"))
+		require.NoError(t, err)
+		return string(html)
+	}
+
+	// The images of the srcset attributes, which the Markdown does not have, are downloaded too
+	websiteInfo := parseFixture(t, integrationFixture{name: "classic", replacements: []string{
+		`alt="The summit" width="640"`,
+		`alt="The summit" srcset="https://example.org/wp-content/uploads/2024/03/panorama-300x100.jpg 300w" width="640"`,
+	}})
+	siteDir := t.TempDir()
+	generator := NewGenerator(siteDir, "", staticMediaProvider{}, true, false, false, false, *websiteInfo,
+		Options{Formats: formats})
+	require.NoError(t, generator.writeContent(context.Background(), siteDir, *websiteInfo))
+	require.Contains(t, readHTML(siteDir), `srcset="/wp-content/uploads/2024/03/panorama.jpg 300w"`)
+	require.FileExists(t, filepath.Join(siteDir, "static", "wp-content", "uploads", "2024", "03", "panorama.jpg"))
+
+	// The content replacements and the host rewrites apply to both formats
+	replacements := []ContentReplacement{{Pattern: "summit", Replacement: "peak", Literal: true}}
+	require.NoError(t, validateContentReplacements(replacements))
+	rewrites, err := ParseHostRewrites([]string{"example.org=cdn.example.net"})
+	require.NoError(t, err)
+	siteDir = t.TempDir()
+	generator = NewGenerator(siteDir, "", nil, false, false, false, false, *websiteInfo,
+		Options{Formats: formats, NoMedia: true, ContentReplacements: replacements, HostRewrites: rewrites})
+	require.NoError(t, generator.writeContent(context.Background(), siteDir, *websiteInfo))
+	// Like in the Markdown, the content replacements leave the front matter untouched
+	_, html, _ := strings.Cut(readHTML(siteDir), "\n---\n")
+	require.Contains(t, html, `src="https://cdn.example.net/wp-content/uploads/2024/03/peak-640x480.jpg"`)
+	require.NotContains(t, html, "summit")
+	require.NotContains(t, html, "https://example.org/")
+}
+
+func TestIncrementalHTMLContentFormat(t *testing.T) {
+	t.Parallel()
+	siteDir := t.TempDir()
+	run := func(fixture integrationFixture) {
+		info := parseFixture(t, fixture)
+		generator := NewGenerator(siteDir, "", nil, false, false, false, false, *info,
+			Options{Incremental: true, Formats: []ContentFormat{ContentFormatMarkdown, ContentFormatHTML}})
+		require.NoError(t, generator.writeContent(context.Background(), siteDir, *info))
+	}
+
+	run(integrationFixture{name: "classic"})
+	teamPath := filepath.Join(siteDir, HTMLContentDir, "pages", "about", "team.html")
+	require.FileExists(t, teamPath)
+	manifest, err := os.ReadFile(filepath.Join(siteDir, _manifestFileName))
+	require.NoError(t, err)
+	require.Contains(t, string(manifest), `"`+HTMLContentDir+`/pages/about/team.html"`)
+
+	// The HTML format of the trashed page is removed along with its Markdown
+	run(integrationFixture{name: "classic", replacements: []string{
+		"<wp:post_name><![CDATA[team]]></wp:post_name>\n    <wp:status><![CDATA[publish]]></wp:status>",
+		"<wp:post_name><![CDATA[team]]></wp:post_name>\n    <wp:status><![CDATA[trash]]></wp:status>",
+	}})
+	require.NoFileExists(t, filepath.Join(siteDir, "content", "pages", "about", "team.md"))
+	require.NoFileExists(t, teamPath)
+}
+
+func TestParseContentFormats(t *testing.T) {
+	t.Parallel()
+	formats, err := ParseContentFormats([]string{""})
+	require.NoError(t, err)
+	require.Equal(t, DefaultContentFormats, formats)
+
+	formats, err = ParseContentFormats([]string{"markdown", " html", "markdown"})
+	require.NoError(t, err)
+	require.Equal(t, []ContentFormat{ContentFormatMarkdown, ContentFormatHTML}, formats)
+
+	_, err = ParseContentFormats([]string{"html"})
+	require.ErrorContains(t, err, "don't include markdown")
+
+	_, err = ParseContentFormats([]string{"pdf"})
+	require.ErrorContains(t, err, `unknown content format "pdf"`)
+}
@@ -0,0 +1,146 @@
+package hugogenerator
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/hugogenerator/hugopage"
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/rs/zerolog/log"
+)
+
+// contentIndexEntry is a row of the content index, see Options.Index
+type contentIndexEntry struct {
+	PostID      string `json:"post_id"`
+	Type        string `json:"type"`
+	Title       string `json:"title"`
+	Status      string `json:"status"`
+	OriginalURL string `json:"original_url"`
+	URL         string `json:"url"`
+	Path        string `json:"path"` // Relative to the site dir
+	Words       int    `json:"words"`
+	Media       int    `json:"media"`
+	// The old URLs redirected to the content, e.g. its GUID with the nginx config
+	Aliases []string `json:"aliases"`
+}
+
+var _contentIndexHeader = []string{"post_id", "type", "title", "status", "original_url", "url", "path", "words", "media", "aliases"}
+
+// contentIndex collects the entries of the content index, shared by the copies of the generator
+type contentIndex struct {
+	entries []contentIndexEntry
+}
+
+type pageStats struct {
+	words int
+	media int
+}
+
+// Markup which is not words: shortcodes, HTML tags, and the destinations of the links and images
+var _nonWordsRegEx = regexp.MustCompile(`{{[<%].*?[>%]}}|<[^>\n]+>|\]\([^)\n]*\)`)
+
+func getPageStats(p *hugopage.Page) pageStats {
+	words := 0
+	for _, field := range strings.Fields(_nonWordsRegEx.ReplaceAllString(p.Markdown(), " ]")) {
+		if strings.IndexFunc(field, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }) >= 0 {
+			words++
+		}
+	}
+	media := slices.Compact(slices.Sorted(slices.Values(p.WPMediaLinks())))
+	return pageStats{words: words, media: len(media)}
+}
+
+func validateIndexPath(indexPath string) error {
+	switch strings.ToLower(filepath.Ext(indexPath)) {
+	case ".csv", ".json":
+		return nil
+	default:
+		return fmt.Errorf("unknown content index format %q, expected a .csv or .json file", filepath.Ext(indexPath))
+	}
+}
+
+// addIndexEntry adds the content written at relativePath to the content index
+func (g Generator) addIndexEntry(relativePath string, stats pageStats, page wpparser.CommonFields) {
+	if g.index == nil {
+		return
+	}
+	entry := contentIndexEntry{
+		PostID:      page.PostID,
+		Title:       page.Title,
+		Status:      string(page.PublishStatus),
+		OriginalURL: page.Link,
+		Path:        relativePath,
+		Words:       stats.words,
+		Media:       stats.media,
+		Aliases:     []string{},
+	}
+	if page.PostType != nil {
+		entry.Type = *page.PostType
+	}
+	if pageURL, err := url.Parse(page.Link); err == nil {
+		entry.URL = g.options.URLPrefix + pageURL.Path
+	}
+	if g.generateNgnixConfig {
+		if oldURLPathWithQuery, _, ok := g.getGUIDRedirect(page); ok {
+			entry.Aliases = append(entry.Aliases, oldURLPathWithQuery)
+		}
+	}
+	g.index.entries = append(g.index.entries, entry)
+}
+
+// writeIndex writes the content index, ordered by path, as CSV or JSON according to the extension of Options.Index
+func (g Generator) writeIndex(siteDir string) error {
+	entries := slices.Clone(g.index.entries)
+	for i := range entries {
+		entries[i].Path = getWrittenPath(siteDir, entries[i].Path)
+	}
+	slices.SortFunc(entries, func(a, b contentIndexEntry) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	var data []byte
+	if strings.ToLower(filepath.Ext(g.options.Index)) == ".json" {
+		var err error
+		if data, err = json.MarshalIndent(entries, "", "  "); err != nil {
+			return fmt.Errorf("error marshalling content index: %w", err)
+		}
+		data = append(data, '\n')
+	} else {
+		var sb strings.Builder
+		w := csv.NewWriter(&sb)
+		if err := w.Write(_contentIndexHeader); err != nil {
+			return fmt.Errorf("error writing content index: %w", err)
+		}
+		for _, entry := range entries {
+			record := []string{
+				entry.PostID, entry.Type, entry.Title, entry.Status, entry.OriginalURL, entry.URL, entry.Path,
+				strconv.Itoa(entry.Words), strconv.Itoa(entry.Media), strings.Join(entry.Aliases, " "),
+			}
+			if err := w.Write(record); err != nil {
+				return fmt.Errorf("error writing content index: %w", err)
+			}
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return fmt.Errorf("error writing content index: %w", err)
+		}
+		data = []byte(sb.String())
+	}
+	if err := os.WriteFile(g.options.Index, data, 0o644); err != nil {
+		return fmt.Errorf("error writing content index: %w", err)
+	}
+	log.Info().
+		Str("location", g.options.Index).
+		Int("entries", len(entries)).
+		Msg("Content index written")
+	return nil
+}
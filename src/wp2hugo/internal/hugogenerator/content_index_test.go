@@ -0,0 +1,60 @@
+package hugogenerator
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestContentIndex(t *testing.T) {
+	t.Parallel()
+	websiteInfo := parseFixture(t, integrationFixture{name: "classic"})
+	generate := func(indexName string) (string, string) {
+		siteDir := t.TempDir()
+		indexPath := filepath.Join(t.TempDir(), indexName)
+		generator := NewGenerator(siteDir, "", nil, false, false, false, true, *websiteInfo, Options{Index: indexPath})
+		require.NoError(t, generator.writeContent(context.Background(), siteDir, *websiteInfo))
+		return siteDir, indexPath
+	}
+
+	_, indexPath := generate("index.csv")
+	file, err := os.Open(indexPath)
+	require.NoError(t, err)
+	defer func() {
+		_ = file.Close()
+	}()
+	records, err := csv.NewReader(file).ReadAll()
+	require.NoError(t, err)
+	require.Equal(t, _contentIndexHeader, records[0])
+	require.Len(t, records, 5)
+
+	var entries []contentIndexEntry
+	siteDir, indexPath := generate("index.json")
+	data, err := os.ReadFile(indexPath)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &entries))
+	require.Len(t, entries, 4)
+	var trip contentIndexEntry
+	for _, entry := range entries {
+		require.FileExists(t, filepath.Join(siteDir, entry.Path))
+		if entry.PostID == "10" {
+			trip = entry
+		}
+	}
+	require.Equal(t, "post", trip.Type)
+	require.Equal(t, "publish", trip.Status)
+	require.Equal(t, "https://example.org/2024/03/05/a-trip-to-the-mountains/", trip.OriginalURL)
+	require.Equal(t, "/2024/03/05/a-trip-to-the-mountains/", trip.URL)
+	require.Equal(t, "content/posts/a-trip-to-the-mountains.md", trip.Path)
+	require.Positive(t, trip.Words)
+	require.Positive(t, trip.Media)
+	require.Equal(t, []string{"/?p=10"}, trip.Aliases)
+
+	require.Error(t, validateIndexPath("index.xml"))
+	require.NoError(t, validateIndexPath("index.JSON"))
+}
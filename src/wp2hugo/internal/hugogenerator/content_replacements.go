@@ -0,0 +1,96 @@
+package hugogenerator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+)
+
+// ContentReplacement is a site-specific fixup of the converted Markdown, e.g. renaming a shortcode.
+// The Pattern is a Go regular expression, and the Replacement may reference its capture groups, e.g. $1 or ${name}.
+// Literal replaces the Pattern as is instead, and does not expand the Replacement.
+type ContentReplacement struct {
+	Pattern     string `yaml:"pattern"`
+	Replacement string `yaml:"replacement"`
+	Literal     bool   `yaml:"literal"`
+
+	regexp *regexp.Regexp
+}
+
+var errEmptyReplacementPattern = errors.New("empty pattern")
+
+// ReadContentReplacements reads a YAML list of the replacement rules, applied in order, e.g.
+// `- {pattern: '\[old_gallery (.*?)\]', replacement: '{{< gallery $1 >}}'}`.
+// The patterns are compiled right away, so that a bad one fails before the conversion.
+func ReadContentReplacements(filePath string) ([]ContentReplacement, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading content replacements: %w", err)
+	}
+	var replacements []ContentReplacement
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	// A misspelled key, e.g. "replace", would silently replace the matches with nothing
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&replacements); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("error parsing content replacements '%s': %w", filePath, err)
+	}
+	if err := validateContentReplacements(replacements); err != nil {
+		return nil, fmt.Errorf("error parsing content replacements '%s': %w", filePath, err)
+	}
+	return replacements, nil
+}
+
+// validateContentReplacements compiles the patterns which are not compiled yet,
+// e.g. of the replacements set by the library users
+func validateContentReplacements(replacements []ContentReplacement) error {
+	for i := range replacements {
+		if replacements[i].regexp != nil {
+			continue
+		}
+		if err := replacements[i].compile(); err != nil {
+			return fmt.Errorf("content replacement %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+func (r *ContentReplacement) compile() error {
+	if r.Pattern == "" {
+		return errEmptyReplacementPattern
+	}
+	pattern := r.Pattern
+	if r.Literal {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid pattern %q: %w", r.Pattern, err)
+	}
+	r.regexp = compiled
+	return nil
+}
+
+// applyContentReplacements applies the replacements to the Markdown in order, each one to the result of the previous one
+func applyContentReplacements(replacements []ContentReplacement, markdown string) string {
+	for _, replacement := range replacements {
+		if replacement.regexp == nil {
+			// Not validated, validateContentReplacements runs before the conversion
+			log.Warn().
+				Str("pattern", replacement.Pattern).
+				Msg("Skipping the uncompiled content replacement")
+			continue
+		}
+		if replacement.Literal {
+			markdown = replacement.regexp.ReplaceAllLiteralString(markdown, replacement.Replacement)
+		} else {
+			markdown = replacement.regexp.ReplaceAllString(markdown, replacement.Replacement)
+		}
+	}
+	return markdown
+}
@@ -0,0 +1,55 @@
+package hugogenerator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestContentReplacements(t *testing.T) {
+	t.Parallel()
+	rulesPath := filepath.Join(t.TempDir(), "rules.yaml")
+	require.NoError(t, os.WriteFile(rulesPath, []byte(`
+- pattern: 'hiking in the \*\*(\w+)\*\*'
+  replacement: 'climbing the ${1}'
+- pattern: 'climbing the mountains'
+  replacement: 'climbing the hills'
+- pattern: 'echo "hello"'
+  replacement: 'echo "$1"'
+  literal: true
+`), 0o644))
+	replacements, err := ReadContentReplacements(rulesPath)
+	require.NoError(t, err)
+	require.Len(t, replacements, 3)
+
+	siteDir := generateFixtureSite(t, integrationFixture{name: "classic"}, Options{ContentReplacements: replacements})
+	content, err := os.ReadFile(filepath.Join(siteDir, "content", "posts", "a-trip-to-the-mountains.md"))
+	require.NoError(t, err)
+	require.Contains(t, string(content), "We went climbing the hills and took")
+	require.Contains(t, string(content), `echo "$1"`)
+	// The front matter is left as is
+	require.Contains(t, string(content), "title: A trip to the mountains\n")
+}
+
+func TestReadContentReplacementsErrors(t *testing.T) {
+	t.Parallel()
+	readRules := func(rules string) error {
+		rulesPath := filepath.Join(t.TempDir(), "rules.yaml")
+		require.NoError(t, os.WriteFile(rulesPath, []byte(rules), 0o644))
+		_, err := ReadContentReplacements(rulesPath)
+		return err
+	}
+
+	err := readRules("- {pattern: 'ok', replacement: ''}\n- {pattern: '(unclosed', replacement: 'x'}\n")
+	require.ErrorContains(t, err, "content replacement 2: invalid pattern")
+	require.ErrorContains(t, readRules("- {pattern: 'a', replace: 'b'}\n"), "field replace not found")
+	require.ErrorIs(t, readRules("- {replacement: 'b'}\n"), errEmptyReplacementPattern)
+	require.NoError(t, readRules(""))
+
+	// Literal patterns are not regular expressions
+	replacements := []ContentReplacement{{Pattern: "(unclosed", Replacement: "$1", Literal: true}}
+	require.NoError(t, validateContentReplacements(replacements))
+	require.Equal(t, "a $1 b", applyContentReplacements(replacements, "a (unclosed b"))
+}
@@ -2,8 +2,11 @@ package hugogenerator
 
 import (
 	"errors"
+	"fmt"
 	"path"
+	"regexp"
 
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/hugogenerator/hugopage"
 	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/utils"
 	"github.com/rs/zerolog/log"
 )
@@ -121,12 +124,60 @@ const _galleryShortCode = `
 </div>
 `
 
+// Renders the inner content as-is, even when Goldmark's unsafe rendering is disabled
+const _rawHTMLShortCode = `{{- .Inner | safeHTML -}}
+`
+
+// Renders the images of the asset pipeline, with the same parameters as the figure shortcode.
+// Adjust it to process the images, e.g. with .Resize or .Fingerprint
+const _resourceShortCode = `{{- $src := .Get "src" -}}
+{{- $url := printf "/%s" $src -}}
+{{- with resources.Get $src }}{{ $url = .RelPermalink }}{{ else }}{{ warnf "%s: resource %q not found" $.Position $src }}{{ end -}}
+{{- $caption := .Get "caption" -}}
+{{- $figure := or $caption (.Get "align") -}}
+{{- if $figure }}<figure{{ with .Get "align" }} class="{{ . }}"{{ end }}>{{ end -}}
+{{- with .Get "link" }}<a href="{{ . }}">{{ end -}}
+<img loading="lazy" src="{{ $url }}" alt="{{ .Get "alt" }}"{{ with .Get "title" }} title="{{ . }}"{{ end }}{{ with .Get "width" }} width="{{ . }}"{{ end }}>
+{{- if .Get "link" }}</a>{{ end -}}
+{{- if $figure }}{{ with $caption }}<figcaption>{{ . | markdownify }}</figcaption>{{ end }}</figure>{{ end -}}
+`
+
+// Renders the [playlist] shortcodes with --playlist-shortcode=playlist, like the HTML5 playlists.
+// The tracks are the nested playlist-track shortcodes.
+const _playlistShortCode = `{{- $type := .Get "type" | default "audio" -}}
+<figure class="wp-playlist wp-{{ $type }}-playlist"><ol>
+{{- .Inner -}}
+</ol></figure>
+`
+
+const _playlistTrackShortCode = `<li>
+{{- if eq (.Parent.Get "type") "video" -}}
+<video controls preload="metadata" src="{{ .Get "src" }}"></video>
+{{- else -}}
+<audio controls preload="metadata" src="{{ .Get "src" }}"></audio>
+{{- end }} {{ .Get "title" }}</li>
+`
+
+// Hugo shortcode names, e.g. "playlist"
+var _shortCodeNameRegEx = regexp.MustCompile(`^[a-zA-Z][\w-]*$`)
+
+func validatePlaylistShortcode(name string) error {
+	if name != "" && !_shortCodeNameRegEx.MatchString(name) {
+		return fmt.Errorf("invalid playlist shortcode %q, expected a Hugo shortcode name, e.g. \"playlist\"", name)
+	}
+	return nil
+}
+
 func WriteCustomShortCodes(siteDir string) error {
 	return errors.Join(writeGoogleMapsShortCode(siteDir),
 		writeSelectedPostsShortCode(siteDir),
 		writeParallaxBlurShortCode(siteDir),
 		writeAudioShortCode(siteDir),
-		writeGalleryShortCode(siteDir))
+		writeGalleryShortCode(siteDir),
+		writeGalleryDataShortCode(siteDir),
+		writeRawHTMLShortCode(siteDir),
+		writeResourceShortCode(siteDir),
+		writePlaylistShortCodes(siteDir))
 }
 
 func writeGoogleMapsShortCode(siteDir string) error {
@@ -149,6 +200,19 @@ func writeGalleryShortCode(siteDir string) error {
 	return writeShortCode(siteDir, "gallery", _galleryShortCode)
 }
 
+func writeRawHTMLShortCode(siteDir string) error {
+	return writeShortCode(siteDir, "rawhtml", _rawHTMLShortCode)
+}
+
+func writeResourceShortCode(siteDir string) error {
+	return writeShortCode(siteDir, hugopage.ResourceShortCodeName, _resourceShortCode)
+}
+
+func writePlaylistShortCodes(siteDir string) error {
+	return errors.Join(writeShortCode(siteDir, "playlist", _playlistShortCode),
+		writeShortCode(siteDir, "playlist-track", _playlistTrackShortCode))
+}
+
 func writeShortCode(siteDir string, shortCodeName string, fileContent string) error {
 	log.Debug().
 		Str("shortcode", shortCodeName).
@@ -0,0 +1,42 @@
+package hugogenerator
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Tokens accepted by the date path option, named after Hugo's permalink tokens
+// Ref: https://gohugo.io/content-management/urls/#tokens
+var _datePathTokens = map[string]func(time.Time) string{
+	":year":      func(t time.Time) string { return t.Format("2006") },
+	":month":     func(t time.Time) string { return t.Format("01") },
+	":monthname": func(t time.Time) string { return strings.ToLower(t.Format("January")) },
+	":day":       func(t time.Time) string { return t.Format("02") },
+}
+
+var _datePathTokenRegEx = regexp.MustCompile(`:[a-z]+`)
+
+// validateDatePath verifies that a date path like ":year/:month" only uses known tokens
+func validateDatePath(pattern string) error {
+	if strings.HasPrefix(pattern, "/") || strings.Contains(pattern, "..") {
+		return fmt.Errorf("date path must be a relative path without '..': %s", pattern)
+	}
+	for _, token := range _datePathTokenRegEx.FindAllString(pattern, -1) {
+		if _, ok := _datePathTokens[token]; !ok {
+			return fmt.Errorf("unknown token %s in date path %s", token, pattern)
+		}
+	}
+	return nil
+}
+
+// getDatePath expands the tokens of the date path for the given publish date,
+// e.g. ":year/:month" -> "2024/03"
+func getDatePath(pattern string, publishDate time.Time) string {
+	expanded := _datePathTokenRegEx.ReplaceAllStringFunc(pattern, func(token string) string {
+		return _datePathTokens[token](publishDate)
+	})
+	return path.Clean(expanded)
+}
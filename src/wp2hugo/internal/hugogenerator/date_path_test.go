@@ -0,0 +1,36 @@
+package hugogenerator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetDatePath(t *testing.T) {
+	t.Parallel()
+	publishDate := time.Date(2024, time.March, 5, 10, 0, 0, 0, time.UTC)
+	require.Equal(t, "2024/03", getDatePath(":year/:month", publishDate))
+	require.Equal(t, "2024/march/05", getDatePath(":year/:monthname/:day", publishDate))
+	require.Equal(t, "archive/2024", getDatePath("archive/:year/", publishDate))
+}
+
+func TestValidateDatePath(t *testing.T) {
+	t.Parallel()
+	require.NoError(t, validateDatePath(":year/:month"))
+	require.Error(t, validateDatePath(":year/:hour"))
+	require.Error(t, validateDatePath("/:year"))
+	require.Error(t, validateDatePath("../:year"))
+}
+
+func TestDatePathOrganizesPosts(t *testing.T) {
+	t.Parallel()
+	siteDir := generateFixtureSite(t, integrationFixture{name: "classic"}, Options{DatePath: ":year/:month"})
+	_, err := os.Stat(filepath.Join(siteDir, "content", "posts", "2024", "03", "a-trip-to-the-mountains.md"))
+	require.NoError(t, err)
+	// Pages are not affected
+	_, err = os.Stat(filepath.Join(siteDir, "content", "pages", "about", "_index.md"))
+	require.NoError(t, err)
+}
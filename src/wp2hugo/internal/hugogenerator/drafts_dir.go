@@ -0,0 +1,62 @@
+package hugogenerator
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+)
+
+// DefaultDraftStatuses are the statuses of the content emitted with draft: true, routed by Options.DraftsDir
+var DefaultDraftStatuses = []wpparser.PublishStatus{wpparser.PublishStatusDraft, wpparser.PublishStatusPending}
+
+// The statuses which can be routed into the drafts dir, i.e. the content which is not published yet or not public
+var _draftsDirStatuses = []wpparser.PublishStatus{
+	wpparser.PublishStatusDraft, wpparser.PublishStatusPending, wpparser.PublishStatusFuture, wpparser.PublishStatusPrivate,
+}
+
+// ParseDraftStatuses parses the statuses routed into the drafts dir, e.g. "draft", empty for DefaultDraftStatuses
+func ParseDraftStatuses(statuses []string) ([]wpparser.PublishStatus, error) {
+	parsed := make([]wpparser.PublishStatus, 0, len(statuses))
+	for _, status := range statuses {
+		status = strings.TrimSpace(status)
+		if status == "" {
+			continue
+		}
+		if !slices.Contains(_draftsDirStatuses, wpparser.PublishStatus(status)) {
+			return nil, fmt.Errorf("unknown draft status %q, expected %s, %s, %s or %s", status,
+				wpparser.PublishStatusDraft, wpparser.PublishStatusPending, wpparser.PublishStatusFuture, wpparser.PublishStatusPrivate)
+		}
+		if !slices.Contains(parsed, wpparser.PublishStatus(status)) {
+			parsed = append(parsed, wpparser.PublishStatus(status))
+		}
+	}
+	if len(parsed) == 0 {
+		return DefaultDraftStatuses, nil
+	}
+	return parsed, nil
+}
+
+func validateDraftsDir(draftsDir string, privateContentDir string) error {
+	if draftsDir != "" && draftsDir == privateContentDir {
+		return fmt.Errorf("drafts dir %q can't be the private content dir, set only the private content dir to segregate the drafts with the private content", draftsDir)
+	}
+	return nil
+}
+
+// isDraftsDirContent reports whether the page is routed into Options.DraftsDir
+func (g Generator) isDraftsDirContent(page wpparser.CommonFields) bool {
+	return g.options.DraftsDir != "" && slices.Contains(g.options.DraftStatuses, page.PublishStatus)
+}
+
+// countDraftsDirContent records the page in the Report if it was routed into Options.DraftsDir
+func (g Generator) countDraftsDirContent(page wpparser.CommonFields) {
+	if !g.isDraftsDirContent(page) {
+		return
+	}
+	if g.report.DraftsDirContent == nil {
+		g.report.DraftsDirContent = make(map[wpparser.PublishStatus]int)
+	}
+	g.report.DraftsDirContent[page.PublishStatus]++
+}
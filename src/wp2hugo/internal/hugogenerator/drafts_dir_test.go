@@ -0,0 +1,63 @@
+package hugogenerator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDraftsDir(t *testing.T) {
+	t.Parallel()
+	websiteInfo := parseFixture(t, integrationFixture{name: "classic"})
+	siteDir := t.TempDir()
+	generator := NewGenerator(siteDir, "", nil, false, false, false, false, *websiteInfo,
+		Options{DraftsDir: "/_drafts/", PrivateContentDir: "_private"})
+	require.NoError(t, generator.writeContent(context.Background(), siteDir, *websiteInfo))
+
+	require.FileExists(t, filepath.Join(siteDir, "content", "posts", "a-trip-to-the-mountains.md"))
+	require.NoFileExists(t, filepath.Join(siteDir, "content", "posts", "unfinished-thoughts.md"))
+	// The drafts dir takes precedence over the private content dir
+	require.NoFileExists(t, filepath.Join(siteDir, "content", "_private", "posts", "unfinished-thoughts.md"))
+	data, err := os.ReadFile(filepath.Join(siteDir, "content", "_drafts", "posts", "unfinished-thoughts.md"))
+	require.NoError(t, err)
+	require.Contains(t, string(data), "\ndraft: \"true\"\n")
+	require.Equal(t, map[wpparser.PublishStatus]int{wpparser.PublishStatusDraft: 1}, generator.Report().DraftsDirContent)
+}
+
+func TestDraftsDirStatuses(t *testing.T) {
+	t.Parallel()
+	websiteInfo := parseFixture(t, integrationFixture{name: "classic"})
+	siteDir := t.TempDir()
+	generator := NewGenerator(siteDir, "", nil, false, false, false, false, *websiteInfo,
+		Options{DraftsDir: "_drafts", DraftStatuses: []wpparser.PublishStatus{wpparser.PublishStatusPending}})
+	require.NoError(t, generator.writeContent(context.Background(), siteDir, *websiteInfo))
+
+	// Only the pending content is routed
+	require.FileExists(t, filepath.Join(siteDir, "content", "posts", "unfinished-thoughts.md"))
+	require.Empty(t, generator.Report().DraftsDirContent)
+}
+
+func TestParseDraftStatuses(t *testing.T) {
+	t.Parallel()
+	statuses, err := ParseDraftStatuses([]string{""})
+	require.NoError(t, err)
+	require.Equal(t, DefaultDraftStatuses, statuses)
+
+	statuses, err = ParseDraftStatuses([]string{"draft", " private", "draft"})
+	require.NoError(t, err)
+	require.Equal(t, []wpparser.PublishStatus{wpparser.PublishStatusDraft, wpparser.PublishStatusPrivate}, statuses)
+
+	_, err = ParseDraftStatuses([]string{"publish"})
+	require.ErrorContains(t, err, `unknown draft status "publish"`)
+}
+
+func TestDraftsDirIsNotPrivateContentDir(t *testing.T) {
+	t.Parallel()
+	generator := NewGenerator(t.TempDir(), "", nil, false, false, false, false, *parseFixture(t, integrationFixture{name: "classic"}),
+		Options{DraftsDir: "_private", PrivateContentDir: "/_private"})
+	require.ErrorContains(t, generator.Generate(context.Background()), "can't be the private content dir")
+}
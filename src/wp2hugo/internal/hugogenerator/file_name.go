@@ -0,0 +1,35 @@
+package hugogenerator
+
+import (
+	"fmt"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+)
+
+// Most filesystems limit a path component to 255 bytes, this leaves room for the
+// language, the deduplication counter and the extension
+const _defaultMaxFileNameLength = 200
+
+// The truncated filenames keep a hash suffix, e.g. "-1a2b3c4d"
+const _minMaxFileNameLength = 16
+
+func validateMaxFileNameLength(maxLength int) error {
+	if maxLength < _minMaxFileNameLength {
+		return fmt.Errorf("max filename length %d is too short, it should be at least %d", maxLength, _minMaxFileNameLength)
+	}
+	return nil
+}
+
+// getSlug returns the WordPress slug of the content, its post_name, or else the slug derived from its link or title,
+// e.g. for the drafts which were never published
+func getSlug(page wpparser.CommonFields) string {
+	if page.Slug != "" {
+		return page.Slug
+	}
+	return page.GetFileInfo().FileNameNoLanguage()
+}
+
+// getFileInfo returns the file info of the content, truncated to Options.MaxFileNameLength
+func (g Generator) getFileInfo(page wpparser.CommonFields) wpparser.FileInfo {
+	return page.GetFileInfo().Truncate(g.options.MaxFileNameLength)
+}
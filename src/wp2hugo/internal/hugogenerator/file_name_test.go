@@ -0,0 +1,44 @@
+package hugogenerator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaxFileNameLength(t *testing.T) {
+	t.Parallel()
+	siteDir := generateFixtureSite(t, integrationFixture{name: "classic"}, Options{MaxFileNameLength: 16})
+
+	matches, err := filepath.Glob(filepath.Join(siteDir, "content", "posts", "a-trip-*.md"))
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	require.Regexp(t, `/a-trip-[0-9a-f]{8}\.md$`, matches[0])
+	content, err := os.ReadFile(matches[0])
+	require.NoError(t, err)
+	require.Contains(t, string(content), "\nslug: a-trip-to-the-mountains\n")
+
+	// Short filenames are kept as is, without a slug
+	content, err = os.ReadFile(filepath.Join(siteDir, "content", "pages", "about", "_index.md"))
+	require.NoError(t, err)
+	require.NotContains(t, string(content), "slug:")
+
+	require.Error(t, validateMaxFileNameLength(8))
+	require.NoError(t, validateMaxFileNameLength(_defaultMaxFileNameLength))
+}
+
+func TestAlwaysEmitSlug(t *testing.T) {
+	t.Parallel()
+	siteDir := generateFixtureSite(t, integrationFixture{name: "classic"}, Options{AlwaysEmitSlug: true})
+
+	content, err := os.ReadFile(filepath.Join(siteDir, "content", "pages", "about", "_index.md"))
+	require.NoError(t, err)
+	require.Contains(t, string(content), "\nslug: about\n")
+
+	// The draft which was never published has no post_name, its slug is derived from its title
+	content, err = os.ReadFile(filepath.Join(siteDir, "content", "posts", "unfinished-thoughts.md"))
+	require.NoError(t, err)
+	require.Contains(t, string(content), "\nslug: unfinished-thoughts\n")
+}
@@ -0,0 +1,49 @@
+package hugogenerator
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrontMatterHook(t *testing.T) {
+	t.Parallel()
+	info := parseFixture(t, integrationFixture{name: "classic"})
+	siteDir := t.TempDir()
+	generator := NewGenerator(siteDir, "", nil, false, false, false, false, *info, Options{
+		FrontMatterHook: func(post *wpparser.PostInfo, frontMatter map[string]any) error {
+			frontMatter["wordpress_link"] = post.Link
+			frontMatter["writer"] = frontMatter["author"]
+			delete(frontMatter, "author")
+			delete(frontMatter, "guid")
+			return nil
+		},
+	})
+	require.NoError(t, generator.writeContent(context.Background(), siteDir, *info))
+	content, err := os.ReadFile(filepath.Join(siteDir, "content", "posts", "a-trip-to-the-mountains.md"))
+	require.NoError(t, err)
+	require.Contains(t, string(content), "wordpress_link: https://example.org/2024/03/05/a-trip-to-the-mountains/\n")
+	require.Contains(t, string(content), "writer: jdoe\n")
+	require.NotContains(t, string(content), "author:")
+	require.NotContains(t, string(content), "guid:")
+
+	failingHook := func(_ *wpparser.PostInfo, _ map[string]any) error {
+		return errors.New("missing field")
+	}
+	generator = NewGenerator(siteDir, "", nil, false, false, false, false, *info, Options{
+		FrontMatterHook: failingHook,
+		FailFast:        true,
+	})
+	require.ErrorContains(t, generator.writeContent(context.Background(), t.TempDir(), *info), "missing field")
+
+	// The pages are skipped otherwise
+	generator = NewGenerator(siteDir, "", nil, false, false, false, false, *info, Options{FrontMatterHook: failingHook})
+	require.NoError(t, generator.writeContent(context.Background(), t.TempDir(), *info))
+	require.NotEmpty(t, generator.Report().SkippedItems)
+	require.Contains(t, generator.Report().SkippedItems[0].Error, "missing field")
+}
@@ -0,0 +1,60 @@
+package hugogenerator
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/hugogenerator/hugopage"
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/utils"
+)
+
+// Renders the galleries emitted as structured data, from the galleries front matter or the data file of
+// the galleries_data front matter, like the gallery shortcode renders the inline ones
+const _galleryDataShortCode = `{{- $galleries := .Page.Params.galleries -}}
+{{- with .Page.Params.galleries_data }}{{ $galleries = index site.Data.galleries . }}{{ end -}}
+{{- with index $galleries (int (.Get "index")) -}}
+<div class="gallery gallery-cols-{{ .columns | default 1 }}">
+{{- range .images }}
+<figure><img loading="lazy" src="{{ .src }}" alt="{{ .alt }}"{{ with .title }} title="{{ . }}"{{ end }}>
+{{- with .caption }}<figcaption>{{ . | markdownify }}</figcaption>{{ end }}</figure>
+{{- end }}
+</div>
+{{- end -}}
+`
+
+func writeGalleryDataShortCode(siteDir string) error {
+	return writeShortCode(siteDir, hugopage.GalleryDataShortCodeName, _galleryDataShortCode)
+}
+
+// getGalleriesDataKey returns the key of the data file of the galleries of the page, its post ID,
+// suffixed with the page number for the pages of the content split at its <!--nextpage--> tags
+func getGalleriesDataKey(part pagePart) string {
+	if part.number > 1 {
+		return part.page.PostID + "-" + strconv.Itoa(part.number)
+	}
+	return part.page.PostID
+}
+
+// getGalleriesDataPath returns the path of the data file of the galleries of the page, see writeGalleriesData
+func getGalleriesDataPath(siteDir string, part pagePart) string {
+	return path.Join(siteDir, "data", "galleries", getGalleriesDataKey(part)+".yaml")
+}
+
+// writeGalleriesData writes the galleries of the page into data/galleries/<key>.yaml with hugopage.GalleryOutputData,
+// once their media are downloaded
+func (g Generator) writeGalleriesData(siteDir string, part pagePart, p *hugopage.Page) error {
+	if g.options.GalleryOutput != hugopage.GalleryOutputData || len(p.Galleries()) == 0 {
+		return nil
+	}
+	dataPath := getGalleriesDataPath(siteDir, part)
+	if err := utils.CreateDirIfNotExist(path.Dir(dataPath)); err != nil {
+		return err
+	}
+	data, err := utils.GetYAML(p.Galleries())
+	if err != nil {
+		return fmt.Errorf("error marshalling the galleries of %s: %w", part.page.Link, err)
+	}
+	p.SetGalleriesDataKey(getGalleriesDataKey(part))
+	return writeFile(dataPath, data)
+}
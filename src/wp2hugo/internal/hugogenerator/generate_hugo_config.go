@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"slices"
 	"strings"
 	"time"
 
@@ -23,10 +24,10 @@ type _HugoNavMenu struct {
 }
 
 type _HugoAttachment struct {
-	Path  string    `yaml:"path"`
-	Title string    `yaml:"title"`
-	ID    string    `yaml:"id"`
-	Date  time.Time `yaml:"published"`
+	Path  string     `yaml:"path"`
+	Title string     `yaml:"title"`
+	ID    string     `yaml:"id"`
+	Date  *time.Time `yaml:"published,omitempty"`
 }
 
 type _HugoConfig struct {
@@ -34,10 +35,19 @@ type _HugoConfig struct {
 	LanguageCode string `yaml:"languageCode"`
 	Title        string `yaml:"title"`
 	Theme        string `yaml:"theme"`
-	Taxonomies   struct {
+	// Only set when the media are downloaded into another dir than "assets", see Options.AssetsDir
+	AssetDir   string `yaml:"assetDir,omitempty"`
+	Taxonomies struct {
 		Category string `yaml:"category"`
 		Tag      string `yaml:"tag"`
+		// Only set when the export has a series taxonomy, see PageOptions.SeriesTaxonomy
+		Series string `yaml:"series,omitempty"`
 	}
+	// Only set for the taxonomies with another URL path than their name, see Options.TaxonomyPaths
+	Permalinks struct {
+		Taxonomy map[string]string `yaml:"taxonomy,omitempty"`
+		Term     map[string]string `yaml:"term,omitempty"`
+	} `yaml:"permalinks,omitempty"`
 	// These will be used for OpenGraph information
 	Params struct {
 		Description         string `yaml:"description"`
@@ -71,6 +81,12 @@ type _HugoConfig struct {
 			Renderer struct {
 				Unsafe bool `yaml:"unsafe"`
 			} `yaml:"renderer"`
+			// Hugo's embedded image render hook resolves the Markdown images with resources.Get
+			RenderHooks struct {
+				Image struct {
+					EnableDefault bool `yaml:"enableDefault,omitempty"`
+				} `yaml:"image,omitempty"`
+			} `yaml:"renderHooks,omitempty"`
 		} `yaml:"goldmark"`
 	}
 	Outputs struct {
@@ -113,7 +129,7 @@ func setupLibraryData(siteDir string, info wpparser.WebsiteInfo) error {
 			Path:  hugopage.ReplaceAbsoluteLinksWithRelative(info.Link().Host, *attachment.GetAttachmentURL()),
 			ID:    attachment.PostID,
 			Title: attachment.Title,
-			Date:  *attachment.PublishDate,
+			Date:  attachment.PublishDate,
 		})
 	}
 
@@ -126,7 +142,76 @@ func setupLibraryData(siteDir string, info wpparser.WebsiteInfo) error {
 	return writeFile(dataPath, data)
 }
 
-func updateConfig(siteDir string, info wpparser.WebsiteInfo) error {
+// setupAuthorsData writes the WordPress users into data/authors.yaml, keyed by their slug
+// so that themes can render author details from the `author` front matter.
+// The authors consolidated into another one by authorMap are left out, the renamed ones are keyed by their new name.
+// The users who authored none of the content, once mapped, are left out too unless allAuthors is set.
+func setupAuthorsData(siteDir string, info wpparser.WebsiteInfo, authorMap map[string]string, allAuthors bool) error {
+	if len(info.Authors()) == 0 {
+		log.Debug().Msg("No authors in the export, skipping authors data")
+		return nil
+	}
+
+	dataPath := path.Join(siteDir, "data", "authors.yaml")
+	if err := os.MkdirAll(path.Dir(dataPath), 0o755); err != nil {
+		return fmt.Errorf("error creating directory: %w", err)
+	}
+
+	contentAuthors := getContentAuthors(info, authorMap)
+	authors := make(map[string]wpparser.AuthorInfo, len(info.Authors()))
+	for _, author := range info.Authors() {
+		if !allAuthors && !contentAuthors[author.Login] {
+			log.Debug().
+				Str("author", author.Login).
+				Msg("Author without content, skipping it in the authors data")
+			continue
+		}
+		key := author.Slug
+		if mapped, ok := getMappedAuthor(info, authorMap, author.Login); ok {
+			if mapped.author != nil && mapped.author.Login != author.Login {
+				continue
+			}
+			if mapped.author == nil {
+				key = mapped.name
+			}
+		}
+		if _, ok := authors[key]; !ok {
+			authors[key] = author
+		}
+	}
+	data, err := utils.GetYAML(authors)
+	if err != nil {
+		return fmt.Errorf("error marshalling authors: %w", err)
+	}
+
+	log.Info().Msgf("Updating authors data file: %s", dataPath)
+	return writeFile(dataPath, data)
+}
+
+// getContentAuthors returns the logins of the authors of the posts, pages and custom posts,
+// once consolidated with authorMap, the renamed authors keep their login
+func getContentAuthors(info wpparser.WebsiteInfo, authorMap map[string]string) map[string]bool {
+	contentAuthors := make(map[string]bool)
+	addAuthor := func(login string) {
+		if mapped, ok := getMappedAuthor(info, authorMap, login); ok && mapped.author != nil {
+			login = mapped.author.Login
+		}
+		contentAuthors[login] = true
+	}
+	for _, post := range info.Posts() {
+		addAuthor(post.Author)
+	}
+	for _, page := range info.Pages() {
+		addAuthor(page.Author)
+	}
+	for _, customPost := range info.CustomPosts() {
+		addAuthor(customPost.Author)
+	}
+	return contentAuthors
+}
+
+// updateConfig fills the hugo.yaml of the site, params are set on top of the config, see setConfigParams
+func updateConfig(siteDir string, info wpparser.WebsiteInfo, options Options, params map[string]string) error {
 	configPath := path.Join(siteDir, "hugo.yaml")
 	r, err := os.OpenFile(configPath, os.O_RDONLY, 0o644)
 	if err != nil {
@@ -145,10 +230,16 @@ func updateConfig(siteDir string, info wpparser.WebsiteInfo) error {
 	}
 	// Ref: https://adityatelange.github.io/hugo-PaperMod/posts/papermod/papermod-faq/
 	config.Title = info.Title()
-	config.BaseURL = info.Link().String()
+	// The URLs of the content keep the path of the blog, e.g. /blog/2024/03/05/slug/ when WordPress
+	// lives in a subdirectory, so the site is at the root of the host
+	config.BaseURL = info.Link().Scheme + "://" + info.Link().Host
 	config.LanguageCode = info.Language()
-	config.Taxonomies.Category = hugopage.CategoryName
-	config.Taxonomies.Tag = hugopage.TagName
+	config.Taxonomies.Category = options.TaxonomyKey(hugopage.CategoryName)
+	config.Taxonomies.Tag = options.TaxonomyKey(hugopage.TagName)
+	if options.SeriesTaxonomy != "" && slices.Contains(info.TaxonomyNames(), options.SeriesTaxonomy) {
+		config.Taxonomies.Series = "series"
+	}
+	config.Permalinks.Taxonomy, config.Permalinks.Term = getTaxonomyPermalinks(options)
 	config.Params.Description = info.Description
 	config.Params.Assets.Favicon = "/favicon.ico"
 	config.Params.Assets.DisableHLJS = true
@@ -167,6 +258,12 @@ func updateConfig(siteDir string, info wpparser.WebsiteInfo) error {
 	config.Markup.Highlight.GuessSyntax = true
 	config.Markup.Highlight.Style = "monokai"
 	config.Markup.Goldmark.Renderer.Unsafe = true
+	if options.AssetsDir != "" {
+		if options.AssetsDir != "assets" {
+			config.AssetDir = options.AssetsDir
+		}
+		config.Markup.Goldmark.RenderHooks.Image.EnableDefault = options.AssetReferences == AssetReferencesPath
+	}
 	// https://adityatelange.github.io/hugo-PaperMod/posts/papermod/papermod-features/#search-page
 	config.Outputs.Home = []string{"HTML", "RSS", "JSON"}
 	config.OutputFormats.RSS.MediaType = "application/rss+xml"
@@ -178,7 +275,11 @@ func updateConfig(siteDir string, info wpparser.WebsiteInfo) error {
 	if err := r.Close(); err != nil {
 		return fmt.Errorf("error closing config file: %w", err)
 	}
-	data, err := utils.GetYAML(config)
+	node, err := setConfigParams(&config, params)
+	if err != nil {
+		return err
+	}
+	data, err := utils.GetYAML(node)
 	if err != nil {
 		return fmt.Errorf("error marshalling config: %w", err)
 	}
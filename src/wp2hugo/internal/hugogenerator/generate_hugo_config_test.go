@@ -0,0 +1,56 @@
+package hugogenerator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetupAuthorsData(t *testing.T) {
+	t.Parallel()
+
+	websiteInfo := parseFixture(t, integrationFixture{name: "classic"})
+
+	siteDir := t.TempDir()
+	require.NoError(t, setupAuthorsData(siteDir, *websiteInfo, nil, false))
+	data, err := os.ReadFile(filepath.Join(siteDir, "data", "authors.yaml"))
+	require.NoError(t, err)
+	require.Contains(t, string(data), "jdoe:\n")
+	require.Contains(t, string(data), "email: jdoe@example.org")
+	require.Contains(t, string(data), "first_name: John")
+}
+
+func TestSetupAuthorsDataWithoutContent(t *testing.T) {
+	t.Parallel()
+
+	jdoe := "<wp:author><wp:author_id>1</wp:author_id>"
+	editor := "<wp:author><wp:author_id>2</wp:author_id><wp:author_login><![CDATA[editor]]></wp:author_login>" +
+		"<wp:author_email><![CDATA[editor@example.org]]></wp:author_email><wp:author_display_name><![CDATA[Ed Itor]]></wp:author_display_name>" +
+		"<wp:author_first_name><![CDATA[Ed]]></wp:author_first_name><wp:author_last_name><![CDATA[Itor]]></wp:author_last_name></wp:author>\n"
+	websiteInfo := parseFixture(t, integrationFixture{name: "classic", replacements: []string{jdoe, editor + jdoe}})
+	require.Equal(t, wpparser.AuthorInfo{
+		ID: "2", Login: "editor", Email: "editor@example.org", DisplayName: "Ed Itor", FirstName: "Ed", LastName: "Itor", Slug: "ed-itor",
+	}, websiteInfo.Authors()[0])
+
+	siteDir := t.TempDir()
+	require.NoError(t, setupAuthorsData(siteDir, *websiteInfo, nil, false))
+	authors, err := os.ReadFile(filepath.Join(siteDir, "data", "authors.yaml"))
+	require.NoError(t, err)
+	require.Contains(t, string(authors), "jdoe:\n")
+	require.NotContains(t, string(authors), "editor")
+
+	require.NoError(t, setupAuthorsData(siteDir, *websiteInfo, nil, true))
+	authors, err = os.ReadFile(filepath.Join(siteDir, "data", "authors.yaml"))
+	require.NoError(t, err)
+	require.Contains(t, string(authors), "ed-itor:\n")
+
+	// The content consolidated into the editor is theirs
+	require.NoError(t, setupAuthorsData(siteDir, *websiteInfo, map[string]string{"jdoe": "editor"}, false))
+	authors, err = os.ReadFile(filepath.Join(siteDir, "data", "authors.yaml"))
+	require.NoError(t, err)
+	require.Contains(t, string(authors), "ed-itor:\n")
+	require.NotContains(t, string(authors), "jdoe:\n")
+}
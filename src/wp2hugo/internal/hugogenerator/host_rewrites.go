@@ -0,0 +1,184 @@
+package hugogenerator
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/hugogenerator/hugopage"
+)
+
+// HostRewrite swaps the host of the URLs of the content, e.g. of the media moved to a CDN or a new domain,
+// without downloading them, see Options.HostRewrites
+type HostRewrite struct {
+	From string
+	To   string
+
+	regexp *regexp.Regexp
+}
+
+var errInvalidHostRewrite = errors.New("invalid host rewrite, expected old=new, e.g. example.org=cdn.example.net")
+
+// ParseHostRewrites parses the host rewrites, e.g. "example.org=cdn.example.net", applied in order
+func ParseHostRewrites(rules []string) ([]HostRewrite, error) {
+	rewrites := make([]HostRewrite, 0, len(rules))
+	for _, rule := range rules {
+		from, to, found := strings.Cut(rule, "=")
+		if !found {
+			return nil, fmt.Errorf("%w: %q", errInvalidHostRewrite, rule)
+		}
+		rewrites = append(rewrites, HostRewrite{From: strings.TrimSpace(from), To: strings.TrimSpace(to)})
+	}
+	if err := validateHostRewrites(rewrites); err != nil {
+		return nil, err
+	}
+	return rewrites, nil
+}
+
+// validateHostRewrites checks that both hosts of the rewrites parse, and compiles the ones which are not compiled yet,
+// e.g. of the rewrites set by the library users
+func validateHostRewrites(rewrites []HostRewrite) error {
+	for i := range rewrites {
+		if rewrites[i].regexp != nil {
+			continue
+		}
+		if err := validateHost(rewrites[i].From); err != nil {
+			return fmt.Errorf("%w: %q: %w", errInvalidHostRewrite, rewrites[i].String(), err)
+		}
+		if err := validateHost(rewrites[i].To); err != nil {
+			return fmt.Errorf("%w: %q: %w", errInvalidHostRewrite, rewrites[i].String(), err)
+		}
+		// The host of the absolute and protocol-relative URLs, up to the end of the host,
+		// so that example.org does not match example.org.uk
+		rewrites[i].regexp = regexp.MustCompile(`(?i)((?:https?:)?//)` + regexp.QuoteMeta(rewrites[i].From) +
+			`([/?#"'\s)\]<>]|$)`)
+	}
+	return nil
+}
+
+// validateHost checks that the host, with an optional port, is the whole URL it parses into
+func validateHost(host string) error {
+	if host == "" {
+		return errors.New("empty host")
+	}
+	u, err := url.Parse("//" + host)
+	if err != nil {
+		return err
+	}
+	if u.Host != host || u.Hostname() == "" {
+		return fmt.Errorf("%q is not a host", host)
+	}
+	return nil
+}
+
+func (r HostRewrite) String() string {
+	return r.From + "=" + r.To
+}
+
+// rewrite swaps the host of the URLs of the text, and returns the number of URLs rewritten
+func (r HostRewrite) rewrite(text string) (string, int) {
+	count := 0
+	text = r.regexp.ReplaceAllStringFunc(text, func(match string) string {
+		count++
+		return r.regexp.ReplaceAllString(match, "${1}"+r.To+"${2}")
+	})
+	return text, count
+}
+
+// applyHostRewrites rewrites the hosts of the URLs of the Markdown, of the HTML format, of the front matter,
+// e.g. the cover image, and of the galleries, and counts the URLs rewritten per rule in the Report.
+// The code samples are left as is, e.g. a snippet querying the API of the old host.
+func (g Generator) applyHostRewrites(p *hugopage.Page) {
+	for _, rewrite := range g.options.HostRewrites {
+		if rewrite.regexp == nil {
+			// Not validated, validateHostRewrites runs before the conversion
+			continue
+		}
+		count := 0
+		p.ReplaceMarkdownOutsideCode(func(markdown string) string {
+			markdown, count = rewrite.rewrite(markdown)
+			return markdown
+		})
+		p.ReplaceHTMLOutsideCode(func(htmlContent string) string {
+			htmlContent, htmlCount := rewrite.rewrite(htmlContent)
+			count += htmlCount
+			return htmlContent
+		})
+		_ = p.ReplaceMetadata(func(metadata map[string]any) error {
+			for key, value := range metadata {
+				var valueCount int
+				metadata[key], valueCount = rewriteMetadataHosts(rewrite, value)
+				count += valueCount
+			}
+			return nil
+		})
+		if g.options.GalleryOutput == hugopage.GalleryOutputData {
+			// Written into data/galleries, with GalleryOutputFrontMatter they are in the front matter
+			count += rewriteGalleriesHosts(rewrite, p.Galleries())
+		}
+		g.report.RewrittenHosts[rewrite.String()] += count
+	}
+}
+
+func rewriteMetadataHosts(rewrite HostRewrite, value any) (any, int) {
+	count := 0
+	switch value := value.(type) {
+	case string:
+		return rewrite.rewrite(value)
+	case []string:
+		for i := range value {
+			var itemCount int
+			value[i], itemCount = rewrite.rewrite(value[i])
+			count += itemCount
+		}
+	case []any:
+		for i := range value {
+			var itemCount int
+			value[i], itemCount = rewriteMetadataHosts(rewrite, value[i])
+			count += itemCount
+		}
+	case map[string]string:
+		for key := range value {
+			var itemCount int
+			value[key], itemCount = rewrite.rewrite(value[key])
+			count += itemCount
+		}
+	case map[string]any:
+		for key := range value {
+			var itemCount int
+			value[key], itemCount = rewriteMetadataHosts(rewrite, value[key])
+			count += itemCount
+		}
+	case []hugopage.Gallery:
+		count = rewriteGalleriesHosts(rewrite, value)
+	}
+	return value, count
+}
+
+// rewriteGalleriesHosts rewrites the hosts of the images of the galleries in place, and of the links of their captions
+func rewriteGalleriesHosts(rewrite HostRewrite, galleries []hugopage.Gallery) int {
+	count := 0
+	for _, gallery := range galleries {
+		for i := range gallery.Images {
+			var srcCount, captionCount int
+			gallery.Images[i].Src, srcCount = rewrite.rewrite(gallery.Images[i].Src)
+			gallery.Images[i].Caption, captionCount = rewrite.rewrite(gallery.Images[i].Caption)
+			count += srcCount + captionCount
+		}
+	}
+	return count
+}
+
+// getRewrittenHosts lists the rules of the host rewrites in the Report, the ones which match nothing included
+func getRewrittenHosts(rewrites []HostRewrite) map[string]int {
+	if len(rewrites) == 0 {
+		return nil
+	}
+	rewritten := make(map[string]int, len(rewrites))
+	for _, rewrite := range rewrites {
+		rewritten[rewrite.String()] = 0
+	}
+	return rewritten
+}
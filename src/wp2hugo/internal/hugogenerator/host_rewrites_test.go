@@ -0,0 +1,87 @@
+package hugogenerator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/hugogenerator/hugopage"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHostRewrites(t *testing.T) {
+	t.Parallel()
+	rewrites, err := ParseHostRewrites([]string{"example.org=cdn.example.net", " old.example.org:8080 = new.example.org "})
+	require.NoError(t, err)
+	require.Len(t, rewrites, 2)
+	require.Equal(t, "old.example.org:8080=new.example.org", rewrites[1].String())
+
+	for _, rule := range []string{"example.org", "=cdn.example.net", "example.org=", "example.org/blog=cdn.example.net",
+		"https://example.org=cdn.example.net", "example.org=cdn example.net"} {
+		_, err := ParseHostRewrites([]string{rule})
+		require.ErrorIs(t, err, errInvalidHostRewrite, rule)
+	}
+}
+
+func TestHostRewrite(t *testing.T) {
+	t.Parallel()
+	rewrites, err := ParseHostRewrites([]string{"example.org=cdn.example.net"})
+	require.NoError(t, err)
+	text, count := rewrites[0].rewrite(`![Summit](https://example.org/a.jpg) <img src="//EXAMPLE.org/b.jpg"> ` +
+		`[home](http://example.org) https://example.org.uk/c.jpg https://www.example.org/d.jpg example.org`)
+	require.Equal(t, `![Summit](https://cdn.example.net/a.jpg) <img src="//cdn.example.net/b.jpg"> `+
+		`[home](http://cdn.example.net) https://example.org.uk/c.jpg https://www.example.org/d.jpg example.org`, text)
+	require.Equal(t, 3, count)
+}
+
+func TestHostRewritesContent(t *testing.T) {
+	t.Parallel()
+	websiteInfo := parseFixture(t, integrationFixture{name: "classic"})
+	rewrites, err := ParseHostRewrites([]string{"example.org=cdn.example.net", "unused.example.org=cdn.example.net"})
+	require.NoError(t, err)
+	siteDir := t.TempDir()
+	generator := NewGenerator(siteDir, "", nil, false, false, false, false, *websiteInfo,
+		Options{NoMedia: true, HostRewrites: rewrites})
+	require.NoError(t, generator.writeContent(context.Background(), siteDir, *websiteInfo))
+
+	content, err := os.ReadFile(filepath.Join(siteDir, "content", "posts", "a-trip-to-the-mountains.md"))
+	require.NoError(t, err)
+	require.Contains(t, string(content), "  image: https://cdn.example.net/wp-content/uploads/2024/03/summit.jpg\n")
+	require.Contains(t, string(content), `src="https://cdn.example.net/wp-content/uploads/2024/03/summit-640x480.jpg"`)
+	require.NotContains(t, string(content), "https://example.org/")
+	require.Positive(t, generator.Report().RewrittenHosts["example.org=cdn.example.net"])
+	require.Contains(t, generator.Report().RewrittenHosts, "unused.example.org=cdn.example.net")
+	require.Zero(t, generator.Report().RewrittenHosts["unused.example.org=cdn.example.net"])
+}
+
+func TestHostRewritesCodeAndGalleries(t *testing.T) {
+	t.Parallel()
+	websiteInfo := parseFixture(t, integrationFixture{name: "classic", replacements: []string{
+		`echo "hello"`, `curl https://example.org/wp-json/wp/v2/posts`,
+	}})
+	rewrites, err := ParseHostRewrites([]string{"example.org=cdn.example.net"})
+	require.NoError(t, err)
+	for _, galleryOutput := range []hugopage.GalleryOutput{hugopage.GalleryOutputFrontMatter, hugopage.GalleryOutputData} {
+		t.Run(string(galleryOutput), func(t *testing.T) {
+			t.Parallel()
+			siteDir := t.TempDir()
+			generator := NewGenerator(siteDir, "", nil, false, false, false, false, *websiteInfo,
+				Options{NoMedia: true, HostRewrites: rewrites, PageOptions: hugopage.PageOptions{GalleryOutput: galleryOutput}})
+			require.NoError(t, generator.writeContent(context.Background(), siteDir, *websiteInfo))
+
+			content, err := os.ReadFile(filepath.Join(siteDir, "content", "posts", "a-trip-to-the-mountains.md"))
+			require.NoError(t, err)
+			// The code sample is left as is
+			require.Contains(t, string(content), "curl https://example.org/wp-json/wp/v2/posts")
+			galleries := string(content)
+			if galleryOutput == hugopage.GalleryOutputData {
+				data, err := os.ReadFile(filepath.Join(siteDir, "data", "galleries", "10.yaml"))
+				require.NoError(t, err)
+				galleries = string(data)
+			}
+			require.Contains(t, galleries, "src: https://cdn.example.net/wp-content/uploads/2024/03/summit.jpg\n")
+			require.NotContains(t, galleries, "src: https://example.org/")
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package hugogenerator
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
@@ -12,6 +13,7 @@ import (
 	"path"
 	"regexp"
 	"runtime"
+	"slices"
 	"strings"
 	"time"
 
@@ -44,7 +46,7 @@ placeholder: "placeholder text in search input box"
 `
 
 // Find image media thumbnails resized by WP, like `some-file-1920x1080.jpg`
-var _resizedMedia = regexp.MustCompile(`(.*)-\d+x\d+\.(jpg|jpeg|png|webp|gif)`)
+var _resizedMedia = regexp.MustCompile(`(.*)-\d+x\d+\.(jpg|jpeg|png|webp|avif|gif)`)
 
 type Generator struct {
 	fontName         string
@@ -61,6 +63,211 @@ type Generator struct {
 	// Nginx related
 	generateNgnixConfig bool
 	ngnixConfig         *nginxgenerator.Config
+
+	options Options
+
+	// Publish dates ordered by post ID, for MissingDatePostID
+	postIDDates []postIDDate
+
+	// Terms of the categories and the tags renamed, see Options.TermCollisions
+	termRenames termRenames
+
+	// URLs of the migrated content and the attachments by post ID, for the shortlinks
+	postLinks postLinks
+
+	// Shared by the copies of the generator, since it uses value receivers
+	report      *Report
+	incremental *incrementalRun
+	index       *contentIndex
+}
+
+// Options holds the optional behaviors of the generator
+type Options struct {
+	hugopage.PageOptions
+
+	// KeepInlineImages leaves base64-embedded `data:` images in the content,
+	// instead of writing them out as files when downloading media
+	KeepInlineImages bool
+
+	// DatePath organizes posts in sub-directories derived from their publish date,
+	// e.g. ":year/:month" writes content/posts/2024/03/slug.md
+	DatePath string
+
+	// DateSource decides whether the GMT or the local publish date drives the date front matter and the DatePath
+	DateSource DateSource
+
+	// MissingDatePolicy decides which date to emit for content without a publish date
+	MissingDatePolicy MissingDatePolicy
+
+	// ConvertImagesToWebP converts the downloaded JPEG and PNG images to WebP at WebPQuality
+	// and rewrites their links. It requires a build with `-tags webp`.
+	// The original images are removed unless KeepOriginalImages is set.
+	ConvertImagesToWebP bool
+	WebPQuality         int
+	KeepOriginalImages  bool
+
+	// PrivateContentDir routes the private, password-protected, draft and pending content
+	// into content/<PrivateContentDir>/ instead of mixing it with the published content
+	PrivateContentDir string
+
+	// DraftsDir routes the content of the DraftStatuses, by default the draft and pending content,
+	// into content/<DraftsDir>/ for a separate review workflow, it keeps its draft: true front matter.
+	// It takes precedence over PrivateContentDir, e.g. for the private drafts.
+	DraftsDir     string
+	DraftStatuses []wpparser.PublishStatus
+
+	// Formats are the formats the content is emitted in, DefaultContentFormats if empty.
+	// With ContentFormatHTML, the HTML content as exported is written into the HTMLContentDir tree
+	// too, with the same front matter and media as the Markdown.
+	Formats []ContentFormat
+
+	// AuthorLogins emits the WordPress login as the `author` front matter, like the previous versions,
+	// instead of the author slug, which keys data/authors.yaml
+	AuthorLogins bool
+
+	// AuthorMap maps the login or the display name of the authors to their target author, e.g. {"former-intern": "jdoe"},
+	// in the `author` front matter and data/authors.yaml. A target which is an author of the export, by its login,
+	// display name or slug, consolidates the content into this author, another target renames the author.
+	AuthorMap map[string]string
+
+	// AllAuthors lists all the users of the export in data/authors.yaml,
+	// including the ones who authored none of the content, e.g. the editors
+	AllAuthors bool
+
+	// AssetsDir, e.g. "assets", downloads the images of the content into this dir of the site
+	// instead of the static dir, for processing them with Hugo's asset pipeline.
+	// AssetReferences decides how the content references them.
+	// Other media, e.g. the cover images and the audio files, stay in the static dir.
+	AssetsDir       string
+	AssetReferences AssetReferenceStyle
+
+	// WooCommerce emits the price, SKU, gallery, attributes and variations of the WooCommerce products
+	// in their front matter. The variations are not written as separate pages then.
+	WooCommerce bool
+
+	// BrokenImages decides what becomes of the content images which failed to download,
+	// with continueOnMediaDownloadFailure. They are listed in the Report either way.
+	BrokenImages BrokenImagePolicy
+
+	// SVGImages decides whether the SVG images of the content are downloaded, or inlined into the content
+	// up to SVGInlineMaxBytes, e.g. the icons
+	SVGImages         SVGImagePolicy
+	SVGInlineMaxBytes int64
+
+	// DetectLanguages emits the language detected from the text of the content as the `lang` front matter,
+	// when it differs from the language of the site, e.g. the posts in French of an English blog.
+	// The detection is heuristic: the short texts and the detections which are not confident are skipped,
+	// and the content with a language set by Polylang or WPML is left as is. The languages are in the Report.
+	DetectLanguages bool
+
+	// EmitCommentStatus emits `comments: true/false` from the WordPress comment status,
+	// and the `comment_count` of the approved comments
+	EmitCommentStatus bool
+
+	// MaxFileNameLength truncates the longer content filenames, the original slug is kept in the front matter
+	MaxFileNameLength int
+
+	// AlwaysEmitSlug emits the WordPress slug, the post_name, as the `slug` front matter of every page,
+	// even when the filename is the slug, so that the URLs don't depend on the filenames.
+	// The drafts which were never published have no post_name, their slug is derived from the link or the title.
+	AlwaysEmitSlug bool
+
+	// PathOverrides maps the post IDs to the output path of their content, relative to the content dir,
+	// e.g. {"42": "about/index.md"}. They take precedence over the _wp2hugo_path postmeta.
+	PathOverrides map[string]string
+
+	// SectionCascades maps the content sections, e.g. "posts", to the front matter cascaded to all their pages,
+	// emitted as the `cascade` front matter of the section _index.md
+	SectionCascades map[string]map[string]any
+
+	// NextPage decides whether the content paginated with <!--nextpage--> tags is collapsed into one page,
+	// the default, or split into one page per page
+	NextPage NextPagePolicy
+
+	// AttachmentPages decides what becomes of the attachment pages of the media, e.g. /summit/ for summit.jpg:
+	// left out, the default, redirected to the content they are attached to, or written as minimal pages
+	AttachmentPages AttachmentPagePolicy
+
+	// ContentReplacements are the site-specific fixups of the converted Markdown, applied in order,
+	// before the media of the content are downloaded
+	ContentReplacements []ContentReplacement
+
+	// FrontMatterHook adjusts the front matter of each page before it is written, e.g. to add computed fields,
+	// drop or rename keys, after the media downloads. An error skips the page, see FailFast. The post is a copy.
+	// The keys are the ones of the Hugo front matter, e.g. "title", "date", "lastmod", "draft", "url", "slug",
+	// "author", "categories" and "tags" (see hugopage.PageOptions.TaxonomyKeys), "cover", "images", "summary",
+	// "post_id", "parent_post_id", "guid", "type", "series", "series_weight", "expiryDate", "comments", "comment_count",
+	// "robots", "sitemap", "lang" and "aliases", most of them only set when they have a value,
+	// along with the custom taxonomies and the postmeta.
+	// The values are mostly strings and lists of strings, "cover" and the decoded PHP-serialized postmeta are maps.
+	// Changing the hook doesn't change the options hash of the incremental runs.
+	FrontMatterHook func(post *wpparser.PostInfo, frontMatter map[string]any) error `json:"-"`
+
+	// TaxonomyWeights emits the order of the taxonomy terms as the `weight` front matter of their term pages,
+	// from their custom order in the term meta, or else their order in the export
+	TaxonomyWeights bool
+
+	// TaxonomyPaths maps the taxonomies to the URL path of their taxonomy and term pages, e.g. "categories" to "topics"
+	// for /topics/news/, emitted as the permalinks of the Hugo config. The taxonomies default to their front matter key.
+	// The term pages stay in the content dir of the front matter key, e.g. content/categories/news/_index.md,
+	// Hugo only reads them there.
+	TaxonomyPaths map[string]string
+
+	// TermMeta emits the term meta into the front matter of the term pages, the term image as their `cover`
+	TermMeta bool
+
+	// FaviconParam and LogoParam are the params of the Hugo config, as dotted paths under `params`,
+	// which the site icon and the custom logo are emitted as, defaulting to the ones of PaperMod
+	FaviconParam string
+	LogoParam    string
+
+	// TermCollisions decides what becomes of the terms named the same in the categories and the tags:
+	// kept separate, the default, merged into the term of TermCollisionTarget, or renamed with a suffix.
+	// TermCollisionTarget, "categories" by default, is the taxonomy which keeps its terms as is.
+	TermCollisions      TermCollisionPolicy
+	TermCollisionTarget string
+
+	// Incremental only rewrites the content which changed since the previous run into the same site,
+	// and removes the content which is not in the export anymore. It requires SiteName.
+	// The content hashes are kept in the .wp2hugo-manifest.json file of the site.
+	Incremental bool
+
+	// SiteName is the name of the site dir created under the output dir.
+	// It defaults to "generated-<timestamp>", which differs on every run.
+	SiteName string
+
+	// NoMedia is a content-only mode, which never fetches anything: the media links are kept absolute,
+	// pointing to the WordPress site, e.g. when the media stay there or are migrated to a CDN separately.
+	// It can't be combined with the media downloads.
+	NoMedia bool
+
+	// HostRewrites swap the host of the URLs of the content, the front matter and the galleries, e.g. {From: "example.org",
+	// To: "cdn.example.net"} for the media moved to a CDN without downloading them, applied in order after the downloads.
+	// The internal links are made relative before, so they are left as is, and so are the code samples.
+	HostRewrites []HostRewrite
+
+	// Index writes a row per converted content, with its original URL, new path, word and media counts and aliases,
+	// into this .csv or .json file, e.g. for spot-checking the conversion. It does not change the content.
+	Index string `json:"-"`
+
+	// CommentThreads writes the approved comments of each content, keyed by its URL path, into this .json file,
+	// with the discussion term of the pathname mapping of Giscus and Utterances, for seeding their discussions or issues.
+	// It is separate from data/comments.yaml, and leaves out the emails of the commenters.
+	CommentThreads string `json:"-"`
+
+	// FailFast aborts the conversion on the first content which fails to convert, e.g. for CI.
+	// By default the content is skipped, and listed with its error in the Report.
+	FailFast bool `json:"-"`
+
+	// OutputZip writes the site into this zip archive instead of the output dir,
+	// e.g. for a single downloadable artifact. It can't be combined with Incremental.
+	OutputZip string
+
+	// SingleFile writes the content into this Markdown document instead of a Hugo site, a section per content
+	// with its front matter summarized below its heading, e.g. for reading or grepping a whole blog at once.
+	// SingleFileTOC adds a table of contents linking to the sections. The media are not downloaded.
+	SingleFile    string `json:"-"`
+	SingleFileTOC bool   `json:"-"`
 }
 
 type MediaProvider interface {
@@ -69,13 +276,38 @@ type MediaProvider interface {
 
 func NewGenerator(outputDirPath string, fontName string,
 	mediaProvider MediaProvider, downloadMedia bool, downloadAll bool, continueOnMediaDownloadFailure bool,
-	generateNgnixConfig bool, info wpparser.WebsiteInfo,
+	generateNgnixConfig bool, info wpparser.WebsiteInfo, options Options,
 ) *Generator {
+	options.URLPrefix = normalizeURLPrefix(options.URLPrefix)
+	options.PrivateContentDir = strings.Trim(strings.TrimSpace(options.PrivateContentDir), "/")
+	options.DraftsDir = strings.Trim(strings.TrimSpace(options.DraftsDir), "/")
+	if len(options.DraftStatuses) == 0 {
+		options.DraftStatuses = DefaultDraftStatuses
+	}
+	if len(options.Formats) == 0 {
+		options.Formats = DefaultContentFormats
+	}
+	options.AssetsDir = strings.Trim(strings.TrimSpace(options.AssetsDir), "/")
+	options.SiteName = strings.TrimSpace(options.SiteName)
+	if options.AssetReferences == "" {
+		options.AssetReferences = AssetReferencesPath
+	}
+	if options.WebPQuality == 0 {
+		options.WebPQuality = _defaultWebPQuality
+	}
+	if options.MaxFileNameLength == 0 {
+		options.MaxFileNameLength = _defaultMaxFileNameLength
+	}
+	if options.TermCollisionTarget == "" {
+		options.TermCollisionTarget = hugopage.CategoryName
+	}
+	options.FaviconParam = cmp.Or(strings.TrimSpace(options.FaviconParam), DefaultFaviconParam)
+	options.LogoParam = cmp.Or(strings.TrimSpace(options.LogoParam), DefaultLogoParam)
 	var ngnixConfig *nginxgenerator.Config
 	if generateNgnixConfig {
 		ngnixConfig = nginxgenerator.NewConfig()
 	}
-	return &Generator{
+	generator := &Generator{
 		fontName:         fontName,
 		imageURLProvider: newImageURLProvider(info),
 		outputDirPath:    outputDirPath,
@@ -90,16 +322,140 @@ func NewGenerator(outputDirPath string, fontName string,
 		// Nginx related
 		generateNgnixConfig: generateNgnixConfig,
 		ngnixConfig:         ngnixConfig,
-	}
+
+		options:     options,
+		postIDDates: getPostIDDates(info),
+		report: &Report{
+			WXRVersion:       info.WXRVersion(),
+			WordPressVersion: info.WordPressVersion(),
+			PostTypes:        info.PostTypeCounts(),
+			SkippedPostTypes: info.SkippedPostTypeCounts(),
+			Plugins:          info.DetectPlugins(),
+			SkippedItems:     slices.Clone(info.SkippedItems()),
+			NoMedia:          options.NoMedia,
+			RewrittenHosts:   getRewrittenHosts(options.HostRewrites),
+		},
+	}
+	if options.Incremental {
+		generator.incremental = newIncrementalRun(generator.getOptionsHash())
+	}
+	if options.Index != "" {
+		generator.index = &contentIndex{}
+	}
+	generator.termRenames = generator.getTermRenames(info)
+	generator.postLinks = generator.getPostLinks(info)
+	return generator
+}
+
+// Report returns the summary of the conversion so far
+func (g Generator) Report() Report {
+	return *g.report
+}
+
+// AddParsedExport records the size and the parsing time of an export of the site in the Report,
+// since the export is parsed before the generator is created
+func (g Generator) AddParsedExport(exportBytes int64, duration time.Duration) {
+	g.report.ExportBytes += exportBytes
+	g.report.ParseDuration += duration
 }
 
 func (g Generator) Generate(ctx context.Context) error {
+	if g.options.SingleFile != "" {
+		return g.generateSingleFile(ctx)
+	}
+	if g.options.OutputZip != "" {
+		return g.generateZipArchive(ctx)
+	}
 	info := g.wpInfo
-	siteDir, err := g.setupHugo(ctx, g.outputDirPath)
+	if g.options.ConvertImagesToWebP {
+		if !_webpEncodingSupported {
+			return errWebPEncodingNotSupported
+		}
+		if err := validateWebPQuality(g.options.WebPQuality); err != nil {
+			return err
+		}
+	}
+	if g.options.AssetsDir == _staticDir {
+		return fmt.Errorf("assets dir can't be the %q dir", _staticDir)
+	}
+	if err := g.validateNoMedia(); err != nil {
+		return err
+	}
+	if err := g.validateSVGImages(); err != nil {
+		return err
+	}
+	if err := validateSiteName(g.options.SiteName); err != nil {
+		return err
+	}
+	if err := validateDraftsDir(g.options.DraftsDir, g.options.PrivateContentDir); err != nil {
+		return err
+	}
+	if g.options.Incremental && g.options.SiteName == "" {
+		return errIncrementalRequiresSiteName
+	}
+	if g.options.Incremental && info.PostIDsFiltered() {
+		return errIncrementalPostIDs
+	}
+	if err := validateMaxFileNameLength(g.options.MaxFileNameLength); err != nil {
+		return err
+	}
+	if err := validatePathOverrides(g.options.PathOverrides); err != nil {
+		return err
+	}
+	if err := validateSectionCascades(g.options.SectionCascades); err != nil {
+		return err
+	}
+	if err := validateContentReplacements(g.options.ContentReplacements); err != nil {
+		return err
+	}
+	if err := validateHostRewrites(g.options.HostRewrites); err != nil {
+		return err
+	}
+	if err := validateTermCollisionTarget(g.options.TermCollisionTarget); err != nil {
+		return err
+	}
+	if err := validatePlaylistShortcode(g.options.PlaylistShortcode); err != nil {
+		return err
+	}
+	if err := validateAuthorMap(g.options.AuthorMap); err != nil {
+		return err
+	}
+	for _, param := range []string{g.options.FaviconParam, g.options.LogoParam} {
+		if err := validateConfigParam(param); err != nil {
+			return err
+		}
+	}
+	if g.options.Index != "" {
+		if err := validateIndexPath(g.options.Index); err != nil {
+			return err
+		}
+	}
+	if g.options.CommentThreads != "" {
+		if err := validateCommentThreadsPath(g.options.CommentThreads); err != nil {
+			return err
+		}
+	}
+	if err := validateTaxonomyPaths(info, g.options); err != nil {
+		return err
+	}
+	warnReservedTaxonomyKeys(info, g.options.PageOptions)
+	outputDirPath, err := createOutputDir(g.outputDirPath)
 	if err != nil {
 		return err
 	}
-	if err = updateConfig(*siteDir, info); err != nil {
+	g.outputDirPath = outputDirPath
+	log.Info().
+		Str("location", outputDirPath).
+		Msg("Writing the Hugo site into the output directory")
+	siteDir, reused, err := g.getSiteDir(ctx)
+	if err != nil {
+		return err
+	}
+	branding, err := g.getSiteBranding(ctx, *siteDir, info)
+	if err != nil {
+		return err
+	}
+	if err = updateConfig(*siteDir, info, g.options, branding); err != nil {
 		return err
 	}
 
@@ -109,16 +465,11 @@ func (g Generator) Generate(ctx context.Context) error {
 		}
 	}
 
-	// Non-hierarchical content:
-	if err = g.writePosts(ctx, *siteDir, info); err != nil {
-		return err
-	}
-
-	// Hierarchical content:
-	if err = g.writePages(ctx, *siteDir, info); err != nil {
-		return err
-	}
-	if err = g.writeCustomPosts(ctx, *siteDir, info); err != nil {
+	if err = g.writeContent(ctx, *siteDir, info); err != nil {
+		if ctx.Err() != nil {
+			// What was converted before the cancellation
+			g.report.log()
+		}
 		return err
 	}
 	if err = setupArchivePage(*siteDir); err != nil {
@@ -127,8 +478,11 @@ func (g Generator) Generate(ctx context.Context) error {
 	if err = setupSearchPage(*siteDir); err != nil {
 		return err
 	}
-	if err = setupFont(*siteDir, g.fontName); err != nil {
-		return err
+	if !reused {
+		// Appended to the theme files
+		if err = setupFont(*siteDir, g.fontName); err != nil {
+			return err
+		}
 	}
 	if err = WriteCustomShortCodes(*siteDir); err != nil {
 		return err
@@ -141,6 +495,10 @@ func (g Generator) Generate(ctx context.Context) error {
 		return err
 	}
 
+	if err = setupAuthorsData(*siteDir, info, g.options.AuthorMap, g.options.AllAuthors); err != nil {
+		return err
+	}
+
 	if err = setupRssFeedFormat(*siteDir); err != nil {
 		return err
 	}
@@ -171,17 +529,82 @@ func (g Generator) Generate(ctx context.Context) error {
 		}
 	}
 
+	g.report.log()
 	log.Debug().
 		Str("cmd", fmt.Sprintf("cd %s && hugo serve", *siteDir)).
 		Msg("Hugo site has been generated")
 	return nil
 }
 
+// writeContent writes the posts, pages and custom posts into the content dir of the site
+func (g Generator) writeContent(ctx context.Context, siteDir string, info wpparser.WebsiteInfo) error {
+	start := time.Now()
+	defer func() {
+		g.report.ContentDuration += time.Since(start)
+	}()
+	if g.incremental != nil {
+		if err := g.prepareIncrementalRun(siteDir, g.getWrittenContent(info)); err != nil {
+			return err
+		}
+	}
+	err := g.writeAllContent(ctx, siteDir, info)
+	if g.incremental != nil {
+		// Also written when interrupted, e.g. cancelled, so that the next run resumes from the content written so far
+		err = errors.Join(err, g.finishIncrementalRun(siteDir))
+	}
+	if err == nil && g.options.Index != "" {
+		err = g.writeIndex(siteDir)
+	}
+	if err == nil && g.options.CommentThreads != "" {
+		err = g.writeCommentThreads(info)
+	}
+	return err
+}
+
+func (g Generator) writeAllContent(ctx context.Context, siteDir string, info wpparser.WebsiteInfo) error {
+	// Non-hierarchical content:
+	if err := g.writePosts(ctx, siteDir, info); err != nil {
+		return err
+	}
+
+	// Hierarchical content:
+	if err := g.writePages(ctx, siteDir, info); err != nil {
+		return err
+	}
+	if err := g.writeCustomPosts(ctx, siteDir, info); err != nil {
+		return err
+	}
+	if g.options.AttachmentPages != "" && g.options.AttachmentPages != AttachmentPagesNone {
+		if err := g.writeAttachmentPages(ctx, siteDir, info); err != nil {
+			return err
+		}
+	}
+	if err := g.writeSectionCascades(siteDir); err != nil {
+		return err
+	}
+	if g.options.TaxonomyWeights || g.options.TermMeta {
+		return g.writeTermPages(ctx, siteDir, info)
+	}
+	return nil
+}
+
+// getSiteDir returns the site dir, which is set up unless reused from the previous incremental run
+func (g Generator) getSiteDir(ctx context.Context) (*string, bool, error) {
+	if g.incremental != nil {
+		siteDir := path.Join(g.outputDirPath, g.options.SiteName)
+		if siteExists(siteDir) {
+			log.Info().
+				Str("location", siteDir).
+				Msg("Updating the Hugo site of the previous run")
+			return &siteDir, true, nil
+		}
+	}
+	siteDir, err := g.setupHugo(ctx, g.outputDirPath)
+	return siteDir, false, err
+}
+
 func (g Generator) setupHugo(ctx context.Context, outputDirPath string) (*string, error) {
-	// Replace spaces and colons with dashes
-	timeFormat := time.Now().Format(
-		strings.ReplaceAll(strings.ReplaceAll(time.DateTime, " ", "-"), ":", "-"))
-	siteName := "generated-" + timeFormat
+	siteName := g.getSiteName()
 	log.Debug().
 		Str("siteName", siteName).
 		Msg("Setting up Hugo site")
@@ -194,16 +617,6 @@ func (g Generator) setupHugo(ctx context.Context, outputDirPath string) (*string
 		return nil, fmt.Errorf("hugo not found, install it from https://gohugo.io/: %w", err)
 	}
 
-	// Create output directory
-	err = os.MkdirAll(outputDirPath, 0o700)
-	if err != nil {
-		log.Fatal().
-			Err(err).
-			Str("outputDirPath", outputDirPath).
-			Msg("error creating output directory")
-		return nil, fmt.Errorf("error creating output directory '%s': %w", outputDirPath, err)
-	}
-
 	commands := []string{
 		"git version",
 		"hugo version",
@@ -266,6 +679,27 @@ func (g Generator) setupHugo(ctx context.Context, outputDirPath string) (*string
 	return &siteDir, nil
 }
 
+func (g Generator) getSiteName() string {
+	if g.options.SiteName != "" {
+		return g.options.SiteName
+	}
+	// Replace spaces and colons with dashes
+	timeFormat := time.Now().Format(
+		strings.ReplaceAll(strings.ReplaceAll(time.DateTime, " ", "-"), ":", "-"))
+	return "generated-" + timeFormat
+}
+
+// The site name is a single dir, used unquoted in the setup commands
+func validateSiteName(siteName string) error {
+	if siteName == "" {
+		return nil
+	}
+	if siteName == "." || siteName == ".." || strings.ContainsAny(siteName, "/\\ \t'\"$`;&|") {
+		return fmt.Errorf("invalid site name %q, expected a single dir name without spaces", siteName)
+	}
+	return nil
+}
+
 func (g Generator) downloadAllMedia(ctx context.Context, outputDirPath string, info wpparser.WebsiteInfo) error {
 	hostname := info.Link().Host
 	prefixes := make([]string, 0, 4)
@@ -276,7 +710,11 @@ func (g Generator) downloadAllMedia(ctx context.Context, outputDirPath string, i
 	prefixes = append(prefixes, "http://www."+hostname)
 
 	for _, attachment := range info.Attachments() {
-		if _, err := downloadMedia(ctx, *attachment.GetAttachmentURL(), outputDirPath, prefixes, g, info.Link()); err != nil {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		attachmentURL := *attachment.GetAttachmentURL()
+		if _, _, err := downloadMedia(ctx, attachmentURL, outputDirPath, g.getImageMediaDir(attachmentURL), prefixes, g, info.Link()); err != nil {
 			return err
 		}
 	}
@@ -284,8 +722,11 @@ func (g Generator) downloadAllMedia(ctx context.Context, outputDirPath string, i
 	return nil
 }
 
-func getPagePath(outputDirPath string, page wpparser.CommonFields, posts []wpparser.CommonFields) (string, error) {
-	pagePath := ""
+func (g Generator) getPagePath(contentDir string, page wpparser.CommonFields, posts []wpparser.CommonFields) (string, error) {
+	pagePath, err := g.getPathOverride(contentDir, page)
+	if err != nil || pagePath != "" {
+		return pagePath, err
+	}
 
 	if page.PostParentID != nil {
 		for _, parent := range posts {
@@ -295,12 +736,12 @@ func getPagePath(outputDirPath string, page wpparser.CommonFields, posts []wppar
 			// which is designed for WooCommerce : product variations are a different
 			// post type than their parent product. All in all, that seems generic enough.
 			if parent.PostID == *page.PostParentID {
-				parentFileName := parent.GetFileInfo().FileNameNoLanguage()
-				pagesDir := path.Join(outputDirPath, "content", *parent.PostType+"s", parentFileName)
+				parentFileName := g.getFileInfo(parent).FileNameNoLanguage()
+				pagesDir := path.Join(contentDir, *parent.PostType+"s", parentFileName)
 				if err := utils.CreateDirIfNotExist(pagesDir); err != nil {
 					return pagePath, err
 				}
-				pagePath = getFilePath(pagesDir, page.GetFileInfo().FileNameWithLanguage())
+				pagePath = getFilePath(pagesDir, g.getFileInfo(page).FileNameWithLanguage())
 				break
 			}
 		}
@@ -315,8 +756,8 @@ func getPagePath(outputDirPath string, page wpparser.CommonFields, posts []wppar
 	// Whether the post has no parent or we could not find it:
 	if pagePath == "" {
 		// Create a branch page bundle using using a dynamic posttype subfolder
-		lang := page.GetFileInfo().Language()
-		pagesDir := path.Join(outputDirPath, "content", *page.PostType+"s", page.GetFileInfo().FileNameNoLanguage())
+		lang := g.getFileInfo(page).Language()
+		pagesDir := path.Join(contentDir, *page.PostType+"s", g.getFileInfo(page).FileNameNoLanguage())
 		if err := utils.CreateDirIfNotExist(pagesDir); err != nil {
 			return pagePath, err
 		}
@@ -411,13 +852,13 @@ func sanitizePageBundles(dirPath string) error {
 	return nil
 }
 
-func sanitizePostType(outputDirPath string, postType string) {
+func sanitizePostType(contentDir string, postType string) {
 	// Content type subfolder always uses the plural form of the type name
 	if !strings.HasSuffix(postType, "s") {
 		postType += "s"
 	}
 
-	if err := sanitizePageBundles(path.Join(outputDirPath, "content", postType)); err != nil {
+	if err := sanitizePageBundles(path.Join(contentDir, postType)); err != nil {
 		// Intentionally ignore the error
 		fmt.Println("Error sanitizing page bundles:", err)
 	}
@@ -429,13 +870,16 @@ func (g Generator) writePages(ctx context.Context, outputDirPath string, info wp
 		return nil
 	}
 
-	pagesDir := path.Join(outputDirPath, "content", "pages")
-	if err := utils.CreateDirIfNotExist(pagesDir); err != nil {
-		return err
-	}
-
 	// Write pages
 	for _, page := range info.Pages() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if skip, err := g.skipUnchanged(outputDirPath, page.CommonFields, info); err != nil {
+			return err
+		} else if skip {
+			continue
+		}
 		// If the current element is a child of another custom post,
 		// ensure it is saved in the same directory and
 		// prepend the name of the parent in the filename
@@ -445,19 +889,24 @@ func (g Generator) writePages(ctx context.Context, outputDirPath string, info wp
 		for i, p := range info.Pages() {
 			pages[i] = p.CommonFields
 		}
-		if pagePath, err := getPagePath(outputDirPath, page.CommonFields, pages); err != nil {
-			return err
-		} else {
-			if err := g.writePage(ctx, outputDirPath, pagePath, page.CommonFields, info); err != nil {
+		pagePath, err := g.getPagePath(g.contentDir(outputDirPath, page.CommonFields), page.CommonFields, pages)
+		if err == nil {
+			err = g.writePage(ctx, outputDirPath, pagePath, page.CommonFields, info)
+		}
+		if err != nil {
+			if err := g.skipContent(page.CommonFields, err); err != nil {
 				return err
 			}
+			continue
 		}
 		// Redirect from old URL to new URL
 		g.maybeAddNginxRedirect(page.CommonFields)
 	}
 
 	// Properly set page bundle type
-	sanitizePostType(outputDirPath, "pages")
+	for _, contentDir := range g.pageBundleDirs(outputDirPath) {
+		sanitizePostType(contentDir, "pages")
+	}
 
 	return nil
 }
@@ -470,6 +919,18 @@ func (g Generator) writeCustomPosts(ctx context.Context, outputDirPath string, i
 
 	// Write custom posts
 	for _, page := range info.CustomPosts() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if g.isEmbeddedVariation(page.CommonFields) {
+			// Emitted in the front matter of the product
+			continue
+		}
+		if skip, err := g.skipUnchanged(outputDirPath, page.CommonFields, info); err != nil {
+			return err
+		} else if skip {
+			continue
+		}
 		// If the current element is a child of another custom post,
 		// ensure it is saved in the same directory and
 		// prepend the name of the parent in the filename
@@ -479,33 +940,76 @@ func (g Generator) writeCustomPosts(ctx context.Context, outputDirPath string, i
 		for i, cp := range info.CustomPosts() {
 			customPosts[i] = cp.CommonFields
 		}
-		if pagePath, err := getPagePath(outputDirPath, page.CommonFields, customPosts); err != nil {
-			return err
-		} else {
-			if err := g.writePage(ctx, outputDirPath, pagePath, page.CommonFields, info); err != nil {
+		pagePath, err := g.getPagePath(g.contentDir(outputDirPath, page.CommonFields), page.CommonFields, customPosts)
+		if err == nil {
+			err = g.writePage(ctx, outputDirPath, pagePath, page.CommonFields, info)
+		}
+		if err != nil {
+			if err := g.skipContent(page.CommonFields, err); err != nil {
 				return err
 			}
+			continue
 		}
 		// Redirect from old URL to new URL
 		g.maybeAddNginxRedirect(page.CommonFields)
 	}
 
 	// Properly set page bundle type
-	for _, postType := range info.CustomPostTypes() {
-		sanitizePostType(outputDirPath, postType)
+	for _, contentDir := range g.pageBundleDirs(outputDirPath) {
+		for _, postType := range info.CustomPostTypes() {
+			sanitizePostType(contentDir, postType)
+		}
 	}
 
 	return nil
 }
 
+// isEmbeddedVariation reports whether the page is a WooCommerce variation, emitted in the front matter
+// of its product instead of being written, see Options.WooCommerce
+func (g Generator) isEmbeddedVariation(page wpparser.CommonFields) bool {
+	return g.options.WooCommerce && page.PostType != nil && *page.PostType == wpparser.ProductVariationPostType
+}
+
+// getWrittenContent returns the posts, pages and custom posts written into the content dir
+func (g Generator) getWrittenContent(info wpparser.WebsiteInfo) []wpparser.CommonFields {
+	contents := make([]wpparser.CommonFields, 0, len(info.Posts())+len(info.Pages())+len(info.CustomPosts()))
+	for _, post := range info.Posts() {
+		contents = append(contents, post.CommonFields)
+	}
+	for _, page := range info.Pages() {
+		contents = append(contents, page.CommonFields)
+	}
+	for _, customPost := range info.CustomPosts() {
+		if !g.isEmbeddedVariation(customPost.CommonFields) {
+			contents = append(contents, customPost.CommonFields)
+		}
+	}
+	return contents
+}
+
 func (g Generator) maybeAddNginxRedirect(page wpparser.CommonFields) {
 	if !g.generateNgnixConfig {
 		return
 	}
-
-	if page.GUID.Value == "" {
+	oldURLPathWithQuery, newPath, ok := g.getGUIDRedirect(page)
+	if !ok {
+		return
+	}
+	if err := g.ngnixConfig.AddRedirect(oldURLPathWithQuery, newPath); err != nil {
+		log.Warn().
+			Err(err).
+			Str("oldURL", oldURLPathWithQuery).
+			Str("newURL", page.Link).
+			Msg("error adding nginx redirect")
 		return
 	}
+}
+
+// getGUIDRedirect returns the redirect from the GUID of the page, e.g. /?p=10, to its URL
+func (g Generator) getGUIDRedirect(page wpparser.CommonFields) (string, string, bool) {
+	if page.GUID == nil || page.GUID.Value == "" {
+		return "", "", false
+	}
 
 	u1, err := url.Parse(strings.TrimSpace(page.GUID.Value))
 	if err != nil {
@@ -513,7 +1017,7 @@ func (g Generator) maybeAddNginxRedirect(page wpparser.CommonFields) {
 			Err(err).
 			Str("url", page.GUID.Value).
 			Msg("error parsing GUID as URL")
-		return
+		return "", "", false
 	}
 
 	u2, err := url.Parse(strings.TrimSpace(page.Link))
@@ -522,23 +1026,15 @@ func (g Generator) maybeAddNginxRedirect(page wpparser.CommonFields) {
 			Err(err).
 			Str("url", page.Link).
 			Msg("error parsing link as URL")
-		return
+		return "", "", false
 	}
 
 	if !sameHost(*u1, *u2) {
-		return
+		return "", "", false
 	}
 
-	oldURLPathWithQuery := u1.Path + "?" + u1.RawQuery
-	newPath := u2.Path
-	if err := g.ngnixConfig.AddRedirect(oldURLPathWithQuery, newPath); err != nil {
-		log.Warn().
-			Err(err).
-			Str("oldURL", oldURLPathWithQuery).
-			Str("newURL", page.Link).
-			Msg("error adding nginx redirect")
-		return
-	}
+	// The redirect source is the original WordPress URL, only the target is prefixed
+	return u1.Path + "?" + u1.RawQuery, g.options.URLPrefix + u2.Path, true
 }
 
 func sameHost(url1 url.URL, url2 url.URL) bool {
@@ -570,23 +1066,57 @@ func getFilePath(pagesDir string, baseFileName string) string {
 	return pagePath
 }
 
+// getPostDir returns the directory of the post, organized by publish date if requested
+func (g Generator) getPostDir(postsDir string, post wpparser.CommonFields) (string, error) {
+	if g.options.DatePath == "" {
+		return postsDir, nil
+	}
+	publishDate := g.getPublishDate(post)
+	if publishDate == nil {
+		log.Warn().
+			Str("title", post.Title).
+			Str("link", post.Link).
+			Msg("Post has no publish date, not organizing it by date")
+		return postsDir, nil
+	}
+	postDir := path.Join(postsDir, getDatePath(g.options.DatePath, *publishDate))
+	if err := utils.CreateDirIfNotExist(postDir); err != nil {
+		return "", err
+	}
+	return postDir, nil
+}
+
 func (g Generator) writePosts(ctx context.Context, outputDirPath string, info wpparser.WebsiteInfo) error {
 	if len(info.Posts()) == 0 {
 		log.Info().Msg("No posts to write")
 		return nil
 	}
 
-	postsDir := path.Join(outputDirPath, "content", "posts")
-	if err := utils.CreateDirIfNotExist(postsDir); err != nil {
-		return err
+	if g.options.DatePath != "" {
+		if err := validateDatePath(g.options.DatePath); err != nil {
+			return err
+		}
 	}
 
 	// Write posts
 	for _, post := range info.Posts() {
-		filename := post.GetFileInfo().FileNameWithLanguage()
-		postPath := getFilePath(postsDir, filename)
-		if err := g.writePage(ctx, outputDirPath, postPath, post.CommonFields, info); err != nil {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if skip, err := g.skipUnchanged(outputDirPath, post.CommonFields, info); err != nil {
 			return err
+		} else if skip {
+			continue
+		}
+		postPath, err := g.getPostPath(g.contentDir(outputDirPath, post.CommonFields), post.CommonFields)
+		if err == nil {
+			err = g.writePage(ctx, outputDirPath, postPath, post.CommonFields, info)
+		}
+		if err != nil {
+			if err := g.skipContent(post.CommonFields, err); err != nil {
+				return err
+			}
+			continue
 		}
 		// Redirect from old URL to new URL
 		g.maybeAddNginxRedirect(post.CommonFields)
@@ -594,6 +1124,21 @@ func (g Generator) writePosts(ctx context.Context, outputDirPath string, info wp
 	return nil
 }
 
+func (g Generator) getPostPath(contentDir string, post wpparser.CommonFields) (string, error) {
+	if postPath, err := g.getPathOverride(contentDir, post); err != nil || postPath != "" {
+		return postPath, err
+	}
+	postsDir := path.Join(contentDir, "posts")
+	if err := utils.CreateDirIfNotExist(postsDir); err != nil {
+		return "", err
+	}
+	postDir, err := g.getPostDir(postsDir, post)
+	if err != nil {
+		return "", err
+	}
+	return getFilePath(postDir, g.getFileInfo(post).FileNameWithLanguage()), nil
+}
+
 // Ref: https://adityatelange.github.io/hugo-PaperMod/posts/papermod/papermod-features/#archives-layout
 func setupArchivePage(siteDir string) error {
 	filePath := path.Join(siteDir, "content", "archives.md")
@@ -609,7 +1154,7 @@ func setupSearchPage(siteDir string) error {
 }
 
 func writeFile(filePath string, content []byte) error {
-	w, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	w, err := _siteFiles.Create(filePath)
 	if err != nil {
 		return fmt.Errorf("error opening archive file: %w", err)
 	}
@@ -677,58 +1222,218 @@ func updateComments(siteDir string, pageData wpparser.CommonFields, info wpparse
 func (g Generator) writePage(ctx context.Context, outputMediaDirPath string, pagePath string,
 	page wpparser.CommonFields, info wpparser.WebsiteInfo,
 ) error {
+	page = g.getPageBuilderContent(page)
+	g.recordEmptyContent(page)
+	language := g.detectLanguage(page)
+	parts := g.getPageParts(pagePath, page)
+	// The files of the page other than its Markdown, e.g. its following pages or its galleries data,
+	// removed with it in incremental runs
+	var partPaths []string
+	var stats pageStats
+	for i, part := range parts {
+		part.language = language
+		partStats, err := g.writePageFile(ctx, outputMediaDirPath, part)
+		if err != nil {
+			// The parts written so far, and the one partially written
+			g.removePageFiles(outputMediaDirPath, parts[:i+1])
+			return err
+		}
+		stats.words += partStats.words
+		stats.media += partStats.media
+		if part.path != pagePath {
+			partPaths = append(partPaths, part.path)
+		}
+		if g.emitsHTML() {
+			htmlPagePath, err := getHTMLPagePath(outputMediaDirPath, part.path)
+			if err != nil {
+				return err
+			}
+			partPaths = append(partPaths, htmlPagePath)
+		}
+		if dataPath := getGalleriesDataPath(outputMediaDirPath, part); utils.FileExists(dataPath) {
+			partPaths = append(partPaths, dataPath)
+		}
+	}
+	g.recordContent(outputMediaDirPath, pagePath, partPaths, stats, page)
+	g.report.addConvertedContent(len(page.Content))
+
+	if err := updateComments(outputMediaDirPath, page, info); err != nil {
+		return fmt.Errorf("error saving comments: %w", err)
+	}
+
+	return nil
+}
+
+// removePageFiles removes the files of the page parts, in the HTML format and their galleries data too,
+// e.g. after failing to write the next part, so that no partial content is left in the site
+func (g Generator) removePageFiles(siteDir string, parts []pagePart) {
+	for _, part := range parts {
+		filePaths := []string{part.path, getGalleriesDataPath(siteDir, part)}
+		if g.emitsHTML() {
+			if htmlPagePath, err := getHTMLPagePath(siteDir, part.path); err == nil {
+				filePaths = append(filePaths, htmlPagePath)
+			}
+		}
+		for _, filePath := range filePaths {
+			if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
+				log.Warn().
+					Err(err).
+					Str("pagePath", filePath).
+					Msg("error removing page file")
+			}
+		}
+	}
+}
+
+// writePageFile converts the page, or one of its parts, and writes it at its path
+func (g Generator) writePageFile(ctx context.Context, outputMediaDirPath string, part pagePart) (pageStats, error) {
+	pagePath, page := part.path, part.page
 	pageURL, err := url.Parse(page.Link)
 	if err != nil {
-		return fmt.Errorf("error parsing page URL: %w", err)
+		return pageStats{}, conversionError(fmt.Errorf("error parsing page URL: %w", err))
 	}
 
 	p, err := g.newHugoPage(pageURL, page)
 	if err != nil {
-		return fmt.Errorf("error creating Hugo page: %w", err)
+		return pageStats{}, conversionError(fmt.Errorf("error creating Hugo page: %w", err))
+	}
+	if g.options.AlwaysEmitSlug {
+		p.SetSlug(getSlug(page))
+	} else if fileInfo := g.getFileInfo(page); fileInfo.IsTruncated() {
+		p.SetSlug(fileInfo.OriginalFileName())
+	}
+	if part.language != "" {
+		p.SetLanguage(part.language)
+	}
+	if part.number == 1 {
+		if err := p.SetExcerpt(page.Excerpt); err != nil {
+			return pageStats{}, conversionError(fmt.Errorf("error setting excerpt: %w", err))
+		}
+	}
+	if aliases := g.getAttachmentAliases(part); len(aliases) > 0 {
+		p.AddAliases(aliases)
+	}
+	if g.options.EmitCommentStatus && page.CommentStatus != "" {
+		p.SetCommentStatus(page.CommentsOpen(), len(page.Comments))
+	}
+	if part.count > 1 {
+		p.SetPart(part.number, part.count)
+	}
+	if len(g.options.ContentReplacements) > 0 {
+		p.ReplaceMarkdown(func(markdown string) string {
+			return applyContentReplacements(g.options.ContentReplacements, markdown)
+		})
+		p.ReplaceHTML(func(htmlContent string) string {
+			return applyContentReplacements(g.options.ContentReplacements, htmlContent)
+		})
 	}
 
+	g.countRemoteMedia(p)
+	// Counted before the downloads, which may replace the media with shortcodes
+	stats := getPageStats(p)
 	if g.downloadMedia {
 		urlReplacements, err := g.downloadPageMedia(ctx, outputMediaDirPath, p, pageURL)
 		if err != nil {
-			return err
+			// A broken media fails its content only, unless the media dir can't be written to, see isIOError
+			return pageStats{}, conversionError(err)
 		} else {
 			p.Replace(urlReplacements)
 		}
+		if g.options.AssetsDir != "" && g.options.AssetReferences == AssetReferencesShortcode {
+			p.ReplaceImagesWithResourceShortcode()
+		}
 	}
 
-	w, err := os.OpenFile(pagePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	g.applyHostRewrites(p)
+
+	if err := g.writeGalleriesData(outputMediaDirPath, part, p); err != nil {
+		return pageStats{}, err
+	}
+
+	if g.options.FrontMatterHook != nil {
+		post := wpparser.PostInfo{CommonFields: page}
+		if err := p.ReplaceMetadata(func(metadata map[string]any) error {
+			return g.options.FrontMatterHook(&post, metadata)
+		}); err != nil {
+			return pageStats{}, conversionError(fmt.Errorf("error in the front matter hook of %s: %w", page.Link, err))
+		}
+	}
+
+	w, err := _siteFiles.Create(pagePath)
 	if err != nil {
-		return fmt.Errorf("error opening page file: %w", err)
+		return pageStats{}, fmt.Errorf("error opening page file: %w", err)
 	}
 
 	if err = p.Write(w); err != nil {
-		return fmt.Errorf("error writing page file: %w", err)
+		_ = w.Close()
+		return pageStats{}, fmt.Errorf("error writing page file: %w", err)
 	}
 
 	if err = w.Close(); err != nil {
-		return fmt.Errorf("error closing page file: %w", err)
+		return pageStats{}, fmt.Errorf("error closing page file: %w", err)
 	}
 
 	log.Info().Msgf("Page written: %s", pagePath)
-
-	if err := updateComments(outputMediaDirPath, page, info); err != nil {
-		return fmt.Errorf("error saving comments: %w", err)
+	if g.emitsHTML() {
+		if err = writeHTMLPageFile(outputMediaDirPath, pagePath, p); err != nil {
+			return pageStats{}, err
+		}
 	}
-
-	return nil
+	g.report.addStrippedShortcodes(p.StrippedShortcodes())
+	return stats, nil
 }
 
 func (g Generator) newHugoPage(pageURL *url.URL, page wpparser.CommonFields) (*hugopage.Page, error) {
+	pageOptions := g.options.PageOptions
+	pageOptions.LocalMedia = g.downloadMedia
+	pageOptions.AbsoluteMediaLinks = g.options.NoMedia
+	pageOptions.KeepHTML = g.emitsHTML()
+	pageOptions.BasePath = strings.TrimSuffix(g.wpInfo.Link().Path, "/")
+	pageOptions.SiteTitle = g.wpInfo.Title()
+	pageOptions.SiteDescription = g.wpInfo.Description
+	pageOptions.PostLinkProvider = g.postLinks
+	if pageOptions.ExtractACFFields {
+		pageOptions.ACFFieldProvider = &g.wpInfo
+	}
+	if g.options.WooCommerce && page.PostType != nil && *page.PostType == wpparser.ProductPostType {
+		pageOptions.WooCommerceProduct = true
+		pageOptions.ProductVariationProvider = &g.wpInfo
+	}
+	categories, tags := g.termRenames.apply(page.Categories, page.Tags)
 	return hugopage.NewPage(
 		g.imageURLProvider,
-		*pageURL, page.Author, page.Title, page.PublishDate,
+		*pageURL, g.getAuthor(page), page.Title, g.getPublishDate(page), page.LastModifiedDate,
 		page.PublishStatus == wpparser.PublishStatusDraft || page.PublishStatus == wpparser.PublishStatusPending,
-		page.Categories, page.Tags, g.wpInfo.GetAttachmentsForPost(page.PostID),
-		page.Footnotes, page.Content, page.GUID, page.FeaturedImageID, page.PostFormat,
-		page.CustomMetaData, page.Taxonomies, page.PostID, page.PostParentID)
+		categories, tags, g.wpInfo.GetAttachmentsForPost(page.PostID),
+		page.Footnotes, hugopage.InlineReusableBlocks(&g.wpInfo, page.Content), page.GUID, page.FeaturedImageID, page.PostFormat,
+		page.CustomMetaData, page.Taxonomies, page.PostID, page.PostParentID, pageOptions)
 }
 
-func downloadMedia(ctx context.Context, link string, outputMediaDirPath string, prefixes []string, g Generator, pageURL *url.URL) (map[string]string, error) {
+func (g Generator) getAuthor(page wpparser.CommonFields) string {
+	login := page.Author
+	if mapped, ok := getMappedAuthor(g.wpInfo, g.options.AuthorMap, login); ok {
+		if mapped.author == nil {
+			// Renamed, as is without AuthorLogins too
+			return mapped.name
+		}
+		login = mapped.author.Login
+	}
+	if g.options.AuthorLogins {
+		return login
+	}
+	if slug, ok := g.wpInfo.GetAuthorSlug(login); ok {
+		return slug
+	}
+	log.Warn().
+		Str("author", login).
+		Str("title", page.Title).
+		Msg("Author not found in the export, keeping the login")
+	return login
+}
+
+// downloadMedia returns the link replacements, and a description of the issue if the media was skipped
+// mediaDir is the dir of the site the media is written to, e.g. "static"
+func downloadMedia(ctx context.Context, link string, outputMediaDirPath string, mediaDir string, prefixes []string, g Generator, pageURL *url.URL) (map[string]string, string, error) {
 	// Uniformize protocol-less links: add protocol
 	if strings.HasPrefix(link, "//") {
 		link = strings.Replace(link, "//", pageURL.Scheme+"://", 1)
@@ -746,11 +1451,11 @@ func downloadMedia(ctx context.Context, link string, outputMediaDirPath string,
 			Str("link", link).
 			Str("source", pageURL.String()).
 			Msg("non-relative link (skipped for download)")
-		return nil, nil
+		return nil, "", nil
 	}
 
 	relativeLink := link
-	outputFilePath := fmt.Sprintf("%s/static/%s", outputMediaDirPath,
+	outputFilePath := fmt.Sprintf("%s/%s/%s", outputMediaDirPath, mediaDir,
 		strings.TrimSuffix(strings.Split(link, "?")[0], "/"))
 
 	if strings.HasPrefix(link, "http") {
@@ -805,6 +1510,10 @@ func downloadMedia(ctx context.Context, link string, outputMediaDirPath string,
 	// Thus we register URL replacements as relative links.
 
 	if err != nil {
+		if ctx.Err() != nil {
+			// Never skipped, even with continueOnMediaDownloadFailure
+			return nil, "", ctx.Err()
+		}
 		if errors.Is(err, mediacache.ErrMediaNotAcceptable) {
 			log.Error().
 				Err(err).
@@ -812,7 +1521,7 @@ func downloadMedia(ctx context.Context, link string, outputMediaDirPath string,
 				Str("pageLink", pageURL.String()).
 				Str("outputFilePath", outputFilePath).
 				Msg("server returned 406 Not Acceptable for media file, skipping")
-			return urlReplacement, nil
+			return urlReplacement, "media download failed: 406 Not Acceptable", nil
 		}
 		if g.continueOnMediaDownloadFailure {
 			log.Error().
@@ -821,13 +1530,14 @@ func downloadMedia(ctx context.Context, link string, outputMediaDirPath string,
 				Str("pageLink", pageURL.String()).
 				Str("outputFilePath", outputFilePath).
 				Msg("error fetching media file")
-			return urlReplacement, nil
+			return urlReplacement, "media download failed", nil
 		} else {
-			return nil, fmt.Errorf("error fetching media file %s: %w", link, err)
+			return nil, "", fmt.Errorf("error fetching media file %s: %w", link, err)
 		}
 	}
 
-	if err = download(outputFilePath, media); err != nil {
+	downloadedBytes, err := download(outputFilePath, media)
+	if err != nil {
 		if g.continueOnMediaDownloadFailure {
 			log.Error().
 				Err(err).
@@ -835,15 +1545,35 @@ func downloadMedia(ctx context.Context, link string, outputMediaDirPath string,
 				Str("pageLink", pageURL.String()).
 				Msg("error downloading media file")
 		} else {
-			return nil, fmt.Errorf("error downloading media file: %w embedded in %s", err, pageURL.String())
+			return nil, "", fmt.Errorf("error downloading media file: %w embedded in %s", err, pageURL.String())
 		}
+		return urlReplacement, "media download failed", nil
 	}
+	g.report.addDownloadedMedia(downloadedBytes)
 
-	return urlReplacement, nil
+	if err = g.maybeConvertImageToWebP(outputFilePath, urlReplacement, relativeLink, link); err != nil {
+		if !g.continueOnMediaDownloadFailure {
+			return nil, "", err
+		}
+		log.Error().
+			Err(err).
+			Str("mediaLink", link).
+			Str("pageLink", pageURL.String()).
+			Msg("error converting media file to WebP, keeping the original")
+	}
+	return urlReplacement, "", nil
 }
 
 func (g Generator) downloadPageMedia(ctx context.Context, outputMediaDirPath string, p *hugopage.Page, pageURL *url.URL) (map[string]string, error) {
-	links := p.WPMediaLinks()
+	// The images of the content can be Hugo resources, the other media are always static,
+	// like the images of the HTML format only, e.g. the resized images of its srcset attributes
+	resourceLinks := p.WPResourceLinks()
+	links := append(resourceLinks, p.WPStaticLinks()...)
+	for _, link := range p.HTMLMediaLinks() {
+		if !slices.Contains(links, link) {
+			links = append(links, link)
+		}
+	}
 	log.Debug().
 		Str("page", pageURL.String()).
 		Int("links", len(links)).
@@ -862,13 +1592,53 @@ func (g Generator) downloadPageMedia(ctx context.Context, outputMediaDirPath str
 	prefixes = append(prefixes, "http://www."+hostname)
 
 	urlReplacements := make(map[string]string)
+	var brokenLinks []string
 
-	for _, link := range links {
-		if replacement, err := downloadMedia(ctx, link, outputMediaDirPath, prefixes, g, pageURL); err != nil {
+	for i, link := range links {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		mediaDir := _staticDir
+		if i < len(resourceLinks) {
+			mediaDir = g.getImageMediaDir(link)
+		}
+		if hugopage.IsDataURI(link) {
+			if g.options.KeepInlineImages {
+				continue
+			}
+			replacement, err := externalizeDataURIImage(outputMediaDirPath, mediaDir, link)
+			if err != nil {
+				log.Warn().
+					Err(err).
+					Str("pageLink", pageURL.String()).
+					Msg("error externalizing base64-embedded image, leaving it inline")
+				continue
+			}
+			maps.Copy(urlReplacements, replacement)
+			continue
+		}
+		if i < len(resourceLinks) && g.options.SVGImages == SVGImagesInline && isSVGLink(link) {
+			if inlined, err := g.inlineSVGImage(ctx, p, link, prefixes, pageURL); err != nil {
+				return nil, err
+			} else if inlined {
+				continue
+			}
+		}
+		if replacement, issue, err := downloadMedia(ctx, link, outputMediaDirPath, mediaDir, prefixes, g, pageURL); err != nil {
 			return nil, err
 		} else {
+			if issue != "" {
+				p.AnnotateLink(link, issue)
+				if i < len(resourceLinks) {
+					brokenLinks = append(brokenLinks, link)
+					g.report.BrokenImages = append(g.report.BrokenImages, BrokenImage{Page: pageURL.String(), Link: link, Issue: issue})
+				}
+			}
 			maps.Copy(urlReplacements, replacement)
 		}
 	}
+	if err := g.handleBrokenImages(outputMediaDirPath, p, brokenLinks, urlReplacements); err != nil {
+		return nil, err
+	}
 	return urlReplacements, nil
 }
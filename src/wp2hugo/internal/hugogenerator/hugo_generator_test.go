@@ -23,7 +23,7 @@ func TestFootnote(t *testing.T) {
 	require.Len(t, post.Footnotes, 1)
 	require.NotNil(t, post.CommonFields)
 
-	generator := NewGenerator("/tmp", "", nil, false, false, false, true, *websiteInfo)
+	generator := NewGenerator("/tmp", "", nil, false, false, false, true, *websiteInfo, Options{})
 	url1, err := url.Parse(post.GUID.Value)
 	require.NoError(t, err)
 	hugoPage, err := generator.newHugoPage(url1, post.CommonFields)
@@ -50,3 +50,14 @@ func TestPost(t *testing.T) {
 	require.Equal(t, "netzfundst��cke", post.Categories[0])
 	require.Len(t, post.Content, 1276)
 }
+
+func TestGetSiteName(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, "blog", Generator{options: Options{SiteName: "blog"}}.getSiteName())
+	require.Regexp(t, `^generated-\d{4}-\d{2}-\d{2}-\d{2}-\d{2}-\d{2}$`, Generator{}.getSiteName())
+
+	require.NoError(t, validateSiteName(""))
+	require.NoError(t, validateSiteName("blog"))
+	require.Error(t, validateSiteName("../blog"))
+	require.Error(t, validateSiteName("my blog"))
+}
@@ -0,0 +1,211 @@
+package hugopage
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/rs/zerolog/log"
+)
+
+// ACFFieldProvider resolves Advanced Custom Fields definitions from their field key
+type ACFFieldProvider interface {
+	GetACFField(fieldKey string) (wpparser.ACFField, bool)
+}
+
+// Sub-field of a repeater row, e.g. "0_title" in "ingredients_0_title"
+var _acfRowFieldRegEx = regexp.MustCompile(`^(\d+)_(.+)$`)
+
+// ACF stores each field value in a postmeta entry named after the field ("price"),
+// and a companion entry "_price" holding the field key ("field_5f3c1a2b3c4d5").
+// Repeater rows are flattened as "ingredients_0_title", "ingredients_1_title", etc.
+// with "ingredients" holding the number of rows.
+type acfExtractor struct {
+	provider  ACFFieldProvider
+	values    map[string]string
+	fieldKeys map[string]string   // field name -> field key
+	children  map[string][]string // repeater field name -> sub-field names
+}
+
+// extractACFFields pairs the `fieldname`/`_fieldname` postmeta entries and returns the
+// decoded ACF fields, along with the set of postmeta keys consumed in the process.
+func extractACFFields(provider ACFFieldProvider, customMetaData []wpparser.CustomMetaDatum) (map[string]any, map[string]bool) {
+	e := acfExtractor{
+		provider:  provider,
+		values:    make(map[string]string, len(customMetaData)),
+		fieldKeys: make(map[string]string),
+		children:  make(map[string][]string),
+	}
+	for _, metadatum := range customMetaData {
+		e.values[metadatum.Key] = metadatum.Value
+	}
+	for key, value := range e.values {
+		name, ok := strings.CutPrefix(key, "_")
+		if !ok || !strings.HasPrefix(value, "field_") {
+			continue
+		}
+		if _, ok := e.values[name]; ok {
+			e.fieldKeys[name] = value
+		}
+	}
+	if len(e.fieldKeys) == 0 {
+		return nil, nil
+	}
+
+	names := make([]string, 0, len(e.fieldKeys))
+	for name := range e.fieldKeys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	consumed := make(map[string]bool, 2*len(names))
+	var topLevel []string
+	for _, name := range names {
+		consumed[name] = true
+		consumed["_"+name] = true
+		if parent := e.parentOf(name, names); parent != "" {
+			e.children[parent] = append(e.children[parent], name)
+		} else {
+			topLevel = append(topLevel, name)
+		}
+	}
+
+	fields := make(map[string]any, len(topLevel))
+	for _, name := range topLevel {
+		if value, ok := e.fieldValue(name); ok {
+			fields[name] = value
+		}
+	}
+	return fields, consumed
+}
+
+// parentOf returns the closest repeater field the given field is a row of, if any
+func (e acfExtractor) parentOf(name string, names []string) string {
+	parent := ""
+	for _, candidate := range names {
+		if len(candidate) <= len(parent) || !strings.HasPrefix(name, candidate+"_") {
+			continue
+		}
+		if _acfRowFieldRegEx.MatchString(strings.TrimPrefix(name, candidate+"_")) {
+			parent = candidate
+		}
+	}
+	return parent
+}
+
+func (e acfExtractor) fieldValue(name string) (any, bool) {
+	var field wpparser.ACFField
+	if e.provider != nil {
+		field, _ = e.provider.GetACFField(e.fieldKeys[name])
+	}
+	switch {
+	case field.Type == "repeater" || field.Type == "flexible_content" || len(e.children[name]) > 0:
+		return e.rows(name), true
+	case field.Type == "relationship":
+		return e.relationship(name)
+	default:
+		return e.scalar(name)
+	}
+}
+
+func (e acfExtractor) rows(name string) []map[string]any {
+	value := e.values[name]
+	numRows, err := strconv.Atoi(value)
+	var layouts []string
+	if err != nil {
+		// Flexible content fields store the list of row layouts instead of the row count
+		for _, layout := range toSlice(e.unserialize(name, value)) {
+			layouts = append(layouts, fmt.Sprint(layout))
+		}
+		numRows = len(layouts)
+	}
+	numRows = max(numRows, 0)
+
+	rows := make([]map[string]any, numRows)
+	for i := range rows {
+		rows[i] = make(map[string]any)
+		if i < len(layouts) {
+			rows[i]["acf_fc_layout"] = layouts[i]
+		}
+	}
+	for _, child := range e.children[name] {
+		matches := _acfRowFieldRegEx.FindStringSubmatch(strings.TrimPrefix(child, name+"_"))
+		index, err := strconv.Atoi(matches[1])
+		if err != nil || index >= numRows {
+			log.Warn().
+				Str("field", child).
+				Int("numRows", numRows).
+				Msg("ACF repeater sub-field out of range, skipping")
+			continue
+		}
+		if childValue, ok := e.fieldValue(child); ok {
+			rows[index][matches[2]] = childValue
+		}
+	}
+	return rows
+}
+
+// relationship fields store a PHP-serialized list of post IDs
+func (e acfExtractor) relationship(name string) (any, bool) {
+	value, ok := e.scalar(name)
+	if !ok {
+		return nil, false
+	}
+	if list := toSlice(value); list != nil {
+		postIDs := make([]string, 0, len(list))
+		for _, postID := range list {
+			postIDs = append(postIDs, fmt.Sprint(postID))
+		}
+		return postIDs, true
+	}
+	return value, true
+}
+
+func (e acfExtractor) scalar(name string) (any, bool) {
+	value := e.values[name]
+	if !looksPHPSerialized(value) {
+		return value, true
+	}
+	decoded := e.unserialize(name, value)
+	if decoded == nil {
+		log.Warn().
+			Str("field", name).
+			Msg("Skipping ACF field with unparseable serialized value")
+		return nil, false
+	}
+	return decoded, true
+}
+
+func (e acfExtractor) unserialize(name string, value string) any {
+	if value == "" {
+		return nil
+	}
+	log.Debug().
+		Str("field", name).
+		Msg("Decoding serialized ACF field")
+	return UnserialiazePHParray(value)
+}
+
+func toSlice(value any) []any {
+	switch v := value.(type) {
+	case []any:
+		return v
+	case map[string]any:
+		// Serialized arrays with non-sequential indices
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		result := make([]any, 0, len(v))
+		for _, key := range keys {
+			result = append(result, v[key])
+		}
+		return result
+	default:
+		return nil
+	}
+}
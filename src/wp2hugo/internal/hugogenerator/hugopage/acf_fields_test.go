@@ -0,0 +1,59 @@
+package hugopage
+
+import (
+	"testing"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeACFFieldProvider map[string]wpparser.ACFField
+
+func (f fakeACFFieldProvider) GetACFField(fieldKey string) (wpparser.ACFField, bool) {
+	field, ok := f[fieldKey]
+	return field, ok
+}
+
+func TestExtractACFFields(t *testing.T) {
+	t.Parallel()
+	provider := fakeACFFieldProvider{
+		"field_3": {Key: "field_3", Name: "related", Type: "relationship"},
+	}
+	customMetaData := []wpparser.CustomMetaDatum{
+		{Key: "price", Value: "42"},
+		{Key: "_price", Value: "field_1"},
+		{Key: "ingredients", Value: "2"},
+		{Key: "_ingredients", Value: "field_2"},
+		{Key: "ingredients_0_name", Value: "Flour"},
+		{Key: "_ingredients_0_name", Value: "field_2a"},
+		{Key: "ingredients_1_name", Value: "Sugar"},
+		{Key: "_ingredients_1_name", Value: "field_2a"},
+		{Key: "related", Value: `a:2:{i:0;s:2:"12";i:1;s:2:"34";}`},
+		{Key: "_related", Value: "field_3"},
+		{Key: "_edit_lock", Value: "1700000000:1"},
+	}
+
+	fields, consumed := extractACFFields(provider, customMetaData)
+	require.Equal(t, map[string]any{
+		"price": "42",
+		"ingredients": []map[string]any{
+			{"name": "Flour"},
+			{"name": "Sugar"},
+		},
+		"related": []string{"12", "34"},
+	}, fields)
+	require.True(t, consumed["_ingredients_1_name"])
+	require.False(t, consumed["_edit_lock"])
+}
+
+func TestExtractACFFieldsSkipsUnparseableValues(t *testing.T) {
+	t.Parallel()
+	customMetaData := []wpparser.CustomMetaDatum{
+		{Key: "broken", Value: `a:2:{i:0;s:2:"12"`},
+		{Key: "_broken", Value: "field_1"},
+	}
+
+	fields, consumed := extractACFFields(nil, customMetaData)
+	require.Empty(t, fields)
+	require.True(t, consumed["broken"])
+}
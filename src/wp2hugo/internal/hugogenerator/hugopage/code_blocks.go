@@ -0,0 +1,92 @@
+package hugopage
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// The <pre> and <code> elements of the HTML, e.g. a code sample with a literal "[gallery]",
+// the whole <pre> is matched when it wraps a <code>
+var _htmlCodeRegEx = regexp.MustCompile(`(?is)<pre\b[^>]*>.*?</pre>|<code\b[^>]*>.*?</code>`)
+
+// The fenced code blocks, without their indentation, and the inline code of the Markdown
+var _markdownCodeRegEx = regexp.MustCompile("(?ms)^[ \t]*((?:```|~~~).*?^[ \t]*(?:```|~~~)[^\n]*$)|`[^`\n]+`")
+
+// The shortcode, link and media passes must leave the code samples verbatim, so we replace each of them
+// with a placeholder, which none of the passes matches, and put the code back once they are done
+const _codePlaceholder = "WP2HUGOCODE%dEND"
+
+// extractHTMLCode replaces the <pre> and <code> elements of the HTML with placeholders
+func extractHTMLCode(htmlData string) (string, []string) {
+	return extractCode(_htmlCodeRegEx, htmlData)
+}
+
+// The markup of the code samples: the tags of the phrasing elements, e.g. the <span> of a syntax highlighter
+// or the <br> of the classic editor, the comments and the character references, e.g. &lt;
+var _codeMarkupRegEx = regexp.MustCompile(`(?is)</?(?:a|abbr|b|br|code|del|em|i|ins|kbd|mark|pre|q|s|samp|small|span|strong|sub|sup|u|var)(?:\s[^<>]*)?/?>` +
+	`|<!--.*?-->|&(?:[a-z][a-z0-9]*|#[0-9]+|#x[0-9a-f]+);`)
+
+// escapeLiteralCode escapes the "<" and "&" of the code sample which are not markup, e.g. the "<stdio.h>" of
+// "#include <stdio.h>" or the "&copy" of "?a=1&copy=2", which the HTML parser would drop as a tag or decode
+// as an entity. The code is then decoded exactly once, e.g. "&amp;amp;" becomes the literal "&amp;".
+func escapeLiteralCode(sample string) string {
+	var sb strings.Builder
+	sb.Grow(len(sample))
+	escaper := strings.NewReplacer("&", "&amp;", "<", "&lt;")
+	lastIndex := 0
+	for _, match := range _codeMarkupRegEx.FindAllStringIndex(sample, -1) {
+		sb.WriteString(escaper.Replace(sample[lastIndex:match[0]]))
+		sb.WriteString(sample[match[0]:match[1]])
+		lastIndex = match[1]
+	}
+	sb.WriteString(escaper.Replace(sample[lastIndex:]))
+	return sb.String()
+}
+
+// extractMarkdownCode replaces the fenced code blocks and the inline code of the Markdown with placeholders
+func extractMarkdownCode(markdown string) (string, []string) {
+	return extractCode(_markdownCodeRegEx, markdown)
+}
+
+func extractCode(regex *regexp.Regexp, content string) (string, []string) {
+	matches := regex.FindAllStringSubmatchIndex(content, -1)
+	if len(matches) == 0 {
+		return content, nil
+	}
+	code := make([]string, 0, len(matches))
+	var sb strings.Builder
+	sb.Grow(len(content))
+	lastIndex := 0
+	for _, match := range matches {
+		start, end := match[0], match[1]
+		if len(match) > 2 && match[2] >= 0 {
+			// Keep the indentation of the fenced code blocks of the list items
+			start, end = match[2], match[3]
+		}
+		sb.WriteString(content[lastIndex:start])
+		code = append(code, content[start:end])
+		sb.WriteString(fmt.Sprintf(_codePlaceholder, len(code)-1))
+		lastIndex = end
+	}
+	sb.WriteString(content[lastIndex:])
+	return sb.String(), code
+}
+
+// restoreCode puts the code samples set aside by extractHTMLCode or extractMarkdownCode back
+func restoreCode(content string, code []string) string {
+	if len(code) == 0 {
+		return content
+	}
+	oldNew := make([]string, 0, 2*len(code))
+	for i, sample := range code {
+		oldNew = append(oldNew, fmt.Sprintf(_codePlaceholder, i), sample)
+	}
+	return strings.NewReplacer(oldNew...).Replace(content)
+}
+
+// replaceOutsideCode applies the replacement to the Markdown, leaving the code samples verbatim
+func replaceOutsideCode(markdown string, replace func(markdown string) string) string {
+	markdown, code := extractMarkdownCode(markdown)
+	return restoreCode(replace(markdown), code)
+}
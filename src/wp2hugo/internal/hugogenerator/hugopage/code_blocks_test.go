@@ -0,0 +1,85 @@
+package hugopage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodeSurvivesConversion(t *testing.T) {
+	t.Parallel()
+	const htmlContent = `<p>Galleries are added with <code>[gallery ids="1,2"]</code>, e.g. on <a href="https://example.com/about/">about</a>:</p>
+<pre class="wp-block-code"><code>[gallery ids="1,2"]
+[caption id="attachment_3"]&lt;img src="https://example.com/wp-content/uploads/a.jpg" /&gt;[/caption]
+[about](https://example.com/about/)</code></pre>
+<p>Write <code>[about](https://example.com/about/)</code> for the link.</p>`
+	const expected = "Galleries are added with `[gallery ids=\"1,2\"]`, e.g. on [about](/about/):\n\n" +
+		"```\n[gallery ids=\"1,2\"]\n[caption id=\"attachment_3\"]<img src=\"https://example.com/wp-content/uploads/a.jpg\" />[/caption]\n" +
+		"[about](https://example.com/about/)\n```\n\n" +
+		"Write `[about](https://example.com/about/)` for the link."
+	testMarkdownExtractor(t, htmlContent, expected)
+}
+
+func TestCodeEntitiesDecodedOnce(t *testing.T) {
+	t.Parallel()
+	// As stored by the code block of the block editor, and typed in the code editor
+	const htmlContent = `<pre class="wp-block-code"><code>if (a &lt; b &amp;&amp; c) {
+  echo "Tom &amp;amp; Jerry";
+  echo "&lt;div class=&quot;note&quot;&gt;&lt;/div&gt;";
+}</code></pre>
+<pre>#include <stdio.h>
+int main() { return a<b && c; }
+curl 'https://example.com/?a=1&copy=2'</pre>
+<p>Inline <code>a < b && c</code> and <code>&amp;amp;</code></p>`
+	const expected = "```\nif (a < b && c) {\n  echo \"Tom &amp; Jerry\";\n  echo \"<div class=\"note\"></div>\";\n}\n```\n\n" +
+		"```\n#include <stdio.h>\nint main() { return a<b && c; }\ncurl 'https://example.com/?a=1&copy=2'\n```\n\n" +
+		"Inline `a < b && c` and `&amp;`"
+	testMarkdownExtractor(t, htmlContent, expected)
+}
+
+func TestEscapeLiteralCode(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, `<pre class="x">a &lt; b &amp;&amp; &lt;stdio.h> &amp; <span>&lt;br&gt;</span><br/></pre>`,
+		escapeLiteralCode(`<pre class="x">a < b && <stdio.h> &amp; <span>&lt;br&gt;</span><br/></pre>`))
+}
+
+func TestCodeSurvivesShortcodeStripping(t *testing.T) {
+	t.Parallel()
+	const htmlContent = `<p>[su_note]Note[/su_note]</p><pre>[su_note]Kept[/su_note]</pre>`
+	page := newTestPage(t, htmlContent, PageOptions{StripShortcodes: StripAllShortcodes})
+	require.Equal(t, "Note\n\n```\n[su_note]Kept[/su_note]\n```", page.Markdown())
+}
+
+func TestCodeSurvivesSourceIsMarkdown(t *testing.T) {
+	t.Parallel()
+	const source = "A [link](https://example.com/about/).\n\n" +
+		"```\n[gallery ids=\"1\"]\n[about](https://example.com/about/)\n```\n\n" +
+		"Inline `[gallery]` and `[about](https://example.com/about/)`.\n"
+	page := newTestPage(t, source, PageOptions{SourceIsMarkdown: true})
+	require.Equal(t, "A [link](/about/).\n\n"+
+		"```\n[gallery ids=\"1\"]\n[about](https://example.com/about/)\n```\n\n"+
+		"Inline `[gallery]` and `[about](https://example.com/about/)`.\n", page.Markdown())
+}
+
+func TestCodeMediaLinks(t *testing.T) {
+	t.Parallel()
+	page := &Page{
+		metadata: map[string]any{},
+		markdown: "![Summit](/wp-content/uploads/summit.jpg)\n\n" +
+			"```\n![Summit](/wp-content/uploads/summit.jpg)\n{{< figure src=\"/wp-content/uploads/lake.jpg\" >}}\n```\n",
+	}
+	require.Equal(t, []string{"/wp-content/uploads/summit.jpg"}, page.WPMediaLinks())
+
+	page.Replace(map[string]string{"/wp-content/uploads/summit.jpg": "/images/summit.jpg"})
+	require.Equal(t, "![Summit](/images/summit.jpg)\n\n"+
+		"```\n![Summit](/wp-content/uploads/summit.jpg)\n{{< figure src=\"/wp-content/uploads/lake.jpg\" >}}\n```\n", page.Markdown())
+}
+
+func TestExtractMarkdownCode(t *testing.T) {
+	t.Parallel()
+	const markdown = "1. Run:\n\n   ```sh\n   echo `date`\n   ```\n2. Then `ls`.\n"
+	extracted, code := extractMarkdownCode(markdown)
+	require.Equal(t, "1. Run:\n\n   WP2HUGOCODE0END\n2. Then WP2HUGOCODE1END.\n", extracted)
+	require.Equal(t, []string{"```sh\n   echo `date`\n   ```", "`ls`"}, code)
+	require.Equal(t, markdown, restoreCode(extracted, code))
+}
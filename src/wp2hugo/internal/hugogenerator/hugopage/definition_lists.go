@@ -0,0 +1,142 @@
+package hugopage
+
+import (
+	"regexp"
+	"strings"
+
+	md "github.com/JohannesKaufmann/html-to-markdown"
+	"github.com/PuerkitoBio/goquery"
+	"github.com/rs/zerolog/log"
+)
+
+// The converted terms and descriptions are delimited with private use characters, until their list is converted
+const (
+	_termStart        = "\uE000"
+	_termEnd          = "\uE001"
+	_descriptionStart = "\uE002"
+	_descriptionEnd   = "\uE003"
+)
+
+var _definitionListItemRegEx = regexp.MustCompile(`(?s)` + _termStart + `(.*?)` + _termEnd + `|` +
+	_descriptionStart + `(.*?)` + _descriptionEnd)
+
+// Goldmark's definition lists, the continuation lines of a description are indented like its first line
+const _descriptionPrefix = ":   "
+
+type definitionListItem struct {
+	isTerm  bool
+	content string
+}
+
+// convertDefinitionLists converts the <dl> lists into the definition lists of Goldmark's definition list extension,
+// enabled by default in Hugo:
+//
+//	Term
+//	:   Description
+//
+// A list which can't be represented, e.g. with a term made of several paragraphs, becomes a bullet list
+// of its terms, with their descriptions nested
+func convertDefinitionLists() md.Plugin {
+	return func(c *md.Converter) []md.Rule {
+		return []md.Rule{
+			{
+				Filter: []string{"dt", "dd"},
+				Replacement: func(content string, selec *goquery.Selection, opt *md.Options) *string {
+					if selec.Closest("dl").Length() == 0 {
+						// Not in a list, only the content is kept
+						return &content
+					}
+					text := _termStart + content + _termEnd
+					if selec.Is("dd") {
+						text = _descriptionStart + content + _descriptionEnd
+					}
+					return &text
+				},
+			},
+			{
+				Filter: []string{"dl"},
+				Replacement: func(content string, selec *goquery.Selection, opt *md.Options) *string {
+					var items []definitionListItem
+					for _, match := range _definitionListItemRegEx.FindAllStringSubmatch(content, -1) {
+						isTerm := strings.HasPrefix(match[0], _termStart)
+						items = append(items, definitionListItem{
+							isTerm:  isTerm,
+							content: strings.TrimSpace(match[1] + match[2]),
+						})
+					}
+					var text string
+					if isDefinitionList(items) {
+						text = getDefinitionList(items)
+					} else {
+						log.Debug().
+							Int("items", len(items)).
+							Msg("Definition list can't be represented, converting it to a bullet list")
+						text = getDefinitionBulletList(items)
+					}
+					text = "\n\n" + text + "\n\n"
+					return &text
+				},
+			},
+		}
+	}
+}
+
+// isDefinitionList returns true if the items can be a Goldmark definition list:
+// each description follows a term, and the terms are single lines
+func isDefinitionList(items []definitionListItem) bool {
+	if len(items) == 0 || !items[0].isTerm {
+		return false
+	}
+	for _, item := range items {
+		if item.isTerm && (item.content == "" || strings.Contains(item.content, "\n")) {
+			return false
+		}
+	}
+	return true
+}
+
+func getDefinitionList(items []definitionListItem) string {
+	var builder strings.Builder
+	for i, item := range items {
+		if item.isTerm {
+			if i > 0 && !items[i-1].isTerm {
+				// The next group of terms
+				builder.WriteString("\n")
+			}
+			builder.WriteString(item.content + "\n")
+			continue
+		}
+		builder.WriteString(indentDescription(_descriptionPrefix, item.content) + "\n")
+	}
+	return strings.TrimSuffix(builder.String(), "\n")
+}
+
+func getDefinitionBulletList(items []definitionListItem) string {
+	var builder strings.Builder
+	hasTerm := false
+	for _, item := range items {
+		if item.isTerm || !hasTerm {
+			// The descriptions before the first term are not nested
+			hasTerm = hasTerm || item.isTerm
+			builder.WriteString(indentDescription("- ", item.content) + "\n")
+			continue
+		}
+		builder.WriteString(indentDescription("    - ", item.content) + "\n")
+	}
+	return strings.TrimSuffix(builder.String(), "\n")
+}
+
+// indentDescription prefixes the first line of the content, and indents the other lines to the same width
+func indentDescription(prefix string, content string) string {
+	indent := strings.Repeat(" ", len(prefix))
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		switch {
+		case i == 0:
+			lines[i] = prefix + line
+		case line != "":
+			lines[i] = indent + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,51 @@
+package hugopage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefinitionLists(t *testing.T) {
+	t.Parallel()
+	const htmlData = `<p>Glossary</p>
+<dl>
+  <dt>Go</dt>
+  <dt>Golang</dt>
+  <dd>A <strong>programming</strong> language.</dd>
+  <dd>A board game.</dd>
+  <dt>Hugo</dt>
+  <dd><p>A static site generator.</p><p>Written in Go.</p></dd>
+</dl>
+<p>After</p>`
+	const expected = "Glossary\n\n" +
+		"Go\n" +
+		"Golang\n" +
+		":   A **programming** language.\n" +
+		":   A board game.\n\n" +
+		"Hugo\n" +
+		":   A static site generator.\n\n" +
+		"    Written in Go.\n\n" +
+		"After"
+	markdown, err := getMarkdownConverter().ConvertString(htmlData)
+	require.NoError(t, err)
+	require.Equal(t, expected, markdown)
+}
+
+func TestDefinitionListFallback(t *testing.T) {
+	t.Parallel()
+	// A description without a term, and a term made of several paragraphs
+	const htmlData = `<dl><dd>Orphan description</dd><dt><p>Long</p><p>term</p></dt><dd>Its description</dd></dl>`
+	const expected = "- Orphan description\n" +
+		"- Long\n\n" +
+		"  term\n" +
+		"    - Its description"
+	markdown, err := getMarkdownConverter().ConvertString(htmlData)
+	require.NoError(t, err)
+	require.Equal(t, expected, markdown)
+
+	// Outside a list, the elements are converted as usual
+	markdown, err = getMarkdownConverter().ConvertString(`<dd>Stray</dd>`)
+	require.NoError(t, err)
+	require.Equal(t, "Stray", markdown)
+}
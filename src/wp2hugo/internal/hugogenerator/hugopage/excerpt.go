@@ -0,0 +1,108 @@
+package hugopage
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ExcerptFormat decides the format the manual excerpt of the content is emitted in as its summary.
+// The excerpt may contain HTML, e.g. a link, and the themes use the summary in the meta description too.
+type ExcerptFormat string
+
+const (
+	// ExcerptPlain strips the HTML of the excerpt, keeping its text, e.g. for the meta description
+	ExcerptPlain ExcerptFormat = "plain"
+	// ExcerptMarkdown converts the HTML of the excerpt to Markdown, like the content
+	ExcerptMarkdown ExcerptFormat = "markdown"
+	// ExcerptHTML keeps the HTML of the excerpt as is, which Goldmark renders with its unsafe renderer
+	ExcerptHTML ExcerptFormat = "html"
+)
+
+func ParseExcerptFormat(format string) (ExcerptFormat, error) {
+	switch ExcerptFormat(format) {
+	case ExcerptPlain, ExcerptMarkdown, ExcerptHTML:
+		return ExcerptFormat(format), nil
+	case "":
+		return ExcerptPlain, nil
+	default:
+		return "", fmt.Errorf("unknown excerpt format %q, expected one of %s, %s, %s",
+			format, ExcerptPlain, ExcerptMarkdown, ExcerptHTML)
+	}
+}
+
+var (
+	_excerptTagRegEx       = regexp.MustCompile(`<[^>]*>`)
+	_excerptEntityRegEx    = regexp.MustCompile(`&(?:#\d+|#[xX][0-9a-fA-F]+|[a-zA-Z][a-zA-Z0-9]*);`)
+	_excerptShortcodeRegEx = regexp.MustCompile(`\[/?[a-zA-Z][^\]]*\]`)
+)
+
+// The entities which are markup in HTML, kept escaped in the HTML excerpts
+var _markupEntities = []string{"<", ">", "&", `"`}
+
+// SetExcerpt emits the manual excerpt of the content as its summary, in PageOptions.ExcerptFormat.
+// It always replaces the summary split at the "read more" tag, like WordPress prefers the excerpt,
+// so the content with both gets the excerpt as its summary, which it did not before the excerpts were emitted.
+// The links of the Markdown and HTML excerpts are rewritten like the ones of the content.
+func (page *Page) SetExcerpt(excerpt string) error {
+	if strings.TrimSpace(excerpt) == "" {
+		return nil
+	}
+	summary, err := formatExcerpt(excerpt, page.options.ExcerptFormat)
+	if err != nil {
+		return err
+	}
+	if summary == "" {
+		return nil
+	}
+	if page.options.ExcerptFormat != ExcerptHTML {
+		summary = applyTypography(summary, page.options.Typography)
+	}
+	if page.options.ExcerptFormat == ExcerptMarkdown || page.options.ExcerptFormat == ExcerptHTML {
+		summary = page.replaceExcerptLinks(summary)
+	}
+	page.metadata["summary"] = summary
+	return nil
+}
+
+// replaceExcerptLinks rewrites the links of the excerpt like the ones of the content, except the links to
+// the page itself, which are not made bare fragments since the summary is rendered in the lists of posts too
+func (page *Page) replaceExcerptLinks(summary string) string {
+	summary = replaceShortlinks(page.options.PostLinkProvider, page.absoluteURL, summary)
+	return replaceAbsoluteLinksWithPrefixed(page.absoluteURL.Host, page.options.BasePath, page.options.URLPrefix,
+		page.options.AbsoluteMediaLinks, summary)
+}
+
+// formatExcerpt converts the excerpt to the format, its entities are decoded in every format,
+// except the ones which are markup in the HTML excerpts, e.g. &lt;
+func formatExcerpt(excerpt string, format ExcerptFormat) (string, error) {
+	switch format {
+	case ExcerptMarkdown:
+		markdown, err := getMarkdownConverter().ConvertString(excerpt)
+		if err != nil {
+			return "", fmt.Errorf("error converting the excerpt to Markdown: %w", err)
+		}
+		return strings.TrimSpace(markdown), nil
+	case ExcerptHTML:
+		return strings.TrimSpace(_excerptEntityRegEx.ReplaceAllStringFunc(excerpt, func(entity string) string {
+			if decoded := html.UnescapeString(entity); !slices.Contains(_markupEntities, decoded) {
+				return decoded
+			}
+			return entity
+		})), nil
+	default:
+		text := _excerptShortcodeRegEx.ReplaceAllString(excerpt, " ")
+		text = html.UnescapeString(_excerptTagRegEx.ReplaceAllString(text, " "))
+		text = strings.Join(strings.Fields(text), " ")
+		if text == "" {
+			log.Debug().
+				Str("excerpt", excerpt).
+				Msg("Excerpt without text, skipped")
+		}
+		return text, nil
+	}
+}
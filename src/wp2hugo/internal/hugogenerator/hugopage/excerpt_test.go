@@ -0,0 +1,54 @@
+package hugopage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseExcerptFormat(t *testing.T) {
+	t.Parallel()
+	format, err := ParseExcerptFormat("")
+	require.NoError(t, err)
+	require.Equal(t, ExcerptPlain, format)
+
+	format, err = ParseExcerptFormat("markdown")
+	require.NoError(t, err)
+	require.Equal(t, ExcerptMarkdown, format)
+
+	_, err = ParseExcerptFormat("text")
+	require.Error(t, err)
+}
+
+const _excerpt = `<p>Rock &amp; roll &#8211; read <a href="https://example.com/guide/">the guide</a>` +
+	`<img src="https://example.com/a.jpg" alt="A"> about &lt;code&gt;&hellip;</p>`
+
+func TestSetExcerpt(t *testing.T) {
+	t.Parallel()
+	page := newTestPage(t, "<p>Before</p><!--more--><p>After</p>", PageOptions{Typography: TypographyCurly})
+	require.Equal(t, "Before", page.metadata["summary"])
+
+	require.NoError(t, page.SetExcerpt("<p>The \"manual\" <em>excerpt</em></p>"))
+	require.Equal(t, "The “manual” excerpt", page.metadata["summary"], "the excerpt replaces the summary")
+
+	require.NoError(t, page.SetExcerpt("<p> </p>"))
+	require.Equal(t, "The “manual” excerpt", page.metadata["summary"], "an empty excerpt is skipped")
+}
+
+func TestExcerptFormats(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		format   ExcerptFormat
+		expected string
+	}{
+		{ExcerptPlain, "Rock & roll – read the guide about <code>…"},
+		{ExcerptMarkdown, "Rock & roll – read [the guide](/guide/)![A](/a.jpg) about <code>…"},
+		{ExcerptHTML, `<p>Rock &amp; roll – read <a href="/guide/">the guide</a>` +
+			`<img src="/a.jpg" alt="A"> about &lt;code&gt;…</p>`},
+	}
+	for _, testCase := range testCases {
+		page := newTestPage(t, "<p>Content</p>", PageOptions{ExcerptFormat: testCase.format})
+		require.NoError(t, page.SetExcerpt(_excerpt))
+		require.Equal(t, testCase.expected, page.metadata["summary"], testCase.format)
+	}
+}
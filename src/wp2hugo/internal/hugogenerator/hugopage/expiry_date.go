@@ -0,0 +1,79 @@
+package hugopage
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/rs/zerolog/log"
+)
+
+// Hugo stops rendering the content after its "expiryDate"
+const _expiryDateKey = "expiryDate"
+
+// Postmeta of the unpublish date of the Post Expirator plugin, now PublishPress Future, a Unix timestamp
+var DefaultExpiryDateMetaKeys = []string{"_expiration-date"}
+
+// Companion postmeta of the expiration, e.g. "_expiration-date-status", which is "saved" when the expiration is enabled,
+// and "_expiration-date-options", what becomes of the post once expired, which are not emitted as is
+const (
+	_expiryDateCompanionSeparator = "-"
+	_expiryDateStatusSuffix       = "-status"
+	_expiryDateEnabledStatus      = "saved"
+)
+
+// Layouts of the expiration dates which are not Unix timestamps, without a time zone they are read as UTC
+var _expiryDateLayouts = []string{time.RFC3339, time.DateTime, "2006-01-02 15:04", time.DateOnly}
+
+// getExpiryDate returns the expiration date of the post from the first of the keys set in the postmeta,
+// and the postmeta of the expiration, which is not emitted as is.
+// An expiration date which does not parse is kept as is in the front matter.
+func getExpiryDate(keys []string, customMetaData []wpparser.CustomMetaDatum) (*time.Time, map[string]bool) {
+	expiryKeys := make(map[string]bool)
+	values := make(map[string]string, len(customMetaData))
+	for _, metadatum := range customMetaData {
+		values[metadatum.Key] = metadatum.Value
+		for _, key := range keys {
+			if strings.HasPrefix(metadatum.Key, key+_expiryDateCompanionSeparator) {
+				expiryKeys[metadatum.Key] = true
+			}
+		}
+	}
+
+	for _, key := range keys {
+		value, ok := values[key]
+		if !ok || strings.TrimSpace(value) == "" {
+			continue
+		}
+		if status, ok := values[key+_expiryDateStatusSuffix]; ok && status != _expiryDateEnabledStatus {
+			// The expiration was set up, then disabled
+			expiryKeys[key] = true
+			continue
+		}
+		expiryDate, ok := parseExpiryDate(value)
+		if !ok {
+			log.Warn().
+				Str("key", key).
+				Str("value", value).
+				Msg("Expiration date not recognized, keeping it as is")
+			continue
+		}
+		expiryKeys[key] = true
+		return &expiryDate, expiryKeys
+	}
+	return nil, expiryKeys
+}
+
+func parseExpiryDate(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	if timestamp, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(timestamp, 0).UTC(), true
+	}
+	for _, layout := range _expiryDateLayouts {
+		if expiryDate, err := time.Parse(layout, value); err == nil {
+			return expiryDate, true
+		}
+	}
+	return time.Time{}, false
+}
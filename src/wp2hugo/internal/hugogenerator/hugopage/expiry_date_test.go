@@ -0,0 +1,61 @@
+package hugopage
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpiryDate(t *testing.T) {
+	t.Parallel()
+	url1, err := url.Parse("https://example.com/post/")
+	require.NoError(t, err)
+	getExpiryMetadata := func(options PageOptions, customMetaData ...wpparser.CustomMetaDatum) map[string]any {
+		metadata, err := getMetadata(nil, *url1, "author", "Title", nil, nil, false, nil, nil, nil,
+			nil, nil, customMetaData, nil, "1", nil, options)
+		require.NoError(t, err)
+		return metadata
+	}
+	options := PageOptions{ExpiryDateMetaKeys: DefaultExpiryDateMetaKeys}
+
+	// Post Expirator, in the future
+	metadata := getExpiryMetadata(options,
+		wpparser.CustomMetaDatum{Key: "_expiration-date", Value: "32503680000"},
+		wpparser.CustomMetaDatum{Key: "_expiration-date-status", Value: "saved"},
+		wpparser.CustomMetaDatum{Key: "_expiration-date-options", Value: `a:1:{s:10:"expireType";s:5:"draft";}`},
+	)
+	require.Equal(t, "3000-01-01T00:00:00+00:00", metadata["expiryDate"])
+	require.NotContains(t, metadata, "draft")
+	require.NotContains(t, metadata, "_expiration-date")
+	require.NotContains(t, metadata, "_expiration-date-status")
+	require.NotContains(t, metadata, "_expiration-date-options")
+
+	// Already expired
+	expired := wpparser.CustomMetaDatum{Key: "_expiration-date", Value: "978307200"}
+	metadata = getExpiryMetadata(options, expired)
+	require.Equal(t, "2001-01-01T00:00:00+00:00", metadata["expiryDate"])
+	metadata = getExpiryMetadata(PageOptions{ExpiryDateMetaKeys: DefaultExpiryDateMetaKeys, ExpiredAsDraft: true}, expired)
+	require.NotContains(t, metadata, "expiryDate")
+	require.Equal(t, "true", metadata["draft"])
+
+	// Disabled expiration
+	metadata = getExpiryMetadata(options, expired, wpparser.CustomMetaDatum{Key: "_expiration-date-status", Value: ""})
+	require.NotContains(t, metadata, "expiryDate")
+	require.NotContains(t, metadata, "_expiration-date")
+
+	// Overridden keys, with a date
+	metadata = getExpiryMetadata(PageOptions{ExpiryDateMetaKeys: []string{"unpublish_on"}},
+		wpparser.CustomMetaDatum{Key: "unpublish_on", Value: "2999-06-30 12:30"})
+	require.Equal(t, "2999-06-30T12:30:00+00:00", metadata["expiryDate"])
+	require.NotContains(t, metadata, "unpublish_on")
+
+	// Unrecognized dates are kept as is, and nothing is read without keys
+	metadata = getExpiryMetadata(options, wpparser.CustomMetaDatum{Key: "_expiration-date", Value: "next week"})
+	require.NotContains(t, metadata, "expiryDate")
+	require.Equal(t, "next week", metadata["_expiration-date"])
+	metadata = getExpiryMetadata(PageOptions{}, expired)
+	require.NotContains(t, metadata, "expiryDate")
+	require.Equal(t, "978307200", metadata["_expiration-date"])
+}
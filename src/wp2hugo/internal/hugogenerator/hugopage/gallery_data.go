@@ -0,0 +1,134 @@
+package hugopage
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+)
+
+// GalleryOutput decides how the galleries of the content are emitted, e.g. for a theme rendering them
+// as lightbox galleries from structured data
+type GalleryOutput string
+
+const (
+	// GalleryOutputShortcode emits them inline, as figure shortcodes nested in a gallery shortcode
+	GalleryOutputShortcode GalleryOutput = "shortcode"
+	// GalleryOutputFrontMatter emits them as the galleries front matter, a gallery-data shortcode in their place
+	GalleryOutputFrontMatter GalleryOutput = "front-matter"
+	// GalleryOutputData emits them into a data file, data/galleries/<key>.yaml,
+	// keyed by the galleries_data front matter, a gallery-data shortcode in their place
+	GalleryOutputData GalleryOutput = "data"
+)
+
+func ParseGalleryOutput(output string) (GalleryOutput, error) {
+	switch GalleryOutput(output) {
+	case GalleryOutputShortcode, GalleryOutputFrontMatter, GalleryOutputData:
+		return GalleryOutput(output), nil
+	case "":
+		return GalleryOutputShortcode, nil
+	default:
+		return "", fmt.Errorf("unknown gallery output %q, expected one of %s, %s, %s",
+			output, GalleryOutputShortcode, GalleryOutputFrontMatter, GalleryOutputData)
+	}
+}
+
+const (
+	_galleriesKey     = "galleries"
+	_galleriesDataKey = "galleries_data"
+	// GalleryDataShortCodeName renders the gallery of the front matter or data file at its index,
+	// e.g. {{< gallery-data index="0" >}}
+	GalleryDataShortCodeName = "gallery-data"
+)
+
+// Gallery is a gallery of the content, emitted as structured data with GalleryOutputFrontMatter or GalleryOutputData
+type Gallery struct {
+	Columns int            `yaml:"columns"`
+	Images  []GalleryImage `yaml:"images"`
+}
+
+// GalleryImage is an image of a Gallery, its src is a local path once the media are downloaded
+type GalleryImage struct {
+	Src     string `yaml:"src"`
+	Alt     string `yaml:"alt"`
+	Caption string `yaml:"caption,omitempty"`
+	Title   string `yaml:"title,omitempty"`
+}
+
+// The galleries converted from the [gallery] shortcodes, and the Gutenberg and Jetpack gallery blocks
+var (
+	_hugoGalleryRegEx       = regexp.MustCompile(`(?s){{< gallery cols="(\d+)" >}}(.*?){{< /gallery >}}`)
+	_hugoGalleryFigureRegEx = regexp.MustCompile(`{{< figure (.*?) >}}`)
+	_shortcodeParamRegEx    = regexp.MustCompile(`(\w+)="([^"]*)"`)
+)
+
+// extractGalleries replaces the galleries of the Markdown with gallery-data shortcodes, keeping their images
+// for the front matter or the data file, with GalleryOutputFrontMatter or GalleryOutputData
+func (page *Page) extractGalleries(markdown string) string {
+	if page.options.GalleryOutput != GalleryOutputFrontMatter && page.options.GalleryOutput != GalleryOutputData {
+		return markdown
+	}
+	markdown = replaceAllStringSubmatchFunc(_hugoGalleryRegEx, markdown, func(groups []string) string {
+		columns, err := strconv.Atoi(groups[1])
+		if err != nil {
+			return groups[0]
+		}
+		gallery := Gallery{Columns: columns, Images: make([]GalleryImage, 0)}
+		for _, figure := range _hugoGalleryFigureRegEx.FindAllStringSubmatch(groups[2], -1) {
+			var image GalleryImage
+			for _, param := range _shortcodeParamRegEx.FindAllStringSubmatch(figure[1], -1) {
+				value := html.UnescapeString(param[2])
+				switch param[1] {
+				case "src":
+					image.Src = value
+				case "alt":
+					image.Alt = value
+				case "caption":
+					image.Caption = value
+				case "title":
+					image.Title = value
+				}
+			}
+			if image.Src != "" {
+				gallery.Images = append(gallery.Images, image)
+			}
+		}
+		page.galleries = append(page.galleries, gallery)
+		return fmt.Sprintf(`{{< %s index="%d" >}}`, GalleryDataShortCodeName, len(page.galleries)-1)
+	})
+	if len(page.galleries) > 0 && page.options.GalleryOutput == GalleryOutputFrontMatter {
+		page.metadata[_galleriesKey] = page.galleries
+	}
+	return markdown
+}
+
+// Galleries returns the galleries of the content, with GalleryOutputFrontMatter or GalleryOutputData
+func (page *Page) Galleries() []Gallery {
+	return page.galleries
+}
+
+// SetGalleriesDataKey emits the key of the data file the galleries are written to with GalleryOutputData,
+// e.g. "42" for data/galleries/42.yaml
+func (page *Page) SetGalleriesDataKey(key string) {
+	page.metadata[_galleriesDataKey] = key
+}
+
+func (page *Page) getGalleryImageLinks() []string {
+	var links []string
+	for _, gallery := range page.galleries {
+		for _, image := range gallery.Images {
+			links = append(links, image.Src)
+		}
+	}
+	return links
+}
+
+func (page *Page) replaceGalleryImageLinks(replacementMap map[string]string) {
+	for _, gallery := range page.galleries {
+		for i, image := range gallery.Images {
+			if replacement, ok := replacementMap[image.Src]; ok {
+				gallery.Images[i].Src = replacement
+			}
+		}
+	}
+}
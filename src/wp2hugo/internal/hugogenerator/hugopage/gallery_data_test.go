@@ -0,0 +1,67 @@
+package hugopage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGalleryOutput(t *testing.T) {
+	t.Parallel()
+	output, err := ParseGalleryOutput("")
+	require.NoError(t, err)
+	require.Equal(t, GalleryOutputShortcode, output)
+	output, err = ParseGalleryOutput("front-matter")
+	require.NoError(t, err)
+	require.Equal(t, GalleryOutputFrontMatter, output)
+	_, err = ParseGalleryOutput("lightbox")
+	require.Error(t, err)
+}
+
+func TestExtractGalleries(t *testing.T) {
+	t.Parallel()
+	const markdown = `Before<br>{{< gallery cols="2" >}}<br>` +
+		`{{< figure src="/wp-content/uploads/2021/05/dunes.jpg" title="Dunes" alt="Dunes" >}}<br>` +
+		`{{< figure src="/wp-content/uploads/2021/05/oasis.jpg" alt="The &#34;oasis&#34;" caption="At dusk" >}}<br>` +
+		`{{< /gallery >}}<br>After`
+	expected := []Gallery{{Columns: 2, Images: []GalleryImage{
+		{Src: "/wp-content/uploads/2021/05/dunes.jpg", Alt: "Dunes", Title: "Dunes"},
+		{Src: "/wp-content/uploads/2021/05/oasis.jpg", Alt: `The "oasis"`, Caption: "At dusk"},
+	}}}
+
+	page := &Page{metadata: map[string]any{}, options: PageOptions{GalleryOutput: GalleryOutputFrontMatter}}
+	require.Equal(t, `Before<br>{{< gallery-data index="0" >}}<br>After`, page.extractGalleries(markdown))
+	require.Equal(t, expected, page.Galleries())
+	require.Equal(t, expected, page.metadata[_galleriesKey])
+	require.Equal(t, []string{"/wp-content/uploads/2021/05/dunes.jpg", "/wp-content/uploads/2021/05/oasis.jpg"},
+		page.getGalleryImageLinks())
+	page.replaceGalleryImageLinks(map[string]string{"/wp-content/uploads/2021/05/dunes.jpg": "/images/dunes.jpg"})
+	require.Equal(t, "/images/dunes.jpg", page.Galleries()[0].Images[0].Src)
+
+	// With a data file, the front matter only has its key
+	page = &Page{metadata: map[string]any{}, options: PageOptions{GalleryOutput: GalleryOutputData}}
+	require.Equal(t, `Before<br>{{< gallery-data index="0" >}}<br>After`, page.extractGalleries(markdown))
+	require.NotContains(t, page.metadata, _galleriesKey)
+	page.SetGalleriesDataKey("42")
+	require.Equal(t, "42", page.metadata[_galleriesDataKey])
+
+	// By default, the galleries stay inline
+	page = &Page{metadata: map[string]any{}}
+	require.Equal(t, markdown, page.extractGalleries(markdown))
+	require.Empty(t, page.Galleries())
+}
+
+func TestGalleryFrontMatter(t *testing.T) {
+	t.Parallel()
+	const htmlContent = `<p>The dunes</p>
+<!-- wp:gallery {"columns":2} -->
+<figure class="wp-block-gallery columns-2"><ul class="blocks-gallery-grid">
+<li class="blocks-gallery-item"><figure><img src="https://example.org/wp-content/uploads/2021/05/dunes.jpg" alt="Dunes"/></figure></li>
+</ul></figure>
+<!-- /wp:gallery -->`
+	page := newTestPageAt(t, "https://example.org/trip/", htmlContent, PageOptions{GalleryOutput: GalleryOutputFrontMatter})
+	require.Contains(t, page.Markdown(), `{{< gallery-data index="0" >}}`)
+	require.NotContains(t, page.Markdown(), "{{< figure")
+	require.Len(t, page.Galleries(), 1)
+	require.Contains(t, page.WPStaticLinks(), page.Galleries()[0].Images[0].Src)
+}
@@ -0,0 +1,31 @@
+package hugopage
+
+import (
+	"fmt"
+	"html"
+
+	md "github.com/JohannesKaufmann/html-to-markdown"
+	"github.com/PuerkitoBio/goquery"
+)
+
+// HTML comments are dropped by the markdown converter, so we wrap the comments we want
+// to keep in a custom tag and convert that tag back to an HTML comment during the conversion
+const _htmlCommentCustomTag = "wp2hugo-comment"
+
+func newHTMLComment(message string) string {
+	return fmt.Sprintf("<%s>%s</%s>", _htmlCommentCustomTag, html.EscapeString(message), _htmlCommentCustomTag)
+}
+
+func convertCustomTagToHTMLComment() md.Plugin {
+	return func(c *md.Converter) []md.Rule {
+		return []md.Rule{
+			{
+				Filter: []string{_htmlCommentCustomTag},
+				Replacement: func(content string, selec *goquery.Selection, opt *md.Options) *string {
+					text := fmt.Sprintf("<!-- wp2hugo: %s -->", selec.Text())
+					return &text
+				},
+			},
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package hugopage
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// setHTML keeps the HTML content as exported, for PageOptions.KeepHTML, with its links rewritten
+// like the ones of the Markdown, so that both formats point to the same pages and media
+func (page *Page) setHTML(htmlContent string) {
+	htmlContent, code := extractHTMLCode(htmlContent)
+	htmlContent = replaceAbsoluteLinksWithPrefixed(page.absoluteURL.Host, page.options.BasePath, page.options.URLPrefix,
+		page.options.AbsoluteMediaLinks, htmlContent)
+	page.html = restoreCode(htmlContent, code)
+}
+
+// HTML returns the HTML content of the page kept with PageOptions.KeepHTML, empty otherwise
+func (page *Page) HTML() string {
+	return page.html
+}
+
+// ReplaceHTML replaces the HTML content kept with PageOptions.KeepHTML, like ReplaceMarkdown for the Markdown
+func (page *Page) ReplaceHTML(replace func(htmlContent string) string) {
+	if page.html == "" {
+		return
+	}
+	page.html = replace(page.html)
+}
+
+// ReplaceHTMLOutsideCode replaces the HTML content like ReplaceHTML, leaving the <pre> and <code> elements verbatim
+func (page *Page) ReplaceHTMLOutsideCode(replace func(htmlContent string) string) {
+	page.ReplaceHTML(func(htmlContent string) string {
+		htmlContent, code := extractHTMLCode(htmlContent)
+		return restoreCode(replace(htmlContent), code)
+	})
+}
+
+// HTMLMediaLinks returns the image links of the HTML content kept with PageOptions.KeepHTML, including the
+// candidates of the srcset attributes, which the Markdown does not have, e.g. the resized images of WordPress.
+// The links of the code samples are left out.
+func (page *Page) HTMLMediaLinks() []string {
+	if page.html == "" {
+		return nil
+	}
+	htmlContent, _ := extractHTMLCode(page.html)
+	document, err := goquery.NewDocumentFromReader(strings.NewReader(htmlContent))
+	if err != nil {
+		return nil
+	}
+	var links []string
+	add := func(link string) {
+		if link = strings.TrimSpace(link); link != "" && !slices.Contains(links, link) {
+			links = append(links, link)
+		}
+	}
+	document.Find("img, source").Each(func(_ int, selection *goquery.Selection) {
+		add(selection.AttrOr("src", ""))
+		// E.g. srcset="/a-300x200.jpg 300w, /a-1024x683.jpg 1024w"
+		for candidate := range strings.SplitSeq(selection.AttrOr("srcset", ""), ",") {
+			if fields := strings.Fields(candidate); len(fields) > 0 {
+				add(fields[0])
+			}
+		}
+	})
+	return links
+}
+
+// replaceHTMLLinks replaces the links of the HTML content, e.g. with the ones of the downloaded media
+func (page *Page) replaceHTMLLinks(replacer *strings.Replacer) {
+	if page.html == "" {
+		return
+	}
+	htmlContent, code := extractHTMLCode(page.html)
+	page.html = restoreCode(replacer.Replace(htmlContent), code)
+}
+
+// WriteHTML writes the page as a Hugo content file in the HTML format: the same front matter
+// as the Markdown, followed by the HTML content kept with PageOptions.KeepHTML
+func (page Page) WriteHTML(w io.Writer) error {
+	if err := page.writeMetadata(w); err != nil {
+		return err
+	}
+	if _, err := w.Write([]byte(strings.TrimRight(page.html, "\n") + "\n")); err != nil {
+		return fmt.Errorf("error writing to page file: %w", err)
+	}
+	return nil
+}
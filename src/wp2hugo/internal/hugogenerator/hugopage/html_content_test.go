@@ -0,0 +1,45 @@
+package hugopage
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeepHTML(t *testing.T) {
+	t.Parallel()
+	const htmlContent = `<p><a href="https://example.com/about/">About</a> <img src="https://example.com/wp-content/uploads/a.jpg" alt="A" /></p>
+<pre>&lt;img src="https://example.com/wp-content/uploads/a.jpg" /&gt;</pre>`
+	page := newTestPage(t, htmlContent, PageOptions{})
+	require.Empty(t, page.HTML())
+
+	page = newTestPage(t, htmlContent, PageOptions{KeepHTML: true})
+	// The media of both formats are replaced alike, the code samples are kept verbatim
+	page.Replace(map[string]string{"/wp-content/uploads/a.jpg": "/images/a.jpg"})
+	require.Contains(t, page.Markdown(), "![A](/images/a.jpg)")
+	require.Equal(t, `<p><a href="/about/">About</a> <img src="/images/a.jpg" alt="A" /></p>
+<pre>&lt;img src="https://example.com/wp-content/uploads/a.jpg" /&gt;</pre>`, page.HTML())
+
+	var buf bytes.Buffer
+	require.NoError(t, page.WriteHTML(&buf))
+	require.Contains(t, buf.String(), "title: Title\n")
+	require.Contains(t, buf.String(), "\n---\n"+page.HTML()+"\n")
+}
+
+func TestHTMLMediaLinks(t *testing.T) {
+	t.Parallel()
+	const htmlContent = `<p><img src="https://example.com/wp-content/uploads/a.jpg" alt="A"
+srcset="https://example.com/wp-content/uploads/a-300x200.jpg 300w, https://example.com/wp-content/uploads/a.jpg 1024w" /></p>
+<pre>&lt;img src="https://example.com/wp-content/uploads/b.jpg" /&gt;</pre>`
+	page := newTestPage(t, htmlContent, PageOptions{KeepHTML: true})
+	require.Equal(t, []string{"/wp-content/uploads/a.jpg", "/wp-content/uploads/a-300x200.jpg"}, page.HTMLMediaLinks())
+	// The Markdown has no srcset
+	require.NotContains(t, page.WPMediaLinks(), "/wp-content/uploads/a-300x200.jpg")
+
+	page.ReplaceHTML(func(htmlContent string) string {
+		return strings.ReplaceAll(htmlContent, "<p>", `<p class="lead">`)
+	})
+	require.True(t, strings.HasPrefix(page.HTML(), `<p class="lead"><img`))
+}
@@ -17,13 +17,21 @@ var (
 	gistMarkdown = regexp.MustCompile(`\\\[gist .*\]`)
 )
 
+// The <hr> and the Gutenberg separator blocks become "---" thematic breaks, between blank lines
+// so that Goldmark does not take the line above for a setext heading
+const _thematicBreak = "---"
+
 func getMarkdownConverter() *md.Converter {
-	converter := md.NewConverter("", true, nil)
+	converter := md.NewConverter("", true, &md.Options{HorizontalRule: _thematicBreak})
 	converter.Use(getYouTubeForHugoConverter())
 	converter.Use(getGoogleMapsEmbedForHugoConverter())
 	converter.Use(convertCustomBRToNewline())
 	converter.Use(convertBrToNewline())
 	converter.Use(convertGistURLsToShortcodes())
+	converter.Use(convertCustomTagToHTMLComment())
+	converter.Use(convertQuoteCitations())
+	converter.Use(convertDefinitionLists())
+	converter.Use(convertTaskLists())
 	return converter
 }
 
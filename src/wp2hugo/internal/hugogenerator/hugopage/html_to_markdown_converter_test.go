@@ -1,6 +1,7 @@
 package hugopage
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -59,3 +60,27 @@ func TestMarkdownGist(t *testing.T) {
 	require.NoError(t, err)
 	require.Contains(t, result, `{{< gist lawrencegripper 6bee7de123bea1936359 >}}`)
 }
+
+func TestSeparator(t *testing.T) {
+	t.Parallel()
+	testMarkdownExtractor(t, "<p>One</p><hr /><p>Two</p>", "One\n\n---\n\nTwo")
+	testMarkdownExtractor(t, "<p>One<hr>Two</p>", "One\n\n---\n\nTwo")
+	testMarkdownExtractor(t, `<!-- wp:paragraph --><p>One</p><!-- /wp:paragraph -->
+<!-- wp:separator {"className":"is-style-dots"} -->
+<hr class="wp-block-separator has-alpha-channel-opacity is-style-dots"/>
+<!-- /wp:separator -->
+<!-- wp:paragraph --><p>Two</p><!-- /wp:paragraph -->`, "One\n\n---\n\nTwo")
+}
+
+func TestSeparatorAtTheStart(t *testing.T) {
+	t.Parallel()
+	const htmlContent = `<!-- wp:separator --><hr class="wp-block-separator"/><!-- /wp:separator --><p>One</p>`
+	page := newTestPage(t, htmlContent, PageOptions{})
+	require.Equal(t, "---\n\nOne", page.Markdown())
+
+	// Apart from the closing delimiter of the front matter
+	var buf bytes.Buffer
+	require.NoError(t, page.Write(&buf))
+	require.Contains(t, buf.String(), "\n---\n\n---\n\nOne\n")
+	require.Equal(t, 2, bytes.Count(buf.Bytes(), []byte("\n---\n")))
+}
@@ -3,10 +3,12 @@ package hugopage
 import (
 	"fmt"
 	"io"
+	"maps"
 	"net/url"
 	"regexp"
 	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -16,14 +18,13 @@ import (
 	"github.com/gomarkdown/markdown"
 	"github.com/gomarkdown/markdown/ast"
 	"github.com/gomarkdown/markdown/parser"
-	"github.com/leeqvip/gophp"
 	"github.com/mmcdole/gofeed/rss"
 	"github.com/rs/zerolog/log"
 )
 
 const (
 	// Seems to be undocumented, but this is the date format used by Hugo
-	_hugoDateFormat = "2006-01-02T15:04:05-07:00"
+	DateFormat = "2006-01-02T15:04:05-07:00"
 
 	CategoryName = "categories"
 	TagName      = "tags"
@@ -36,6 +37,129 @@ type Page struct {
 
 	metadata map[string]any
 	markdown string
+	// The HTML content as exported, with PageOptions.KeepHTML
+	html string
+
+	// Names of the shortcodes removed with PageOptions.StripShortcodes
+	strippedShortcodes []string
+
+	// Galleries of the content, emitted as structured data with PageOptions.GalleryOutput
+	galleries []Gallery
+
+	options PageOptions
+}
+
+// PageOptions controls the optional conversion behaviors of a page
+type PageOptions struct {
+	// Wrap the content of Custom HTML blocks in the "rawhtml" shortcode,
+	// needed when Goldmark's unsafe rendering is disabled in the Hugo config
+	WrapCustomHTMLInShortcode bool
+
+	// If set, the WordPress post ID is also written to this front matter key,
+	// for joining the migrated content with external systems (analytics, comment imports, etc.)
+	WordPressIDKey string
+
+	// Pair the Advanced Custom Fields postmeta entries with their "_fieldname" companions
+	// and emit them as decoded front matter params.
+	// ACFFieldProvider is optional, it resolves the field types from the exported field definitions.
+	ExtractACFFields bool
+	ACFFieldProvider ACFFieldProvider
+
+	// "lastmod" is not emitted when the last modification is within this duration of the publish date
+	LastModTolerance time.Duration
+
+	// URLPrefix namespaces the generated URLs, e.g. "/blog", for sites migrated into
+	// a subpath of a larger Hugo site. Media links are left at the root of the static dir.
+	URLPrefix string
+
+	// The featured image is emitted in the "images" front matter for Open Graph, unless OmitOpenGraphImages is set.
+	// OpenGraphContentImage adds the first image of the content as well.
+	OmitOpenGraphImages   bool
+	OpenGraphContentImage bool
+
+	// LocalMedia is set when the media are downloaded into the site, local paths are used then
+	LocalMedia bool
+	// AbsoluteMediaLinks keeps the media links absolute, pointing to the WordPress site,
+	// set by the generator when the media are never downloaded
+	AbsoluteMediaLinks bool
+	// KeepHTML keeps the HTML content as exported too, for writing it with WriteHTML,
+	// set by the generator when the content is emitted in the HTML format
+	KeepHTML bool
+	// BasePath is the path of the blog under its host, e.g. "/blog" when WordPress lives in a subdirectory,
+	// set by the generator from the base blog URL of the export. Its media are under BasePath + "/wp-content/".
+	BasePath string
+
+	// TaxonomyKeys renames the taxonomy front matter keys, e.g. "tags" to "keywords",
+	// to match the taxonomies of the Hugo config. Keys are CategoryName, TagName or a custom taxonomy name.
+	TaxonomyKeys map[string]string
+
+	// Insert "<!-- wp2hugo: ... -->" comments where the conversion degraded the content,
+	// e.g. unhandled shortcodes, flattened layout blocks or media which failed to download
+	AnnotateIssues bool
+
+	// SourceIsMarkdown treats the content as Markdown, e.g. stored by Jetpack Markdown or WP-Markdown,
+	// instead of converting it from HTML. Only the shortcodes and links are rewritten.
+	SourceIsMarkdown bool
+
+	// StripShortcodes removes the unhandled shortcodes, or all of them, keeping the text they enclose
+	StripShortcodes ShortcodeStripping
+
+	// SEOTitleSeparator replaces the %%sep%% variable of the Yoast SEO titles, %%sitename%% and %%sitedesc%%
+	// are replaced with SiteTitle and SiteDescription, set by the generator from the export
+	SEOTitleSeparator string
+	SiteTitle         string
+	SiteDescription   string
+
+	// NoIndexExclusion decides how the content noindexed with the SEO plugins is excluded from the site,
+	// from the sitemap by default
+	NoIndexExclusion NoIndexExclusion
+
+	// Typography straightens or curls the quotes, dashes and ellipses of the content, they are kept by default
+	Typography Typography
+
+	// ExcerptFormat decides whether the manual excerpts are emitted as the summary in plain text,
+	// the default, in Markdown or in HTML
+	ExcerptFormat ExcerptFormat
+
+	// TitleNormalization trims the titles and collapses their whitespace, or title-cases them as well,
+	// they are kept by default
+	TitleNormalization TitleNormalization
+
+	// PlaylistShortcode emits the [playlist] shortcodes as this Hugo shortcode, e.g. "playlist",
+	// with a nested <PlaylistShortcode>-track shortcode per track, instead of an HTML5 playlist
+	PlaylistShortcode string
+
+	// GalleryOutput emits the galleries as structured data, in the front matter or a data file,
+	// instead of inline gallery shortcodes
+	GalleryOutput GalleryOutput
+
+	// StripEmptyParagraphs removes the paragraphs made of non-breaking spaces only, e.g. "&nbsp;",
+	// which are left in the content written in Markdown and in the raw HTML
+	StripEmptyParagraphs bool
+
+	// PreserveLinkAttributes keeps the links with a meaningful rel or target attribute, e.g. rel="sponsored"
+	// or target="_blank", as raw HTML links instead of converting them to Markdown links, which have no attributes
+	PreserveLinkAttributes bool
+
+	// ExpiryDateMetaKeys are the postmeta of the unpublish date, e.g. DefaultExpiryDateMetaKeys of Post Expirator,
+	// emitted as the "expiryDate" front matter, after which Hugo stops rendering the content. Disabled if empty.
+	// ExpiredAsDraft emits the content whose expiry date has already passed as a draft instead.
+	ExpiryDateMetaKeys []string
+	ExpiredAsDraft     bool
+
+	// SeriesTaxonomy is the custom taxonomy of the series, e.g. "series" with the Organize Series plugin,
+	// emitted as the "series" front matter with the "series_weight" of the post in the series. Disabled if empty.
+	SeriesTaxonomy string
+
+	// WooCommerceProduct is set by the generator for the WooCommerce products, whose price, SKU, gallery
+	// and attributes are then decoded from postmeta into front matter.
+	// ProductVariationProvider is optional, it returns the variations of the variable products.
+	WooCommerceProduct       bool
+	ProductVariationProvider ProductVariationProvider
+
+	// PostLinkProvider is set by the generator, it resolves the shortlinks of the content, e.g. /?p=123,
+	// to the URL of the migrated content
+	PostLinkProvider PostLinkProvider
 }
 
 const _WordPressMoreTag = "<!--more-->"
@@ -58,14 +182,20 @@ var (
 	// Replace multiple consecutive newlines with just two newlines
 	_moreThanTwoNewlines = regexp.MustCompile(`\n{3,}`)
 
-	// Catch \nspace\n
-	_spaceSurroundedByNewlines = regexp.MustCompile(`\n[ \t]+\n`)
+	// A thematic break made of dashes on the first line, e.g. "---" or "- - -"
+	_leadingThematicBreakRegEx = regexp.MustCompile(`^ {0,3}(?:-[ \t]*){3,}(?:\n|$)`)
 
 	// Workaround for https://github.com/ashishb/wp2hugo/issues/11
 	// The html-to-markdown library inserts an extra space before links when they directly
 	// follow certain punctuation (e.g. `"<a>`, `(<a>`).
 	// Ref: https://github.com/JohannesKaufmann/html-to-markdown/issues/95
 	_extraSpaceBeforeLinkRegex = regexp.MustCompile(`([\"'(]) \[`)
+
+	// E.g. "  12. item"
+	_orderedListItemRegex = regexp.MustCompile(`^([ \t]*)(\d+)\.(\s)`)
+
+	// Markdown link to a footnote anchor, e.g. "[1](#footnote-1)"
+	_footnoteLinkRegex = regexp.MustCompile(`\[\S+?\]\(#([^)\s]+)\)`)
 )
 
 // Extracts "src" from Hugo figure shortcode
@@ -76,18 +206,26 @@ var _hugoFigureLinks = regexp.MustCompile(`{{< figure.*?src="([^\"]+?)".*? >}}`)
 // {{< audio src="/wp-content/uploads/2023/01/session.mp3" alt="" >}}
 var _hugoAudioLinks = regexp.MustCompile(`{{< audio.*?src="([^\"]+?)".*? >}}`)
 
+// Extracts "src" from the tracks of the playlists, see replacePlaylistShortCode
+// <li><audio controls preload="metadata" src="/wp-content/uploads/2023/01/a.mp3"></audio> A</li>
+// {{< playlist-track src="/wp-content/uploads/2023/01/a.mp3" title="A" >}}
+var (
+	_htmlPlaylistTrackLinks = regexp.MustCompile(`<(?:audio|video) [^>]*?src="([^"]+?)"`)
+	_hugoPlaylistTrackLinks = regexp.MustCompile(`{{< [\w-]+` + _playlistTrackShortcodeSuffix + ` .*?src="([^"]+?)".*? >}}`)
+)
+
 // {{< parallaxblur src="/wp-content/uploads/2018/12/bora%5Fbora%5F5%5Fresized.jpg" >}}
 var _hugoParallaxBlurLinks = regexp.MustCompile(`{{< parallaxblur.*?src="([^\"]+?)".*? >}}`)
 
 func NewPage(provider ImageURLProvider, pageURL url.URL, author string, title string, publishDate *time.Time,
-	isDraft bool, categories []string, tags []string, attachments []wpparser.AttachmentInfo,
+	lastModifiedDate *time.Time, isDraft bool, categories []string, tags []string, attachments []wpparser.AttachmentInfo,
 	footnotes []wpparser.Footnote,
 	htmlContent string, guid *rss.GUID, featuredImageID *string, postFormat *string,
 	customMetaData []wpparser.CustomMetaDatum, taxinomies []wpparser.TaxonomyInfo,
-	postID string, parentPostID *string,
+	postID string, parentPostID *string, options PageOptions,
 ) (*Page, error) {
-	metadata, err := getMetadata(provider, pageURL, author, title, publishDate, isDraft, categories, tags, guid,
-		featuredImageID, postFormat, customMetaData, taxinomies, postID, parentPostID)
+	metadata, err := getMetadata(provider, pageURL, author, title, publishDate, lastModifiedDate, isDraft, categories, tags, guid,
+		featuredImageID, postFormat, customMetaData, taxinomies, postID, parentPostID, options)
 	if err != nil {
 		return nil, err
 	}
@@ -95,6 +233,7 @@ func NewPage(provider ImageURLProvider, pageURL url.URL, author string, title st
 		absoluteURL: pageURL,
 		metadata:    metadata,
 		attachments: attachments,
+		options:     options,
 	}
 	// htmlContent is the HTML content of the page that will be
 	// transformed to Markdown
@@ -103,16 +242,95 @@ func NewPage(provider ImageURLProvider, pageURL url.URL, author string, title st
 		return nil, err
 	}
 	page.markdown = *markdown
+	if options.KeepHTML {
+		page.setHTML(htmlContent)
+	}
+	page.setOpenGraphImages()
 	return &page, nil
 }
 
+// SetSlug emits the slug front matter, e.g. when the filename of the page is not its slug
+func (page *Page) SetSlug(slug string) {
+	if decoded, err := url.PathUnescape(slug); err == nil {
+		slug = decoded
+	}
+	page.metadata["slug"] = slug
+}
+
+// AddAliases adds URL paths redirected to the page by Hugo, e.g. the attachment pages of its media,
+// to its aliases front matter
+func (page *Page) AddAliases(aliases []string) {
+	existing, _ := page.metadata["aliases"].([]string)
+	page.metadata["aliases"] = append(existing, aliases...)
+}
+
+// SetLanguage emits the language of the page, e.g. "fr", for the theme to set the lang attribute of its HTML
+func (page *Page) SetLanguage(language string) {
+	page.metadata["lang"] = language
+}
+
+// SetCommentStatus emits whether the comments are open, e.g. for the theme to render a comment widget,
+// and the number of approved comments
+func (page *Page) SetCommentStatus(open bool, count int) {
+	page.metadata["comments"] = open
+	page.metadata["comment_count"] = count
+}
+
 func (page *Page) Markdown() string {
 	return page.markdown
 }
 
+// ReplaceMarkdown replaces the converted Markdown, the front matter is left as is
+func (page *Page) ReplaceMarkdown(replace func(markdown string) string) {
+	page.markdown = replace(page.markdown)
+}
+
+// ReplaceMarkdownOutsideCode replaces the converted Markdown like ReplaceMarkdown, leaving the code samples verbatim
+func (page *Page) ReplaceMarkdownOutsideCode(replace func(markdown string) string) {
+	page.markdown = replaceOutsideCode(page.markdown, replace)
+}
+
+// ReplaceMetadata adjusts the front matter in place, the Markdown is left as is
+func (page *Page) ReplaceMetadata(replace func(metadata map[string]any) error) error {
+	return replace(page.metadata)
+}
+
 func (page *Page) Replace(replacementMap map[string]string) {
-	for old, new := range replacementMap {
-		page.markdown = strings.ReplaceAll(page.markdown, old, new)
+	if len(replacementMap) == 0 {
+		return
+	}
+	// Longest keys first so that a key which is a prefix of another one does not win,
+	// and the whole markdown is scanned once instead of once per key.
+	keys := make([]string, 0, len(replacementMap))
+	for old := range replacementMap {
+		keys = append(keys, old)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) > len(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+	oldNew := make([]string, 0, 2*len(keys))
+	for _, old := range keys {
+		oldNew = append(oldNew, old, replacementMap[old])
+	}
+	replacer := strings.NewReplacer(oldNew...)
+	page.markdown = replaceOutsideCode(page.markdown, replacer.Replace)
+	page.replaceHTMLLinks(replacer)
+	if coverImageURL := page.getCoverImageURL(); coverImageURL != nil {
+		if replacement, ok := replacementMap[*coverImageURL]; ok {
+			page.metadata["cover"].(map[string]string)["image"] = replacement
+		}
+	}
+	replaceProductImageLinks(page.metadata, replacementMap)
+	page.replaceGalleryImageLinks(replacementMap)
+	if images, ok := page.metadata[_openGraphImagesKey].([]string); ok {
+		for i, image := range images {
+			if replacement, ok := replacementMap[image]; ok {
+				images[i] = replacement
+			}
+		}
 	}
 }
 
@@ -127,17 +345,33 @@ func (page Page) Write(w io.Writer) error {
 }
 
 func (page *Page) WPMediaLinks() []string {
-	arr1 := getImageLinks([]byte(page.markdown))
-	arr2 := getMarkdownLinks(_hugoFigureLinks, page.markdown)
-	arr3 := getMarkdownLinks(_hugoParallaxBlurLinks, page.markdown)
-	arr4 := getMarkdownLinks(_hugoAudioLinks, page.markdown)
-	arr5 := getPDFLinks([]byte(page.markdown))
+	return append(page.WPResourceLinks(), page.WPStaticLinks()...)
+}
+
+// WPResourceLinks returns the image links of the content, which can be resolved as Hugo resources
+func (page *Page) WPResourceLinks() []string {
+	// The links of the code samples are left as is
+	markdown, _ := extractMarkdownCode(page.markdown)
+	arr1 := getImageLinks([]byte(markdown))
+	arr2 := getMarkdownLinks(_hugoFigureLinks, markdown)
+	return append(arr1, arr2...)
+}
+
+// WPStaticLinks returns the other media links, which have to be served from the static dir,
+// e.g. the cover image, the product gallery, the audio files, the playlist tracks and the galleries of GalleryOutput
+func (page *Page) WPStaticLinks() []string {
+	markdown, _ := extractMarkdownCode(page.markdown)
+	arr3 := getMarkdownLinks(_hugoParallaxBlurLinks, markdown)
+	arr4 := getMarkdownLinks(_hugoAudioLinks, markdown)
+	arr5 := getPDFLinks([]byte(markdown))
+	arr6 := getMarkdownLinks(_htmlPlaylistTrackLinks, markdown)
+	arr7 := getMarkdownLinks(_hugoPlaylistTrackLinks, markdown)
 	coverImageURL := page.getCoverImageURL()
-	result := append(append(append(append(arr1, arr2...), arr3...), arr4...), arr5...)
+	result := slices.Concat(arr3, arr4, arr5, arr6, arr7)
 	if coverImageURL != nil {
 		result = append(result, *coverImageURL)
 	}
-	return result
+	return slices.Concat(result, getProductImageLinks(page.metadata), page.getGalleryImageLinks())
 }
 
 func getImageLinks(content []byte) []string {
@@ -194,9 +428,9 @@ func UnserialiazePHParray(array string) any {
 	/* Ex:
 	a:2:{s:10:"taxonomies";s:32:"f166db6f0df2a3df4c2715a8bcc30eec";s:15:"postmeta_fields";s:32:"0edff5c6e53a54394f90f7b5a8fc1e76";}
 	*/
-	phpArray, err := gophp.Unserialize([]byte(array))
+	phpArray, err := unserializePHP(array)
 	if err != nil {
-		log.Error().
+		log.Warn().
 			Err(err).
 			Str("array", array).
 			Msg("Failed to decode PHP serialized array")
@@ -206,50 +440,161 @@ func UnserialiazePHParray(array string) any {
 	}
 }
 
+// isModifiedAfterPublishing reports whether "lastmod" is worth emitting.
+// Most posts were never edited after publishing, and a lastmod equal to the date is only noise
+// that some themes display as "updated".
+func isModifiedAfterPublishing(publishDate *time.Time, lastModifiedDate *time.Time, tolerance time.Duration) bool {
+	if lastModifiedDate == nil {
+		return false
+	}
+	if publishDate == nil {
+		return true
+	}
+	return lastModifiedDate.Sub(*publishDate) > tolerance
+}
+
+// sortTerms returns the sorted unique taxonomy terms, so that the front matter
+// does not depend on the order of the terms in the export
+func sortTerms(terms []string) []string {
+	terms = slices.Clone(terms)
+	sort.Strings(terms)
+	return slices.Compact(terms)
+}
+
+// TaxonomyKey returns the front matter key of the taxonomy
+func (options PageOptions) TaxonomyKey(taxonomy string) string {
+	if key, ok := options.TaxonomyKeys[taxonomy]; ok {
+		return key
+	}
+	return taxonomy
+}
+
 func getMetadata(provider ImageURLProvider, pageURL url.URL, author string, title string, publishDate *time.Time,
-	isDraft bool, categories []string, tags []string, guid *rss.GUID, featuredImageID *string,
+	lastModifiedDate *time.Time, isDraft bool, categories []string, tags []string, guid *rss.GUID, featuredImageID *string,
 	postFormat *string, customMetaData []wpparser.CustomMetaDatum, taxinomies []wpparser.TaxonomyInfo,
-	postID string, parentPostID *string,
+	postID string, parentPostID *string, options PageOptions,
 ) (map[string]any, error) {
 	metadata := make(map[string]any)
-	metadata["url"] = pageURL.Path // Relative URL
+	metadata["url"] = options.URLPrefix + pageURL.Path // Relative URL
 	metadata["author"] = author
+	if normalized := normalizeTitle(title, options.TitleNormalization); normalized != title {
+		if options.TitleNormalization == TitleNormalizationTitleCase && normalized != normalizeTitle(title, TitleNormalizationClean) {
+			metadata[_originalTitleKey] = title
+		}
+		title = normalized
+	}
 	metadata["title"] = title
 	metadata["post_id"] = postID
 	metadata["parent_post_id"] = parentPostID
+	if options.WordPressIDKey != "" {
+		if _, ok := metadata[options.WordPressIDKey]; ok {
+			log.Warn().
+				Str("key", options.WordPressIDKey).
+				Msg("WordPress ID key collides with an existing front matter key, ignoring it")
+		} else {
+			metadata[options.WordPressIDKey] = postID
+		}
+	}
 	if publishDate != nil {
-		metadata["date"] = publishDate.Format(_hugoDateFormat)
+		metadata["date"] = publishDate.Format(DateFormat)
+	}
+	if isModifiedAfterPublishing(publishDate, lastModifiedDate, options.LastModTolerance) {
+		metadata["lastmod"] = lastModifiedDate.Format(DateFormat)
 	}
 	if isDraft {
 		metadata["draft"] = "true"
 	}
+	var expiryKeys map[string]bool
+	if len(options.ExpiryDateMetaKeys) > 0 {
+		var expiryDate *time.Time
+		expiryDate, expiryKeys = getExpiryDate(options.ExpiryDateMetaKeys, customMetaData)
+		if expiryDate != nil {
+			if options.ExpiredAsDraft && expiryDate.Before(time.Now()) {
+				metadata["draft"] = "true"
+			} else {
+				metadata[_expiryDateKey] = expiryDate.Format(DateFormat)
+			}
+		}
+	}
 	if len(categories) > 0 {
-		sort.Strings(categories)
-		metadata[CategoryName] = slices.Compact(categories)
+		metadata[options.TaxonomyKey(CategoryName)] = sortTerms(categories)
 	}
 	if len(tags) > 0 {
-		sort.Strings(tags)
-		metadata[TagName] = slices.Compact(tags)
+		metadata[options.TaxonomyKey(TagName)] = sortTerms(tags)
 	}
 
+	var seriesKeys map[string]bool
+	if options.SeriesTaxonomy != "" {
+		var series []string
+		var seriesWeight *int
+		series, seriesWeight, seriesKeys = getSeries(options.SeriesTaxonomy, taxinomies, customMetaData)
+		if len(series) > 0 {
+			metadata[_seriesKey] = sortTerms(series)
+		}
+		if seriesWeight != nil {
+			metadata[_seriesWeightKey] = *seriesWeight
+		}
+	}
+
+	customTerms := make(map[string][]string)
 	for _, taxinomy := range taxinomies {
-		if existing, ok := metadata[taxinomy.Taxonomy]; ok {
-			switch v := existing.(type) {
-			case []string:
-				metadata[taxinomy.Taxonomy] = append(v, taxinomy.Name)
-			default:
-				metadata[taxinomy.Taxonomy] = []string{taxinomy.Name}
-			}
-		} else {
-			metadata[taxinomy.Taxonomy] = []string{taxinomy.Name}
+		if options.SeriesTaxonomy != "" && taxinomy.Taxonomy == options.SeriesTaxonomy {
+			// Emitted above as the series
+			continue
+		}
+		customTerms[taxinomy.Taxonomy] = append(customTerms[taxinomy.Taxonomy], taxinomy.Name)
+	}
+	for taxonomy, terms := range customTerms {
+		key := options.TaxonomyKey(taxonomy)
+		if _, ok := metadata[key]; ok {
+			log.Warn().
+				Str("taxonomy", taxonomy).
+				Str("key", key).
+				Msg("Taxonomy collides with an existing front matter key, ignoring it")
+			continue
 		}
+		metadata[key] = sortTerms(terms)
+	}
+
+	// The image links are relative to the page host, unless the media stay on the WordPress site
+	imageBaseURL := pageURL
+	if options.AbsoluteMediaLinks {
+		imageBaseURL = url.URL{}
+	}
+	customMetaData = resolveSEOTemplates(newSEOTemplate(title, author, publishDate, categories, tags, options), customMetaData)
+
+	var acfFields map[string]any
+	var acfKeys map[string]bool
+	if options.ExtractACFFields {
+		acfFields, acfKeys = extractACFFields(options.ACFFieldProvider, customMetaData)
+	}
+	var productFields map[string]any
+	var productKeys map[string]bool
+	if options.WooCommerceProduct {
+		productFields, productKeys = extractWooCommerceProduct(provider, options.ProductVariationProvider, imageBaseURL,
+			postID, customMetaData, taxinomies)
 	}
+	robotsDirectives, robotsKeys := getRobotsDirectives(customMetaData)
+	sitemapExcluded, sitemapKeys := getSitemapExclusion(customMetaData)
+	maps.Copy(robotsKeys, sitemapKeys)
 
 	for _, metadatum := range customMetaData {
-		if strings.HasPrefix(metadatum.Value, "a:") {
+		if acfKeys[metadatum.Key] || productKeys[metadatum.Key] || robotsKeys[metadatum.Key] {
+			// Emitted below as a decoded ACF field, WooCommerce product field, robots directive or sitemap exclusion
+			continue
+		}
+		if seriesKeys[metadatum.Key] || expiryKeys[metadatum.Key] {
+			// Emitted above as the series weight or the expiry date
+			continue
+		}
+		if metadatum.Key == wpparser.PathOverrideKey {
+			// Used for the output path, not a front matter
+			continue
+		}
+		if looksPHPSerialized(metadatum.Value) {
 			phpArray := UnserialiazePHParray(metadatum.Value)
 			if phpArray != nil {
-				// Decoded array is a nested dictionnary
+				// Decoded array is a nested dictionnary or a list
 				metadata[metadatum.Key] = phpArray
 			} else {
 				// Fallback to ugly serialized array
@@ -262,6 +607,9 @@ func getMetadata(provider ImageURLProvider, pageURL url.URL, author string, titl
 		// Note: if any step of the PHP array reading/decoding failed,
 		// now we got the original serialized array
 	}
+	maps.Copy(metadata, acfFields)
+	maps.Copy(metadata, productFields)
+	setRobotsMetadata(metadata, robotsDirectives, sitemapExcluded, options.NoIndexExclusion)
 
 	if guid != nil {
 		metadata["guid"] = guid.Value
@@ -275,15 +623,8 @@ func getMetadata(provider ImageURLProvider, pageURL url.URL, author string, titl
 				Msg("Image URL not found")
 		} else {
 			coverInfo := make(map[string]string)
-			imageURL, err := url.Parse(imageInfo.ImageURL)
-			if err != nil {
-				return nil, fmt.Errorf("error parsing image URL '%s': %w", imageInfo.ImageURL, err)
-			}
-			if imageURL.Host == pageURL.Host {
-				// If the image URL is on the same host as the page, we can use a relative URL
-				coverInfo["image"] = imageURL.Path
-			} else {
-				coverInfo["image"] = imageInfo.ImageURL
+			if coverInfo["image"], err = getImageLink(imageBaseURL, *imageInfo); err != nil {
+				return nil, err
 			}
 			coverInfo["alt"] = imageInfo.Title
 			metadata["cover"] = coverInfo
@@ -295,6 +636,18 @@ func getMetadata(provider ImageURLProvider, pageURL url.URL, author string, titl
 	return metadata, nil
 }
 
+// getImageLink returns the link of the image, relative if it is on the same host as the page
+func getImageLink(pageURL url.URL, imageInfo ImageInfo) (string, error) {
+	imageURL, err := url.Parse(imageInfo.ImageURL)
+	if err != nil {
+		return "", fmt.Errorf("error parsing image URL '%s': %w", imageInfo.ImageURL, err)
+	}
+	if imageURL.Host == pageURL.Host {
+		return imageURL.Path, nil
+	}
+	return imageInfo.ImageURL, nil
+}
+
 func (page *Page) getCoverImageURL() *string {
 	if page.metadata == nil {
 		return nil
@@ -339,27 +692,125 @@ func (page *Page) getMarkdown(provider ImageURLProvider, htmlContent string, foo
 		attachmentIDs = append(attachmentIDs, attachment.PostID)
 	}
 
+	// Left when the content is not split into several pages
+	htmlContent = collapseNextPages(htmlContent, page.options.SourceIsMarkdown)
+
+	var markdown string
+	// Left as placeholders until the Markdown passes are done, they must not rewrite the raw HTML
+	var customHTMLBlocks []string
+	if page.options.SourceIsMarkdown {
+		markdown = page.getMarkdownFromSource(provider, attachmentIDs, htmlContent)
+	} else {
+		converted, blocks, err := page.convertHTMLToMarkdown(provider, attachmentIDs, htmlContent)
+		if err != nil {
+			return nil, err
+		}
+		markdown, customHTMLBlocks = converted, blocks
+	}
+	if len(strings.TrimSpace(markdown)) == 0 {
+		// The page contains no markdown. Warn the user, but keep going.
+		log.Warn().
+			Str("page", page.absoluteURL.String()).
+			Msg("empty markdown")
+	}
+	markdown, code := extractMarkdownCode(markdown)
+	markdown = replaceShortlinks(page.options.PostLinkProvider, page.absoluteURL, markdown)
+	markdown = replaceSamePageLinks(page.absoluteURL, markdown)
+	markdown = replaceAbsoluteLinksWithPrefixed(page.absoluteURL.Host, page.options.BasePath, page.options.URLPrefix,
+		page.options.AbsoluteMediaLinks, markdown)
+	markdown = page.extractGalleries(markdown)
+	markdown = replaceCatlistWithShortcode(markdown)
+	// Disabled for now, as it does not work well
+	if false {
+		markdown = highlightCode(markdown)
+	} else {
+		log.Debug().Msg("Auto-detecting languages of code blocks is disabled for now")
+	}
+
+	// Replace footnote links with actual Hugo-style footnotes
+	// Ref: https://geekthis.net/post/hugo-footnotes-and-citations
+	footnoteStrs := make([]string, 0, len(footnotes))
+	if len(footnotes) > 0 {
+		// [^1]: And that's the footnote.
+		footnoteNumbers := make(map[string]int, len(footnotes))
+		for i, footnote := range footnotes {
+			tmp := fmt.Sprintf("[^%d]: %s", i+1, footnote.Content)
+			footnoteStrs = append(footnoteStrs, tmp)
+			if _, ok := footnoteNumbers[footnote.ID]; !ok {
+				footnoteNumbers[footnote.ID] = i + 1
+			}
+		}
+		// Single pass over the markdown, instead of one regex per footnote
+		markdown = _footnoteLinkRegex.ReplaceAllStringFunc(markdown, func(link string) string {
+			id := _footnoteLinkRegex.FindStringSubmatch(link)[1]
+			if number, ok := footnoteNumbers[id]; ok {
+				return fmt.Sprintf(`[^%d]`, number)
+			}
+			return link
+		})
+		markdown = fmt.Sprintf("%s\n\n%s", markdown, strings.Join(footnoteStrs, "\n\n"))
+	}
+
+	markdown = replaceOrderedListNumbers(markdown)
+	markdown = replacePlaintextYoutubeURL(markdown)
+	if !page.options.SourceIsMarkdown {
+		// Workaround for https://github.com/ashishb/wp2hugo/issues/11
+		markdown = removeExtraSpaceBeforeLinks(markdown)
+	}
+	markdown = page.normalizeWhitespace(markdown)
+	markdown = restoreCode(markdown, code)
+	markdown = page.applyTypography(markdown)
+	markdown = restoreCustomHTMLBlocks(markdown, customHTMLBlocks, page.options.WrapCustomHTMLInShortcode)
+
+	return &markdown, nil
+}
+
+// convertHTMLToMarkdown converts the WordPress HTML content, and its shortcodes, to Markdown.
+// The custom HTML blocks are returned along with the Markdown, which has placeholders in their place,
+// see restoreCustomHTMLBlocks.
+func (page *Page) convertHTMLToMarkdown(provider ImageURLProvider, attachmentIDs []string, htmlContent string) (string, []string, error) {
 	converter := getMarkdownConverter()
-	htmlContent = improvePreTagsWithCode(htmlContent)
+	if page.options.PreserveLinkAttributes {
+		converter.Use(preserveLinkAttributes())
+	}
+	htmlContent = escapeUnterminatedComments(htmlContent)
+	htmlContent, code := extractHTMLCode(htmlContent)
+	if page.options.StripShortcodes == StripAllShortcodes {
+		htmlContent = page.stripShortcodes(htmlContent, true)
+	}
+	htmlContent = page.replacePlaylistShortCode(provider, attachmentIDs, htmlContent)
+	htmlContent, customHTMLBlocks := extractCustomHTMLBlocks(htmlContent)
+	htmlContent = closeUnclosedFormatting(htmlContent)
+	htmlContent = page.preserveOrderedListNumbers(htmlContent)
 	htmlContent = replaceCaptionWithFigure(htmlContent)
 	htmlContent = replaceImageBlockWithFigure(htmlContent)
 	htmlContent = replaceAudioShortCode(htmlContent)
 	htmlContent = replaceGutembergGalleryWithFigure(htmlContent)
+	htmlContent = replaceJetpackTiledGalleryWithFigure(htmlContent)
 	htmlContent = replaceGalleryWithFigure(provider, attachmentIDs, htmlContent)
 	htmlContent = replaceAWBWithParallaxBlur(provider, htmlContent)
-	htmlContent = strings.Replace(htmlContent, _WordPressMoreTag, _customMoreTag, 1)
+	htmlContent = normalizeMoreTag(htmlContent, _customMoreTag)
 
 	// We convert consecutive <br> to a custom tag
 	// then we convert <br> to "  \n" and then we convert the custom tag to "\n\n"
 	// It is convoluted but it works.
 	htmlContent = convertConsecutiveBRToCustomTag(htmlContent)
 
-	// This handling is specific to paperMod theme
-	// Ref: https://adityatelange.github.io/hugo-PaperMod/posts/papermod/papermod-features/#show-table-of-contents-toc-on-blog-post
-	if strings.Contains(htmlContent, _wordPressTocTag) {
-		htmlContent = strings.Replace(htmlContent, _wordPressTocTag, "", 1)
-		page.metadata["ShowToc"] = true
-		page.metadata["TocOpen"] = true
+	htmlContent = page.replaceTocTag(htmlContent)
+	if page.options.StripShortcodes == StripUnhandledShortcodes {
+		htmlContent = page.stripShortcodes(htmlContent, false)
+	}
+	if page.options.AnnotateIssues {
+		htmlContent = annotateIssues(htmlContent)
+	}
+	// The custom HTML blocks are kept as raw HTML, only the code samples converted to Markdown are escaped
+	convertedCode := make([]string, len(code))
+	for i, sample := range code {
+		convertedCode[i] = escapeLiteralCode(sample)
+	}
+	htmlContent = improvePreTagsWithCode(restoreCode(htmlContent, convertedCode))
+	for i, block := range customHTMLBlocks {
+		customHTMLBlocks[i] = restoreCode(block, code)
 	}
 	markdown, err := converter.ConvertString(htmlContent)
 	log.Debug().
@@ -368,13 +819,7 @@ func (page *Page) getMarkdown(provider ImageURLProvider, htmlContent string, foo
 		Msg("Markdown conversion")
 
 	if err != nil {
-		return nil, fmt.Errorf("error converting HTML to Markdown: %w", err)
-	}
-	if len(strings.TrimSpace(markdown)) == 0 {
-		// The page contains no markdown. Warn the user, but keep going.
-		log.Warn().
-			Str("page", page.absoluteURL.String()).
-			Msg("empty markdown")
+		return "", nil, fmt.Errorf("error converting HTML to Markdown: %w", err)
 	}
 	if strings.Contains(markdown, _customMoreTag) {
 		// Ref: https://gohugo.io/content-management/summaries/#manual-summary-splitting
@@ -388,37 +833,19 @@ func (page *Page) getMarkdown(provider ImageURLProvider, htmlContent string, foo
 	}
 
 	markdown = strings.ReplaceAll(markdown, _doubleSpaceWithNewline, "  \n")
-	markdown = ReplaceAbsoluteLinksWithRelative(page.absoluteURL.Host, markdown)
-	markdown = replaceCatlistWithShortcode(markdown)
-	// Disabled for now, as it does not work well
-	if false {
-		markdown = highlightCode(markdown)
-	} else {
-		log.Debug().Msg("Auto-detecting languages of code blocks is disabled for now")
-	}
+	return markdown, customHTMLBlocks, nil
+}
 
-	// Replace footnote links with actual Hugo-style footnotes
-	// Ref: https://geekthis.net/post/hugo-footnotes-and-citations
-	footnoteStrs := make([]string, 0, len(footnotes))
-	if len(footnotes) > 0 {
-		// [^1]: And that's the footnote.
-		for i, footnote := range footnotes {
-			tmp := fmt.Sprintf("[^%d]: %s", i+1, footnote.Content)
-			footnoteStrs = append(footnoteStrs, tmp)
-			regex1 := regexp.MustCompile(fmt.Sprintf(`\[\S+\]\(#%s\)`, footnote.ID))
-			markdown = regex1.ReplaceAllString(markdown, fmt.Sprintf(`[^%d]`, i+1))
-		}
-		markdown = fmt.Sprintf("%s\n\n%s", markdown, strings.Join(footnoteStrs, "\n\n"))
+// replaceTocTag removes the [toc] shortcode and enables the table of contents instead.
+// This handling is specific to paperMod theme
+// Ref: https://adityatelange.github.io/hugo-PaperMod/posts/papermod/papermod-features/#show-table-of-contents-toc-on-blog-post
+func (page *Page) replaceTocTag(content string) string {
+	if strings.Contains(content, _wordPressTocTag) {
+		content = strings.Replace(content, _wordPressTocTag, "", 1)
+		page.metadata["ShowToc"] = true
+		page.metadata["TocOpen"] = true
 	}
-
-	markdown = replaceOrderedListNumbers(markdown)
-	markdown = replaceConsecutiveNewlines(markdown)
-	markdown = replacePlaintextYoutubeURL(markdown)
-	markdown = removeTrailingSpaces(markdown)
-	// Workaround for https://github.com/ashishb/wp2hugo/issues/11
-	markdown = removeExtraSpaceBeforeLinks(markdown)
-
-	return &markdown, nil
+	return content
 }
 
 func removeAllHugoShortcodes(summary string) string {
@@ -518,28 +945,56 @@ func getLanguageCode(code string) string {
 func replaceOrderedListNumbers(markdown string) string {
 	// Ref: https://github.com/markdownlint/markdownlint/blob/main/docs/RULES.md#md029---ordered-list-item-prefix
 	// Find all the ordered list items starting with optional whitespaces followed by \d. and replace with 1.
-	reg1 := regexp.MustCompile(`(?m)^(\s*)(\d+)\.(\s)`)
-	return reg1.ReplaceAllString(markdown, `${1}1.$3`)
+	// Markdown takes the start of an ordered list from its first item, so lists that
+	// don't start at 1 (<ol start="5">) keep their numbers.
+	lines := strings.Split(markdown, "\n")
+	// Indentation of the nested lists -> number of their first item
+	listStarts := make(map[int]int)
+	for i, line := range lines {
+		match := _orderedListItemRegex.FindStringSubmatch(line)
+		if match == nil {
+			if strings.TrimSpace(line) != "" {
+				// Any other content ends the lists that are indented as much or more
+				endLists(listStarts, len(line)-len(strings.TrimLeft(line, " \t")))
+			}
+			continue
+		}
+
+		indent := len(match[1])
+		start, ok := listStarts[indent]
+		if !ok {
+			endLists(listStarts, indent)
+			start, _ = strconv.Atoi(match[2])
+			listStarts[indent] = start
+		}
+		if start == 1 {
+			lines[i] = match[1] + "1." + match[3] + line[len(match[0]):]
+		}
+	}
+	return strings.Join(lines, "\n")
 }
 
-func replaceConsecutiveNewlines(markdown string) string {
-	return _moreThanTwoNewlines.ReplaceAllString(markdown, "\n\n")
+func endLists(listStarts map[int]int, minIndent int) {
+	for indent := range listStarts {
+		if indent >= minIndent {
+			delete(listStarts, indent)
+		}
+	}
 }
 
-func removeTrailingSpaces(markdown string) string {
-	return _spaceSurroundedByNewlines.ReplaceAllString(markdown, "\n\n")
+func replaceConsecutiveNewlines(markdown string) string {
+	return _moreThanTwoNewlines.ReplaceAllString(markdown, "\n\n")
 }
 
 func (page Page) writeContent(w io.Writer) error {
-	if _, err := w.Write([]byte(page.markdown)); err != nil {
-		return fmt.Errorf("error writing to page file: %w", err)
+	markdown := page.markdown
+	if _leadingThematicBreakRegEx.MatchString(markdown) {
+		// Kept apart from the closing delimiter of the front matter, which it looks like
+		markdown = "\n" + markdown
 	}
-
-	if !strings.HasSuffix(page.markdown, "\n") {
-		// Add a newline at the end of the file
-		if _, err := w.Write([]byte("\n")); err != nil {
-			return fmt.Errorf("error writing newline to page file: %w", err)
-		}
+	// A single newline at the end of the file
+	if _, err := w.Write([]byte(strings.TrimRight(markdown, "\n") + "\n")); err != nil {
+		return fmt.Errorf("error writing to page file: %w", err)
 	}
 	return nil
 }
@@ -548,9 +1003,31 @@ func ReplaceAbsoluteLinksWithRelative(hostName string, markdownData string) stri
 	log.Debug().
 		Str("hostName", hostName).
 		Msg("Replacing absolute links with relative links")
-	re1 := regexp.MustCompile("https://" + hostName + "/")
-	re2 := regexp.MustCompile("http://" + hostName + "/")
-	markdownData = re1.ReplaceAllString(markdownData, "/")
-	markdownData = re2.ReplaceAllString(markdownData, "/")
-	return markdownData
+	replacer := strings.NewReplacer("https://"+hostName+"/", "/", "http://"+hostName+"/", "/")
+	return replacer.Replace(markdownData)
+}
+
+// replaceAbsoluteLinksWithPrefixed is ReplaceAbsoluteLinksWithRelative for sites
+// generated under a URL prefix: internal links get the prefix, except media links
+// since the media files are not moved under the prefix.
+// With absoluteMedia, the media links are kept as is, the media are served by the WordPress site.
+// The media links are the ones under the wp-content dir of the blog at basePath, e.g. "/blog".
+func replaceAbsoluteLinksWithPrefixed(hostName string, basePath string, urlPrefix string, absoluteMedia bool,
+	markdownData string,
+) string {
+	if urlPrefix == "" && !absoluteMedia {
+		return ReplaceAbsoluteLinksWithRelative(hostName, markdownData)
+	}
+	mediaPath := basePath + "/wp-content/"
+	httpsMediaPrefix, httpMediaPrefix := "https://"+hostName+mediaPath, "http://"+hostName+mediaPath
+	oldNew := []string{httpsMediaPrefix, mediaPath, httpMediaPrefix, mediaPath}
+	if absoluteMedia {
+		// Replaced by themselves, so that the internal links below do not match them
+		oldNew = []string{httpsMediaPrefix, httpsMediaPrefix, httpMediaPrefix, httpMediaPrefix}
+	}
+	// The replacer compares the old strings in argument order, so the media links go first
+	replacer := strings.NewReplacer(append(oldNew,
+		"https://"+hostName+"/", urlPrefix+"/",
+		"http://"+hostName+"/", urlPrefix+"/")...)
+	return replacer.Replace(markdownData)
 }
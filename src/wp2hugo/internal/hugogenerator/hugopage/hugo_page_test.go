@@ -1,10 +1,15 @@
 package hugopage
 
 import (
+	"fmt"
 	"net/url"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 )
 
 const (
@@ -43,6 +48,16 @@ const (
 	_sampleMarkdownOutput6 = `'[Example](https://example.com)'`
 )
 
+const (
+	_sampleHTMLInput7      = `<!-- wp:list --><ul><li>First<!-- wp:list {"ordered":true} --><ol><li>Step one</li><li>Step two</li></ol><!-- /wp:list --></li><li>Second<ul><li>Nested bullet</li></ul></li></ul><!-- /wp:list -->`
+	_sampleMarkdownOutput7 = "- First\n  1. Step one\n  1. Step two\n- Second\n  - Nested bullet"
+)
+
+const (
+	_sampleHTMLInput8      = `<ol start="5"><li>Fifth item</li><li>Sixth item<ol><li>Nested item</li><li>Another nested item</li></ol></li><li>Seventh item</li></ol>`
+	_sampleMarkdownOutput8 = "5. Fifth item\n6. Sixth item\n   1. Nested item\n   1. Another nested item\n7. Seventh item"
+)
+
 func TestMarkdownExtractorWithLink1(t *testing.T) {
 	t.Parallel()
 	testMarkdownExtractor(t, _sampleHTMLInput2, _sampleMarkdownOutput2)
@@ -67,6 +82,16 @@ func TestListExtractor(t *testing.T) {
 	testMarkdownExtractor(t, _sampleHTMLInput3, _sampleMarkdownOutput3)
 }
 
+func TestNestedMixedListExtractor(t *testing.T) {
+	t.Parallel()
+	testMarkdownExtractor(t, _sampleHTMLInput7, _sampleMarkdownOutput7)
+}
+
+func TestOrderedListWithStartExtractor(t *testing.T) {
+	t.Parallel()
+	testMarkdownExtractor(t, _sampleHTMLInput8, _sampleMarkdownOutput8)
+}
+
 func TestConsecutiveNewlines(t *testing.T) {
 	t.Parallel()
 	testMarkdownExtractor(t, _sampleHTMLInput4, _sampleMarkdownOutput4)
@@ -77,12 +102,26 @@ func TestManualLineBreaks(t *testing.T) {
 	testMarkdownExtractor(t, _sampleHTMLInput5, _sampleMarkdownOutput5)
 }
 
-func testMarkdownExtractor(t *testing.T, htmlInput string, markdownOutput string) {
+// newTestPage converts htmlContent into the page at https://example.com/post/, with the default metadata
+func newTestPage(t testing.TB, htmlContent string, options PageOptions) *Page {
 	t.Helper()
-	url1, err := url.Parse("https://example.com")
+	return newTestPageAt(t, "https://example.com/post/", htmlContent, options)
+}
+
+// newTestPageAt converts htmlContent into the page at pageURL, with the default metadata
+func newTestPageAt(t testing.TB, pageURL string, htmlContent string, options PageOptions) *Page {
+	t.Helper()
+	url1, err := url.Parse(pageURL)
 	require.NoError(t, err)
-	page, err := NewPage(nil, *url1, "author", "Title", nil, false, nil, nil, nil, nil, htmlInput, nil, nil, nil, nil, nil, "0", nil)
+	page, err := NewPage(nil, *url1, "author", "Title", nil, nil, false, nil, nil, nil, nil, htmlContent, nil, nil, nil, nil, nil, "0",
+		nil, options)
 	require.NoError(t, err)
+	return page
+}
+
+func testMarkdownExtractor(t *testing.T, htmlInput string, markdownOutput string) {
+	t.Helper()
+	page := newTestPage(t, htmlInput, PageOptions{})
 	md, err := page.getMarkdown(nil, htmlInput, nil)
 	require.NoError(t, err)
 	require.Equal(t, markdownOutput, *md)
@@ -102,3 +141,186 @@ func TestPreTagExtractor2(t *testing.T) {
 	require.Len(t, result3[0], 3)
 	require.Equal(t, "sh", result3[0][1])
 }
+
+func TestWordPressIDFrontMatter(t *testing.T) {
+	t.Parallel()
+	url1, err := url.Parse("https://example.com/hello-world/")
+	require.NoError(t, err)
+
+	metadata, err := getMetadata(nil, *url1, "author", "Title", nil, nil, false, nil, nil, nil, nil, nil, nil, nil, "42", nil, PageOptions{})
+	require.NoError(t, err)
+	require.NotContains(t, metadata, "wordpress_id")
+
+	metadata, err = getMetadata(nil, *url1, "author", "Title", nil, nil, false, nil, nil, nil, nil, nil, nil, nil, "42", nil,
+		PageOptions{WordPressIDKey: "wordpress_id"})
+	require.NoError(t, err)
+	require.Equal(t, "42", metadata["wordpress_id"])
+}
+
+func TestReplaceLongestKeyFirst(t *testing.T) {
+	t.Parallel()
+	page := Page{markdown: "![](/wp-content/a-100x100.jpg) ![](/wp-content/a.jpg)"}
+	page.Replace(map[string]string{
+		"/wp-content/a.jpg":         "/images/a.jpg",
+		"/wp-content/a-100x100.jpg": "/images/a-small.jpg",
+	})
+	require.Equal(t, "![](/images/a-small.jpg) ![](/images/a.jpg)", page.markdown)
+}
+
+// Unclosed shortcodes used to make the greedy patterns swallow the rest of the line
+func TestLargePostWithUnclosedShortcodes(t *testing.T) {
+	t.Parallel()
+	htmlInput := largePost(2_000)
+	page := newTestPage(t, htmlInput, PageOptions{})
+	require.Contains(t, page.markdown, `Paragraph 1999 \[caption id="x"`)
+	require.Equal(t, 2_000, strings.Count(page.markdown, "{{< audio src=\"/a.mp3\" >}} trailing text"))
+}
+
+func BenchmarkNewPageLargePost(b *testing.B) {
+	// Roughly 5 MB of post content
+	htmlInput := largePost(40_000)
+	b.SetBytes(int64(len(htmlInput)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		newTestPage(b, htmlInput, PageOptions{})
+	}
+}
+
+func BenchmarkNewPage(b *testing.B) {
+	for _, numParagraphs := range []int{10, 100, 1_000} {
+		htmlInput := largePost(numParagraphs)
+		b.Run(fmt.Sprintf("paragraphs_%d", numParagraphs), func(b *testing.B) {
+			b.SetBytes(int64(len(htmlInput)))
+			for b.Loop() {
+				newTestPage(b, htmlInput, PageOptions{})
+			}
+		})
+	}
+}
+
+func largePost(numParagraphs int) string {
+	var sb strings.Builder
+	for i := 0; i < numParagraphs; i++ {
+		fmt.Fprintf(&sb, "<p>Paragraph %d [caption id=\"x\" align=\"aligncenter\" width=\"5\"] <b>never closed</b> "+
+			"with a <a href=\"https://example.com/post-%d/\">link</a> [audio mp3=\"/a.mp3\"] trailing text</p>\n", i, i)
+	}
+	return sb.String()
+}
+
+func TestLastModFrontMatter(t *testing.T) {
+	t.Parallel()
+	url1, err := url.Parse("https://example.com/hello-world/")
+	require.NoError(t, err)
+	publishDate := time.Date(2024, time.March, 5, 10, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name         string
+		lastModified time.Time
+		tolerance    time.Duration
+		expected     any
+	}{
+		{"equal", publishDate, 0, nil},
+		{"slightly later", publishDate.Add(30 * time.Second), 0, "2024-03-05T10:00:30+00:00"},
+		{"slightly later within tolerance", publishDate.Add(30 * time.Second), time.Minute, nil},
+		{"much later", publishDate.AddDate(1, 0, 0), time.Hour, "2025-03-05T10:00:00+00:00"},
+	}
+	for _, testCase := range testCases {
+		metadata, err := getMetadata(nil, *url1, "author", "Title", &publishDate, &testCase.lastModified, false,
+			nil, nil, nil, nil, nil, nil, nil, "42", nil, PageOptions{LastModTolerance: testCase.tolerance})
+		require.NoError(t, err, testCase.name)
+		require.Equal(t, testCase.expected, metadata["lastmod"], testCase.name)
+	}
+}
+
+func TestReplaceAbsoluteLinksWithPrefixed(t *testing.T) {
+	t.Parallel()
+	markdown := "[post](https://example.com/2024/hello/) ![img](http://example.com/wp-content/uploads/a.jpg) [ext](https://other.com/x/)"
+	require.Equal(t, "[post](/2024/hello/) ![img](/wp-content/uploads/a.jpg) [ext](https://other.com/x/)",
+		replaceAbsoluteLinksWithPrefixed("example.com", "", "", false, markdown))
+	require.Equal(t, "[post](/blog/2024/hello/) ![img](/wp-content/uploads/a.jpg) [ext](https://other.com/x/)",
+		replaceAbsoluteLinksWithPrefixed("example.com", "", "/blog", false, markdown))
+	require.Equal(t, "[post](/2024/hello/) ![img](http://example.com/wp-content/uploads/a.jpg) [ext](https://other.com/x/)",
+		replaceAbsoluteLinksWithPrefixed("example.com", "", "", true, markdown))
+
+	// WordPress in a subdirectory
+	markdown = "[post](https://example.com/blog/2024/hello/) ![img](https://example.com/blog/wp-content/uploads/a.jpg)"
+	require.Equal(t, "[post](/news/blog/2024/hello/) ![img](/blog/wp-content/uploads/a.jpg)",
+		replaceAbsoluteLinksWithPrefixed("example.com", "/blog", "/news", false, markdown))
+	require.Equal(t, "[post](/blog/2024/hello/) ![img](https://example.com/blog/wp-content/uploads/a.jpg)",
+		replaceAbsoluteLinksWithPrefixed("example.com", "/blog", "", true, markdown))
+}
+
+func TestEmojiTitleFrontMatter(t *testing.T) {
+	t.Parallel()
+	url1, err := url.Parse("https://example.com/hello-%f0%9f%91%8b-world/")
+	require.NoError(t, err)
+
+	for _, title := range []string{"Hello 👋 World", "👨‍👩‍👧: a family trip", `Literal \U0001F44B`} {
+		page, err := NewPage(nil, *url1, "author", title, nil, nil, false, nil, nil, nil, nil, "<p>Hi</p>",
+			nil, nil, nil, nil, nil, "1", nil, PageOptions{})
+		require.NoError(t, err)
+		var sb strings.Builder
+		require.NoError(t, page.Write(&sb))
+		if !strings.HasPrefix(title, "Literal") {
+			// Written as is, instead of the "\U0001F44B" escape sequence
+			require.Contains(t, sb.String(), `title: "`+title+`"`)
+		}
+
+		var frontMatter map[string]any
+		require.NoError(t, yaml.Unmarshal([]byte(strings.Split(sb.String(), "---")[1]), &frontMatter))
+		require.Equal(t, title, frontMatter["title"])
+		require.Equal(t, "/hello-👋-world/", frontMatter["url"])
+	}
+}
+
+func TestReplaceUpdatesCoverImage(t *testing.T) {
+	t.Parallel()
+	page := Page{
+		metadata: map[string]any{"cover": map[string]string{"image": "/wp-content/uploads/photo.jpg", "alt": "Photo"}},
+		markdown: "![Photo](/wp-content/uploads/photo.jpg)",
+	}
+	page.Replace(map[string]string{"/wp-content/uploads/photo.jpg": "/wp-content/uploads/photo.jpg.webp"})
+	require.Equal(t, "![Photo](/wp-content/uploads/photo.jpg.webp)", page.Markdown())
+	require.Equal(t, "/wp-content/uploads/photo.jpg.webp", *page.getCoverImageURL())
+}
+
+func TestTaxonomyTermsAreSorted(t *testing.T) {
+	t.Parallel()
+	url1, err := url.Parse("https://example.com/post/")
+	require.NoError(t, err)
+	categories := []string{"travel", "food", "travel"}
+	taxonomies := []wpparser.TaxonomyInfo{
+		{Taxonomy: "cuisine", Name: "Italian"},
+		{Taxonomy: "cuisine", Name: "French"},
+		{Taxonomy: "course", Name: "Main"},
+	}
+	metadata, err := getMetadata(nil, *url1, "author", "Title", nil, nil, false, categories, []string{"b", "a"}, nil,
+		nil, nil, nil, taxonomies, "1", nil, PageOptions{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"food", "travel"}, metadata[CategoryName])
+	require.Equal(t, []string{"a", "b"}, metadata[TagName])
+	require.Equal(t, []string{"French", "Italian"}, metadata["cuisine"])
+	require.Equal(t, []string{"Main"}, metadata["course"])
+	// The input is left untouched
+	require.Equal(t, []string{"travel", "food", "travel"}, categories)
+}
+
+func TestTaxonomyKeys(t *testing.T) {
+	t.Parallel()
+	url1, err := url.Parse("https://example.com/post/")
+	require.NoError(t, err)
+	taxonomies := []wpparser.TaxonomyInfo{
+		{Taxonomy: "cuisine", Name: "Italian"},
+		{Taxonomy: "title", Name: "Chef"},
+	}
+	options := PageOptions{TaxonomyKeys: map[string]string{CategoryName: "category", TagName: "keywords", "cuisine": "cuisines"}}
+	metadata, err := getMetadata(nil, *url1, "author", "Title", nil, nil, false, []string{"food"}, []string{"pizza"}, nil,
+		nil, nil, nil, taxonomies, "1", nil, options)
+	require.NoError(t, err)
+	require.Equal(t, []string{"food"}, metadata["category"])
+	require.Equal(t, []string{"pizza"}, metadata["keywords"])
+	require.Equal(t, []string{"Italian"}, metadata["cuisines"])
+	require.NotContains(t, metadata, CategoryName)
+	require.NotContains(t, metadata, TagName)
+	// A taxonomy never overrides another front matter key
+	require.Equal(t, "Title", metadata["title"])
+}
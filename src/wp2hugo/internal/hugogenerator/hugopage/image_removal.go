@@ -0,0 +1,14 @@
+package hugopage
+
+import "regexp"
+
+// RemoveImage removes the Markdown images and the figures of the content referencing the link,
+// along with the links wrapping them, e.g. `[![alt](link)](link)`
+func (page *Page) RemoveImage(link string) {
+	quoted := regexp.QuoteMeta(link)
+	image := `!\[[^\]]*\]\(` + quoted + `(?:\s+"[^"]*")?\)`
+	figure := `{{< figure[^>]*? src="` + quoted + `".*? >}}`
+	removal := regexp.MustCompile(`\[(?:` + image + `|` + figure + `)\]\([^)\s]*\)|` + image + `|` + figure)
+	page.markdown = removal.ReplaceAllString(page.markdown, "")
+	page.markdown = replaceConsecutiveNewlines(page.markdown)
+}
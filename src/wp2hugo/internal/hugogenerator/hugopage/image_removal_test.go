@@ -0,0 +1,19 @@
+package hugopage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoveImage(t *testing.T) {
+	t.Parallel()
+	const htmlInput = `<p>Before</p><p><img src="https://example.com/gone.jpg" alt="Gone" /></p>` +
+		`<p><a href="https://example.com/gone.jpg"><img src="https://example.com/gone.jpg" alt="Linked" /></a></p>` +
+		`[caption id="attachment_1" align="alignnone" width="300"]<img src="https://example.com/gone.jpg" alt="Figure" /> A caption[/caption]` +
+		`<p><img src="https://example.com/kept.jpg" alt="Kept" /></p><p>After</p>`
+	page := newTestPage(t, htmlInput, PageOptions{})
+
+	page.RemoveImage("/gone.jpg")
+	require.Equal(t, "Before\n\n![Kept](/kept.jpg)\n\nAfter", page.Markdown())
+}
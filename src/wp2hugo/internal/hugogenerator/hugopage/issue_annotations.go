@@ -0,0 +1,109 @@
+package hugopage
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Opening tag of a WordPress shortcode, e.g. `[su_box title="Note"]`
+var _shortcodeRegEx = regexp.MustCompile(`\[([a-zA-Z][\w-]*)((?:\s[^\[\]]*)?)\]`)
+
+// Code samples are not annotated, they often document shortcodes
+var _codeBlockRegEx = regexp.MustCompile(`(?is)<(pre|code)\b.*?</(?:pre|code)>`)
+
+// Gutenberg blocks whose side-by-side layout is lost in Markdown, e.g. `<!-- wp:columns {"align":"wide"} -->`
+var _flattenedLayoutBlockRegEx = regexp.MustCompile(`<!-- wp:(columns|media-text)\s(?:\{.*?\}\s)?-->`)
+
+// Shortcodes still present in the HTML, but converted after the Markdown conversion
+var _shortcodesConvertedFromMarkdown = []string{"catlist", "embed", "youtube"}
+
+// annotateIssues inserts an HTML comment next to the content which will not survive the conversion
+func annotateIssues(htmlContent string) string {
+	htmlContent = replaceAllStringSubmatchFunc(_flattenedLayoutBlockRegEx, htmlContent, func(groups []string) string {
+		log.Debug().
+			Str("block", groups[1]).
+			Msg("Annotating flattened layout block")
+		return groups[0] + "<p>" + newHTMLComment("flattened layout block wp:"+groups[1]) + "</p>"
+	})
+
+	codeBlocks := _codeBlockRegEx.FindAllStringIndex(htmlContent, -1)
+	var sb strings.Builder
+	lastIndex := 0
+	for _, match := range _shortcodeRegEx.FindAllStringSubmatchIndex(htmlContent, -1) {
+		name := htmlContent[match[2]:match[3]]
+		attrs := htmlContent[match[4]:match[5]]
+		if !isUnhandledShortcode(htmlContent, name, attrs) || isInRanges(match[0], codeBlocks) {
+			continue
+		}
+		log.Warn().
+			Str("shortcode", name).
+			Msg("Unhandled shortcode")
+		sb.WriteString(htmlContent[lastIndex:match[1]])
+		sb.WriteString(newHTMLComment(fmt.Sprintf("unhandled shortcode [%s]", name)))
+		lastIndex = match[1]
+	}
+	sb.WriteString(htmlContent[lastIndex:])
+	return sb.String()
+}
+
+func isUnhandledShortcode(htmlContent string, name string, attrs string) bool {
+	if slices.Contains(_shortcodesConvertedFromMarkdown, name) {
+		return false
+	}
+	return isShortcode(htmlContent, name, attrs)
+}
+
+// Brackets are common in prose, e.g. "[sic]", so a shortcode is expected to have
+// attributes, a closing tag or to be self-closing, e.g. `[su_divider /]`
+func isShortcode(htmlContent string, name string, attrs string) bool {
+	return strings.Contains(attrs, "=") || strings.HasSuffix(attrs, "/") ||
+		strings.Contains(htmlContent, "[/"+name+"]")
+}
+
+func isInRanges(index int, ranges [][]int) bool {
+	for _, r := range ranges {
+		if index >= r[0] && index < r[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// AnnotateLink inserts an HTML comment describing the issue after each reference to the link,
+// if PageOptions.AnnotateIssues is set
+func (page *Page) AnnotateLink(link string, issue string) {
+	if !page.options.AnnotateIssues || link == "" {
+		return
+	}
+	comment := fmt.Sprintf("<!-- wp2hugo: %s -->", issue)
+	var sb strings.Builder
+	rest := page.markdown
+	for {
+		start := strings.Index(rest, link)
+		if start < 0 {
+			break
+		}
+		// Annotate after the whole Markdown link or shortcode, and never at the start of a line,
+		// where the comment would turn the line into an HTML block
+		end := start + len(link)
+		switch {
+		case strings.HasSuffix(rest[:start], "("):
+			if i := strings.IndexByte(rest[end:], ')'); i >= 0 {
+				end += i + 1
+			}
+		case strings.HasSuffix(rest[:start], `src="`):
+			if i := strings.Index(rest[end:], ">}}"); i >= 0 {
+				end += i + len(">}}")
+			}
+		}
+		sb.WriteString(rest[:end])
+		sb.WriteString(comment)
+		rest = rest[end:]
+	}
+	sb.WriteString(rest)
+	page.markdown = sb.String()
+}
@@ -0,0 +1,40 @@
+package hugopage
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnnotateUnhandledShortcode(t *testing.T) {
+	t.Parallel()
+	const htmlInput = `<p>[su_box title="Note"]Be careful[/su_box]</p><p>Quoting [sic] is not a shortcode</p><pre>[su_box title="Code"]</pre>`
+
+	markdown := newTestPage(t, htmlInput, PageOptions{AnnotateIssues: true}).Markdown()
+	require.Contains(t, markdown, `<!-- wp2hugo: unhandled shortcode [su_box] -->Be careful`)
+	require.Equal(t, 1, strings.Count(markdown, "<!-- wp2hugo:"))
+
+	require.NotContains(t, newTestPage(t, htmlInput, PageOptions{}).Markdown(), "<!-- wp2hugo:")
+}
+
+func TestAnnotateFlattenedLayoutBlock(t *testing.T) {
+	t.Parallel()
+	const htmlInput = `<!-- wp:columns {"align":"wide"} --><div class="wp-block-columns"><div class="wp-block-column"><p>Left</p></div><div class="wp-block-column"><p>Right</p></div></div><!-- /wp:columns -->`
+	require.Equal(t, "<!-- wp2hugo: flattened layout block wp:columns -->\n\nLeft\n\nRight",
+		newTestPage(t, htmlInput, PageOptions{AnnotateIssues: true}).Markdown())
+}
+
+func TestAnnotateLink(t *testing.T) {
+	t.Parallel()
+	const htmlInput = `<p><img src="https://example.com/wp-content/uploads/a.jpg" alt="A"></p><p>See <a href="https://example.com/wp-content/uploads/a.jpg">the photo</a></p>`
+
+	page := newTestPage(t, htmlInput, PageOptions{AnnotateIssues: true})
+	page.AnnotateLink("/wp-content/uploads/a.jpg", "media download failed")
+	require.Equal(t, "![A](/wp-content/uploads/a.jpg)<!-- wp2hugo: media download failed -->\n\n"+
+		"See [the photo](/wp-content/uploads/a.jpg)<!-- wp2hugo: media download failed -->", page.Markdown())
+
+	page = newTestPage(t, htmlInput, PageOptions{})
+	page.AnnotateLink("/wp-content/uploads/a.jpg", "media download failed")
+	require.NotContains(t, page.Markdown(), "<!-- wp2hugo:")
+}
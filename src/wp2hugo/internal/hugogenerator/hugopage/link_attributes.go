@@ -0,0 +1,67 @@
+package hugopage
+
+import (
+	"fmt"
+	"html"
+	"slices"
+	"strings"
+
+	md "github.com/JohannesKaufmann/html-to-markdown"
+	"github.com/PuerkitoBio/goquery"
+)
+
+// rel values which WordPress adds to the links opened in a new tab, they don't change a link on their own
+var _defaultRelValues = []string{"noopener", "noreferrer"}
+
+// preserveLinkAttributes keeps the links with a meaningful rel or target attribute as raw HTML links,
+// e.g. the affiliate links, `<a href="..." rel="sponsored nofollow">`, or the links opened in a new tab,
+// since Markdown links have no attributes. Their text is still converted, the other links become Markdown links.
+func preserveLinkAttributes() md.Plugin {
+	return func(c *md.Converter) []md.Rule {
+		return []md.Rule{
+			{
+				Filter: []string{"a"},
+				Replacement: func(content string, selec *goquery.Selection, opt *md.Options) *string {
+					href := strings.TrimSpace(selec.AttrOr("href", ""))
+					attributes := getLinkAttributes(selec)
+					if href == "" || href == "#" || len(attributes) == 0 || strings.TrimSpace(content) == "" {
+						// Converted to a Markdown link
+						return nil
+					}
+					link := fmt.Sprintf(`<a href="%s"`, html.EscapeString(href))
+					for _, name := range []string{"title", "rel", "target"} {
+						if value, ok := attributes[name]; ok {
+							link += fmt.Sprintf(` %s="%s"`, name, html.EscapeString(value))
+						}
+					}
+					text := md.AddSpaceIfNessesary(selec, link+">"+content+"</a>")
+					return &text
+				},
+			},
+		}
+	}
+}
+
+// getLinkAttributes returns the attributes of the link to preserve, none unless its rel or target is meaningful
+func getLinkAttributes(selec *goquery.Selection) map[string]string {
+	rel := strings.Join(strings.Fields(selec.AttrOr("rel", "")), " ")
+	target := strings.TrimSpace(selec.AttrOr("target", ""))
+	meaningfulRel := slices.ContainsFunc(strings.Fields(rel), func(value string) bool {
+		return !slices.Contains(_defaultRelValues, strings.ToLower(value))
+	})
+	meaningfulTarget := target != "" && target != "_self"
+	if !meaningfulRel && !meaningfulTarget {
+		return nil
+	}
+	attributes := make(map[string]string, 3)
+	if rel != "" {
+		attributes["rel"] = rel
+	}
+	if meaningfulTarget {
+		attributes["target"] = target
+	}
+	if title := selec.AttrOr("title", ""); title != "" {
+		attributes["title"] = title
+	}
+	return attributes
+}
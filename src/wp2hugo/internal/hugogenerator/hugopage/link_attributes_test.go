@@ -0,0 +1,32 @@
+package hugopage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreserveLinkAttributes(t *testing.T) {
+	t.Parallel()
+	getMarkdown := func(htmlContent string, preserve bool) string {
+		page := newTestPage(t, htmlContent, PageOptions{PreserveLinkAttributes: preserve})
+		markdown, err := page.getMarkdown(nil, htmlContent, nil)
+		require.NoError(t, err)
+		return *markdown
+	}
+
+	// An affiliate link is not downgraded to a plain Markdown link
+	const affiliateLink = `<p>Buy <a href="https://shop.example.org/item?ref=42&amp;tag=blog" rel="sponsored nofollow">the <strong>book</strong></a> now</p>`
+	require.Equal(t, `Buy <a href="https://shop.example.org/item?ref=42&amp;tag=blog" rel="sponsored nofollow">the **book**</a> now`,
+		getMarkdown(affiliateLink, true))
+	require.Equal(t, "Buy [the **book**](https://shop.example.org/item?ref=42&tag=blog) now", getMarkdown(affiliateLink, false))
+
+	// A link opened in a new tab, internal links are still made relative
+	require.Equal(t, `See <a href="/about/" title="About us" rel="noopener noreferrer" target="_blank">about</a>`,
+		getMarkdown(`<p>See <a href="https://example.com/about/" target="_blank" rel="noopener noreferrer" title="About us">about</a></p>`, true))
+
+	// The plain links, and the default rel values on their own, are converted to Markdown links
+	require.Equal(t, "[plain](https://example.org/) and [noopener](https://example.org/) and [self](https://example.org/)",
+		getMarkdown(`<p><a href="https://example.org/">plain</a> and <a href="https://example.org/" rel="noopener">noopener</a> and `+
+			`<a href="https://example.org/" target="_self">self</a></p>`, true))
+}
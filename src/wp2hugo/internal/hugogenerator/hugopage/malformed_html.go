@@ -0,0 +1,98 @@
+package hugopage
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"golang.org/x/net/html"
+)
+
+const (
+	_htmlCommentStart = "<!--"
+	_htmlCommentEnd   = "-->"
+)
+
+// The HTML parser is lenient and recovers from unclosed or stray tags like browsers do,
+// but per the HTML5 spec, a comment that is never closed runs until the end of the content.
+// Browsers hide the rest of the post then, so we keep it as text instead of silently dropping it.
+func escapeUnterminatedComments(htmlContent string) string {
+	pos := 0
+	for {
+		start := strings.Index(htmlContent[pos:], _htmlCommentStart)
+		if start < 0 {
+			return htmlContent
+		}
+		start += pos
+		end := strings.Index(htmlContent[start+len(_htmlCommentStart):], _htmlCommentEnd)
+		if end < 0 {
+			log.Warn().
+				Int("offset", start).
+				Msg("Unterminated HTML comment, keeping the rest of the content as text")
+			return htmlContent[:start] + strings.ReplaceAll(htmlContent[start:], _htmlCommentStart, "&lt;!--")
+		}
+		pos = start + len(_htmlCommentStart) + end + len(_htmlCommentEnd)
+	}
+}
+
+// Inline formatting elements, which only contain text and other inline elements
+var _formattingTags = []string{"b", "strong", "i", "em", "u", "s", "strike", "del", "ins", "mark", "small", "big", "sub", "sup", "code"}
+
+// Text blocks in which WordPress content commonly leaves formatting elements unclosed
+var _textBlockTags = []string{"p", "li", "h1", "h2", "h3", "h4", "h5", "h6", "td", "th", "dt", "dd", "figcaption"}
+
+var _blockTags = append([]string{"div", "ul", "ol", "dl", "blockquote", "table", "tr", "figure", "pre", "hr",
+	"section", "article", "header", "footer", "aside", "nav"}, _textBlockTags...)
+
+// closeUnclosedFormatting closes the formatting elements left open at the end of a text block,
+// e.g. "<p><b>Bold</p><ul>...". HTML5 parsers reopen them in every following block,
+// which turns the rest of the post bold and breaks the Markdown lists.
+// Well-formed HTML is returned unchanged.
+func closeUnclosedFormatting(htmlContent string) string {
+	var sb strings.Builder
+	var openTags []string
+	inTextBlock := false
+	z := html.NewTokenizer(strings.NewReader(htmlContent))
+	for {
+		tokenType := z.Next()
+		if tokenType == html.ErrorToken {
+			break
+		}
+		raw := z.Raw()
+		if tokenType != html.StartTagToken && tokenType != html.EndTagToken {
+			sb.Write(raw)
+			continue
+		}
+		name, _ := z.TagName()
+		tag := string(name)
+		switch {
+		case slices.Contains(_formattingTags, tag) && tokenType == html.StartTagToken:
+			if inTextBlock {
+				openTags = append(openTags, tag)
+			}
+		case slices.Contains(_formattingTags, tag):
+			for i := len(openTags) - 1; i >= 0; i-- {
+				if openTags[i] == tag {
+					openTags = slices.Delete(openTags, i, i+1)
+					break
+				}
+			}
+		case slices.Contains(_blockTags, tag):
+			for i := len(openTags) - 1; i >= 0; i-- {
+				sb.WriteString("</" + openTags[i] + ">")
+			}
+			if len(openTags) > 0 {
+				log.Debug().
+					Strs("tags", openTags).
+					Msg("Closing the formatting elements left open in a text block")
+			}
+			openTags = openTags[:0]
+			inTextBlock = tokenType == html.StartTagToken && slices.Contains(_textBlockTags, tag)
+		}
+		sb.Write(raw)
+	}
+	for i := len(openTags) - 1; i >= 0; i-- {
+		sb.WriteString("</" + openTags[i] + ">")
+	}
+	return sb.String()
+}
@@ -0,0 +1,34 @@
+package hugopage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEscapeUnterminatedComments(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, "<p>A</p><!-- B --><p>C</p>", escapeUnterminatedComments("<p>A</p><!-- B --><p>C</p>"))
+	require.Equal(t, "<!--more--><p>Text &lt;!-- oops</p><p>Kept &lt;!-- too</p>",
+		escapeUnterminatedComments("<!--more--><p>Text <!-- oops</p><p>Kept <!-- too</p>"))
+}
+
+func TestCloseUnclosedFormatting(t *testing.T) {
+	t.Parallel()
+	const wellFormed = `<p>Some <b>bold</b> and <em>emphasis</em>{{< figure src="/a.jpg" >}}</p><b><p>Bold paragraph</p></b><ul><li><i>Item</i></li></ul>`
+	require.Equal(t, wellFormed, closeUnclosedFormatting(wellFormed))
+
+	require.Equal(t, "<p><b>Bold <i>italic</i></b></p><ul><li>One</li></ul>",
+		closeUnclosedFormatting("<p><b>Bold <i>italic</i></p><ul><li>One</li></ul>"))
+	require.Equal(t, "<p><strong>Never closed</strong><p>Next</p>", closeUnclosedFormatting("<p><strong>Never closed<p>Next</p>"))
+	require.Equal(t, "<li><em>Last</em>", closeUnclosedFormatting("<li><em>Last"))
+}
+
+func TestMalformedHTMLIsNotTruncated(t *testing.T) {
+	t.Parallel()
+	const htmlInput = "<p>One\n<p>Two<br>lines</br>" +
+		`<div><p>Unclosed div <img src="/a.jpg" alt="A"></p>` +
+		"<p><em>Never closed</p><ul><li>First<li>Second</ul>" +
+		"<p>A < B & C <!-- unterminated</p><p>Last</p>"
+	testMarkdownExtractor(t, htmlInput, "One\n\nTwo  \nlines\n\nUnclosed div ![A](/a.jpg)\n\n_Never closed_\n\n- First\n- Second\n\nA < B & C <!-- unterminated\n\nLast")
+}
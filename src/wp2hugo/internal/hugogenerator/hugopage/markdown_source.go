@@ -0,0 +1,38 @@
+package hugopage
+
+import (
+	"regexp"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Block-level tags which are seldom written in Markdown, their presence means that the content
+// was rendered to HTML, e.g. Jetpack Markdown exports the rendered HTML and keeps the source aside
+var _renderedHTMLTagRegEx = regexp.MustCompile(`(?i)<(p|div|h[1-6]|ul|ol|li|blockquote|pre|table|br)\b[^>]*>`)
+
+// getMarkdownFromSource returns the content already written in Markdown, e.g. with Jetpack Markdown
+// or WP-Markdown, with its WordPress shortcodes rewritten. It avoids the lossy Markdown -> HTML -> Markdown round-trip.
+// The "<!--more-->" summary divider is kept, without its custom link text, Hugo reads it from Markdown too.
+func (page *Page) getMarkdownFromSource(provider ImageURLProvider, attachmentIDs []string, content string) string {
+	content, code := extractMarkdownCode(content)
+	if page.options.StripShortcodes == StripAllShortcodes {
+		content = page.stripShortcodes(content, true)
+	}
+	content = normalizeMoreTag(content, _WordPressMoreTag)
+	content = replaceCaptionWithFigure(content)
+	content = replaceAudioShortCode(content)
+	content = replaceGalleryWithFigure(provider, attachmentIDs, content)
+	content = page.replacePlaylistShortCode(provider, attachmentIDs, content)
+	content = page.replaceTocTag(content)
+	if page.options.StripShortcodes == StripUnhandledShortcodes {
+		content = page.stripShortcodes(content, false)
+	}
+	content = restoreCode(content, code)
+	if match := _renderedHTMLTagRegEx.FindString(content); match != "" {
+		log.Warn().
+			Str("page", page.absoluteURL.String()).
+			Str("tag", match).
+			Msg("Content looks like HTML despite --source-is-markdown, it is kept as is")
+	}
+	return content
+}
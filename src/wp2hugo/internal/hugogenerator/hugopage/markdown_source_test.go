@@ -0,0 +1,29 @@
+package hugopage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSourceIsMarkdown(t *testing.T) {
+	t.Parallel()
+	const source = "# Heading\n\nSome *emphasis* and a [link](https://example.com/about/).\n\n" +
+		"[caption id=\"attachment_1\" align=\"alignnone\" width=\"300\"]<img src=\"https://example.com/a.jpg\" alt=\"A\" /> A caption[/caption]\n\n" +
+		"<!--more-->\n\n1. one\n2. two\n"
+	page := newTestPage(t, source, PageOptions{SourceIsMarkdown: true})
+	require.Equal(t, "# Heading\n\nSome *emphasis* and a [link](/about/).\n\n"+
+		"{{< figure align=\"alignnone\" width=300 src=\"/a.jpg\" alt=\"A\" caption=\"A\" >}}\n\n"+
+		"<!--more-->\n\n1. one\n1. two\n", page.Markdown())
+	require.NotContains(t, page.metadata, "summary")
+}
+
+func TestRenderedHTMLTagRegEx(t *testing.T) {
+	t.Parallel()
+	require.True(t, _renderedHTMLTagRegEx.MatchString("<p>Rendered</p>"))
+	require.True(t, _renderedHTMLTagRegEx.MatchString("Line<BR />break"))
+	require.True(t, _renderedHTMLTagRegEx.MatchString(`<h2 id="heading">Heading</h2>`))
+	// Inline HTML is fine in Markdown
+	require.False(t, _renderedHTMLTagRegEx.MatchString("Some <span class=\"x\">inline</span> HTML\n\n<!--more-->"))
+	require.False(t, _renderedHTMLTagRegEx.MatchString("<picture><source srcset=\"a.webp\"></picture>"))
+}
@@ -0,0 +1,67 @@
+package hugopage
+
+import (
+	"slices"
+	"strings"
+)
+
+// Hugo's embedded Open Graph and Twitter Cards templates, and many themes,
+// read the social sharing images from this front matter list.
+// Ref: https://gohugo.io/templates/embedded/#open-graph
+const _openGraphImagesKey = "images"
+
+// setOpenGraphImages emits the featured image, and optionally the first image of the content,
+// as the "images" front matter
+func (page *Page) setOpenGraphImages() {
+	if page.options.OmitOpenGraphImages {
+		return
+	}
+	var images []string
+	if coverImageURL := page.getCoverImageURL(); coverImageURL != nil {
+		images = append(images, page.getOpenGraphImageURL(*coverImageURL))
+	}
+	if page.options.OpenGraphContentImage {
+		if link := getFirstContentImage(page.markdown); link != "" {
+			if imageURL := page.getOpenGraphImageURL(link); !slices.Contains(images, imageURL) {
+				images = append(images, imageURL)
+			}
+		}
+	}
+	if len(images) > 0 {
+		page.metadata[_openGraphImagesKey] = images
+	}
+}
+
+// getOpenGraphImageURL returns the local path of the image when media is downloaded,
+// and the original URL otherwise, since the image is then only served by the WordPress site
+func (page *Page) getOpenGraphImageURL(link string) string {
+	if page.options.LocalMedia || !strings.HasPrefix(link, "/") || strings.HasPrefix(link, "//") {
+		return link
+	}
+	return page.absoluteURL.Scheme + "://" + page.absoluteURL.Host + link
+}
+
+// getFirstContentImage returns the first image of the markdown,
+// whether it is a Markdown image or a figure shortcode
+func getFirstContentImage(markdown string) string {
+	firstLink := ""
+	firstIndex := len(markdown)
+	for _, link := range getImageLinks([]byte(markdown)) {
+		if IsDataURI(link) {
+			continue
+		}
+		if index := strings.Index(markdown, "("+link); index >= 0 {
+			firstLink, firstIndex = link, index
+		}
+		break
+	}
+	if match := _hugoFigureLinks.FindStringSubmatchIndex(markdown); match != nil && match[0] < firstIndex {
+		firstLink = markdown[match[2]:match[3]]
+	}
+	return firstLink
+}
+
+// IsDataURI reports whether the link is a data URI, e.g. the "data:image/png;base64,..." of an image embedded in the content
+func IsDataURI(link string) bool {
+	return strings.HasPrefix(link, "data:")
+}
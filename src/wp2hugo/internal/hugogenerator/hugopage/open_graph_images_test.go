@@ -0,0 +1,60 @@
+package hugopage
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testImageURLProvider struct{}
+
+func (testImageURLProvider) GetImageInfo(imageID string) (*ImageInfo, error) {
+	return &ImageInfo{ImageURL: "https://example.com/wp-content/uploads/" + imageID + ".jpg", Title: "Featured"}, nil
+}
+
+func TestOpenGraphImages(t *testing.T) {
+	t.Parallel()
+	pageURL, err := url.Parse("https://example.com/hello-world/")
+	require.NoError(t, err)
+	featuredImageID := "featured"
+	htmlContent := `<p>Hello</p><p><img src="https://example.com/wp-content/uploads/first.png" alt="First"></p>` +
+		`<p><img src="https://example.com/wp-content/uploads/second.png" alt="Second"></p>`
+
+	testCases := []struct {
+		name     string
+		options  PageOptions
+		expected any
+	}{
+		{"remote media", PageOptions{}, []string{"https://example.com/wp-content/uploads/featured.jpg"}},
+		{"local media", PageOptions{LocalMedia: true}, []string{"/wp-content/uploads/featured.jpg"}},
+		{"with content image", PageOptions{LocalMedia: true, OpenGraphContentImage: true},
+			[]string{"/wp-content/uploads/featured.jpg", "/wp-content/uploads/first.png"}},
+		{"opt-out", PageOptions{OmitOpenGraphImages: true}, nil},
+	}
+	for _, testCase := range testCases {
+		page, err := NewPage(testImageURLProvider{}, *pageURL, "author", "Title", nil, nil, false, nil, nil, nil, nil,
+			htmlContent, nil, &featuredImageID, nil, nil, nil, "1", nil, testCase.options)
+		require.NoError(t, err, testCase.name)
+		require.Equal(t, testCase.expected, page.metadata["images"], testCase.name)
+	}
+
+	// Without a featured image
+	page := newTestPageAt(t, "https://example.com/hello-world/", htmlContent, PageOptions{OpenGraphContentImage: true})
+	require.Equal(t, []string{"https://example.com/wp-content/uploads/first.png"}, page.metadata["images"])
+}
+
+func TestGetFirstContentImage(t *testing.T) {
+	t.Parallel()
+	require.Empty(t, getFirstContentImage("No image here"))
+	require.Equal(t, "/a.png", getFirstContentImage(`Text {{< figure src="/a.png" >}} ![b](/b.png)`))
+	require.Equal(t, "/b.png", getFirstContentImage(`![b](/b.png) {{< figure src="/a.png" >}}`))
+	require.Equal(t, "/b.png", getFirstContentImage(`![inline](data:image/png;base64,AAAA) ![b](/b.png)`))
+}
+
+func TestReplaceUpdatesOpenGraphImages(t *testing.T) {
+	t.Parallel()
+	page := Page{metadata: map[string]any{"images": []string{"/wp-content/uploads/photo-640x480.jpg"}}}
+	page.Replace(map[string]string{"/wp-content/uploads/photo-640x480.jpg": "/wp-content/uploads/photo.jpg"})
+	require.Equal(t, []string{"/wp-content/uploads/photo.jpg"}, page.metadata["images"])
+}
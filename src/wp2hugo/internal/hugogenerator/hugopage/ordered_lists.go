@@ -0,0 +1,56 @@
+package hugopage
+
+import (
+	"regexp"
+
+	"github.com/rs/zerolog/log"
+)
+
+// An ordered list whose first item sets its number, e.g. `<ol><li value="4">`, like `<ol start="4">`
+var _orderedListFirstItemValueRegEx = regexp.MustCompile(`(?i)<ol\b([^>]*)>(\s*)<li\b([^>]*?)\s+value=["']?(\d+)["']?([^>]*)>`)
+
+// The numbering Markdown can't represent: the items numbered within the list, and the reversed lists.
+// Markdown only takes the number of the first item of a list, the following items are numbered from it.
+var (
+	_listItemValueRegEx       = regexp.MustCompile(`(?i)<li\b[^>]*\svalue=[^>]*>`)
+	_reversedOrderedListRegEx = regexp.MustCompile(`(?i)<ol\b[^>]*\sreversed\b[^>]*>`)
+	_listStartAttributeRegEx  = regexp.MustCompile(`(?i)\sstart=`)
+)
+
+// preserveOrderedListNumbers keeps the numbering of the ordered lists which continue another one, e.g. after an image,
+// when their first item is numbered with a value attribute instead of the start attribute of the list.
+// The lists with a start attribute keep it as is, Markdown takes the start of a list from its first item.
+// The numbering which Markdown can't represent is logged, and annotated with PageOptions.AnnotateIssues,
+// it resets in Markdown.
+func (page *Page) preserveOrderedListNumbers(htmlContent string) string {
+	htmlContent = replaceAllStringSubmatchFunc(_orderedListFirstItemValueRegEx, htmlContent, func(groups []string) string {
+		if _listStartAttributeRegEx.MatchString(groups[1]) {
+			return groups[0]
+		}
+		log.Debug().
+			Str("start", groups[4]).
+			Msg("Ordered list numbered from its first item")
+		return `<ol` + groups[1] + ` start="` + groups[4] + `">` + groups[2] + `<li` + groups[3] + groups[5] + `>`
+	})
+
+	reversed := _reversedOrderedListRegEx.MatchString(htmlContent)
+	numberedItems := _listItemValueRegEx.MatchString(htmlContent)
+	if !reversed && !numberedItems {
+		return htmlContent
+	}
+	log.Warn().
+		Str("page", page.absoluteURL.String()).
+		Bool("reversed", reversed).
+		Bool("numberedItems", numberedItems).
+		Msg("Ordered list numbering can't be represented in Markdown, it resets, review the lists")
+	if !page.options.AnnotateIssues {
+		return htmlContent
+	}
+	comment := newHTMLComment("ordered list numbering not preserved")
+	htmlContent = _reversedOrderedListRegEx.ReplaceAllStringFunc(htmlContent, func(tag string) string {
+		return "<p>" + comment + "</p>" + tag
+	})
+	return _listItemValueRegEx.ReplaceAllStringFunc(htmlContent, func(tag string) string {
+		return tag + comment
+	})
+}
@@ -0,0 +1,42 @@
+package hugopage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderedListSplitByImage(t *testing.T) {
+	t.Parallel()
+	const htmlContent = `<!-- wp:list {"ordered":true} -->
+<ol><li>Pack the bags</li><li>Drive to the trailhead</li><li>Climb to the hut</li></ol>
+<!-- /wp:list -->
+
+<!-- wp:image {"id":12} -->
+<figure class="wp-block-image"><img src="https://example.com/wp-content/uploads/hut.jpg" alt="The hut"/></figure>
+<!-- /wp:image -->
+
+<!-- wp:list {"ordered":true} -->
+<ol><li value="4">Reach the summit</li><li>Walk back down</li></ol>
+<!-- /wp:list -->`
+	const expected = "1. Pack the bags\n1. Drive to the trailhead\n1. Climb to the hut\n\n" +
+		"{{< figure src=\"/wp-content/uploads/hut.jpg\" alt=\"The hut\" caption=\"The hut\" >}}\n\n" +
+		"4. Reach the summit\n5. Walk back down"
+	testMarkdownExtractor(t, htmlContent, expected)
+}
+
+func TestOrderedListFirstItemValue(t *testing.T) {
+	t.Parallel()
+	testMarkdownExtractor(t, `<ol><li>One</li></ol><p>Then</p><ol><li value="2">Two</li><li>Three</li></ol>`,
+		"1. One\n\nThen\n\n2. Two\n3. Three")
+	// The start attribute wins
+	testMarkdownExtractor(t, `<ol start="5"><li value="2">Five</li></ol>`, "5. Five")
+}
+
+func TestOrderedListNumberingNotPreserved(t *testing.T) {
+	t.Parallel()
+	const htmlContent = `<ol reversed><li>Two</li><li>One</li></ol><ol><li>One</li><li value="5">Five</li></ol>`
+	page := newTestPage(t, htmlContent, PageOptions{AnnotateIssues: true})
+	require.Equal(t, "<!-- wp2hugo: ordered list numbering not preserved -->\n\n1. Two\n1. One\n\n"+
+		"1. One\n1. <!-- wp2hugo: ordered list numbering not preserved -->Five", page.Markdown())
+}
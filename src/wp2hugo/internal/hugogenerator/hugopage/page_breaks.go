@@ -0,0 +1,67 @@
+package hugopage
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// The TinyMCE "read more" tag, optionally with a custom link text, e.g. <!--more Continue reading-->
+	_moreTagRegEx = regexp.MustCompile(`<!--more(?:\s[^>]*?)?-->`)
+	// Hides the summary on the page itself, which Hugo can't do
+	_noTeaserTag = "<!--noteaser-->"
+
+	// The TinyMCE page break, which paginates the content, along with its Gutenberg block if any
+	_nextPageTagRegEx = regexp.MustCompile(`(?:<!-- wp:nextpage -->\s*)?<!--nextpage-->(?:\s*<!-- /wp:nextpage -->)?`)
+)
+
+// HasNextPages reports whether the content is paginated with <!--nextpage--> tags
+func HasNextPages(content string) bool {
+	return _nextPageTagRegEx.MatchString(content)
+}
+
+// SplitNextPages splits the content at its <!--nextpage--> tags, into the pages WordPress paginates it into
+func SplitNextPages(content string) []string {
+	parts := _nextPageTagRegEx.Split(content, -1)
+	for i, part := range parts {
+		parts[i] = strings.TrimSpace(part)
+	}
+	return parts
+}
+
+// collapseNextPages replaces the <!--nextpage--> tags with a thematic break, for the content to be one page
+func collapseNextPages(content string, sourceIsMarkdown bool) string {
+	if sourceIsMarkdown {
+		return _nextPageTagRegEx.ReplaceAllString(content, "\n\n---\n\n")
+	}
+	return _nextPageTagRegEx.ReplaceAllString(content, "<hr />")
+}
+
+// normalizeMoreTag replaces the first "read more" tag with moreTag, and removes the others,
+// since WordPress only splits the summary at the first one
+func normalizeMoreTag(content string, moreTag string) string {
+	content = strings.ReplaceAll(content, _noTeaserTag, "")
+	replaced := false
+	return _moreTagRegEx.ReplaceAllStringFunc(content, func(string) string {
+		if replaced {
+			return ""
+		}
+		replaced = true
+		return moreTag
+	})
+}
+
+// SetPart emits the number of the page of the content split at its <!--nextpage--> tags, and their count.
+// The following pages are only reachable from the page links, they are left out of the lists and feeds.
+func (page *Page) SetPart(number int, count int) {
+	page.metadata["part"] = number
+	page.metadata["parts"] = count
+	if number == 1 {
+		return
+	}
+	if build, ok := page.metadata[_buildKey].(map[string]string); ok {
+		build["list"] = "never"
+	} else {
+		page.metadata[_buildKey] = map[string]string{"list": "never"}
+	}
+}
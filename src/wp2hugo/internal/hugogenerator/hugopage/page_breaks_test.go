@@ -0,0 +1,23 @@
+package hugopage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitNextPages(t *testing.T) {
+	t.Parallel()
+	content := "<p>One</p>\n<!--nextpage-->\n<p>Two</p>\n<!-- wp:nextpage -->\n<!--nextpage-->\n<!-- /wp:nextpage -->\n<p>Three</p>"
+	require.True(t, HasNextPages(content))
+	require.Equal(t, []string{"<p>One</p>", "<p>Two</p>", "<p>Three</p>"}, SplitNextPages(content))
+	require.Equal(t, "<p>One</p>\n<hr />\n<p>Two</p>\n<hr />\n<p>Three</p>", collapseNextPages(content, false))
+	require.False(t, HasNextPages("<p>One</p><!--more-->"))
+}
+
+func TestNormalizeMoreTag(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, "Teaser<!--more-->Rest", normalizeMoreTag("Teaser<!--more Continue reading-->Rest", _WordPressMoreTag))
+	require.Equal(t, "Teaser{{< more >}}Rest and more", normalizeMoreTag("Teaser<!--more--><!--noteaser-->Rest<!--more--> and more", _customMoreTag))
+	require.Equal(t, "<!--moreover-->", normalizeMoreTag("<!--moreover-->", _customMoreTag))
+}
@@ -0,0 +1,202 @@
+package hugopage
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// A value produced by PHP's serialize(), as commonly found in WordPress postmeta, e.g.
+// a:2:{i:0;s:3:"foo";i:1;a:1:{s:3:"bar";b:1;}}
+var _phpSerializedRegEx = regexp.MustCompile(`^(?:a:\d+:\{|O:\d+:"|s:\d+:"|i:-?\d+;$|d:[^;]+;$|b:[01];$|N;$)`)
+
+// Nested arrays deeper than this are most likely corrupted data
+const _maxPHPSerializedDepth = 64
+
+var errPHPSerializedTrailingData = errors.New("trailing data after serialized value")
+
+func looksPHPSerialized(value string) bool {
+	return _phpSerializedRegEx.MatchString(value)
+}
+
+// unserializePHP decodes a PHP-serialized value into Go values:
+// arrays with sequential integer keys become []any, other arrays and objects
+// become map[string]any, and scalars become string, int, float64, bool or nil.
+func unserializePHP(value string) (any, error) {
+	u := phpUnserializer{data: value}
+	result, err := u.readValue(0)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(u.data[u.pos:]) != "" {
+		return nil, fmt.Errorf("%w at offset %d", errPHPSerializedTrailingData, u.pos)
+	}
+	return result, nil
+}
+
+type phpUnserializer struct {
+	data string
+	pos  int
+}
+
+func (u *phpUnserializer) readValue(depth int) (any, error) {
+	if depth > _maxPHPSerializedDepth {
+		return nil, fmt.Errorf("serialized value nested too deep at offset %d", u.pos)
+	}
+	if u.pos+1 >= len(u.data) {
+		return nil, fmt.Errorf("unexpected end of serialized value")
+	}
+	token := u.data[u.pos]
+	if token == 'N' {
+		return nil, u.expect("N;")
+	}
+	u.pos++
+	if err := u.expect(":"); err != nil {
+		return nil, err
+	}
+
+	switch token {
+	case 'b':
+		raw, err := u.readUntil(';')
+		if err != nil {
+			return nil, err
+		}
+		return raw == "1", nil
+	case 'i':
+		raw, err := u.readUntil(';')
+		if err != nil {
+			return nil, err
+		}
+		return strconv.Atoi(raw)
+	case 'd':
+		raw, err := u.readUntil(';')
+		if err != nil {
+			return nil, err
+		}
+		return strconv.ParseFloat(raw, 64)
+	case 's':
+		str, err := u.readString()
+		if err != nil {
+			return nil, err
+		}
+		return str, u.expect(";")
+	case 'a':
+		return u.readArray(depth)
+	case 'O':
+		// O:8:"stdClass":1:{s:1:"a";i:1;}, we only keep the properties
+		if _, err := u.readString(); err != nil {
+			return nil, err
+		}
+		if err := u.expect(":"); err != nil {
+			return nil, err
+		}
+		return u.readArray(depth)
+	default:
+		return nil, fmt.Errorf("unknown serialized type %q at offset %d", token, u.pos-2)
+	}
+}
+
+// readString reads `N:"..."`.
+// The declared byte length is often wrong in WordPress exports, since the data
+// went through charset or line-ending conversions after being serialized.
+// In that case, we fall back to the closing `"` followed by a `;` or `:`.
+func (u *phpUnserializer) readString() (string, error) {
+	raw, err := u.readUntil(':')
+	if err != nil {
+		return "", err
+	}
+	length, err := strconv.Atoi(raw)
+	if err != nil || length < 0 {
+		return "", fmt.Errorf("invalid string length %q at offset %d", raw, u.pos)
+	}
+	if err := u.expect(`"`); err != nil {
+		return "", err
+	}
+
+	end := u.pos + length
+	if end+1 < len(u.data) && u.data[end] == '"' && (u.data[end+1] == ';' || u.data[end+1] == ':') {
+		str := u.data[u.pos:end]
+		u.pos = end + 1
+		return str, nil
+	}
+	for i := u.pos; i+1 < len(u.data); i++ {
+		if u.data[i] == '"' && (u.data[i+1] == ';' || u.data[i+1] == ':') {
+			str := u.data[u.pos:i]
+			u.pos = i + 1
+			return str, nil
+		}
+	}
+	return "", fmt.Errorf("unterminated string at offset %d", u.pos)
+}
+
+func (u *phpUnserializer) readArray(depth int) (any, error) {
+	raw, err := u.readUntil(':')
+	if err != nil {
+		return nil, err
+	}
+	size, err := strconv.Atoi(raw)
+	if err != nil || size < 0 {
+		return nil, fmt.Errorf("invalid array size %q at offset %d", raw, u.pos)
+	}
+	if err := u.expect("{"); err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, min(size, len(u.data)))
+	values := make([]any, 0, min(size, len(u.data)))
+	isList := true
+	for i := 0; i < size; i++ {
+		key, err := u.readValue(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		switch k := key.(type) {
+		case int:
+			isList = isList && k == i
+			keys = append(keys, strconv.Itoa(k))
+		case string:
+			isList = false
+			keys = append(keys, k)
+		default:
+			return nil, fmt.Errorf("invalid array key %v at offset %d", key, u.pos)
+		}
+
+		value, err := u.readValue(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	if err := u.expect("}"); err != nil {
+		return nil, err
+	}
+
+	if isList {
+		return values, nil
+	}
+	result := make(map[string]any, len(keys))
+	for i, key := range keys {
+		result[key] = values[i]
+	}
+	return result, nil
+}
+
+func (u *phpUnserializer) readUntil(delimiter byte) (string, error) {
+	end := strings.IndexByte(u.data[u.pos:], delimiter)
+	if end < 0 {
+		return "", fmt.Errorf("expected %q after offset %d", delimiter, u.pos)
+	}
+	raw := u.data[u.pos : u.pos+end]
+	u.pos += end + 1
+	return raw, nil
+}
+
+func (u *phpUnserializer) expect(s string) error {
+	if !strings.HasPrefix(u.data[u.pos:], s) {
+		return fmt.Errorf("expected %q at offset %d", s, u.pos)
+	}
+	u.pos += len(s)
+	return nil
+}
@@ -0,0 +1,73 @@
+package hugopage
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnserializePHP(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		input    string
+		expected any
+	}{
+		{`s:3:"foo";`, "foo"},
+		{`i:-5;`, -5},
+		{`d:1.5;`, 1.5},
+		{`b:1;`, true},
+		{`N;`, nil},
+		{`a:2:{i:0;s:3:"foo";i:1;s:3:"bar";}`, []any{"foo", "bar"}},
+		{`a:2:{i:3;s:3:"foo";i:7;s:3:"bar";}`, map[string]any{"3": "foo", "7": "bar"}},
+		// Nested arrays and embedded quotes
+		{
+			`a:2:{s:5:"title";s:10:"Say "hi";!";s:4:"list";a:1:{i:0;a:1:{s:3:"url";s:1:"/";}}}`,
+			map[string]any{"title": `Say "hi";!`, "list": []any{map[string]any{"url": "/"}}},
+		},
+		// Wrong string length, e.g. after a charset conversion
+		{`a:1:{i:0;s:3:"héllo";}`, []any{"héllo"}},
+		{`O:8:"stdClass":1:{s:1:"a";i:1;}`, map[string]any{"a": 1}},
+	}
+	for _, testCase := range testCases {
+		require.True(t, looksPHPSerialized(testCase.input), testCase.input)
+		result, err := unserializePHP(testCase.input)
+		require.NoError(t, err, testCase.input)
+		require.Equal(t, testCase.expected, result, testCase.input)
+	}
+}
+
+func TestUnserializePHPErrors(t *testing.T) {
+	t.Parallel()
+	for _, input := range []string{
+		`a:2:{i:0;s:2:"12"`,
+		`a:1:{i:0;s:2:"12";`,
+		`s:3:"foo`,
+		`x:1;`,
+		`i:1;i:2;`,
+	} {
+		_, err := unserializePHP(input)
+		require.Error(t, err, input)
+	}
+	require.False(t, looksPHPSerialized("just a string"))
+	require.False(t, looksPHPSerialized("a:b"))
+}
+
+func TestSerializedPostMetaInFrontMatter(t *testing.T) {
+	t.Parallel()
+	url1, err := url.Parse("https://example.com/hello-world/")
+	require.NoError(t, err)
+	customMetaData := []wpparser.CustomMetaDatum{
+		{Key: "list", Value: `a:2:{i:0;s:3:"foo";i:1;s:3:"bar";}`},
+		{Key: "broken", Value: `a:2:{i:0;s:3:"foo";`},
+		{Key: "plain", Value: "hello"},
+	}
+
+	metadata, err := getMetadata(nil, *url1, "author", "Title", nil, nil, false, nil, nil, nil, nil, nil, customMetaData, nil, "42", nil, PageOptions{})
+	require.NoError(t, err)
+	require.Equal(t, []any{"foo", "bar"}, metadata["list"])
+	require.Equal(t, `a:2:{i:0;s:3:"foo";`, metadata["broken"])
+	require.Equal(t, "hello", metadata["plain"])
+}
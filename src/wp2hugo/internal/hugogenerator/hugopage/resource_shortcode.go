@@ -0,0 +1,52 @@
+package hugopage
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ResourceShortCodeName renders images of Hugo's asset pipeline, fetched with resources.Get
+const ResourceShortCodeName = "resource"
+
+// E.g. ![The summit](/wp-content/uploads/2024/03/summit.jpg "Summit")
+var _markdownImageRegEx = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]+)(?:\s+"([^"]*)")?\)`)
+
+// E.g. {{< figure align="aligncenter" src="/wp-content/uploads/2024/03/summit.jpg" alt="The summit" >}}
+var _hugoFigureSrcRegEx = regexp.MustCompile(`{{< figure(.*?) src="([^"]+?)"(.*?) >}}`)
+
+// ReplaceImagesWithResourceShortcode references the local images of the content through
+// the resource shortcode, for processing them with Hugo's asset pipeline.
+// Resources are looked up relative to the assets dir, hence without the leading "/".
+func (page *Page) ReplaceImagesWithResourceShortcode() {
+	page.markdown = replaceAllStringSubmatchFunc(_markdownImageRegEx, page.markdown, func(groups []string) string {
+		alt, link, title := groups[1], groups[2], groups[3]
+		if !isLocalLink(link) {
+			return groups[0]
+		}
+		shortcode := fmt.Sprintf(`{{< %s src="%s" alt="%s"`, ResourceShortCodeName, toResourcePath(link), escapeShortcodeParam(alt))
+		if title != "" {
+			shortcode += fmt.Sprintf(` title="%s"`, escapeShortcodeParam(title))
+		}
+		return shortcode + " >}}"
+	})
+	page.markdown = replaceAllStringSubmatchFunc(_hugoFigureSrcRegEx, page.markdown, func(groups []string) string {
+		if !isLocalLink(groups[2]) {
+			return groups[0]
+		}
+		return fmt.Sprintf(`{{< %s%s src="%s"%s >}}`, ResourceShortCodeName, groups[1], toResourcePath(groups[2]), groups[3])
+	})
+}
+
+// Site-relative links, as opposed to external and protocol-relative links
+func isLocalLink(link string) bool {
+	return strings.HasPrefix(link, "/") && !strings.HasPrefix(link, "//")
+}
+
+func toResourcePath(link string) string {
+	return strings.TrimPrefix(link, "/")
+}
+
+func escapeShortcodeParam(value string) string {
+	return strings.ReplaceAll(value, `"`, `\"`)
+}
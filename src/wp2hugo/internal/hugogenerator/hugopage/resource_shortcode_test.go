@@ -0,0 +1,43 @@
+package hugopage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplaceImagesWithResourceShortcode(t *testing.T) {
+	t.Parallel()
+	page := Page{markdown: `![The summit](/wp-content/uploads/summit.jpg "Summit")
+
+![External](https://example.org/a.jpg)
+
+{{< figure align="aligncenter" width=640 src="/wp-content/uploads/valley.jpg" alt="The \"valley\"" caption="Valley" >}}
+
+{{< figure src="//cdn.example.org/b.jpg" alt="" >}}`}
+	page.ReplaceImagesWithResourceShortcode()
+	require.Equal(t, `{{< resource src="wp-content/uploads/summit.jpg" alt="The summit" title="Summit" >}}
+
+![External](https://example.org/a.jpg)
+
+{{< resource align="aligncenter" width=640 src="wp-content/uploads/valley.jpg" alt="The \"valley\"" caption="Valley" >}}
+
+{{< figure src="//cdn.example.org/b.jpg" alt="" >}}`, page.Markdown())
+}
+
+func TestWPResourceAndStaticLinks(t *testing.T) {
+	t.Parallel()
+	page := Page{
+		markdown: `![A](/wp-content/uploads/a.jpg)
+
+{{< figure src="/wp-content/uploads/b.jpg" alt="" >}}
+
+{{< audio src="/wp-content/uploads/c.mp3" >}}
+
+[Menu](/wp-content/uploads/menu.pdf)`,
+		metadata: map[string]any{"cover": map[string]string{"image": "/wp-content/uploads/cover.jpg"}},
+	}
+	require.Equal(t, []string{"/wp-content/uploads/a.jpg", "/wp-content/uploads/b.jpg"}, page.WPResourceLinks())
+	require.Equal(t, []string{"/wp-content/uploads/c.mp3", "/wp-content/uploads/menu.pdf", "/wp-content/uploads/cover.jpg"},
+		page.WPStaticLinks())
+}
@@ -0,0 +1,51 @@
+package hugopage
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Destinations of the Markdown links and href attributes of the raw HTML, up to their fragment
+var _fragmentLinkRegEx = regexp.MustCompile(`(\]\(|href=["'])([^\s()"'#]*)#`)
+
+// isPageRelativeLink reports whether the link is relative to the page itself, e.g. #top or ?replytocom=5,
+// which is kept as is since it works wherever the page is served
+func isPageRelativeLink(link string) bool {
+	return link == "" || strings.HasPrefix(link, "#") || strings.HasPrefix(link, "?")
+}
+
+// replaceSamePageLinks replaces the links to the page itself plus a fragment, e.g. https://example.com/post/#section,
+// with the bare fragment, #section, which does not depend on the URL of the page
+func replaceSamePageLinks(pageURL url.URL, markdown string) string {
+	if !strings.Contains(markdown, "#") {
+		return markdown
+	}
+	return _fragmentLinkRegEx.ReplaceAllStringFunc(markdown, func(match string) string {
+		groups := _fragmentLinkRegEx.FindStringSubmatch(match)
+		if isPageRelativeLink(groups[2]) || !isSamePage(pageURL, groups[2]) {
+			return match
+		}
+		return groups[1] + "#"
+	})
+}
+
+// isSamePage reports whether the link, absolute or relative to the site, points to the page
+func isSamePage(pageURL url.URL, link string) bool {
+	linkURL, err := url.Parse(link)
+	if err != nil || linkURL.RawQuery != "" || linkURL.Opaque != "" {
+		return false
+	}
+	if linkURL.Host != "" {
+		if linkURL.Scheme != "" && linkURL.Scheme != "http" && linkURL.Scheme != "https" {
+			return false
+		}
+		if strings.TrimPrefix(linkURL.Host, "www.") != strings.TrimPrefix(pageURL.Host, "www.") {
+			return false
+		}
+	} else if !strings.HasPrefix(linkURL.Path, "/") {
+		// Relative to the dir of the page, e.g. "post/#section", left as is
+		return false
+	}
+	return strings.TrimSuffix(linkURL.Path, "/") == strings.TrimSuffix(pageURL.Path, "/")
+}
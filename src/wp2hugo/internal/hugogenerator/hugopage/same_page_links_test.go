@@ -0,0 +1,42 @@
+package hugopage
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplaceSamePageLinks(t *testing.T) {
+	t.Parallel()
+	pageURL, err := url.Parse("https://example.com/2024/hello/")
+	require.NoError(t, err)
+
+	testCases := map[string]string{
+		// Relative to the page, left as is
+		"[Back to top](#top)":            "[Back to top](#top)",
+		"[Reply](?replytocom=5)":         "[Reply](?replytocom=5)",
+		"[Reply](?replytocom=5#respond)": "[Reply](?replytocom=5#respond)",
+		// The page itself
+		"[Section](https://example.com/2024/hello/#section)":      "[Section](#section)",
+		"[Section](http://www.example.com/2024/hello#section)":    "[Section](#section)",
+		"[Section](/2024/hello/#section)":                         "[Section](#section)",
+		`<a href="https://example.com/2024/hello/#section">S</a>`: `<a href="#section">S</a>`,
+		// Other pages, or the page with a query
+		"[Other](https://example.com/2024/other/#section)":         "[Other](https://example.com/2024/other/#section)",
+		"[Other](https://other.com/2024/hello/#section)":           "[Other](https://other.com/2024/hello/#section)",
+		"[Comment](https://example.com/2024/hello/?c=1#comment-1)": "[Comment](https://example.com/2024/hello/?c=1#comment-1)",
+		"[Relative](hello/#section)":                               "[Relative](hello/#section)",
+	}
+	for markdown, expected := range testCases {
+		require.Equal(t, expected, replaceSamePageLinks(*pageURL, markdown), markdown)
+	}
+}
+
+func TestSamePageLinksInPage(t *testing.T) {
+	t.Parallel()
+	htmlInput := `<p><a href="#top">Top</a> <a href="?replytocom=5">Reply</a> ` +
+		`<a href="https://example.com/2024/hello/#section">Section</a> <a href="https://example.com/2024/other/">Other</a></p>`
+	page := newTestPageAt(t, "https://example.com/2024/hello/", htmlInput, PageOptions{})
+	require.Equal(t, "[Top](#top) [Reply](?replytocom=5) [Section](#section) [Other](/2024/other/)", page.markdown)
+}
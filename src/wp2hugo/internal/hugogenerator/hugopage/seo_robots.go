@@ -0,0 +1,165 @@
+package hugopage
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+)
+
+// The robots directives are emitted as a "robots" front matter, e.g. "noindex, nofollow",
+// for the <meta name="robots"> tag of the theme.
+// Noindexed content is also excluded from the sitemap with `sitemap: {disable: true}`, see NoIndexExclusion.
+const (
+	_robotsKey  = "robots"
+	_sitemapKey = "sitemap"
+	_buildKey   = "build"
+
+	_noIndex  = "noindex"
+	_noFollow = "nofollow"
+)
+
+// Yoast SEO postmeta, "1" enables the directive, "2" (noindex only) forces indexing
+const (
+	_yoastNoIndexKey  = "_yoast_wpseo_meta-robots-noindex"
+	_yoastNoFollowKey = "_yoast_wpseo_meta-robots-nofollow"
+	// CSV list of the advanced directives, e.g. "noimageindex,noarchive", or "-" for none
+	_yoastAdvancedKey = "_yoast_wpseo_meta-robots-adv"
+)
+
+// Rank Math postmeta, a PHP-serialized list of directives, e.g. a:2:{i:0;s:7:"noindex";i:1;s:8:"nofollow";}
+const _rankMathRobotsKey = "rank_math_robots"
+
+// Postmeta excluding the content from the sitemap only, without noindexing it:
+// older Yoast SEO versions ("never") and All in One SEO ("on")
+const (
+	_yoastSitemapIncludeKey  = "_yoast_wpseo_sitemap-include"
+	_aioseoSitemapExcludeKey = "_aioseop_sitemap_exclude"
+)
+
+// NoIndexExclusion decides how the noindexed content is excluded from the Hugo site
+type NoIndexExclusion string
+
+const (
+	// NoIndexExcludeNothing only emits the robots directives, the content stays in the sitemap,
+	// including the content excluded from the sitemap only
+	NoIndexExcludeNothing NoIndexExclusion = "none"
+	// NoIndexExcludeFromSitemap excludes the noindexed content from the sitemap, with `sitemap: {disable: true}`
+	NoIndexExcludeFromSitemap NoIndexExclusion = "sitemap"
+	// NoIndexUnlisted also excludes it from the section lists and the RSS feeds, with `build: {list: never}`.
+	// The pages are still rendered at their URL.
+	NoIndexUnlisted NoIndexExclusion = "unlisted"
+	// NoIndexUnrendered does not render the pages at all, with `build: {list: never, render: never}`
+	NoIndexUnrendered NoIndexExclusion = "unrendered"
+)
+
+func ParseNoIndexExclusion(exclusion string) (NoIndexExclusion, error) {
+	switch NoIndexExclusion(exclusion) {
+	case NoIndexExcludeNothing, NoIndexExcludeFromSitemap, NoIndexUnlisted, NoIndexUnrendered:
+		return NoIndexExclusion(exclusion), nil
+	case "":
+		return NoIndexExcludeFromSitemap, nil
+	default:
+		return "", fmt.Errorf("unknown noindex exclusion %q, expected one of %s, %s, %s, %s", exclusion,
+			NoIndexExcludeNothing, NoIndexExcludeFromSitemap, NoIndexUnlisted, NoIndexUnrendered)
+	}
+}
+
+// getRobotsDirectives returns the restrictive robots directives set by the SEO plugins,
+// along with the set of postmeta keys consumed in the process
+func getRobotsDirectives(customMetaData []wpparser.CustomMetaDatum) ([]string, map[string]bool) {
+	var directives []string
+	consumedKeys := make(map[string]bool)
+	add := func(directive string) {
+		directive = strings.ToLower(strings.TrimSpace(directive))
+		// "index" and "follow" are the defaults, "none" and "-" mean no advanced directive
+		if strings.HasPrefix(directive, "no") && directive != "none" && !slices.Contains(directives, directive) {
+			directives = append(directives, directive)
+		}
+	}
+	for _, metadatum := range customMetaData {
+		switch metadatum.Key {
+		case _yoastNoIndexKey:
+			if metadatum.Value == "1" {
+				add(_noIndex)
+			}
+		case _yoastNoFollowKey:
+			if metadatum.Value == "1" {
+				add(_noFollow)
+			}
+		case _yoastAdvancedKey:
+			for directive := range strings.SplitSeq(metadatum.Value, ",") {
+				add(directive)
+			}
+		case _rankMathRobotsKey:
+			values, _ := UnserialiazePHParray(metadatum.Value).([]any)
+			for _, value := range values {
+				if directive, ok := value.(string); ok {
+					add(directive)
+				}
+			}
+		default:
+			continue
+		}
+		consumedKeys[metadatum.Key] = true
+	}
+	// Same order as the plugins write them: noindex, nofollow, then the advanced directives
+	slices.SortStableFunc(directives, func(a, b string) int {
+		return robotsDirectiveRank(a) - robotsDirectiveRank(b)
+	})
+	return directives, consumedKeys
+}
+
+func robotsDirectiveRank(directive string) int {
+	switch directive {
+	case _noIndex:
+		return 0
+	case _noFollow:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// getSitemapExclusion reports whether the SEO plugins exclude the content from the sitemap,
+// along with the set of postmeta keys consumed in the process
+func getSitemapExclusion(customMetaData []wpparser.CustomMetaDatum) (bool, map[string]bool) {
+	excluded := false
+	consumedKeys := make(map[string]bool)
+	for _, metadatum := range customMetaData {
+		switch metadatum.Key {
+		case _yoastSitemapIncludeKey:
+			excluded = excluded || metadatum.Value == "never"
+		case _aioseoSitemapExcludeKey:
+			excluded = excluded || metadatum.Value == "on"
+		default:
+			continue
+		}
+		consumedKeys[metadatum.Key] = true
+	}
+	return excluded, consumedKeys
+}
+
+func setRobotsMetadata(metadata map[string]any, directives []string, sitemapExcluded bool, exclusion NoIndexExclusion) {
+	if len(directives) > 0 {
+		metadata[_robotsKey] = strings.Join(directives, ", ")
+	}
+	if exclusion == "" {
+		exclusion = NoIndexExcludeFromSitemap
+	}
+	if exclusion == NoIndexExcludeNothing {
+		// Neither the noindexed content nor the content excluded from the sitemap only
+		return
+	}
+	noIndex := slices.Contains(directives, _noIndex)
+	if noIndex || sitemapExcluded {
+		metadata[_sitemapKey] = map[string]bool{"disable": true}
+	}
+	switch {
+	case noIndex && exclusion == NoIndexUnlisted:
+		metadata[_buildKey] = map[string]string{"list": "never"}
+	case noIndex && exclusion == NoIndexUnrendered:
+		metadata[_buildKey] = map[string]string{"list": "never", "render": "never"}
+	}
+}
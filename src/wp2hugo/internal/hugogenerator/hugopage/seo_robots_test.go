@@ -0,0 +1,90 @@
+package hugopage
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRobotsDirectives(t *testing.T) {
+	t.Parallel()
+	url1, err := url.Parse("https://example.com/post/")
+	require.NoError(t, err)
+	getRobotsMetadata := func(customMetaData ...wpparser.CustomMetaDatum) map[string]any {
+		metadata, err := getMetadata(nil, *url1, "author", "Title", nil, nil, false, nil, nil, nil,
+			nil, nil, customMetaData, nil, "1", nil, PageOptions{})
+		require.NoError(t, err)
+		return metadata
+	}
+
+	metadata := getRobotsMetadata(
+		wpparser.CustomMetaDatum{Key: "_yoast_wpseo_meta-robots-adv", Value: "noarchive,nosnippet"},
+		wpparser.CustomMetaDatum{Key: "_yoast_wpseo_meta-robots-nofollow", Value: "1"},
+		wpparser.CustomMetaDatum{Key: "_yoast_wpseo_meta-robots-noindex", Value: "1"},
+	)
+	require.Equal(t, "noindex, nofollow, noarchive, nosnippet", metadata["robots"])
+	require.Equal(t, map[string]bool{"disable": true}, metadata["sitemap"])
+	require.NotContains(t, metadata, "_yoast_wpseo_meta-robots-noindex")
+
+	metadata = getRobotsMetadata(wpparser.CustomMetaDatum{Key: "rank_math_robots", Value: `a:2:{i:0;s:5:"index";i:1;s:8:"nofollow";}`})
+	require.Equal(t, "nofollow", metadata["robots"])
+	require.NotContains(t, metadata, "sitemap")
+
+	// Forced indexing and no advanced directive
+	metadata = getRobotsMetadata(
+		wpparser.CustomMetaDatum{Key: "_yoast_wpseo_meta-robots-noindex", Value: "2"},
+		wpparser.CustomMetaDatum{Key: "_yoast_wpseo_meta-robots-adv", Value: "-"},
+	)
+	require.NotContains(t, metadata, "robots")
+	require.NotContains(t, metadata, "sitemap")
+
+	// Excluded from the sitemap only
+	metadata = getRobotsMetadata(wpparser.CustomMetaDatum{Key: "_yoast_wpseo_sitemap-include", Value: "never"})
+	require.NotContains(t, metadata, "robots")
+	require.Equal(t, map[string]bool{"disable": true}, metadata["sitemap"])
+	require.NotContains(t, metadata, "_yoast_wpseo_sitemap-include")
+	metadata = getRobotsMetadata(wpparser.CustomMetaDatum{Key: "_aioseop_sitemap_exclude", Value: "on"})
+	require.Equal(t, map[string]bool{"disable": true}, metadata["sitemap"])
+	require.NotContains(t, metadata, "build")
+}
+
+func TestNoIndexExclusion(t *testing.T) {
+	t.Parallel()
+	url1, err := url.Parse("https://example.com/post/")
+	require.NoError(t, err)
+	getExclusionMetadata := func(exclusion NoIndexExclusion, customMetaData ...wpparser.CustomMetaDatum) map[string]any {
+		if len(customMetaData) == 0 {
+			customMetaData = []wpparser.CustomMetaDatum{{Key: "_yoast_wpseo_meta-robots-noindex", Value: "1"}}
+		}
+		metadata, err := getMetadata(nil, *url1, "author", "Title", nil, nil, false, nil, nil, nil, nil, nil,
+			customMetaData, nil, "1", nil, PageOptions{NoIndexExclusion: exclusion})
+		require.NoError(t, err)
+		return metadata
+	}
+
+	metadata := getExclusionMetadata(NoIndexExcludeNothing)
+	require.Equal(t, "noindex", metadata["robots"])
+	require.NotContains(t, metadata, "sitemap")
+	require.NotContains(t, metadata, "build")
+
+	sitemapExclusion := wpparser.CustomMetaDatum{Key: "_aioseop_sitemap_exclude", Value: "on"}
+	metadata = getExclusionMetadata(NoIndexExcludeNothing, sitemapExclusion)
+	require.NotContains(t, metadata, "sitemap")
+	metadata = getExclusionMetadata(NoIndexExcludeFromSitemap, sitemapExclusion)
+	require.Equal(t, map[string]bool{"disable": true}, metadata["sitemap"])
+
+	metadata = getExclusionMetadata(NoIndexUnlisted)
+	require.Equal(t, map[string]bool{"disable": true}, metadata["sitemap"])
+	require.Equal(t, map[string]string{"list": "never"}, metadata["build"])
+
+	metadata = getExclusionMetadata(NoIndexUnrendered)
+	require.Equal(t, map[string]string{"list": "never", "render": "never"}, metadata["build"])
+
+	exclusion, err := ParseNoIndexExclusion("")
+	require.NoError(t, err)
+	require.Equal(t, NoIndexExcludeFromSitemap, exclusion)
+	_, err = ParseNoIndexExclusion("hidden")
+	require.Error(t, err)
+}
@@ -0,0 +1,108 @@
+package hugopage
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/rs/zerolog/log"
+)
+
+// Yoast SEO postmeta of the SEO title and the meta description, they may contain template variables,
+// e.g. "%%title%% %%sep%% %%sitename%%"
+const (
+	_yoastTitleKey    = "_yoast_wpseo_title"
+	_yoastMetaDescKey = "_yoast_wpseo_metadesc"
+)
+
+// Yoast's default title separator, "sc-dash"
+const DefaultSEOTitleSeparator = "-"
+
+// Ref: https://yoast.com/help/list-available-snippet-variables-yoast-seo/
+var _seoTemplateVariableRegEx = regexp.MustCompile(`%%([\w-]+)%%`)
+
+var _multipleSpacesRegEx = regexp.MustCompile(`\s{2,}`)
+
+// seoTemplate holds the values of the template variables of a page
+type seoTemplate struct {
+	separator string
+	variables map[string]string
+}
+
+func newSEOTemplate(title string, author string, publishDate *time.Time, categories []string, tags []string,
+	options PageOptions,
+) seoTemplate {
+	separator := options.SEOTitleSeparator
+	if separator == "" {
+		separator = DefaultSEOTitleSeparator
+	}
+	variables := map[string]string{
+		"title":    title,
+		"sitename": options.SiteTitle,
+		"sitedesc": options.SiteDescription,
+		"sep":      separator,
+		"name":     author,
+		// Only set on the paginated archives, never on a single page
+		"page":       "",
+		"pagenumber": "",
+		"pagetotal":  "",
+	}
+	if publishDate != nil {
+		// WordPress' default date format, "F j, Y"
+		variables["date"] = publishDate.Format("January 2, 2006")
+	}
+	if len(categories) > 0 {
+		variables["category"] = strings.Join(categories, ", ")
+		variables["primary_category"] = categories[0]
+	}
+	if len(tags) > 0 {
+		variables["tag"] = strings.Join(tags, ", ")
+	}
+	return seoTemplate{separator: separator, variables: variables}
+}
+
+// resolve replaces the template variables of the value, and strips the unknown ones with a warning
+func (t seoTemplate) resolve(value string) string {
+	if !strings.Contains(value, "%%") {
+		return value
+	}
+	resolved := _seoTemplateVariableRegEx.ReplaceAllStringFunc(value, func(variable string) string {
+		name := strings.Trim(variable, "%")
+		if replacement, ok := t.variables[name]; ok {
+			return replacement
+		}
+		log.Warn().
+			Str("variable", variable).
+			Str("value", value).
+			Msg("Stripping the unresolved SEO template variable")
+		return ""
+	})
+	// The empty variables, e.g. %%page%%, leave extra spaces and dangling separators behind
+	resolved = strings.TrimSpace(_multipleSpacesRegEx.ReplaceAllString(resolved, " "))
+	for {
+		trimmed := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(resolved, t.separator), t.separator))
+		if trimmed == resolved {
+			break
+		}
+		resolved = trimmed
+	}
+	doubleSeparator := " " + t.separator + " " + t.separator + " "
+	for strings.Contains(resolved, doubleSeparator) {
+		resolved = strings.ReplaceAll(resolved, doubleSeparator, " "+t.separator+" ")
+	}
+	return resolved
+}
+
+// resolveSEOTemplates resolves the template variables of the SEO title and meta description in the postmeta.
+// The postmeta is copied, it belongs to the parsed export.
+func resolveSEOTemplates(template seoTemplate, customMetaData []wpparser.CustomMetaDatum) []wpparser.CustomMetaDatum {
+	resolved := slices.Clone(customMetaData)
+	for i, metadatum := range resolved {
+		if metadatum.Key == _yoastTitleKey || metadatum.Key == _yoastMetaDescKey {
+			resolved[i].Value = template.resolve(metadatum.Value)
+		}
+	}
+	return resolved
+}
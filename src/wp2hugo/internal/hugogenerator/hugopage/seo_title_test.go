@@ -0,0 +1,46 @@
+package hugopage
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveSEOTemplate(t *testing.T) {
+	t.Parallel()
+	publishDate := time.Date(2024, time.March, 5, 10, 0, 0, 0, time.UTC)
+	template := newSEOTemplate("A trip", "jdoe", &publishDate, []string{"Travel", "News"}, nil,
+		PageOptions{SiteTitle: "Example", SiteDescription: "A test site"})
+
+	require.Equal(t, "A trip - Example", template.resolve("%%title%% %%sep%% %%sitename%%"))
+	require.Equal(t, "A trip - Example", template.resolve("%%title%% %%page%% %%sep%% %%sitename%%"))
+	require.Equal(t, "Travel: A trip, March 5, 2024", template.resolve("%%primary_category%%: %%title%%, %%date%%"))
+	require.Equal(t, "Example - A test site", template.resolve("%%sitename%% %%sep%% %%sitedesc%%"))
+	require.Equal(t, "A trip", template.resolve("%%title%% %%sep%% %%unknown%%"))
+	require.Equal(t, "No variable", template.resolve("No variable"))
+
+	template = newSEOTemplate("A trip", "jdoe", nil, nil, nil, PageOptions{SiteTitle: "Example", SEOTitleSeparator: "|"})
+	require.Equal(t, "A trip | Example", template.resolve("%%title%% %%sep%% %%category%% %%sep%% %%sitename%%"))
+}
+
+func TestResolveSEOTemplatesOfPostmeta(t *testing.T) {
+	t.Parallel()
+	url1, err := url.Parse("https://example.com")
+	require.NoError(t, err)
+	customMetaData := []wpparser.CustomMetaDatum{
+		{Key: "_yoast_wpseo_title", Value: "%%title%% %%sep%% %%sitename%%"},
+		{Key: "_yoast_wpseo_metadesc", Value: "All about %%title%%"},
+		{Key: "notes", Value: "%%title%%"},
+	}
+	page, err := NewPage(nil, *url1, "author", "Title", nil, nil, false, nil, nil, nil, nil, "<p>Hello</p>", nil, nil, nil,
+		customMetaData, nil, "0", nil, PageOptions{SiteTitle: "Example"})
+	require.NoError(t, err)
+	require.Equal(t, "Title - Example", page.metadata["_yoast_wpseo_title"])
+	require.Equal(t, "All about Title", page.metadata["_yoast_wpseo_metadesc"])
+	require.Equal(t, "%%title%%", page.metadata["notes"])
+	// The postmeta of the export is left untouched
+	require.Equal(t, "%%title%% %%sep%% %%sitename%%", customMetaData[0].Value)
+}
@@ -0,0 +1,62 @@
+package hugopage
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+)
+
+// The terms of the series taxonomy, see PageOptions.SeriesTaxonomy, are emitted as the "series" front matter,
+// the taxonomy of Hugo's series navigation, and the part of the post in the series as "series_weight"
+const (
+	_seriesKey       = "series"
+	_seriesWeightKey = "series_weight"
+)
+
+// Taxonomy of the Organize Series plugin
+const DefaultSeriesTaxonomy = "series"
+
+// Organize Series postmeta of the part of the post in the series, "_series_part_<term ID>",
+// or "_series_part" with the older versions of the plugin
+const _seriesPartKeyPrefix = "_series_part"
+
+// getSeries returns the series of the post, its part in the first of them, and the postmeta of the parts,
+// which is not emitted as is
+func getSeries(seriesTaxonomy string, taxonomies []wpparser.TaxonomyInfo, customMetaData []wpparser.CustomMetaDatum,
+) ([]string, *int, map[string]bool) {
+	partKeys := make(map[string]bool)
+	for _, metadatum := range customMetaData {
+		if metadatum.Key == _seriesPartKeyPrefix || strings.HasPrefix(metadatum.Key, _seriesPartKeyPrefix+"_") {
+			partKeys[metadatum.Key] = true
+		}
+	}
+
+	var series []string
+	var weight *int
+	for _, taxonomy := range taxonomies {
+		if taxonomy.Taxonomy != seriesTaxonomy {
+			continue
+		}
+		series = append(series, taxonomy.Name)
+		if weight == nil {
+			weight = getSeriesPart(customMetaData, _seriesPartKeyPrefix+"_"+strconv.Itoa(taxonomy.ID))
+		}
+	}
+	if len(series) > 0 && weight == nil {
+		weight = getSeriesPart(customMetaData, _seriesPartKeyPrefix)
+	}
+	return series, weight, partKeys
+}
+
+func getSeriesPart(customMetaData []wpparser.CustomMetaDatum, key string) *int {
+	for _, metadatum := range customMetaData {
+		if metadatum.Key != key {
+			continue
+		}
+		if part, err := strconv.Atoi(strings.TrimSpace(metadatum.Value)); err == nil {
+			return &part
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package hugopage
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeries(t *testing.T) {
+	t.Parallel()
+	url1, err := url.Parse("https://example.com/post/")
+	require.NoError(t, err)
+	taxonomies := []wpparser.TaxonomyInfo{
+		{ID: 7, Taxonomy: "series", Name: "Getting started with Go"},
+		{ID: 8, Taxonomy: "genre", Name: "Tutorial"},
+	}
+	getSeriesMetadata := func(seriesTaxonomy string, customMetaData ...wpparser.CustomMetaDatum) map[string]any {
+		metadata, err := getMetadata(nil, *url1, "author", "Title", nil, nil, false, nil, nil, nil,
+			nil, nil, customMetaData, taxonomies, "1", nil, PageOptions{SeriesTaxonomy: seriesTaxonomy})
+		require.NoError(t, err)
+		return metadata
+	}
+
+	metadata := getSeriesMetadata(DefaultSeriesTaxonomy, wpparser.CustomMetaDatum{Key: "_series_part_7", Value: "2"})
+	require.Equal(t, []string{"Getting started with Go"}, metadata["series"])
+	require.Equal(t, 2, metadata["series_weight"])
+	require.Equal(t, []string{"Tutorial"}, metadata["genre"])
+	require.NotContains(t, metadata, "_series_part_7")
+
+	// Older versions of Organize Series
+	metadata = getSeriesMetadata(DefaultSeriesTaxonomy, wpparser.CustomMetaDatum{Key: "_series_part", Value: "3"})
+	require.Equal(t, 3, metadata["series_weight"])
+
+	// Another taxonomy holds the series, the "series" taxonomy is emitted as is
+	metadata = getSeriesMetadata("genre")
+	require.Equal(t, []string{"Tutorial"}, metadata["series"])
+	require.NotContains(t, metadata, "series_weight")
+
+	// Disabled
+	metadata = getSeriesMetadata("", wpparser.CustomMetaDatum{Key: "_series_part_7", Value: "2"})
+	require.Equal(t, []string{"Getting started with Go"}, metadata["series"])
+	require.NotContains(t, metadata, "series_weight")
+	require.Equal(t, "2", metadata["_series_part_7"])
+}
@@ -0,0 +1,95 @@
+package hugopage
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ShortcodeStripping decides which WordPress shortcodes are removed from the content,
+// for clean text migrations. The text between the opening and closing tags is kept,
+// e.g. `[su_note]text[/su_note]` -> `text`.
+type ShortcodeStripping string
+
+const (
+	// StripNoShortcodes passes the unhandled shortcodes through
+	StripNoShortcodes ShortcodeStripping = "none"
+	// StripUnhandledShortcodes strips the shortcodes which are not converted, e.g. [su_note]
+	StripUnhandledShortcodes ShortcodeStripping = "unhandled"
+	// StripAllShortcodes strips all the shortcodes before the conversion, including e.g. [caption] and [gallery]
+	StripAllShortcodes ShortcodeStripping = "all"
+)
+
+func ParseShortcodeStripping(mode string) (ShortcodeStripping, error) {
+	switch ShortcodeStripping(mode) {
+	case StripNoShortcodes, StripUnhandledShortcodes, StripAllShortcodes:
+		return ShortcodeStripping(mode), nil
+	case "":
+		return StripNoShortcodes, nil
+	default:
+		return "", fmt.Errorf("unknown shortcode stripping %q, expected one of %s, %s, %s",
+			mode, StripNoShortcodes, StripUnhandledShortcodes, StripAllShortcodes)
+	}
+}
+
+// Closing tag of a WordPress shortcode, e.g. `[/su_box]`
+var _closingShortcodeRegEx = regexp.MustCompile(`\[/([a-zA-Z][\w-]*)\]`)
+
+// stripShortcodes removes the shortcode tags from the content, outside of the code samples,
+// and records their names in page.strippedShortcodes.
+// Unless all is set, the shortcodes converted after the Markdown conversion are kept.
+func (page *Page) stripShortcodes(content string, all bool) string {
+	var stripped []string
+	codeBlocks := _codeBlockRegEx.FindAllStringIndex(content, -1)
+	var sb strings.Builder
+	lastIndex := 0
+	for _, match := range _shortcodeRegEx.FindAllStringSubmatchIndex(content, -1) {
+		name := content[match[2]:match[3]]
+		attrs := content[match[4]:match[5]]
+		if !all && slices.Contains(_shortcodesConvertedFromMarkdown, name) {
+			continue
+		}
+		if !isShortcode(content, name, attrs) || isInRanges(match[0], codeBlocks) {
+			continue
+		}
+		sb.WriteString(content[lastIndex:match[0]])
+		lastIndex = match[1]
+		if !slices.Contains(stripped, name) {
+			stripped = append(stripped, name)
+		}
+	}
+	sb.WriteString(content[lastIndex:])
+	if len(stripped) == 0 {
+		return content
+	}
+
+	// Then the closing tags of the stripped shortcodes
+	content = sb.String()
+	codeBlocks = _codeBlockRegEx.FindAllStringIndex(content, -1)
+	sb.Reset()
+	lastIndex = 0
+	for _, match := range _closingShortcodeRegEx.FindAllStringSubmatchIndex(content, -1) {
+		name := content[match[2]:match[3]]
+		if !slices.Contains(stripped, name) || isInRanges(match[0], codeBlocks) {
+			continue
+		}
+		sb.WriteString(content[lastIndex:match[0]])
+		lastIndex = match[1]
+	}
+	sb.WriteString(content[lastIndex:])
+
+	log.Debug().
+		Str("page", page.absoluteURL.String()).
+		Strs("shortcodes", stripped).
+		Msg("Stripped shortcodes")
+	page.strippedShortcodes = append(page.strippedShortcodes, stripped...)
+	return sb.String()
+}
+
+// StrippedShortcodes returns the names of the shortcodes stripped from the content, see PageOptions.StripShortcodes
+func (page *Page) StrippedShortcodes() []string {
+	return page.strippedShortcodes
+}
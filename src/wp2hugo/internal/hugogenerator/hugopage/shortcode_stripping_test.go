@@ -0,0 +1,45 @@
+package hugopage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStripUnhandledShortcodes(t *testing.T) {
+	t.Parallel()
+	const htmlInput = `<p>[su_note note_color="#fff"]Be careful[/su_note] [su_divider /]</p>` +
+		`<p>Quoting [sic] is fine</p>` +
+		`<p>[caption id="attachment_1" align="alignnone" width="300"]<img src="https://example.com/a.jpg" alt="A" /> A caption[/caption]</p>` +
+		`<pre>[su_note]Code[/su_note]</pre>`
+
+	page := newTestPage(t, htmlInput, PageOptions{StripShortcodes: StripUnhandledShortcodes})
+	require.Equal(t, "Be careful\n\nQuoting \\[sic\\] is fine\n\n"+
+		"{{< figure align=\"alignnone\" width=300 src=\"/a.jpg\" alt=\"A\" caption=\"A\" >}}\n\n"+
+		"```\n[su_note]Code[/su_note]\n```", page.Markdown())
+	require.Equal(t, []string{"su_note", "su_divider"}, page.StrippedShortcodes())
+
+	require.Empty(t, newTestPage(t, htmlInput, PageOptions{StripShortcodes: StripNoShortcodes}).StrippedShortcodes())
+}
+
+func TestStripAllShortcodes(t *testing.T) {
+	t.Parallel()
+	const htmlInput = `<p>[su_note note_color="#fff"]Be careful[/su_note]</p>` +
+		`<p>[caption id="attachment_1" align="alignnone" width="300"]A caption[/caption]</p>`
+
+	page := newTestPage(t, htmlInput, PageOptions{StripShortcodes: StripAllShortcodes})
+	require.Equal(t, "Be careful\n\nA caption", page.Markdown())
+	require.Equal(t, []string{"su_note", "caption"}, page.StrippedShortcodes())
+}
+
+func TestParseShortcodeStripping(t *testing.T) {
+	t.Parallel()
+	stripping, err := ParseShortcodeStripping("unhandled")
+	require.NoError(t, err)
+	require.Equal(t, StripUnhandledShortcodes, stripping)
+	stripping, err = ParseShortcodeStripping("")
+	require.NoError(t, err)
+	require.Equal(t, StripNoShortcodes, stripping)
+	_, err = ParseShortcodeStripping("some")
+	require.Error(t, err)
+}
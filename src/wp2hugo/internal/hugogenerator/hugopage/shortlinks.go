@@ -0,0 +1,75 @@
+package hugopage
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// PostLinkProvider resolves the post IDs of the shortlinks, e.g. /?p=123, to the URL of the migrated content,
+// or of the media for the attachments
+type PostLinkProvider interface {
+	GetPostLink(postID string) (string, bool)
+}
+
+// Query parameters of the WordPress shortlinks, e.g. /?p=123, ?page_id=45 or /?attachment_id=67
+var _shortlinkQueryKeys = []string{"p", "page_id", "attachment_id"}
+
+// Destinations of the Markdown links and href attributes of the raw HTML with a shortlink query, up to their fragment
+var _shortlinkRegEx = regexp.MustCompile(`(\]\(|href=["'])([^\s()"'#?]*\?(?:p|page_id|attachment_id)=\d+)([\s)"'#])`)
+
+// replaceShortlinks replaces the shortlinks to the content of the site with the URL of the migrated content.
+// The shortlinks which do not resolve, e.g. to a post which is not in the export, are left as is.
+func replaceShortlinks(provider PostLinkProvider, pageURL url.URL, markdown string) string {
+	if provider == nil || !strings.Contains(markdown, "?") {
+		return markdown
+	}
+	return replaceAllStringSubmatchFunc(_shortlinkRegEx, markdown, func(groups []string) string {
+		postID, ok := getShortlinkPostID(pageURL, groups[2])
+		if !ok {
+			return groups[0]
+		}
+		link, ok := provider.GetPostLink(postID)
+		if !ok {
+			log.Debug().
+				Str("link", groups[2]).
+				Str("postID", postID).
+				Msg("Shortlink to unknown content, keeping it")
+			return groups[0]
+		}
+		return groups[1] + link + groups[3]
+	})
+}
+
+// getShortlinkPostID returns the post ID of the link, if it is a shortlink to the site,
+// relative or absolute, with the post ID as its only query parameter
+func getShortlinkPostID(pageURL url.URL, link string) (string, bool) {
+	linkURL, err := url.Parse(link)
+	if err != nil || linkURL.Opaque != "" {
+		return "", false
+	}
+	if linkURL.Host != "" {
+		if linkURL.Scheme != "" && linkURL.Scheme != "http" && linkURL.Scheme != "https" {
+			return "", false
+		}
+		if strings.TrimPrefix(linkURL.Host, "www.") != strings.TrimPrefix(pageURL.Host, "www.") {
+			return "", false
+		}
+	}
+	// The shortlinks are on the home page, or relative to the page itself
+	if linkURL.Path != "" && linkURL.Path != "/" && linkURL.Path != "/index.php" {
+		return "", false
+	}
+	query := linkURL.Query()
+	if len(query) != 1 {
+		return "", false
+	}
+	for _, key := range _shortlinkQueryKeys {
+		if values := query[key]; len(values) == 1 {
+			return values[0], true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,45 @@
+package hugopage
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type mapPostLinkProvider map[string]string
+
+func (p mapPostLinkProvider) GetPostLink(postID string) (string, bool) {
+	link, ok := p[postID]
+	return link, ok
+}
+
+func TestReplaceShortlinks(t *testing.T) {
+	t.Parallel()
+	pageURL, err := url.Parse("https://example.com/2024/hello/")
+	require.NoError(t, err)
+	provider := mapPostLinkProvider{
+		"123": "/2024/other/",
+		"45":  "/about/",
+		"67":  "https://example.com/wp-content/uploads/2024/01/a.jpg",
+	}
+
+	testCases := map[string]string{
+		"[Other](/?p=123)":                                     "[Other](/2024/other/)",
+		"[Other](https://www.example.com/?p=123)":              "[Other](/2024/other/)",
+		"[Other](http://example.com/index.php?p=123#comments)": "[Other](/2024/other/#comments)",
+		"[About](?page_id=45)":                                 "[About](/about/)",
+		`<a href="https://example.com/?page_id=45">About</a>`:  `<a href="/about/">About</a>`,
+		`[Image](/?attachment_id=67 "Title")`:                  `[Image](https://example.com/wp-content/uploads/2024/01/a.jpg "Title")`,
+		// Unknown post, other sites, other paths and other queries are left as is
+		"[Missing](/?p=999)":                     "[Missing](/?p=999)",
+		"[Other site](https://other.com/?p=123)": "[Other site](https://other.com/?p=123)",
+		"[Path](/2024/?p=123)":                   "[Path](/2024/?p=123)",
+		"[Preview](/?p=123&preview=true)":        "[Preview](/?p=123&preview=true)",
+		"[Search](/?s=123)":                      "[Search](/?s=123)",
+	}
+	for markdown, expected := range testCases {
+		require.Equal(t, expected, replaceShortlinks(provider, *pageURL, markdown), markdown)
+	}
+	require.Equal(t, "[Other](/?p=123)", replaceShortlinks(nil, *pageURL, "[Other](/?p=123)"))
+}
@@ -0,0 +1,233 @@
+package hugopage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Blank lines end an HTML block in Markdown
+var _svgNewlinesRegEx = regexp.MustCompile(`\s*\n\s*`)
+
+// The style sheets of the SVG files are often wrapped in a CDATA section
+var _svgCDATAReplacer = strings.NewReplacer("<![CDATA[", "", "]]>", "")
+
+// The elements kept in the inlined SVG, by lowercase name, as the tokenizer lowercases them.
+// The others are removed along with their content, e.g. <script>, <foreignObject> or the editor metadata.
+var _svgElements = svgNames(
+	"svg", "g", "defs", "symbol", "use", "image", "switch", "a", "title", "desc", "style",
+	"path", "rect", "circle", "ellipse", "line", "polyline", "polygon", "text", "tspan", "textPath",
+	"linearGradient", "radialGradient", "stop", "pattern", "clipPath", "mask", "marker",
+	"animate", "animateMotion", "animateTransform", "set", "mpath",
+	"filter", "feBlend", "feColorMatrix", "feComponentTransfer", "feComposite", "feConvolveMatrix",
+	"feDiffuseLighting", "feDisplacementMap", "feDistantLight", "feDropShadow", "feFlood", "feFuncA", "feFuncB",
+	"feFuncG", "feFuncR", "feGaussianBlur", "feImage", "feMerge", "feMergeNode", "feMorphology", "feOffset",
+	"fePointLight", "feSpecularLighting", "feSpotLight", "feTile", "feTurbulence",
+)
+
+// The attributes kept in the inlined SVG, by lowercase name. Event handlers, e.g. onload, are never kept.
+var _svgAttributes = svgNames(
+	"id", "class", "style", "lang", "xml:lang", "xml:space", "role", "aria-label", "aria-labelledby",
+	"aria-describedby", "aria-hidden", "focusable", "xmlns", "xmlns:xlink", "version",
+	"width", "height", "viewBox", "preserveAspectRatio", "x", "y", "transform",
+	"d", "cx", "cy", "r", "rx", "ry", "x1", "y1", "x2", "y2", "points", "pathLength",
+	"dx", "dy", "rotate", "textLength", "lengthAdjust", "startOffset", "method", "spacing",
+	"fill", "fill-opacity", "fill-rule", "stroke", "stroke-width", "stroke-linecap", "stroke-linejoin",
+	"stroke-miterlimit", "stroke-dasharray", "stroke-dashoffset", "stroke-opacity", "opacity", "color",
+	"display", "visibility", "overflow", "clip-path", "clip-rule", "mask", "filter",
+	"marker-start", "marker-mid", "marker-end", "font-family", "font-size", "font-weight", "font-style",
+	"font-variant", "text-anchor", "dominant-baseline", "alignment-baseline", "baseline-shift",
+	"letter-spacing", "word-spacing", "text-decoration", "writing-mode", "stop-color", "stop-opacity",
+	"vector-effect", "shape-rendering", "text-rendering", "image-rendering", "color-interpolation-filters",
+	"mix-blend-mode", "isolation", "paint-order", "flood-color", "flood-opacity", "lighting-color",
+	"offset", "gradientUnits", "gradientTransform", "spreadMethod", "fx", "fy", "fr",
+	"patternUnits", "patternContentUnits", "patternTransform", "clipPathUnits", "maskUnits", "maskContentUnits",
+	"markerWidth", "markerHeight", "markerUnits", "refX", "refY", "orient",
+	"filterUnits", "primitiveUnits", "in", "in2", "result", "stdDeviation", "mode", "type", "values",
+	"operator", "k1", "k2", "k3", "k4", "scale", "xChannelSelector", "yChannelSelector", "radius",
+	"baseFrequency", "numOctaves", "seed", "stitchTiles", "tableValues", "slope", "intercept", "amplitude",
+	"exponent", "kernelMatrix", "order", "divisor", "bias", "targetX", "targetY", "edgeMode", "preserveAlpha",
+	"surfaceScale", "diffuseConstant", "specularConstant", "specularExponent", "azimuth", "elevation",
+	"pointsAtX", "pointsAtY", "pointsAtZ", "limitingConeAngle", "kernelUnitLength",
+	"href", "xlink:href", "xlink:title", "target",
+	"attributeName", "attributeType", "from", "to", "by", "begin", "dur", "end", "min", "max",
+	"repeatCount", "repeatDur", "calcMode", "keyTimes", "keySplines", "keyPoints", "additive", "accumulate",
+	"restart", "path",
+)
+
+// The attributes holding a link, and the animation values set on the animated attribute,
+// which is a link when the animation targets the href
+var (
+	_svgLinkAttributes      = []string{"href", "xlink:href"}
+	_svgAnimationAttributes = []string{"from", "to", "by", "values"}
+)
+
+// The links kept in the inlined SVG: fragments, web pages and embedded images
+var _svgSafeLinkPrefixes = []string{"#", "http:", "https:", "data:image/"}
+
+// svgNames returns the names by lowercase name, SVG being case-sensitive
+func svgNames(names ...string) map[string]string {
+	byLowercase := make(map[string]string, len(names))
+	for _, name := range names {
+		byLowercase[strings.ToLower(name)] = name
+	}
+	return byLowercase
+}
+
+// sanitizeSVG keeps the allowed elements and attributes of the SVG, see _svgElements and _svgAttributes,
+// without the links other than the fragments, web pages and embedded images, e.g. javascript: links,
+// and returns it on a single line, for inlining it in Markdown. The prolog and the comments are removed too.
+func sanitizeSVG(svg string) (string, error) {
+	var sb strings.Builder
+	// The open elements, closed at the end if the SVG is truncated
+	var open []string
+	// The removed element whose content is skipped, with its nesting depth
+	skipped, skippedDepth := "", 0
+	tokenizer := html.NewTokenizer(strings.NewReader(svg))
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == html.ErrorToken {
+			if err := tokenizer.Err(); !errors.Is(err, io.EOF) {
+				return "", fmt.Errorf("error reading SVG: %w", err)
+			}
+			break
+		}
+		token := tokenizer.Token()
+		if skippedDepth > 0 {
+			if token.Data == skipped && tokenType == html.StartTagToken {
+				skippedDepth++
+			} else if token.Data == skipped && tokenType == html.EndTagToken {
+				skippedDepth--
+			}
+			continue
+		}
+		if sb.Len() > 0 && len(open) == 0 {
+			// Past the root element
+			break
+		}
+
+		switch tokenType {
+		case html.StartTagToken, html.SelfClosingTagToken:
+			if sb.Len() == 0 && token.Data != "svg" {
+				return "", errors.New("not an SVG document")
+			}
+			name, ok := _svgElements[token.Data]
+			if !ok {
+				if tokenType == html.StartTagToken {
+					skipped, skippedDepth = token.Data, 1
+				}
+				continue
+			}
+			sb.WriteString("<" + name)
+			for _, attr := range sanitizeSVGAttributes(token.Attr) {
+				fmt.Fprintf(&sb, ` %s="%s"`, attr.Key, html.EscapeString(attr.Val))
+			}
+			if tokenType == html.SelfClosingTagToken {
+				sb.WriteString("/>")
+			} else {
+				sb.WriteString(">")
+				open = append(open, name)
+			}
+		case html.EndTagToken:
+			name, ok := _svgElements[token.Data]
+			if index := slices.Index(open, name); ok && index >= 0 {
+				for len(open) > index {
+					sb.WriteString("</" + open[len(open)-1] + ">")
+					open = open[:len(open)-1]
+				}
+			}
+		case html.TextToken:
+			if len(open) == 0 {
+				if strings.TrimSpace(token.Data) != "" {
+					return "", errors.New("not an SVG document")
+				}
+			} else if open[len(open)-1] == "style" {
+				// Inline, the style is parsed as markup and not as raw text, so it is escaped too,
+				// without the CDATA section markers and the imports of external style sheets
+				if !strings.Contains(strings.ToLower(token.Data), "@import") {
+					sb.WriteString(html.EscapeString(_svgCDATAReplacer.Replace(token.Data)))
+				}
+			} else {
+				sb.WriteString(html.EscapeString(token.Data))
+			}
+		default:
+			// The prolog, e.g. <?xml ...?> and <!DOCTYPE svg ...>, and the comments
+			continue
+		}
+	}
+	if sb.Len() == 0 {
+		return "", errors.New("not an SVG document")
+	}
+	for i := len(open) - 1; i >= 0; i-- {
+		sb.WriteString("</" + open[i] + ">")
+	}
+	return strings.TrimSpace(_svgNewlinesRegEx.ReplaceAllString(sb.String(), " ")), nil
+}
+
+// sanitizeSVGAttributes returns the allowed attributes with their SVG name, without the unsafe links.
+// The values are already decoded by the tokenizer, e.g. "&#106;avascript:" is "javascript:".
+func sanitizeSVGAttributes(attrs []html.Attribute) []html.Attribute {
+	animatesLink := slices.ContainsFunc(attrs, func(attr html.Attribute) bool {
+		return attr.Key == "attributename" && slices.Contains(_svgLinkAttributes, strings.ToLower(strings.TrimSpace(attr.Val)))
+	})
+	sanitized := make([]html.Attribute, 0, len(attrs))
+	for _, attr := range attrs {
+		name, ok := _svgAttributes[attr.Key]
+		if !ok {
+			continue
+		}
+		isLink := slices.Contains(_svgLinkAttributes, attr.Key) ||
+			(animatesLink && slices.Contains(_svgAnimationAttributes, attr.Key))
+		if isLink && !isSafeSVGLink(attr.Key, attr.Val) {
+			continue
+		}
+		sanitized = append(sanitized, html.Attribute{Key: name, Val: attr.Val})
+	}
+	return sanitized
+}
+
+// isSafeSVGLink reports whether all the links of the attribute are safe, see _svgSafeLinkPrefixes.
+// The animation values are a ";"-separated list of links.
+func isSafeSVGLink(key string, value string) bool {
+	links := []string{value}
+	if key == "values" {
+		links = strings.Split(value, ";")
+	}
+	for _, link := range links {
+		// Browsers ignore the whitespace and the control characters, e.g. "java\tscript:"
+		link = strings.ToLower(strings.Map(func(r rune) rune {
+			if r <= ' ' {
+				return -1
+			}
+			return r
+		}, link))
+		if !slices.ContainsFunc(_svgSafeLinkPrefixes, func(prefix string) bool { return strings.HasPrefix(link, prefix) }) {
+			return false
+		}
+	}
+	return true
+}
+
+// InlineSVGImage replaces the Markdown images of the link, linked or not, with the SVG, sanitized with sanitizeSVG,
+// labelled with the alt text of the image. It returns whether the link is still referenced, e.g. by a figure shortcode,
+// which is not inlined to keep its caption.
+func (page *Page) InlineSVGImage(link string, svg string) (bool, error) {
+	sanitized, err := sanitizeSVG(svg)
+	if err != nil {
+		return false, err
+	}
+	image := regexp.MustCompile(`!\[([^\]]*)\]\(` + regexp.QuoteMeta(link) + `(?:\s+"[^"]*")?\)`)
+	page.markdown = replaceAllStringSubmatchFunc(image, page.markdown, func(groups []string) string {
+		alt := groups[1]
+		if alt == "" || strings.Contains(sanitized, "aria-label") {
+			return sanitized
+		}
+		return fmt.Sprintf(`<svg role="img" aria-label="%s"`, html.EscapeString(alt)) + strings.TrimPrefix(sanitized, "<svg")
+	})
+	return strings.Contains(page.markdown, link), nil
+}
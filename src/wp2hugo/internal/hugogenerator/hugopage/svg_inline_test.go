@@ -0,0 +1,79 @@
+package hugopage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSanitizeSVG(t *testing.T) {
+	t.Parallel()
+	svg, err := sanitizeSVG(`<?xml version="1.0" encoding="UTF-8"?>
+<!-- Generator: Illustrator -->
+<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" viewBox="0 0 8 8">
+  <a xlink:href="javascript:alert(1)" onclick='alert(2)'><circle r="4" onmouseover=alert(3) /></a>
+  <foreignObject><iframe src="https://example.com"></iframe></foreignObject>
+  <a href="https://example.org/"><rect width="4" height="4"/></a>
+  <linearGradient id="g"><stop offset="0" stop-color="#fff"/></linearGradient>
+</svg>`)
+	require.NoError(t, err)
+	require.Equal(t, `<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" viewBox="0 0 8 8"> `+
+		`<a><circle r="4"/></a> <a href="https://example.org/"><rect width="4" height="4"/></a> `+
+		`<linearGradient id="g"><stop offset="0" stop-color="#fff"/></linearGradient> </svg>`, svg)
+
+	_, err = sanitizeSVG(`<html><body>Not found</body></html>`)
+	require.Error(t, err)
+}
+
+func TestSanitizeSVGBypasses(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name     string
+		svg      string
+		expected string
+	}{
+		{
+			name:     "animated href",
+			svg:      `<svg><a><animate attributeName="href" to="javascript:alert(1)"/><text>Click</text></a></svg>`,
+			expected: `<svg><a><animate attributeName="href"/><text>Click</text></a></svg>`,
+		},
+		{
+			name:     "set href",
+			svg:      `<svg><a><set attributeName="xlink:href" values="#top;javascript:alert(1)"/></a></svg>`,
+			expected: `<svg><a><set attributeName="xlink:href"/></a></svg>`,
+		},
+		{
+			name:     "animated color",
+			svg:      `<svg><circle r="4"><animate attributeName="fill" values="red;blue" dur="1s"/></circle></svg>`,
+			expected: `<svg><circle r="4"><animate attributeName="fill" values="red;blue" dur="1s"/></circle></svg>`,
+		},
+		{
+			name:     "event handler after a slash",
+			svg:      `<svg><image/onload=alert(1) href="data:image/png;base64,iVBORw0KGgo="/></svg>`,
+			expected: `<svg><image href="data:image/png;base64,iVBORw0KGgo="/></svg>`,
+		},
+		{
+			name:     "entity-encoded link",
+			svg:      `<svg><a href="&#106;avascript:alert(1)"><text>Click</text></a><a href="java&#9;script:alert(2)"></a></svg>`,
+			expected: `<svg><a><text>Click</text></a><a></a></svg>`,
+		},
+		{
+			name:     "unclosed script",
+			svg:      `<svg><rect width="4"/><script>alert(1)</svg>`,
+			expected: `<svg><rect width="4"/></svg>`,
+		},
+		{
+			name:     "style sheets",
+			svg:      `<svg><style><![CDATA[.a > b { fill: red }]]></style><style>@import url(https://example.com/a.css);</style></svg>`,
+			expected: `<svg><style>.a &gt; b { fill: red }</style><style></style></svg>`,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			svg, err := sanitizeSVG(testCase.svg)
+			require.NoError(t, err)
+			require.Equal(t, testCase.expected, svg)
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package hugopage
+
+import (
+	"html"
+	"regexp"
+	"slices"
+	"strings"
+
+	md "github.com/JohannesKaufmann/html-to-markdown"
+	"github.com/PuerkitoBio/goquery"
+	"github.com/rs/zerolog/log"
+)
+
+// The checkboxes of the task list items are replaced with private use characters, until the Markdown is written
+const (
+	_checkedTaskStart   = "\uE004"
+	_uncheckedTaskStart = "\uE005"
+)
+
+var (
+	_taskStartRegEx = regexp.MustCompile(`(` + _checkedTaskStart + `|` + _uncheckedTaskStart + `)[ \t]*`)
+	// The checkbox typed as text, e.g. "[x] Done"
+	_textCheckboxRegEx = regexp.MustCompile(`^\s*\[([ xX])\](?:\s+|$)`)
+)
+
+// The classes marking a checkbox as checked, e.g. <span class="checkbox checked">
+var _checkedClasses = []string{"checked", "is-checked", "done", "is-done", "completed"}
+
+// convertTaskLists converts the bullet lists whose items all start with a checkbox into the task lists
+// of Goldmark's task list extension, enabled by default in Hugo, e.g. "- [x] Done" and "- [ ] To do".
+// The checkbox is an <input type="checkbox">, an element with a checkbox class, e.g. <span class="checkbox checked">,
+// as rendered by the checklist plugins, or typed as text, e.g. "[x] Done". A list with an item without a checkbox
+// is ambiguous, it stays a bullet list.
+func convertTaskLists() md.Plugin {
+	return func(c *md.Converter) []md.Rule {
+		c.Before(func(selec *goquery.Selection) {
+			selec.Find("ul").Each(func(_ int, list *goquery.Selection) {
+				var checkboxes []*goquery.Selection
+				list.ChildrenFiltered("li").EachWithBreak(func(_ int, item *goquery.Selection) bool {
+					checkbox := findTaskCheckbox(item)
+					if checkbox == nil {
+						checkboxes = nil
+						return false
+					}
+					checkboxes = append(checkboxes, checkbox)
+					return true
+				})
+				if len(checkboxes) == 0 {
+					return
+				}
+				for _, checkbox := range checkboxes {
+					replaceTaskCheckbox(checkbox)
+				}
+				log.Debug().
+					Int("items", len(checkboxes)).
+					Msg("Task list found")
+			})
+		})
+		c.After(func(markdown string) string {
+			return _taskStartRegEx.ReplaceAllStringFunc(markdown, func(start string) string {
+				if strings.HasPrefix(start, _checkedTaskStart) {
+					return "[x] "
+				}
+				return "[ ] "
+			})
+		})
+		return nil
+	}
+}
+
+// findTaskCheckbox returns the checkbox starting the list item, its first input, element or text,
+// descending into its first element, e.g. a <label> or a <p>, or nil if the item does not start with a checkbox
+func findTaskCheckbox(item *goquery.Selection) *goquery.Selection {
+	var checkbox *goquery.Selection
+	item.Contents().EachWithBreak(func(_ int, node *goquery.Selection) bool {
+		switch {
+		case goquery.NodeName(node) == "#text":
+			if strings.TrimSpace(node.Text()) == "" {
+				return true
+			}
+			if _textCheckboxRegEx.MatchString(node.Text()) {
+				checkbox = node
+			}
+		case goquery.NodeName(node) == "#comment":
+			return true
+		case isCheckboxElement(node):
+			checkbox = node
+		case !node.Is("ul, ol"):
+			checkbox = findTaskCheckbox(node)
+		}
+		return false
+	})
+	return checkbox
+}
+
+func isCheckboxElement(selec *goquery.Selection) bool {
+	if selec.Is("input") {
+		return strings.EqualFold(selec.AttrOr("type", ""), "checkbox")
+	}
+	if strings.TrimSpace(selec.Text()) != "" {
+		return false
+	}
+	return slices.ContainsFunc(strings.Fields(selec.AttrOr("class", "")), func(class string) bool {
+		return strings.Contains(strings.ToLower(class), "checkbox")
+	})
+}
+
+func isCheckedElement(selec *goquery.Selection) bool {
+	if _, checked := selec.Attr("checked"); checked || selec.AttrOr("aria-checked", "") == "true" ||
+		selec.AttrOr("data-checked", "") == "true" {
+		return true
+	}
+	return slices.ContainsFunc(strings.Fields(selec.AttrOr("class", "")), func(class string) bool {
+		class = strings.ToLower(class)
+		return slices.Contains(_checkedClasses, class) ||
+			(strings.HasSuffix(class, "-checked") && !strings.HasSuffix(class, "-unchecked"))
+	})
+}
+
+// replaceTaskCheckbox replaces the checkbox with the private use character of its state
+func replaceTaskCheckbox(checkbox *goquery.Selection) {
+	if goquery.NodeName(checkbox) == "#text" {
+		text := checkbox.Text()
+		groups := _textCheckboxRegEx.FindStringSubmatch(text)
+		start := _uncheckedTaskStart
+		if groups[1] != " " {
+			start = _checkedTaskStart
+		}
+		checkbox.ReplaceWithHtml(start + html.EscapeString(text[len(groups[0]):]))
+		return
+	}
+	start := _uncheckedTaskStart
+	if isCheckedElement(checkbox) {
+		start = _checkedTaskStart
+	}
+	// Merged with the text following the checkbox, the converter formats each text node separately
+	siblings := checkbox.Parent().Contents()
+	if next := siblings.Eq(siblings.IndexOfSelection(checkbox) + 1); goquery.NodeName(next) == "#text" {
+		next.ReplaceWithHtml(start + html.EscapeString(next.Text()))
+		checkbox.Remove()
+	} else {
+		checkbox.ReplaceWithHtml(start)
+	}
+}
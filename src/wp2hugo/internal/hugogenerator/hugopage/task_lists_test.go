@@ -0,0 +1,41 @@
+package hugopage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskLists(t *testing.T) {
+	t.Parallel()
+	const htmlData = `<p>Packing</p>
+<ul>
+  <li><input type="checkbox" checked disabled> Tent</li>
+  <li><label><input type="checkbox"> Sleeping <strong>bag</strong></label></li>
+  <li><span class="wp-checklist-checkbox is-checked"></span>Stove</li>
+  <li><span class="wp-checklist-checkbox"></span>Matches
+    <ul>
+      <li>[x] Lighter</li>
+      <li>[ ] Firesteel</li>
+    </ul>
+  </li>
+</ul>
+<p>Maybe</p>
+<ul>
+  <li>[x] Map</li>
+  <li>Compass</li>
+</ul>`
+	const expected = "Packing\n\n" +
+		"- [x] Tent\n" +
+		"- [ ] Sleeping **bag**\n" +
+		"- [x] Stove\n" +
+		"- [ ] Matches\n" +
+		"  - [x] Lighter\n" +
+		"  - [ ] Firesteel\n\n" +
+		"Maybe\n\n" +
+		"- \\[x\\] Map\n" +
+		"- Compass"
+	markdown, err := getMarkdownConverter().ConvertString(htmlData)
+	require.NoError(t, err)
+	require.Equal(t, expected, markdown)
+}
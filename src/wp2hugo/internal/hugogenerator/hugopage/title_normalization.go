@@ -0,0 +1,117 @@
+package hugopage
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TitleNormalization decides how the titles are cleaned up before they are emitted,
+// legacy posts often have ALL CAPS titles, trailing whitespace or double spaces
+type TitleNormalization string
+
+const (
+	// TitleNormalizationKeep leaves the titles as they are in the export
+	TitleNormalizationKeep TitleNormalization = "keep"
+	// TitleNormalizationClean trims the titles and collapses their whitespace
+	TitleNormalizationClean TitleNormalization = "clean"
+	// TitleNormalizationTitleCase cleans the titles and title-cases them, e.g. "A TRIP TO THE MOUNTAINS"
+	// to "A Trip to the Mountains". The original title is kept as the "original_title" front matter.
+	TitleNormalizationTitleCase TitleNormalization = "title-case"
+)
+
+func ParseTitleNormalization(normalization string) (TitleNormalization, error) {
+	switch TitleNormalization(normalization) {
+	case TitleNormalizationKeep, TitleNormalizationClean, TitleNormalizationTitleCase:
+		return TitleNormalization(normalization), nil
+	case "":
+		return TitleNormalizationKeep, nil
+	default:
+		return "", fmt.Errorf("unknown title normalization %q, expected one of %s, %s, %s",
+			normalization, TitleNormalizationKeep, TitleNormalizationClean, TitleNormalizationTitleCase)
+	}
+}
+
+// Front matter key of the original title, when title-casing changed it
+const _originalTitleKey = "original_title"
+
+// Minor words which stay lowercase in a title, unless first or last
+var _titleCaseStopwords = []string{
+	"a", "an", "and", "as", "at", "but", "by", "for", "from", "in", "into", "nor",
+	"of", "on", "or", "over", "the", "to", "up", "via", "vs", "with",
+}
+
+// normalizeTitle returns the title cleaned up following the normalization
+func normalizeTitle(title string, normalization TitleNormalization) string {
+	switch normalization {
+	case TitleNormalizationClean:
+		return strings.Join(strings.Fields(title), " ")
+	case TitleNormalizationTitleCase:
+		return titleCase(strings.Fields(title))
+	default:
+		return title
+	}
+}
+
+// titleCase capitalizes the words, except the stopwords in the middle of the title.
+// The words are lowercased first when the whole title is in capitals,
+// otherwise the words with an uppercase letter after the first one are kept as is, e.g. "iPhone" or "NASA".
+func titleCase(words []string) string {
+	shouting := isShouting(words)
+	cased := make([]string, len(words))
+	for i, word := range words {
+		if shouting {
+			word = strings.ToLower(word)
+		}
+		if hasInnerUppercase(word) {
+			// E.g. "iPhone" or "McDonald's", cased on purpose
+			cased[i] = word
+			continue
+		}
+		lower := strings.ToLower(strings.TrimFunc(word, unicode.IsPunct))
+		// The first word after a colon or a dash starts a subtitle
+		subtitle := i > 0 && (strings.HasSuffix(words[i-1], ":") || words[i-1] == "-" || words[i-1] == "—")
+		if i > 0 && i < len(words)-1 && !subtitle && slices.Contains(_titleCaseStopwords, lower) {
+			cased[i] = strings.ToLower(word)
+			continue
+		}
+		cased[i] = capitalize(word)
+	}
+	return strings.Join(cased, " ")
+}
+
+// isShouting returns true if the title has several words and no lowercase letter
+func isShouting(words []string) bool {
+	wordsWithLetters := 0
+	for _, word := range words {
+		if strings.IndexFunc(word, unicode.IsLower) >= 0 {
+			return false
+		}
+		if strings.IndexFunc(word, unicode.IsLetter) >= 0 {
+			wordsWithLetters++
+		}
+	}
+	return wordsWithLetters > 1
+}
+
+// hasInnerUppercase returns true if a letter of the word after the first one is uppercase
+func hasInnerUppercase(word string) bool {
+	index := strings.IndexFunc(word, unicode.IsLetter)
+	if index < 0 {
+		return false
+	}
+	_, size := utf8.DecodeRuneInString(word[index:])
+	return strings.IndexFunc(word[index+size:], unicode.IsUpper) >= 0
+}
+
+// capitalize uppercases the first letter of the word, after any leading punctuation, e.g. `"hello"`
+func capitalize(word string) string {
+	index := strings.IndexFunc(word, unicode.IsLetter)
+	if index < 0 {
+		return word
+	}
+	letter, size := utf8.DecodeRuneInString(word[index:])
+	return word[:index] + string(unicode.ToUpper(letter)) + word[index+size:]
+}
@@ -0,0 +1,64 @@
+package hugopage
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTitleNormalization(t *testing.T) {
+	t.Parallel()
+	normalization, err := ParseTitleNormalization("")
+	require.NoError(t, err)
+	require.Equal(t, TitleNormalizationKeep, normalization)
+
+	normalization, err = ParseTitleNormalization("title-case")
+	require.NoError(t, err)
+	require.Equal(t, TitleNormalizationTitleCase, normalization)
+
+	_, err = ParseTitleNormalization("upper")
+	require.Error(t, err)
+}
+
+func TestNormalizeTitle(t *testing.T) {
+	t.Parallel()
+	const allCaps = "A TRIP TO THE MOUNTAINS: THE FIRST DAY"
+	require.Equal(t, allCaps, normalizeTitle(allCaps, TitleNormalizationKeep))
+	require.Equal(t, allCaps, normalizeTitle(allCaps, TitleNormalizationClean))
+	require.Equal(t, "A Trip to the Mountains: The First Day", normalizeTitle(allCaps, TitleNormalizationTitleCase))
+
+	const whitespace = "  Hello,\tworld  of   \"iPhone\" and NASA \n"
+	require.Equal(t, whitespace, normalizeTitle(whitespace, TitleNormalizationKeep))
+	require.Equal(t, `Hello, world of "iPhone" and NASA`, normalizeTitle(whitespace, TitleNormalizationClean))
+	require.Equal(t, `Hello, World of "iPhone" and NASA`, normalizeTitle(whitespace, TitleNormalizationTitleCase))
+
+	// A single capitalized word is an acronym, not shouting
+	require.Equal(t, "NASA", normalizeTitle("NASA", TitleNormalizationTitleCase))
+	require.Equal(t, "Where to Go To", normalizeTitle("where to go to", TitleNormalizationTitleCase))
+}
+
+func TestTitleNormalizationMetadata(t *testing.T) {
+	t.Parallel()
+	url1, err := url.Parse("https://example.com/post/")
+	require.NoError(t, err)
+	getTitleMetadata := func(title string, normalization TitleNormalization) map[string]any {
+		metadata, err := getMetadata(nil, *url1, "author", title, nil, nil, false, nil, nil, nil,
+			nil, nil, nil, nil, "1", nil, PageOptions{TitleNormalization: normalization})
+		require.NoError(t, err)
+		return metadata
+	}
+
+	metadata := getTitleMetadata("MY  FIRST POST ", TitleNormalizationTitleCase)
+	require.Equal(t, "My First Post", metadata["title"])
+	require.Equal(t, "MY  FIRST POST ", metadata[_originalTitleKey])
+
+	// Only the whitespace changed
+	metadata = getTitleMetadata("My  First Post ", TitleNormalizationTitleCase)
+	require.Equal(t, "My First Post", metadata["title"])
+	require.NotContains(t, metadata, _originalTitleKey)
+
+	metadata = getTitleMetadata("MY  FIRST POST ", TitleNormalizationClean)
+	require.Equal(t, "MY FIRST POST", metadata["title"])
+	require.NotContains(t, metadata, _originalTitleKey)
+}
@@ -0,0 +1,124 @@
+package hugopage
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Typography decides the style of the quotes, dashes and ellipses of the content.
+// WordPress curls them when rendering the content (wptexturize), but the export has whatever was typed.
+type Typography string
+
+const (
+	// TypographyKeep leaves the quotes and dashes as they are in the export
+	TypographyKeep Typography = "keep"
+	// TypographyStraight straightens them, e.g. “ to " and — to ---, for Goldmark's typographer extension
+	// to curl them again when Hugo renders the site
+	TypographyStraight Typography = "straight"
+	// TypographyCurly curls them like WordPress does, e.g. " to “ and -- to –
+	TypographyCurly Typography = "curly"
+)
+
+func ParseTypography(typography string) (Typography, error) {
+	switch Typography(typography) {
+	case TypographyKeep, TypographyStraight, TypographyCurly:
+		return Typography(typography), nil
+	case "":
+		return TypographyKeep, nil
+	default:
+		return "", fmt.Errorf("unknown typography %q, expected one of %s, %s, %s",
+			typography, TypographyKeep, TypographyStraight, TypographyCurly)
+	}
+}
+
+// Markdown which is not prose: code, shortcodes, HTML tags, link destinations, URLs,
+// and the thematic breaks and table delimiter rows made of dashes
+var _typographyProtectedRegEx = regexp.MustCompile("(?ms)^(?:```|~~~).*?^(?:```|~~~)[^\n]*$" +
+	"|`[^`\n]+`" +
+	`|{{[<%].*?[>%]}}` +
+	`|<!--.*?-->|<[^>\n]+>` +
+	`|\]\([^)\n]*\)` +
+	`|https?://[^\s)\]]+` +
+	`|(?m)^[ \t]*[-|: \t]*-{3,}[-|: \t]*$`)
+
+var _straightReplacer = strings.NewReplacer(
+	"‘", "'", "’", "'", "‚", "'", "‛", "'",
+	"“", `"`, "”", `"`, "„", `"`, "‟", `"`,
+	"–", "--", "—", "---", "…", "...",
+)
+
+var _curlyDashReplacer = strings.NewReplacer("---", "—", " -- ", " — ", "--", "–", "...", "…")
+
+// applyTypography restyles the Markdown, along with the title and the summary
+func (page *Page) applyTypography(markdown string) string {
+	for _, key := range []string{"title", "summary"} {
+		if value, ok := page.metadata[key].(string); ok {
+			page.metadata[key] = applyTypography(value, page.options.Typography)
+		}
+	}
+	return applyTypography(markdown, page.options.Typography)
+}
+
+// applyTypography restyles the prose of the Markdown, see PageOptions.Typography
+func applyTypography(markdown string, typography Typography) string {
+	if typography != TypographyStraight && typography != TypographyCurly {
+		return markdown
+	}
+	var sb strings.Builder
+	lastIndex := 0
+	for _, match := range _typographyProtectedRegEx.FindAllStringIndex(markdown, -1) {
+		sb.WriteString(restyleProse(markdown[lastIndex:match[0]], sb.String(), typography))
+		sb.WriteString(markdown[match[0]:match[1]])
+		lastIndex = match[1]
+	}
+	sb.WriteString(restyleProse(markdown[lastIndex:], sb.String(), typography))
+	return sb.String()
+}
+
+// restyleProse restyles the text, which follows the already restyled text before
+func restyleProse(text string, before string, typography Typography) string {
+	if typography == TypographyStraight {
+		return _straightReplacer.Replace(text)
+	}
+	text = _curlyDashReplacer.Replace(text)
+	previous, _ := utf8.DecodeLastRuneInString(before)
+	var sb strings.Builder
+	for i, r := range text {
+		switch r {
+		case '"':
+			next, _ := utf8.DecodeRuneInString(text[i+1:])
+			if isOpeningQuotePosition(previous) || (isEmphasisMarker(previous) && !unicode.IsSpace(next)) {
+				r = '“'
+			} else {
+				r = '”'
+			}
+		case '\'':
+			next, _ := utf8.DecodeRuneInString(text[i+1:])
+			switch {
+			case unicode.IsLetter(previous) || unicode.IsDigit(previous):
+				// Apostrophe, e.g. "it's", or closing quote
+				r = '’'
+			case isOpeningQuotePosition(previous) && (unicode.IsLetter(next) || unicode.IsDigit(next)):
+				r = '‘'
+			default:
+				r = '’'
+			}
+		}
+		sb.WriteRune(r)
+		previous = r
+	}
+	return sb.String()
+}
+
+// Emphasized quotations, e.g. *"quoted"*, open after the emphasis marker
+func isEmphasisMarker(r rune) bool {
+	return r == '*' || r == '_'
+}
+
+// Quotes open a quotation at the start of the text, or after a space, an opening bracket, an HTML tag or a dash
+func isOpeningQuotePosition(previous rune) bool {
+	return previous == utf8.RuneError || unicode.IsSpace(previous) || strings.ContainsRune("([{>“‘—–-", previous)
+}
@@ -0,0 +1,64 @@
+package hugopage
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTypography(t *testing.T) {
+	t.Parallel()
+	typography, err := ParseTypography("")
+	require.NoError(t, err)
+	require.Equal(t, TypographyKeep, typography)
+
+	typography, err = ParseTypography("curly")
+	require.NoError(t, err)
+	require.Equal(t, TypographyCurly, typography)
+
+	_, err = ParseTypography("smart")
+	require.Error(t, err)
+}
+
+func TestStraightTypography(t *testing.T) {
+	t.Parallel()
+	const markdown = "“Hello,” she said — it’s 1–2 o’clock…\n\n" +
+		"`“code”` [“link”](https://example.com/“a”)\n\n" +
+		"```\nfmt.Println(“unchanged”)\n```"
+	require.Equal(t, "\"Hello,\" she said --- it's 1--2 o'clock...\n\n"+
+		"`“code”` [\"link\"](https://example.com/“a”)\n\n"+
+		"```\nfmt.Println(“unchanged”)\n```", applyTypography(markdown, TypographyStraight))
+}
+
+func TestCurlyTypography(t *testing.T) {
+	t.Parallel()
+	const markdown = "\"Hello,\" she said -- it's 'quoted' in 1--2 and 3---4...\n\n" +
+		"*\"emphasized\"* (\"bracketed\")\n\n" +
+		"---\n\n" +
+		"| a | b |\n| --- | --- |\n\n" +
+		"`\"code\"` {{< figure src=\"/a.jpg\" >}} <a href=\"/b\">\"b\"</a>\n\n" +
+		"```\nx--\n```"
+	require.Equal(t, "“Hello,” she said — it’s ‘quoted’ in 1–2 and 3—4…\n\n"+
+		"*“emphasized”* (“bracketed”)\n\n"+
+		"---\n\n"+
+		"| a | b |\n| --- | --- |\n\n"+
+		"`\"code\"` {{< figure src=\"/a.jpg\" >}} <a href=\"/b\">“b”</a>\n\n"+
+		"```\nx--\n```", applyTypography(markdown, TypographyCurly))
+}
+
+func TestTypographyOfTitle(t *testing.T) {
+	t.Parallel()
+	url1, err := url.Parse("https://example.com")
+	require.NoError(t, err)
+	page, err := NewPage(nil, *url1, "author", "It's \"new\"", nil, nil, false, nil, nil, nil, nil,
+		"<p>It's \"new\"</p>", nil, nil, nil, nil, nil, "0", nil, PageOptions{Typography: TypographyCurly})
+	require.NoError(t, err)
+	require.Equal(t, "It’s “new”", page.metadata["title"])
+	require.Equal(t, "It’s “new”", page.Markdown())
+
+	page, err = NewPage(nil, *url1, "author", "It's \"new\"", nil, nil, false, nil, nil, nil, nil,
+		"<p>It's \"new\"</p>", nil, nil, nil, nil, nil, "0", nil, PageOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "It's \"new\"", page.metadata["title"])
+}
@@ -0,0 +1,36 @@
+package hugopage
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Lines made of non-breaking spaces only, e.g. "&nbsp;" or "<p>&nbsp;</p>", the spacers of the visual editor
+// which the HTML converter drops but which are left in the content written in Markdown and in the raw HTML
+var _emptyParagraphRegEx = regexp.MustCompile(`(?im)^[ \t]*(?:<p>)?[ \t]*(?:(?:&nbsp;|&#160;|&#xa0;|\x{00A0})[ \t]*)+(?:</p>)?[ \t]*$`)
+
+// normalizeWhitespace is the final pass over the Markdown, outside of the code samples: it trims the trailing
+// whitespace of the lines, keeping the hard line breaks, and collapses the runs of blank lines.
+// The paragraphs made of non-breaking spaces only are removed too with StripEmptyParagraphs.
+func (page *Page) normalizeWhitespace(markdown string) string {
+	if page.options.StripEmptyParagraphs {
+		markdown = _emptyParagraphRegEx.ReplaceAllString(markdown, "")
+	}
+	markdown = trimTrailingWhitespace(markdown)
+	markdown = replaceConsecutiveNewlines(markdown)
+	return strings.TrimLeft(markdown, "\n")
+}
+
+// trimTrailingWhitespace trims the trailing whitespace of the lines, except for the two spaces of
+// the hard line breaks, which are followed by another line of the paragraph
+func trimTrailingWhitespace(markdown string) string {
+	lines := strings.Split(markdown, "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimRight(line, " \t")
+		if trimmed != "" && strings.HasSuffix(line, "  ") && i+1 < len(lines) && strings.TrimSpace(lines[i+1]) != "" {
+			trimmed += "  "
+		}
+		lines[i] = trimmed
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,32 @@
+package hugopage
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrimTrailingWhitespace(t *testing.T) {
+	t.Parallel()
+	// The line breaks are kept, the spaces before a blank line or at the end are not
+	require.Equal(t, "Two  \nlines\n\nTrailing\n\nLast",
+		trimTrailingWhitespace("Two  \nlines  \n\nTrailing \t\n\t\nLast   "))
+	// Only the spaces are line breaks
+	require.Equal(t, "Tab\nspace", trimTrailingWhitespace("Tab\t\t\nspace"))
+}
+
+func TestNormalizeWhitespace(t *testing.T) {
+	t.Parallel()
+	const source = "\n\nOne  \n&nbsp;\n\n\n\n<p>&nbsp;</p>\n\nTwo&nbsp;and \n\n```\ncode  \n\n\n\n&nbsp;\n```\n\n\n"
+	page := newTestPage(t, source, PageOptions{SourceIsMarkdown: true})
+	require.Equal(t, "One  \n&nbsp;\n\n<p>&nbsp;</p>\n\nTwo&nbsp;and\n\n```\ncode  \n\n\n\n&nbsp;\n```\n\n", page.Markdown())
+
+	page = newTestPage(t, source, PageOptions{SourceIsMarkdown: true, StripEmptyParagraphs: true})
+	require.Equal(t, "One\n\nTwo&nbsp;and\n\n```\ncode  \n\n\n\n&nbsp;\n```\n\n", page.Markdown())
+
+	// A single newline at the end of the file
+	var buf bytes.Buffer
+	require.NoError(t, page.writeContent(&buf))
+	require.Equal(t, "One\n\nTwo&nbsp;and\n\n```\ncode  \n\n\n\n&nbsp;\n```\n", buf.String())
+}
@@ -0,0 +1,202 @@
+package hugopage
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/rs/zerolog/log"
+)
+
+// ProductVariationProvider returns the variations of a WooCommerce variable product
+type ProductVariationProvider interface {
+	GetProductVariations(productID string) []wpparser.CommonFields
+}
+
+// WooCommerce postmeta keys and the front matter keys they are emitted as
+var _wooCommerceMetaKeys = map[string]string{
+	"_price":         "price",
+	"_regular_price": "regular_price",
+	"_sale_price":    "sale_price",
+	"_sku":           "sku",
+	"_stock_status":  "stock_status",
+}
+
+const (
+	// CSV list of attachment IDs, e.g. "101,102"
+	_wooCommerceGalleryKey = "_product_image_gallery"
+	// PHP-serialized map of the attributes, keyed by their slug, e.g. "pa_color" or "size"
+	_wooCommerceAttributesKey = "_product_attributes"
+	// Prefix of the attributes of a variation, e.g. "attribute_pa_color" = "blue"
+	_wooCommerceVariationAttributePrefix = "attribute_"
+	// Prefix of the global attributes, which are stored as taxonomies
+	_wooCommerceTaxonomyPrefix = "pa_"
+
+	_productGalleryKey    = "gallery"
+	_productAttributesKey = "attributes"
+	_productVariationsKey = "variations"
+)
+
+// extractWooCommerceProduct decodes the price, SKU, gallery, attributes and variations of a
+// WooCommerce product, along with the set of postmeta keys consumed in the process.
+// Attributes and variations are a best-effort conversion.
+func extractWooCommerceProduct(provider ImageURLProvider, variationProvider ProductVariationProvider, pageURL url.URL,
+	postID string, customMetaData []wpparser.CustomMetaDatum, taxinomies []wpparser.TaxonomyInfo,
+) (map[string]any, map[string]bool) {
+	fields := getWooCommerceFields(customMetaData)
+	consumedKeys := make(map[string]bool)
+	for _, metadatum := range customMetaData {
+		if _, ok := _wooCommerceMetaKeys[metadatum.Key]; ok {
+			consumedKeys[metadatum.Key] = true
+		}
+		switch metadatum.Key {
+		case _wooCommerceGalleryKey:
+			consumedKeys[metadatum.Key] = true
+			if gallery := getProductGallery(provider, pageURL, metadatum.Value); len(gallery) > 0 {
+				fields[_productGalleryKey] = gallery
+			}
+		case _wooCommerceAttributesKey:
+			consumedKeys[metadatum.Key] = true
+			if attributes := getProductAttributes(metadatum.Value, taxinomies); len(attributes) > 0 {
+				fields[_productAttributesKey] = attributes
+			}
+		}
+	}
+
+	if variationProvider != nil {
+		var variations []map[string]any
+		for _, variation := range variationProvider.GetProductVariations(postID) {
+			variations = append(variations, getProductVariation(provider, pageURL, variation))
+		}
+		if len(variations) > 0 {
+			fields[_productVariationsKey] = variations
+		}
+	}
+	return fields, consumedKeys
+}
+
+func getWooCommerceFields(customMetaData []wpparser.CustomMetaDatum) map[string]any {
+	fields := make(map[string]any)
+	for _, metadatum := range customMetaData {
+		if key, ok := _wooCommerceMetaKeys[metadatum.Key]; ok && metadatum.Value != "" {
+			fields[key] = metadatum.Value
+		}
+	}
+	return fields
+}
+
+func getProductGallery(provider ImageURLProvider, pageURL url.URL, imageIDs string) []string {
+	var gallery []string
+	for imageID := range strings.SplitSeq(imageIDs, ",") {
+		imageID = strings.TrimSpace(imageID)
+		if imageID == "" {
+			continue
+		}
+		imageInfo, err := provider.GetImageInfo(imageID)
+		if err != nil {
+			log.Warn().
+				Err(err).
+				Str("imageID", imageID).
+				Msg("Product gallery image not found")
+			continue
+		}
+		link, err := getImageLink(pageURL, *imageInfo)
+		if err != nil {
+			log.Warn().
+				Err(err).
+				Str("imageID", imageID).
+				Msg("Invalid product gallery image URL")
+			continue
+		}
+		gallery = append(gallery, link)
+	}
+	return gallery
+}
+
+// getProductAttributes returns the values of each attribute, keyed by the attribute slug
+// without the "pa_" prefix, as the variations reference them
+func getProductAttributes(serializedAttributes string, taxinomies []wpparser.TaxonomyInfo) map[string][]string {
+	attributes, ok := UnserialiazePHParray(serializedAttributes).(map[string]any)
+	if !ok {
+		return nil
+	}
+	result := make(map[string][]string, len(attributes))
+	for slug, attribute := range attributes {
+		attributeMap, ok := attribute.(map[string]any)
+		if !ok {
+			continue
+		}
+		var values []string
+		if isTaxonomy, _ := attributeMap["is_taxonomy"].(int); isTaxonomy == 1 {
+			for _, taxinomy := range taxinomies {
+				if taxinomy.Taxonomy == slug {
+					values = append(values, taxinomy.Name)
+				}
+			}
+		} else if value, ok := attributeMap["value"].(string); ok {
+			for v := range strings.SplitSeq(value, "|") {
+				if v = strings.TrimSpace(v); v != "" {
+					values = append(values, v)
+				}
+			}
+		}
+		if len(values) > 0 {
+			result[strings.TrimPrefix(slug, _wooCommerceTaxonomyPrefix)] = values
+		}
+	}
+	return result
+}
+
+func getProductVariation(provider ImageURLProvider, pageURL url.URL, variation wpparser.CommonFields) map[string]any {
+	fields := getWooCommerceFields(variation.CustomMetaData)
+	fields["title"] = variation.Title
+	attributes := make(map[string]string)
+	for _, metadatum := range variation.CustomMetaData {
+		if name, ok := strings.CutPrefix(metadatum.Key, _wooCommerceVariationAttributePrefix); ok {
+			// An empty value means "any value" of the attribute
+			attributes[strings.TrimPrefix(name, _wooCommerceTaxonomyPrefix)] = metadatum.Value
+		}
+	}
+	if len(attributes) > 0 {
+		fields[_productAttributesKey] = attributes
+	}
+	if variation.FeaturedImageID != nil {
+		if imageInfo, err := provider.GetImageInfo(*variation.FeaturedImageID); err == nil {
+			if link, err := getImageLink(pageURL, *imageInfo); err == nil {
+				fields["image"] = link
+			}
+		}
+	}
+	return fields
+}
+
+// getProductImageLinks returns the gallery and variation images of the product front matter
+func getProductImageLinks(metadata map[string]any) []string {
+	gallery, _ := metadata[_productGalleryKey].([]string)
+	links := slices.Clone(gallery)
+	variations, _ := metadata[_productVariationsKey].([]map[string]any)
+	for _, variation := range variations {
+		if image, ok := variation["image"].(string); ok {
+			links = append(links, image)
+		}
+	}
+	return links
+}
+
+func replaceProductImageLinks(metadata map[string]any, replacementMap map[string]string) {
+	gallery, _ := metadata[_productGalleryKey].([]string)
+	for i, link := range gallery {
+		if replacement, ok := replacementMap[link]; ok {
+			gallery[i] = replacement
+		}
+	}
+	variations, _ := metadata[_productVariationsKey].([]map[string]any)
+	for _, variation := range variations {
+		if image, ok := variation["image"].(string); ok {
+			if replacement, ok := replacementMap[image]; ok {
+				variation["image"] = replacement
+			}
+		}
+	}
+}
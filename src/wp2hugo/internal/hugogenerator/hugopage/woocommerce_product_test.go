@@ -0,0 +1,24 @@
+package hugopage
+
+import (
+	"testing"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetProductAttributes(t *testing.T) {
+	t.Parallel()
+	const serializedAttributes = `a:2:{s:8:"pa_color";a:2:{s:4:"name";s:8:"pa_color";s:11:"is_taxonomy";i:1;}s:4:"size";a:3:{s:4:"name";s:4:"Size";s:5:"value";s:14:"Small | Large ";s:11:"is_taxonomy";i:0;}}`
+	taxinomies := []wpparser.TaxonomyInfo{
+		{Taxonomy: "pa_color", Name: "Blue"},
+		{Taxonomy: "product_cat", Name: "Mugs"},
+		{Taxonomy: "pa_color", Name: "Red"},
+	}
+
+	require.Equal(t, map[string][]string{
+		"color": {"Blue", "Red"},
+		"size":  {"Small", "Large"},
+	}, getProductAttributes(serializedAttributes, taxinomies))
+	require.Nil(t, getProductAttributes("not serialized", taxinomies))
+}
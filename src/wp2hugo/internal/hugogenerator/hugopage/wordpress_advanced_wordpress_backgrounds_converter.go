@@ -25,6 +25,9 @@ var _AWBRegEx = regexp.MustCompile(`\[nk_awb awb_type="image".*?awb_image="([^"]
 func replaceAWBWithParallaxBlur(provider ImageURLProvider, htmlData string) string {
 	log.Debug().
 		Msg("Replacing AWB (Advanced WordPress Backgrounds) with parallaxblur")
+	if !strings.Contains(htmlData, "nk_awb") {
+		return htmlData
+	}
 
 	htmlData = replaceAllStringSubmatchFunc(_AWBRegEx, htmlData,
 		func(groups []string) string {
@@ -20,7 +20,7 @@ import (
 //
 // Reference : https://wordpress.org/documentation/article/audio-shortcode/
 var (
-	_AudioShortCodeRegEx = regexp.MustCompile(`\[audio ([^\]]+)\](?:.*)(?:\[\/audio\])?`)
+	_AudioShortCodeRegEx = regexp.MustCompile(`\[audio ([^\]]+)\](?:[^\[]*\[\/audio\])?`)
 	_AudioHTMLRegEx      = regexp.MustCompile(`<figure (?:.*?)class="(?:.*?)wp-block-audio(?:.*?)">\s*<audio ([^<>]*?)\/?>(?:<\/audio>)?(?:[\s\S]*?)</figure>`)
 )
 
@@ -35,6 +35,9 @@ var (
 func replaceAudioShortCode(htmlData string) string {
 	log.Debug().
 		Msg("Replacing Audio shortcodes")
+	if !strings.Contains(htmlData, "[audio ") && !strings.Contains(htmlData, "wp-block-audio") {
+		return htmlData
+	}
 	htmlData = replaceAllStringSubmatchFunc(_AudioShortCodeRegEx, htmlData, AudioReplacementFunction)
 	htmlData = replaceAllStringSubmatchFunc(_AudioHTMLRegEx, htmlData, AudioReplacementFunction)
 	return htmlData
@@ -41,3 +41,10 @@ func TestReplaceAudio5(t *testing.T) {
 	const expected = `{{< audio src="file%5Fexample%5Fmp3%5F700kb.mp3" >}}`
 	require.Equal(t, expected, replaceAudioShortCode(htmlData))
 }
+
+func TestReplaceAudioKeepsTrailingText(t *testing.T) {
+	t.Parallel()
+	const htmlData = `[audio mp3="/a.mp3"] Listen above, then read on. [audio mp3="/b.mp3"][/audio] The end.`
+	const expected = `{{< audio src="/a.mp3" >}} Listen above, then read on. {{< audio src="/b.mp3" >}} The end.`
+	require.Equal(t, expected, replaceAudioShortCode(htmlData))
+}
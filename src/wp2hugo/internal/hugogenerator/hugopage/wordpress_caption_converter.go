@@ -16,7 +16,7 @@ import (
 //	[/caption]
 //
 // the important fields to extract are "align", "width", "src", "alt"
-var _CaptionRegEx1 = regexp.MustCompile(`\[caption [^ ]* align="([^"]+)" width="([^"]+)"\]` +
+var _CaptionRegEx1 = regexp.MustCompile(`\[caption [^ ]* align="([^"]+)" width="([^"]*)"\]` +
 	`.*?` +
 	`<img.*?src="([^"]+)" alt="([^"]*?)".*?/>` +
 	`(?:</a>)?` +
@@ -24,13 +24,25 @@ var _CaptionRegEx1 = regexp.MustCompile(`\[caption [^ ]* align="([^"]+)" width="
 	`\[/caption\]`)
 
 // No alt
-var _CaptionRegEx2 = regexp.MustCompile(`\[caption [^ ]* align="([^"]+)" width="([^"]+)"\]` +
+var _CaptionRegEx2 = regexp.MustCompile(`\[caption [^ ]* align="([^"]+)" width="([^"]*)"\]` +
 	`.*?` +
 	`<img.*?src="([^"]+)".*?/>` +
 	`(?:</a>)?` +
 	`(.+?)` +
 	`\[/caption\]`)
 
+// Variants of the caption shortcode, normalized into the form above by normalizeCaptionShortcodes:
+// [wp_caption], used before WordPress 2.6, the caption in a caption="..." attribute, and the attributes
+// in another order, missing or extra, e.g. class="...":
+//
+//	[wp_caption id="attachment_12" align="alignleft" width="300" caption="Sunset"]<img src="..." />[/wp_caption]
+var (
+	_captionShortcodeRegEx   = regexp.MustCompile(`(?s)\[(wp_caption|caption)((?:\s[^\]]*)?)\](.*?)\[/(?:wp_caption|caption)\]`)
+	_canonicalCaptionRegEx   = regexp.MustCompile(`^ id="[^" ]*" align="[^"]+" width="[^"]+"$`)
+	_shortcodeAttributeRegEx = regexp.MustCompile(`([\w-]+)="([^"]*)"`)
+	_imageWidthRegEx         = regexp.MustCompile(`<img[^>]*?\swidth="(\d+)"`)
+)
+
 // Gutenberg image blocs, no figcaption :
 // <!-- wp:image {"align":"center","id":3875,"sizeSlug":"large","className":"is-style-default"} -->
 // <div class="wp-block-image is-style-default"><figure class="aligncenter size-large"><img src="https://photo.aurelienpierre.com/wp-content/uploads/sites/3/2016/03/Shooting-Minh-Ly-0155-_DSC0155-Minh-Ly-WEB-1100x1100.jpg" alt="" class="wp-image-3875"/></figure></div>
@@ -57,15 +69,56 @@ var _FigureRegexCaption = regexp.MustCompile(`(?ms)<!-- wp:image.*?-->` +
 func replaceCaptionWithFigure(htmlData string) string {
 	log.Debug().
 		Msg("Replacing caption with figure")
+	if !strings.Contains(htmlData, "[caption") && !strings.Contains(htmlData, "[wp_caption") {
+		return htmlData
+	}
 
+	htmlData = normalizeCaptionShortcodes(htmlData)
 	htmlData = replaceAllStringSubmatchFunc(_CaptionRegEx1, htmlData, captionReplacementFunction)
 	htmlData = replaceAllStringSubmatchFunc(_CaptionRegEx2, htmlData, captionReplacementFunction)
 	return htmlData
 }
 
+// normalizeCaptionShortcodes rewrites the variants of the caption shortcode into the `[caption id="" align="" width=""]`
+// form, with the caption after the image. The align defaults to "alignnone", the width to the one of the image.
+func normalizeCaptionShortcodes(htmlData string) string {
+	return replaceAllStringSubmatchFunc(_captionShortcodeRegEx, htmlData, func(groups []string) string {
+		name, attributes, body := groups[1], groups[2], groups[3]
+		if name == "caption" && _canonicalCaptionRegEx.MatchString(attributes) {
+			return groups[0]
+		}
+		values := make(map[string]string)
+		for _, match := range _shortcodeAttributeRegEx.FindAllStringSubmatch(attributes, -1) {
+			values[match[1]] = match[2]
+		}
+		log.Info().
+			Str("variant", name).
+			Str("attributes", strings.TrimSpace(attributes)).
+			Msg("Converting a variant of the caption shortcode")
+		align := values["align"]
+		if align == "" {
+			align = "alignnone"
+		}
+		width := values["width"]
+		if width == "" {
+			if match := _imageWidthRegEx.FindStringSubmatch(body); match != nil {
+				width = match[1]
+			}
+		}
+		if caption := strings.TrimSpace(values["caption"]); caption != "" {
+			body = strings.TrimRight(body, " \n") + " " + caption
+		}
+		return fmt.Sprintf(`[caption id="%s" align="%s" width="%s"]%s[/caption]`,
+			strings.ReplaceAll(values["id"], " ", ""), align, width, body)
+	})
+}
+
 func replaceImageBlockWithFigure(htmlData string) string {
 	log.Debug().
 		Msg("Replacing Gutenberg image with figure")
+	if !strings.Contains(htmlData, "<!-- wp:image") {
+		return htmlData
+	}
 
 	htmlData = replaceAllStringSubmatchFunc(_FigureRegexNoCaption, htmlData, imageBlockReplacementFunction)
 	htmlData = replaceAllStringSubmatchFunc(_FigureRegexCaption, htmlData, imageBlockReplacementFunction)
@@ -117,6 +170,10 @@ func captionReplacementFunction(groups []string) string {
 		alt = sanitizeQuotes(groups[4])
 	}
 
+	if groups[2] == "" {
+		// Unknown width, see normalizeCaptionShortcodes
+		return fmt.Sprintf(`{{< figure align="%s" src="%s" alt="%s" caption="%s" >}}`, groups[1], src, alt, alt)
+	}
 	return fmt.Sprintf(`{{< figure align="%s" width=%s src="%s" alt="%s" caption="%s" >}}`,
 		groups[1], groups[2], src, alt, alt)
 }
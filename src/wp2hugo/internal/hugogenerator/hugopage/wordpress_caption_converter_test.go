@@ -54,3 +54,24 @@ func TestCaption4Replace(t *testing.T) {
 //	expected := "\n{{< figure src=\"https://photo.aurelienpierre.com/wp-content/uploads/sites/3/2016/03/Shooting-Minh-Ly-0155-%5FDSC0155-Minh-Ly-WEB-1100x1100.jpg\" alt=\"\" caption=\"Minh-Ly\" >}}\n"
 //	require.Equal(t, expected, replaceImageBlockWithFigure(example5))
 //}
+
+func TestCaptionVariants(t *testing.T) {
+	t.Parallel()
+	// Pre-2.6 caption with the caption in an attribute
+	const wpCaption = `[wp_caption id="attachment_12" align="alignleft" width="300" caption="Sunset"]<img src="https://example.org/wp-content/uploads/2008/05/sunset.jpg" alt="Sunset over the bay" width="300" height="200" />[/wp_caption]`
+	require.Equal(t, `{{< figure align="alignleft" width=300 src="https://example.org/wp-content/uploads/2008/05/sunset.jpg" alt="Sunset over the bay" caption="Sunset over the bay" >}}`,
+		replaceCaptionWithFigure(wpCaption))
+
+	// Attributes in another order, an extra class, and no width on the shortcode
+	const reordered = `[caption align="aligncenter" class="shadow" id="attachment_13"]<img src="/wp-content/uploads/2010/01/dune.jpg" alt="Dune" width="640" /> The dune[/caption]`
+	require.Equal(t, `{{< figure align="aligncenter" width=640 src="/wp-content/uploads/2010/01/dune.jpg" alt="Dune" caption="Dune" >}}`,
+		replaceCaptionWithFigure(reordered))
+
+	// No align nor width at all
+	const bare = `[caption]<img src="/wp-content/uploads/2010/01/dune.jpg" alt="Dune" /> The dune[/caption]`
+	require.Equal(t, `{{< figure align="alignnone" src="/wp-content/uploads/2010/01/dune.jpg" alt="Dune" caption="Dune" >}}`,
+		replaceCaptionWithFigure(bare))
+
+	// The canonical form is left as is
+	require.Equal(t, example1, normalizeCaptionShortcodes(example1))
+}
@@ -10,13 +10,16 @@ import (
 )
 
 // Example: "[catlist name="programming" catlink=yes date=yes date\_tag=p excerpt=no numberposts=5 date=no thumbnail=no]"
-var _CatlistRegEx = regexp.MustCompile(`\\\[catlist name="([^"]+)" catlink=(yes|no) .* numberposts=([0-9]+).*]`)
+var _CatlistRegEx = regexp.MustCompile(`\\\[catlist name="([^"]+)" catlink=(yes|no) [^\]]*numberposts=([0-9]+)[^\]]*]`)
 
 // Converts the catlist shortcode to Hugo shortcode using our custom
 // shortcode _selectedPostsShortCode
 func replaceCatlistWithShortcode(markdownData string) string {
 	log.Debug().
 		Msg("Replacing catlist with shortcode")
+	if !strings.Contains(markdownData, "catlist") {
+		return markdownData
+	}
 
 	markdownData = replaceAllStringSubmatchFunc(_CatlistRegEx, markdownData, func(groups []string) string {
 		return fmt.Sprintf("{{< catlist category=\"%s\" catlink=%s count=%s >}}",
@@ -29,20 +32,29 @@ func replaceCatlistWithShortcode(markdownData string) string {
 
 // Ref: https://gist.github.com/elliotchance/d419395aa776d632d897
 func replaceAllStringSubmatchFunc(re *regexp.Regexp, str string, repl func([]string) string) string {
-	result := ""
-	lastIndex := 0
+	matches := re.FindAllStringSubmatchIndex(str, -1)
+	if len(matches) == 0 {
+		return str
+	}
 
+	lastIndex := 0
 	var resultSb strings.Builder
-	for _, v := range re.FindAllStringSubmatchIndex(str, -1) {
-		var groups []string
+	resultSb.Grow(len(str))
+	for _, v := range matches {
+		groups := make([]string, 0, len(v)/2)
 		for i := 0; i < len(v); i += 2 {
+			if v[i] < 0 {
+				// Optional group that did not participate in the match
+				groups = append(groups, "")
+				continue
+			}
 			groups = append(groups, str[v[i]:v[i+1]])
 		}
 
-		resultSb.WriteString(str[lastIndex:v[0]] + repl(groups))
+		resultSb.WriteString(str[lastIndex:v[0]])
+		resultSb.WriteString(repl(groups))
 		lastIndex = v[1]
 	}
-	result += resultSb.String()
-
-	return result + str[lastIndex:]
+	resultSb.WriteString(str[lastIndex:])
+	return resultSb.String()
 }
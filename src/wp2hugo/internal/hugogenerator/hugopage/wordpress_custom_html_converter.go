@@ -0,0 +1,47 @@
+package hugopage
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Gutenberg "Custom HTML" block, the content must be kept as-is
+// Example:
+// <!-- wp:html -->
+// <div class="newsletter"><form action="/subscribe">...</form></div>
+// <!-- /wp:html -->
+var _CustomHTMLBlockRegEx = regexp.MustCompile(`(?s)<!-- wp:html(?: \{[^}]*\})? -->(.*?)<!-- /wp:html -->`)
+
+// The markdown converter would escape or drop the raw HTML, so we replace each block with a
+// placeholder paragraph and put the raw HTML back once the conversion is done
+const _customHTMLPlaceholder = "WP2HUGOCUSTOMHTMLBLOCK%dEND"
+
+func extractCustomHTMLBlocks(htmlData string) (string, []string) {
+	blocks := make([]string, 0)
+	htmlData = replaceAllStringSubmatchFunc(_CustomHTMLBlockRegEx, htmlData, func(groups []string) string {
+		blocks = append(blocks, strings.TrimSpace(groups[1]))
+		return fmt.Sprintf("<p>"+_customHTMLPlaceholder+"</p>", len(blocks)-1)
+	})
+	if len(blocks) > 0 {
+		log.Debug().
+			Int("count", len(blocks)).
+			Msg("Custom HTML blocks found")
+	}
+	return htmlData, blocks
+}
+
+// restoreCustomHTMLBlocks puts the raw HTML back into the markdown.
+// Hugo only renders raw HTML when Goldmark's "unsafe" mode is on, wrapping the HTML in
+// the "rawhtml" shortcode makes it render regardless of that setting.
+func restoreCustomHTMLBlocks(markdown string, blocks []string, wrapInShortcode bool) string {
+	for i, block := range blocks {
+		if wrapInShortcode {
+			block = fmt.Sprintf("{{< rawhtml >}}\n%s\n{{< /rawhtml >}}", block)
+		}
+		markdown = strings.Replace(markdown, fmt.Sprintf(_customHTMLPlaceholder, i), block, 1)
+	}
+	return markdown
+}
@@ -0,0 +1,40 @@
+package hugopage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const _customHTMLBlock = `<!-- wp:paragraph --><p>Sign up below</p><!-- /wp:paragraph -->
+<!-- wp:html -->
+<div class="newsletter" data-list="weekly"><span>**Weekly** digest</span> &amp; more</div>
+<!-- /wp:html -->
+<!-- wp:paragraph --><p>Thanks!</p><!-- /wp:paragraph -->`
+
+func TestCustomHTMLBlockSurvivesConversion(t *testing.T) {
+	t.Parallel()
+	const expected = "Sign up below\n\n" +
+		`<div class="newsletter" data-list="weekly"><span>**Weekly** digest</span> &amp; more</div>` +
+		"\n\nThanks!"
+	testMarkdownExtractor(t, _customHTMLBlock, expected)
+}
+
+func TestCustomHTMLBlockWrappedInShortcode(t *testing.T) {
+	t.Parallel()
+	page := newTestPage(t, _customHTMLBlock, PageOptions{WrapCustomHTMLInShortcode: true})
+	const expected = "{{< rawhtml >}}\n" +
+		`<div class="newsletter" data-list="weekly"><span>**Weekly** digest</span> &amp; more</div>` +
+		"\n{{< /rawhtml >}}"
+	require.Contains(t, page.Markdown(), expected)
+}
+
+func TestCustomHTMLBlockUntouchedByMarkdownPasses(t *testing.T) {
+	t.Parallel()
+	const block = `<a href="https://example.com/about/">About</a>
+5. five
+<script>var greeting = "it's --- here";</script>`
+	page := newTestPage(t, "<p>A \"quoted\" intro</p>\n<!-- wp:html -->\n"+block+"\n<!-- /wp:html -->",
+		PageOptions{Typography: TypographyCurly})
+	require.Equal(t, "A “quoted” intro\n\n"+block, page.Markdown())
+}
@@ -16,10 +16,16 @@ import (
 // `size` is legacy from pre-responsive design and should be discarded now
 // `link` is probably something to enforce in Hugo figure shortcode,
 // It is mostly "file" to handle, since "attachment_page" makes no sense for Hugo.
+//
+// Jetpack adds a type="rectangular|square|circle|columns|slideshow" attribute, for its tiled galleries and slideshows,
+// which degrade to a simple gallery, of 3 columns unless set, or 1 for the slideshows.
 var (
-	_GalleryRegEx = regexp.MustCompile(`\[gallery ([^\[\]]*)\]`)
+	_GalleryRegEx = regexp.MustCompile(`\[gallery((?:\s[^\[\]]*)?)\]`)
 	_idRegEx      = regexp.MustCompile(`ids="([^"]+)"`)
 	_colsRegEx    = regexp.MustCompile(`columns="([^"]+)"`)
+	_typeRegEx    = regexp.MustCompile(`type="([^"]+)"`)
+
+	_galleryColumnsRegEx = regexp.MustCompile(`columns-(\d+)`)
 )
 
 // Example:
@@ -37,6 +43,18 @@ var _innerFigureCaption = regexp.MustCompile(`(?ms)<figure.*?` +
 	`<figcaption.*?>(.*?)</figcaption>` +
 	`.*?</figure>`)
 
+// Jetpack tiled gallery blocks, the images are nested in rows and columns of <div>:
+// <!-- wp:jetpack/tiled-gallery {"columns":2,"ids":[12,13],"linkTo":"media"} -->
+// <div class="wp-block-jetpack-tiled-gallery aligncenter is-style-rectangular"><div class="tiled-gallery__gallery"><div class="tiled-gallery__row"><div class="tiled-gallery__col"><figure class="tiled-gallery__item"><img alt="Dunes" data-id="12" data-url="https://example.org/wp-content/uploads/2021/05/dunes.jpg" src="https://i0.wp.com/example.org/wp-content/uploads/2021/05/dunes.jpg?ssl=1"/></figure>...</div></div></div></div>
+// <!-- /wp:jetpack/tiled-gallery -->
+var (
+	_JetpackTiledGalleryRegEx = regexp.MustCompile(`(?ms)<!-- wp:jetpack/tiled-gallery(.*?)-->(.*?)<!-- /wp:jetpack/tiled-gallery -->`)
+	_blockColumnsRegEx        = regexp.MustCompile(`"columns":(\d+)`)
+)
+
+// Columns of the galleries converted from the Jetpack tiled galleries, which have no columns
+const _tiledGalleryColumns = "3"
+
 var errGalleryWithNoIDs = errors.New("no image IDs found in gallery shortcode")
 
 // TODO: should we handle `order="ASC|DESC"` when `orderby="ID"` ?
@@ -45,12 +63,15 @@ var errGalleryWithNoIDs = errors.New("no image IDs found in gallery shortcode")
 func replaceGalleryWithFigure(provider ImageURLProvider, attachmentIDs []string, htmlData string) string {
 	log.Debug().
 		Msg("Replacing gallery with figures")
+	if !strings.Contains(htmlData, "[gallery") {
+		return htmlData
+	}
 
 	htmlData = replaceAllStringSubmatchFunc(_GalleryRegEx, htmlData,
 		func(groups []string) string {
 			info, err := galleryReplacementFunction(provider, attachmentIDs, groups[1])
 			if err != nil {
-				return fmt.Sprintf("[gallery %s]", info) // Return the original shortcode
+				return groups[0] // Return the original shortcode
 			}
 			return info
 		})
@@ -61,6 +82,9 @@ func replaceGalleryWithFigure(provider ImageURLProvider, attachmentIDs []string,
 func replaceGutembergGalleryWithFigure(htmlData string) string {
 	log.Debug().
 		Msg("Replacing Gutenberg gallery with figures")
+	if !strings.Contains(htmlData, "wp:gallery") {
+		return htmlData
+	}
 
 	return replaceAllStringSubmatchFunc(_GutenbergGalleryRegEx, htmlData, gutenbergGalleryReplacementFunction)
 }
@@ -122,8 +146,7 @@ func gutenbergGalleryReplacementFunction(groups []string) string {
 		}
 
 		if classAttr != "" {
-			re := regexp.MustCompile(`columns-(\d+)`)
-			matches := re.FindStringSubmatch(classAttr)
+			matches := _galleryColumnsRegEx.FindStringSubmatch(classAttr)
 			if len(matches) == 2 {
 				cols = matches[1]
 			} else {
@@ -154,12 +177,106 @@ func gutenbergGalleryReplacementFunction(groups []string) string {
 	return output.String()
 }
 
+// replaceJetpackTiledGalleryWithFigure converts the Jetpack tiled gallery blocks to a simple gallery,
+// like the Gutenberg gallery blocks
+func replaceJetpackTiledGalleryWithFigure(htmlData string) string {
+	if !strings.Contains(htmlData, "wp:jetpack/tiled-gallery") {
+		return htmlData
+	}
+	return replaceAllStringSubmatchFunc(_JetpackTiledGalleryRegEx, htmlData, func(groups []string) string {
+		doc, err := html.Parse(strings.NewReader(groups[2]))
+		if err != nil {
+			return groups[0]
+		}
+		var figures []*html.Node
+		findInnerFigures(doc, &figures)
+		if len(figures) == 0 {
+			return groups[0]
+		}
+		cols := _tiledGalleryColumns
+		if match := _blockColumnsRegEx.FindStringSubmatch(groups[1]); match != nil {
+			cols = match[1]
+		}
+		log.Info().
+			Int("images", len(figures)).
+			Str("cols", cols).
+			Msg("Converting a Jetpack tiled gallery block to a simple gallery")
+
+		var output strings.Builder
+		output.WriteString("<br>") // This will get converted to newline later on
+		fmt.Fprintf(&output, `{{< gallery cols="%s" >}}`, cols)
+		output.WriteString("<br>") // This will get converted to newline later on
+		for _, figure := range figures {
+			src, alt, caption := getTiledGalleryImage(figure)
+			if src == "" {
+				continue
+			}
+			if alt == "" {
+				alt = caption
+			}
+			fmt.Fprintf(&output, `{{< figure src="%s" alt="%s" caption="%s" >}}`,
+				sanitizeLinks(src), sanitizeQuotes(alt), sanitizeQuotes(caption))
+			output.WriteString("<br>") // This will get converted to newline later on
+		}
+		output.WriteString(`{{< /gallery >}}`)
+		output.WriteString("<br>") // This will get converted to newline later on
+		return output.String()
+	})
+}
+
+// getTiledGalleryImage returns the image of a figure of a Jetpack tiled gallery, its original URL rather than
+// the resized one of the Jetpack CDN, and its caption
+func getTiledGalleryImage(figure *html.Node) (string, string, string) {
+	src, alt, caption := "", "", ""
+	var visit func(node *html.Node)
+	visit = func(node *html.Node) {
+		if node.Type == html.ElementNode && node.Data == "img" && src == "" {
+			for _, attr := range node.Attr {
+				switch {
+				case attr.Key == "data-url", attr.Key == "src" && src == "":
+					src = attr.Val
+				case attr.Key == "alt":
+					alt = attr.Val
+				}
+			}
+		}
+		if node.Type == html.ElementNode && node.Data == "figcaption" {
+			caption = strings.TrimSpace(textContent(node))
+		}
+		for c := node.FirstChild; c != nil; c = c.NextSibling {
+			visit(c)
+		}
+	}
+	visit(figure)
+	return src, alt, caption
+}
+
+func textContent(node *html.Node) string {
+	if node.Type == html.TextNode {
+		return node.Data
+	}
+	var sb strings.Builder
+	for c := node.FirstChild; c != nil; c = c.NextSibling {
+		sb.WriteString(textContent(c))
+	}
+	return sb.String()
+}
+
 func galleryReplacementFunction(provider ImageURLProvider, attachmentIDs []string, galleryInfo string) (string, error) {
 	var output strings.Builder
 
 	// Find columns layout
 	cols := _colsRegEx.FindStringSubmatch(galleryInfo)
 	colNb := "1"
+	if galleryType := _typeRegEx.FindStringSubmatch(galleryInfo); galleryType != nil && galleryType[1] != "default" {
+		log.Info().
+			Str("galleryInfo", galleryInfo).
+			Str("type", galleryType[1]).
+			Msg("Converting a Jetpack gallery type to a simple gallery")
+		if galleryType[1] != "slideshow" {
+			colNb = _tiledGalleryColumns
+		}
+	}
 	if cols != nil {
 		colNb = cols[1]
 	}
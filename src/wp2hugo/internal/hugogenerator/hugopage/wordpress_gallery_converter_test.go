@@ -29,3 +29,40 @@ func TestReplaceGutenbergGallery(t *testing.T) {
 	const expected = `<br>{{< gallery cols="2" >}}<br>{{< figure src="https://photo.aurelienpierre.com/wp-content/uploads/sites/3/2020/02/haute-diffusion-1.jpg" alt="Lumière fortement diffusée" caption="Lumière fortement diffusée" >}}<br>{{< figure src="https://photo.aurelienpierre.com/wp-content/uploads/sites/3/2020/02/faible-diffusion.jpg" alt="Lumière faiblement diffusée<br/>" caption="Lumière faiblement diffusée<br/>" >}}<br>{{< /gallery >}}<br>`
 	require.Equal(t, expected, replaceGutembergGalleryWithFigure(htmlData))
 }
+
+func TestJetpackGalleryTypes(t *testing.T) {
+	t.Parallel()
+	provider := mapImageURLProvider{
+		"12": {ImageURL: "https://example.org/wp-content/uploads/2021/05/dunes.jpg", Title: "Dunes"},
+		"13": {ImageURL: "https://example.org/wp-content/uploads/2021/05/oasis.jpg", Title: "Oasis"},
+	}
+	const figures = `{{< figure src="https://example.org/wp-content/uploads/2021/05/dunes.jpg" title="Dunes" alt="Dunes" >}}<br><br>` +
+		`{{< figure src="https://example.org/wp-content/uploads/2021/05/oasis.jpg" title="Oasis" alt="Oasis" >}}<br>`
+
+	require.Equal(t, `<br>{{< gallery cols="3" >}}<br>`+figures+`{{< /gallery >}}<br>`,
+		replaceGalleryWithFigure(provider, nil, `[gallery type="rectangular" ids="12,13"]`))
+	require.Equal(t, `<br>{{< gallery cols="2" >}}<br>`+figures+`{{< /gallery >}}<br>`,
+		replaceGalleryWithFigure(provider, nil, `[gallery type="square" columns="2" ids="12,13"]`))
+	require.Equal(t, `<br>{{< gallery cols="1" >}}<br>`+figures+`{{< /gallery >}}<br>`,
+		replaceGalleryWithFigure(provider, nil, `[gallery type="slideshow" ids="12,13"]`))
+	// No attributes, the attachments of the page
+	require.Equal(t, `<br>{{< gallery cols="1" >}}<br>`+figures+`{{< /gallery >}}<br>`,
+		replaceGalleryWithFigure(provider, []string{"12", "13"}, `[gallery]`))
+	// Nothing to show, the shortcode is kept
+	require.Equal(t, `[gallery]`, replaceGalleryWithFigure(provider, nil, `[gallery]`))
+}
+
+func TestReplaceJetpackTiledGallery(t *testing.T) {
+	t.Parallel()
+	const htmlData = `<!-- wp:jetpack/tiled-gallery {"columns":2,"ids":[12,13],"linkTo":"media"} -->
+<div class="wp-block-jetpack-tiled-gallery aligncenter is-style-rectangular"><div class="tiled-gallery__gallery"><div class="tiled-gallery__row">` +
+		`<div class="tiled-gallery__col"><figure class="tiled-gallery__item"><img alt="Dunes" data-id="12" src="https://i0.wp.com/example.org/wp-content/uploads/2021/05/dunes.jpg?ssl=1" data-url="https://example.org/wp-content/uploads/2021/05/dunes.jpg"/></figure></div>` +
+		`<div class="tiled-gallery__col"><figure class="tiled-gallery__item"><img alt="" data-id="13" src="https://example.org/wp-content/uploads/2021/05/oasis.jpg"/><figcaption class="tiled-gallery__caption">The "oasis"</figcaption></figure></div>` +
+		`</div></div></div>
+<!-- /wp:jetpack/tiled-gallery -->`
+	const expected = `<br>{{< gallery cols="2" >}}<br>` +
+		`{{< figure src="https://example.org/wp-content/uploads/2021/05/dunes.jpg" alt="Dunes" caption="" >}}<br>` +
+		`{{< figure src="https://example.org/wp-content/uploads/2021/05/oasis.jpg" alt="The 'oasis'" caption="The 'oasis'" >}}<br>` +
+		`{{< /gallery >}}<br>`
+	require.Equal(t, expected, replaceJetpackTiledGalleryWithFigure(htmlData))
+}
@@ -0,0 +1,147 @@
+package hugopage
+
+import (
+	"fmt"
+	"html"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Example: [playlist type="video" ids="1710,1713" order="DESC"]
+// The tracks are the attachments of the ids, in this order, or else the attachments of the page.
+// The style, tracklist, tracknumbers, images and artists attributes only change the rendering of the WordPress player.
+// Reference: https://wordpress.org/documentation/article/playlist-shortcode/
+var (
+	_PlaylistRegEx      = regexp.MustCompile(`\[playlist((?:\s[^\[\]]*)?)\]`)
+	_playlistTypeRegEx  = regexp.MustCompile(`type="([^"]+)"`)
+	_playlistOrderRegEx = regexp.MustCompile(`order="([^"]+)"`)
+)
+
+// Extensions of the media files WordPress lists in the audio and video playlists
+var _playlistExtensions = map[string][]string{
+	"audio": {".mp3", ".m4a", ".ogg", ".oga", ".wav", ".flac", ".aac"},
+	"video": {".mp4", ".m4v", ".webm", ".ogv", ".mov"},
+}
+
+// Hugo shortcode of the tracks, <PlaylistShortcode>-track, nested in the playlist shortcode
+const _playlistTrackShortcodeSuffix = "-track"
+
+type playlistTrack struct {
+	src   string
+	title string
+}
+
+// replacePlaylistShortCode converts the [playlist] shortcodes into an HTML5 playlist, a list of <audio> or <video>
+// elements, or into the PageOptions.PlaylistShortcode Hugo shortcode, with a nested shortcode per track.
+// The HTML playlist of the converted HTML is kept as is, like a Custom HTML block.
+func (page *Page) replacePlaylistShortCode(provider ImageURLProvider, attachmentIDs []string, content string) string {
+	if !strings.Contains(content, "[playlist") || provider == nil {
+		return content
+	}
+	return replaceAllStringSubmatchFunc(_PlaylistRegEx, content, func(groups []string) string {
+		mediaType := "audio"
+		if match := _playlistTypeRegEx.FindStringSubmatch(groups[1]); match != nil {
+			mediaType = strings.ToLower(match[1])
+		}
+		if _, ok := _playlistExtensions[mediaType]; !ok {
+			log.Warn().
+				Str("playlist", groups[0]).
+				Str("type", mediaType).
+				Msg("Unknown playlist type, keeping the shortcode")
+			return groups[0]
+		}
+		tracks := getPlaylistTracks(provider, attachmentIDs, groups[1], mediaType)
+		if len(tracks) == 0 {
+			log.Warn().
+				Str("playlist", groups[0]).
+				Msg("No track found in the playlist, keeping the shortcode")
+			return groups[0]
+		}
+		if page.options.PlaylistShortcode != "" {
+			return page.getPlaylistShortcode(mediaType, tracks)
+		}
+		return page.getHTMLPlaylist(mediaType, tracks)
+	})
+}
+
+func getPlaylistTracks(provider ImageURLProvider, attachmentIDs []string, attrs string, mediaType string) []playlistTrack {
+	ids := attachmentIDs
+	if match := _idRegEx.FindStringSubmatch(attrs); match != nil {
+		ids = strings.Split(match[1], ",")
+	}
+	tracks := make([]playlistTrack, 0, len(ids))
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		info, err := provider.GetImageInfo(id)
+		if info == nil {
+			log.Warn().
+				Err(err).
+				Str("attachmentID", id).
+				Msg("Playlist track not found, skipping it")
+			continue
+		}
+		if !slices.Contains(_playlistExtensions[mediaType], strings.ToLower(path.Ext(info.ImageURL))) {
+			// The attachments of the page may be images too
+			log.Debug().
+				Str("attachmentID", id).
+				Str("url", info.ImageURL).
+				Str("type", mediaType).
+				Msg("Not a playlist track of this type, skipping it")
+			continue
+		}
+		tracks = append(tracks, playlistTrack{src: info.ImageURL, title: info.Title})
+	}
+	if match := _playlistOrderRegEx.FindStringSubmatch(attrs); match != nil && strings.EqualFold(match[1], "DESC") {
+		slices.Reverse(tracks)
+	}
+	return tracks
+}
+
+// getHTMLPlaylist returns the tracks as a list of <audio> or <video> elements, with their title
+func (page *Page) getHTMLPlaylist(mediaType string, tracks []playlistTrack) string {
+	var playlist strings.Builder
+	fmt.Fprintf(&playlist, `<figure class="wp-playlist wp-%s-playlist"><ol>`, mediaType)
+	for _, track := range tracks {
+		// Rewritten like the links of the content, which the Markdown passes leave out of the custom HTML blocks
+		src := replaceAbsoluteLinksWithPrefixed(page.absoluteURL.Host, page.options.BasePath, page.options.URLPrefix,
+			page.options.AbsoluteMediaLinks, track.src)
+		fmt.Fprintf(&playlist, "\n"+`<li><%s controls preload="metadata" src="%s"></%s>`, mediaType, html.EscapeString(src), mediaType)
+		if track.title != "" {
+			fmt.Fprintf(&playlist, " %s", html.EscapeString(track.title))
+		}
+		playlist.WriteString("</li>")
+	}
+	playlist.WriteString("\n</ol></figure>")
+	if page.options.SourceIsMarkdown {
+		return playlist.String()
+	}
+	// Kept as is by the Markdown conversion
+	return "<!-- wp:html -->\n" + playlist.String() + "\n<!-- /wp:html -->"
+}
+
+// getPlaylistShortcode returns the tracks as a PageOptions.PlaylistShortcode shortcode, e.g.
+// {{< playlist type="audio" >}} {{< playlist-track src="/a.mp3" title="A" >}} {{< /playlist >}}
+func (page *Page) getPlaylistShortcode(mediaType string, tracks []playlistTrack) string {
+	// The <br> are converted to newlines, the Markdown source is kept as is
+	newline := "<br>"
+	if page.options.SourceIsMarkdown {
+		newline = "\n"
+	}
+	name := page.options.PlaylistShortcode
+	var playlist strings.Builder
+	playlist.WriteString(newline)
+	fmt.Fprintf(&playlist, `{{< %s type="%s" >}}`, name, mediaType)
+	for _, track := range tracks {
+		playlist.WriteString(newline)
+		fmt.Fprintf(&playlist, `{{< %s%s src="%s" title="%s" >}}`, name, _playlistTrackShortcodeSuffix,
+			sanitizeLinks(track.src), sanitizeQuotes(track.title))
+	}
+	playlist.WriteString(newline)
+	fmt.Fprintf(&playlist, `{{< /%s >}}`, name)
+	playlist.WriteString(newline)
+	return playlist.String()
+}
@@ -0,0 +1,87 @@
+package hugopage
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/stretchr/testify/require"
+)
+
+type mapImageURLProvider map[string]ImageInfo
+
+func (m mapImageURLProvider) GetImageInfo(imageID string) (*ImageInfo, error) {
+	if info, ok := m[imageID]; ok {
+		return &info, nil
+	}
+	return nil, errors.New("attachment not found")
+}
+
+var _playlistProvider = mapImageURLProvider{
+	"1": {ImageURL: "https://example.com/wp-content/uploads/2024/01/intro.mp3", Title: "Intro"},
+	"2": {ImageURL: "https://example.com/wp-content/uploads/2024/01/outro.mp3", Title: `The "End"`},
+	"3": {ImageURL: "https://example.com/wp-content/uploads/2024/01/cover.jpg", Title: "Cover"},
+	"4": {ImageURL: "https://example.com/wp-content/uploads/2024/01/talk.mp4", Title: "Talk"},
+}
+
+func getPlaylistMarkdown(t *testing.T, htmlContent string, attachmentIDs []string, options PageOptions) (string, []string) {
+	t.Helper()
+	url1, err := url.Parse("https://example.com/post/")
+	require.NoError(t, err)
+	attachments := make([]wpparser.AttachmentInfo, 0, len(attachmentIDs))
+	for _, attachmentID := range attachmentIDs {
+		attachments = append(attachments, wpparser.AttachmentInfo{CommonFields: wpparser.CommonFields{PostID: attachmentID}})
+	}
+	page, err := NewPage(_playlistProvider, *url1, "author", "Title", nil, nil, false, nil, nil, attachments, nil, htmlContent,
+		nil, nil, nil, nil, nil, "0", nil, options)
+	require.NoError(t, err)
+	return page.markdown, page.WPStaticLinks()
+}
+
+func TestHTMLPlaylist(t *testing.T) {
+	t.Parallel()
+	markdown, links := getPlaylistMarkdown(t, `<p>Listen:</p><p>[playlist ids="1,99,2"]</p>`, nil, PageOptions{})
+	require.Equal(t, "Listen:\n\n"+`<figure class="wp-playlist wp-audio-playlist"><ol>
+<li><audio controls preload="metadata" src="/wp-content/uploads/2024/01/intro.mp3"></audio> Intro</li>
+<li><audio controls preload="metadata" src="/wp-content/uploads/2024/01/outro.mp3"></audio> The &#34;End&#34;</li>
+</ol></figure>`, markdown)
+	require.Equal(t, []string{
+		"/wp-content/uploads/2024/01/intro.mp3",
+		"/wp-content/uploads/2024/01/outro.mp3",
+	}, links)
+
+	// A video playlist in the reverse order
+	markdown, _ = getPlaylistMarkdown(t, `<p>[playlist type="video" ids="1,4" order="DESC"]</p>`, nil, PageOptions{})
+	require.Equal(t, `<figure class="wp-playlist wp-video-playlist"><ol>
+<li><video controls preload="metadata" src="/wp-content/uploads/2024/01/talk.mp4"></video> Talk</li>
+</ol></figure>`, markdown)
+
+	// The playlist of the Markdown source is raw HTML too
+	markdown, _ = getPlaylistMarkdown(t, "Talk:\n\n[playlist type=\"video\" ids=\"4\"]", nil, PageOptions{SourceIsMarkdown: true})
+	require.Equal(t, "Talk:\n\n"+`<figure class="wp-playlist wp-video-playlist"><ol>
+<li><video controls preload="metadata" src="/wp-content/uploads/2024/01/talk.mp4"></video> Talk</li>
+</ol></figure>`, markdown)
+}
+
+func TestPlaylistShortcode(t *testing.T) {
+	t.Parallel()
+	// Without ids, the audio attachments of the page
+	markdown, links := getPlaylistMarkdown(t, `<p>[playlist order="DESC"]</p>`, []string{"1", "3", "2"}, PageOptions{PlaylistShortcode: "playlist"})
+	// Like the galleries, the shortcodes are separated with line breaks
+	require.Equal(t, `{{< playlist type="audio" >}}`+"  \n"+
+		`{{< playlist-track src="/wp-content/uploads/2024/01/outro.mp3" title="The 'End'" >}}`+"  \n"+
+		`{{< playlist-track src="/wp-content/uploads/2024/01/intro.mp3" title="Intro" >}}`+"  \n"+
+		`{{< /playlist >}}`+"\n", markdown)
+	require.Equal(t, []string{
+		"/wp-content/uploads/2024/01/outro.mp3",
+		"/wp-content/uploads/2024/01/intro.mp3",
+	}, links)
+}
+
+func TestUnresolvedPlaylist(t *testing.T) {
+	t.Parallel()
+	markdown, links := getPlaylistMarkdown(t, `<p>[playlist ids="98,99"]</p>`, nil, PageOptions{})
+	require.Equal(t, `\[playlist ids="98,99"\]`, markdown)
+	require.Empty(t, links)
+}
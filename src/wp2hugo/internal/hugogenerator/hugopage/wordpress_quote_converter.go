@@ -0,0 +1,39 @@
+package hugopage
+
+import (
+	"slices"
+	"strings"
+
+	md "github.com/JohannesKaufmann/html-to-markdown"
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Dashes the citations may already start with, e.g. "— Author"
+var _citationDashes = []string{"—", "–", "-"}
+
+// convertQuoteCitations converts the citation of the Gutenberg quote and pullquote blocks,
+// `<blockquote><p>...</p><cite>Author</cite></blockquote>`, into a trailing "— Author" line of the blockquote.
+// The inline citations elsewhere, e.g. the title of a book in a paragraph, are kept as is.
+func convertQuoteCitations() md.Plugin {
+	return func(c *md.Converter) []md.Rule {
+		return []md.Rule{
+			{
+				Filter: []string{"cite"},
+				Replacement: func(content string, selec *goquery.Selection, opt *md.Options) *string {
+					if !selec.Parent().Is("blockquote") {
+						return &content
+					}
+					citation := strings.TrimSpace(content)
+					if citation == "" {
+						return &citation
+					}
+					if !slices.ContainsFunc(_citationDashes, func(dash string) bool { return strings.HasPrefix(citation, dash) }) {
+						citation = "— " + citation
+					}
+					text := "\n\n" + citation + "\n\n"
+					return &text
+				},
+			},
+		}
+	}
+}
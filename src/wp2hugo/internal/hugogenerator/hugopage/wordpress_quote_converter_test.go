@@ -0,0 +1,24 @@
+package hugopage
+
+import "testing"
+
+func TestQuoteWithCitation(t *testing.T) {
+	t.Parallel()
+	testMarkdownExtractor(t,
+		`<!-- wp:quote --><blockquote class="wp-block-quote"><p>Stay <em>hungry</em>.</p><p>Stay foolish.</p><cite>Steve <strong>Jobs</strong></cite></blockquote><!-- /wp:quote -->`,
+		"> Stay _hungry_.\n>\n> Stay foolish.\n>\n> — Steve **Jobs**")
+	testMarkdownExtractor(t,
+		`<!-- wp:pullquote --><figure class="wp-block-pullquote"><blockquote><p>Less is more.</p><cite>— Mies van der Rohe</cite></blockquote></figure><!-- /wp:pullquote -->`,
+		"> Less is more.\n>\n> — Mies van der Rohe")
+}
+
+func TestQuoteWithoutCitation(t *testing.T) {
+	t.Parallel()
+	testMarkdownExtractor(t,
+		`<!-- wp:quote --><blockquote class="wp-block-quote"><p>Stay <em>hungry</em>.</p></blockquote><!-- /wp:quote -->`,
+		"> Stay _hungry_.")
+	// An empty citation of the block, and a title cited in a paragraph
+	testMarkdownExtractor(t,
+		`<blockquote class="wp-block-quote"><p>Call me Ishmael.</p><cite></cite></blockquote><p>From <cite>Moby-Dick</cite>.</p>`,
+		"> Call me Ishmael.\n\nFrom Moby-Dick.")
+}
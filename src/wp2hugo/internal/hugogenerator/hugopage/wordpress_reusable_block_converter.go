@@ -0,0 +1,61 @@
+package hugopage
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Reusable blocks are stored as a separate "wp_block" post and are referenced from the content
+// Example: <!-- wp:block {"ref":123} /-->
+var _ReusableBlockRegEx = regexp.MustCompile(`<!-- wp:block (\{[^}]*\}) /-->`)
+
+// Reusable blocks can reference other reusable blocks, guard against reference cycles
+const _maxReusableBlockDepth = 5
+
+type ReusableBlockProvider interface {
+	// E.g. converts "123" to the raw HTML content of the reusable block with post ID 123
+	GetReusableBlock(blockID string) (string, bool)
+}
+
+// InlineReusableBlocks replaces the references to reusable blocks with their content
+func InlineReusableBlocks(provider ReusableBlockProvider, htmlData string) string {
+	return inlineReusableBlocks(provider, htmlData, 0)
+}
+
+func inlineReusableBlocks(provider ReusableBlockProvider, htmlData string, depth int) string {
+	return replaceAllStringSubmatchFunc(_ReusableBlockRegEx, htmlData, func(groups []string) string {
+		var attrs struct {
+			Ref json.Number `json:"ref"`
+		}
+		if err := json.Unmarshal([]byte(groups[1]), &attrs); err != nil || attrs.Ref == "" {
+			log.Warn().
+				Err(err).
+				Str("block", groups[0]).
+				Msg("Reusable block reference without a valid ref")
+			return groups[0]
+		}
+
+		blockID := attrs.Ref.String()
+		content, ok := provider.GetReusableBlock(blockID)
+		if !ok {
+			log.Warn().
+				Str("blockID", blockID).
+				Msg("Reusable block not found in the export")
+			return newHTMLComment(fmt.Sprintf("missing reusable block %s", blockID))
+		}
+		if depth >= _maxReusableBlockDepth {
+			log.Warn().
+				Str("blockID", blockID).
+				Int("depth", depth).
+				Msg("Reusable blocks are nested too deep, possibly a reference cycle")
+			return newHTMLComment(fmt.Sprintf("reusable block %s nested too deep", blockID))
+		}
+		log.Debug().
+			Str("blockID", blockID).
+			Msg("Inlining reusable block")
+		return inlineReusableBlocks(provider, content, depth+1)
+	})
+}
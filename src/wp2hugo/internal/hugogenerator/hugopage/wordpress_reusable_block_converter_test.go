@@ -0,0 +1,47 @@
+package hugopage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeReusableBlockProvider map[string]string
+
+func (f fakeReusableBlockProvider) GetReusableBlock(blockID string) (string, bool) {
+	content, ok := f[blockID]
+	return content, ok
+}
+
+func TestInlineReusableBlock(t *testing.T) {
+	t.Parallel()
+	provider := fakeReusableBlockProvider{
+		"123": `<!-- wp:paragraph --><p>Subscribe to the newsletter</p><!-- /wp:paragraph -->`,
+	}
+	const htmlData = `<p>Before</p><!-- wp:block {"ref":123} /--><p>After</p>`
+	const expected = `<p>Before</p><!-- wp:paragraph --><p>Subscribe to the newsletter</p><!-- /wp:paragraph --><p>After</p>`
+	require.Equal(t, expected, InlineReusableBlocks(provider, htmlData))
+}
+
+func TestInlineNestedReusableBlock(t *testing.T) {
+	t.Parallel()
+	provider := fakeReusableBlockProvider{
+		"1": `<p>Outer</p><!-- wp:block {"ref":2} /-->`,
+		"2": `<p>Inner</p>`,
+	}
+	require.Equal(t, `<p>Outer</p><p>Inner</p>`, InlineReusableBlocks(provider, `<!-- wp:block {"ref":1} /-->`))
+}
+
+func TestInlineReusableBlockCycle(t *testing.T) {
+	t.Parallel()
+	provider := fakeReusableBlockProvider{
+		"1": `<!-- wp:block {"ref":1} /-->`,
+	}
+	require.Contains(t, InlineReusableBlocks(provider, `<!-- wp:block {"ref":1} /-->`), "nested too deep")
+}
+
+func TestMissingReusableBlockLeavesComment(t *testing.T) {
+	t.Parallel()
+	htmlData := InlineReusableBlocks(fakeReusableBlockProvider{}, `<p>Before</p><!-- wp:block {"ref":404} /--><p>After</p>`)
+	testMarkdownExtractor(t, htmlData, "Before\n\n<!-- wp2hugo: missing reusable block 404 -->\n\nAfter")
+}
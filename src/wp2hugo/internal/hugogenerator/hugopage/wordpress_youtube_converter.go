@@ -3,6 +3,7 @@ package hugopage
 import (
 	"fmt"
 	"regexp"
+	"strings"
 
 	"github.com/rs/zerolog/log"
 )
@@ -21,6 +22,8 @@ var _YoutubeRegEx = regexp.MustCompile(`(?m)(^|\s)http[sav]?://(?:m\.|www\.)?(?:
 var (
 	_YoutubeGutenbergRegEx = regexp.MustCompile(`(?ms)(^|\s)<!-- wp:embed {"url":"[^"]+v=([^"]+)".*?<!-- /wp:embed -->`)
 	_YoutubeEmbedRegEx     = regexp.MustCompile(`(?m)(^|\s)(?:[embed])http[sav]?://(?:m\.|www\.)?(?:youtu\.be|youtube\.com)/(?:watch|w)\?v=([^&\s]+)(?:[/embed])`)
+	_YoutubeShortCodeRegEx = regexp.MustCompile(`(?m)\[youtube (.*?)]`)
+	_EmbedShortCodeRegEx   = regexp.MustCompile(`(?m)\[embed](.*?)\[/embed]`)
 )
 
 func replacePlaintextYoutubeURL(htmlData string) string {
@@ -28,11 +31,15 @@ func replacePlaintextYoutubeURL(htmlData string) string {
 		Msg("Replacing Youtube URLs with embeds")
 
 	// Replace "[embed](.*)[/embed]" with $1, to remove the embed tags and leave the URL for the next regex to process
-	htmlData = regexp.MustCompile(`(?m)\[embed](.*?)\[/embed]`).ReplaceAllString(htmlData, "$1")
+	htmlData = _EmbedShortCodeRegEx.ReplaceAllString(htmlData, "$1")
+	if !strings.Contains(htmlData, "youtu") && !strings.Contains(htmlData, "wp:embed") {
+		// Skip the remaining passes on posts that never mention Youtube
+		return htmlData
+	}
 
 	// Replace [youtube http://www.youtube.com/watch?v=1cNDSPutas8] with just the URL for the next regex to process
 	// Ref: https://github.com/ashishb/wp2hugo/issues/268
-	htmlData = regexp.MustCompile(`(?m)\[youtube (.*?)]`).ReplaceAllString(htmlData, "$1")
+	htmlData = _YoutubeShortCodeRegEx.ReplaceAllString(htmlData, "$1")
 
 	htmlData = replaceAllStringSubmatchFunc(_YoutubeGutenbergRegEx, htmlData, YoutubeReplacementFunction)
 	htmlData = replaceAllStringSubmatchFunc(_YoutubeRegEx, htmlData, YoutubeReplacementFunction)
@@ -0,0 +1,245 @@
+package hugogenerator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/utils"
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/rs/zerolog/log"
+)
+
+// The manifest records the content written by the previous run in the site dir, see Options.Incremental
+const _manifestFileName = ".wp2hugo-manifest.json"
+
+var (
+	errIncrementalRequiresSiteName = errors.New("incremental runs require a site name, to find the site of the previous run")
+	// The content filtered out would be removed from the site, like the content which is not in the export anymore
+	errIncrementalPostIDs = errors.New("incremental runs update the whole site, they can't convert only some post IDs, " +
+		"convert them into a separate site")
+)
+
+type contentManifest struct {
+	// The whole content is rewritten when the options change
+	OptionsHash string                          `json:"optionsHash"`
+	Content     map[string]contentManifestEntry `json:"content"` // Keyed by post ID
+}
+
+type contentManifestEntry struct {
+	Path  string   `json:"path"`            // Relative to the site dir
+	Parts []string `json:"parts,omitempty"` // The following pages of the content split with Options.NextPage, its HTML format and galleries data
+	Hash  string   `json:"hash"`
+
+	// Kept for the content index of the next runs, see Options.Index
+	Words int `json:"words,omitempty"`
+	Media int `json:"media,omitempty"`
+}
+
+// incrementalRun skips the content which did not change since the previous run,
+// and removes the content which is not in the export anymore
+type incrementalRun struct {
+	previous  contentManifest
+	next      contentManifest
+	unchanged map[string]bool
+}
+
+func newIncrementalRun(optionsHash string) *incrementalRun {
+	return &incrementalRun{
+		previous:  contentManifest{Content: make(map[string]contentManifestEntry)},
+		next:      contentManifest{OptionsHash: optionsHash, Content: make(map[string]contentManifestEntry)},
+		unchanged: make(map[string]bool),
+	}
+}
+
+func hashJSON(value any) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		// Only exported data is hashed, it can always be marshalled
+		log.Warn().Err(err).Msg("error hashing the content")
+	}
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+// getOptionsHash hashes everything which changes the generated content besides the content itself
+func (g Generator) getOptionsHash() string {
+	return hashJSON(struct {
+		Options       Options
+		DownloadMedia bool
+	}{g.options, g.downloadMedia})
+}
+
+// getContentHash hashes the source content and metadata of the page, along with the variations of
+// the WooCommerce products, which are emitted in their front matter
+func (g Generator) getContentHash(page wpparser.CommonFields) string {
+	if g.options.WooCommerce && page.PostType != nil && *page.PostType == wpparser.ProductPostType {
+		return hashJSON([]any{page, g.wpInfo.GetProductVariations(page.PostID)})
+	}
+	return hashJSON(page)
+}
+
+func siteExists(siteDir string) bool {
+	return utils.FileExists(path.Join(siteDir, _manifestFileName))
+}
+
+// prepareIncrementalRun reads the manifest of the previous run, and removes the files of the changed and removed content,
+// so that they are written at the same path again
+func (g Generator) prepareIncrementalRun(siteDir string, contents []wpparser.CommonFields) error {
+	run := g.incremental
+	manifestPath := path.Join(siteDir, _manifestFileName)
+	if utils.FileExists(manifestPath) {
+		data, err := os.ReadFile(manifestPath)
+		if err != nil {
+			return fmt.Errorf("error reading manifest: %w", err)
+		}
+		if err := json.Unmarshal(data, &run.previous); err != nil {
+			return fmt.Errorf("error parsing manifest '%s': %w", manifestPath, err)
+		}
+		if run.previous.OptionsHash != run.next.OptionsHash {
+			log.Info().Msg("The options changed since the previous run, rewriting all the content")
+		}
+	}
+
+	current := make(map[string]bool, len(contents))
+	for _, page := range contents {
+		current[page.PostID] = true
+		entry, ok := run.previous.Content[page.PostID]
+		if !ok {
+			continue
+		}
+		if run.previous.OptionsHash == run.next.OptionsHash && entry.Hash == g.getContentHash(page) &&
+			utils.FileExists(path.Join(siteDir, entry.Path)) {
+			run.unchanged[page.PostID] = true
+			continue
+		}
+		if err := removeContentFiles(siteDir, entry); err != nil {
+			return err
+		}
+	}
+	for postID, entry := range run.previous.Content {
+		if current[postID] {
+			continue
+		}
+		log.Info().
+			Str("postID", postID).
+			Str("path", entry.Path).
+			Msg("Removing the content which is not in the export anymore")
+		if err := removeContentFiles(siteDir, entry); err != nil {
+			return err
+		}
+		g.report.RemovedContent++
+	}
+	// Rebuilt from all the content, unchanged or not
+	if err := os.Remove(path.Join(siteDir, "data", "comments.yaml")); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error removing comments: %w", err)
+	}
+	return nil
+}
+
+// getWrittenPath returns the path of the content once written, relative to the site dir:
+// page bundles are switched between _index.md and index.md once their children are written
+func getWrittenPath(siteDir string, relativePath string) string {
+	if utils.FileExists(path.Join(siteDir, relativePath)) {
+		return relativePath
+	}
+	dir, name := path.Split(relativePath)
+	switch {
+	case strings.HasPrefix(name, "_index."):
+		return dir + strings.TrimPrefix(name, "_")
+	case strings.HasPrefix(name, "index."):
+		return dir + "_" + name
+	}
+	return relativePath
+}
+
+// removeContentFiles removes the file of the content, and its other files if any, e.g. its following pages
+func removeContentFiles(siteDir string, entry contentManifestEntry) error {
+	for _, relativePath := range append([]string{entry.Path}, entry.Parts...) {
+		if err := removeContentFile(siteDir, relativePath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeContentFile removes the file, and its dir if empty, e.g. a page bundle
+func removeContentFile(siteDir string, relativePath string) error {
+	filePath := path.Join(siteDir, relativePath)
+	if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error removing '%s': %w", filePath, err)
+	}
+	// Fails if the dir is not empty
+	_ = os.Remove(path.Dir(filePath))
+	return nil
+}
+
+// skipUnchanged reports whether the page did not change since the previous run, its file is kept then
+func (g Generator) skipUnchanged(siteDir string, page wpparser.CommonFields, info wpparser.WebsiteInfo) (bool, error) {
+	if g.incremental == nil || !g.incremental.unchanged[page.PostID] {
+		return false, nil
+	}
+	entry := g.incremental.previous.Content[page.PostID]
+	g.incremental.next.Content[page.PostID] = entry
+	g.addIndexEntry(entry.Path, pageStats{words: entry.Words, media: entry.Media}, page)
+	g.countDraftsDirContent(page)
+	g.report.UnchangedContent++
+	if err := updateComments(siteDir, page, info); err != nil {
+		return true, fmt.Errorf("error saving comments: %w", err)
+	}
+	g.maybeAddNginxRedirect(page)
+	return true, nil
+}
+
+// recordContent records the page written at pagePath, and its following pages written at partPaths,
+// in the manifest and the content index
+func (g Generator) recordContent(siteDir string, pagePath string, partPaths []string, stats pageStats, page wpparser.CommonFields) {
+	relativePath := strings.TrimPrefix(strings.TrimPrefix(pagePath, siteDir), "/")
+	g.addIndexEntry(relativePath, stats, page)
+	g.countDraftsDirContent(page)
+	if g.incremental == nil {
+		return
+	}
+	if _, ok := g.incremental.previous.Content[page.PostID]; ok {
+		g.report.ChangedContent++
+	} else {
+		g.report.AddedContent++
+	}
+	entry := contentManifestEntry{
+		Path:  relativePath,
+		Hash:  g.getContentHash(page),
+		Words: stats.words,
+		Media: stats.media,
+	}
+	for _, partPath := range partPaths {
+		entry.Parts = append(entry.Parts, strings.TrimPrefix(strings.TrimPrefix(partPath, siteDir), "/"))
+	}
+	g.incremental.next.Content[page.PostID] = entry
+}
+
+// finishIncrementalRun writes the manifest for the next run
+func (g Generator) finishIncrementalRun(siteDir string) error {
+	// The unchanged content which was not reached, e.g. if the run was cancelled, is still there
+	for postID := range g.incremental.unchanged {
+		if _, ok := g.incremental.next.Content[postID]; !ok {
+			g.incremental.next.Content[postID] = g.incremental.previous.Content[postID]
+		}
+	}
+	for postID, entry := range g.incremental.next.Content {
+		entry.Path = getWrittenPath(siteDir, entry.Path)
+		for i, part := range entry.Parts {
+			entry.Parts[i] = getWrittenPath(siteDir, part)
+		}
+		g.incremental.next.Content[postID] = entry
+	}
+	data, err := json.MarshalIndent(g.incremental.next, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshalling manifest: %w", err)
+	}
+	return writeFile(path.Join(siteDir, _manifestFileName), data)
+}
@@ -0,0 +1,153 @@
+package hugogenerator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/hugogenerator/hugopage"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIncrementalRun(t *testing.T) {
+	t.Parallel()
+	siteDir := t.TempDir()
+	run := func(fixture integrationFixture) Report {
+		info := parseFixture(t, fixture)
+		generator := NewGenerator(siteDir, "", nil, false, false, false, false, *info, Options{Incremental: true})
+		require.NoError(t, generator.writeContent(context.Background(), siteDir, *info))
+		return generator.Report()
+	}
+
+	report := run(integrationFixture{name: "classic"})
+	require.Equal(t, 4, report.AddedContent)
+	require.Zero(t, report.ChangedContent+report.UnchangedContent+report.RemovedContent)
+	require.FileExists(t, filepath.Join(siteDir, _manifestFileName))
+
+	// Unchanged content is not rewritten
+	unchangedPath := filepath.Join(siteDir, "content", "posts", "a-trip-to-the-mountains.md")
+	unchanged, err := os.ReadFile(unchangedPath)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(unchangedPath, append(unchanged, "Local edit\n"...), 0o600))
+
+	// The draft is revised and the Team page is trashed
+	revised := integrationFixture{name: "classic", replacements: []string{
+		"Draft content.", "Draft content, revised.",
+		"<wp:post_name><![CDATA[team]]></wp:post_name>\n    <wp:status><![CDATA[publish]]></wp:status>",
+		"<wp:post_name><![CDATA[team]]></wp:post_name>\n    <wp:status><![CDATA[trash]]></wp:status>",
+	}}
+	report = run(revised)
+	require.Zero(t, report.AddedContent)
+	require.Equal(t, 1, report.ChangedContent)
+	require.Equal(t, 2, report.UnchangedContent)
+	require.Equal(t, 1, report.RemovedContent)
+
+	content, err := os.ReadFile(unchangedPath)
+	require.NoError(t, err)
+	require.True(t, strings.HasSuffix(string(content), "Local edit\n"))
+	content, err = os.ReadFile(filepath.Join(siteDir, "content", "posts", "unfinished-thoughts.md"))
+	require.NoError(t, err)
+	require.Contains(t, string(content), "Draft content, revised.")
+	require.NoFileExists(t, filepath.Join(siteDir, "content", "pages", "about", "team.md"))
+	// The About page is now a leaf bundle
+	require.FileExists(t, filepath.Join(siteDir, "content", "pages", "about", "index.md"))
+	// Rewritten at the same path, without a deduplication suffix
+	matches, err := filepath.Glob(filepath.Join(siteDir, "content", "posts", "*.md"))
+	require.NoError(t, err)
+	require.Len(t, matches, 2)
+	// The comments are not duplicated
+	comments, err := os.ReadFile(filepath.Join(siteDir, "data", "comments.yaml"))
+	require.NoError(t, err)
+	require.Equal(t, 1, strings.Count(string(comments), "Lovely pictures!"))
+
+	report = run(revised)
+	require.Equal(t, 3, report.UnchangedContent)
+	require.Zero(t, report.AddedContent+report.ChangedContent+report.RemovedContent)
+}
+
+func TestIncrementalRunWithChangedOptions(t *testing.T) {
+	t.Parallel()
+	info := parseFixture(t, integrationFixture{name: "classic"})
+	siteDir := t.TempDir()
+	for _, options := range []Options{{Incremental: true}, {Incremental: true, EmitCommentStatus: true}} {
+		generator := NewGenerator(siteDir, "", nil, false, false, false, false, *info, options)
+		require.NoError(t, generator.writeContent(context.Background(), siteDir, *info))
+	}
+	content, err := os.ReadFile(filepath.Join(siteDir, "content", "posts", "a-trip-to-the-mountains.md"))
+	require.NoError(t, err)
+	require.Contains(t, string(content), "\ncomments: true\n")
+}
+
+func TestIncrementalRunRequiresSiteName(t *testing.T) {
+	t.Parallel()
+	info := parseFixture(t, integrationFixture{name: "classic"})
+	generator := NewGenerator(t.TempDir(), "", nil, false, false, false, false, *info, Options{Incremental: true})
+	require.ErrorIs(t, generator.Generate(context.Background()), errIncrementalRequiresSiteName)
+}
+
+func TestIncrementalRunRejectsPostIDs(t *testing.T) {
+	t.Parallel()
+	info := parseFixture(t, integrationFixture{name: "classic"}).FilterPostIDs([]string{"10"}, nil)
+	generator := NewGenerator(t.TempDir(), "", nil, false, false, false, false, *info,
+		Options{Incremental: true, SiteName: "site"})
+	require.ErrorIs(t, generator.Generate(context.Background()), errIncrementalPostIDs)
+}
+
+func TestCancelledIncrementalRun(t *testing.T) {
+	t.Parallel()
+	revised := integrationFixture{name: "classic", replacements: []string{"Draft content.", "Draft content, revised."}}
+	siteDir := t.TempDir()
+	run := func(ctx context.Context, fixture integrationFixture) (Report, error) {
+		info := parseFixture(t, fixture)
+		generator := NewGenerator(siteDir, "", nil, false, false, false, false, *info, Options{Incremental: true})
+		err := generator.writeContent(ctx, siteDir, *info)
+		return generator.Report(), err
+	}
+
+	_, err := run(context.Background(), integrationFixture{name: "classic"})
+	require.NoError(t, err)
+
+	// Cancelled before writing anything, the manifest still lists the unchanged content
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	report, err := run(ctx, revised)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Zero(t, report.AddedContent+report.ChangedContent+report.UnchangedContent)
+
+	// The next run resumes with the revised draft only
+	report, err = run(context.Background(), revised)
+	require.NoError(t, err)
+	require.Equal(t, 1, report.AddedContent)
+	require.Equal(t, 3, report.UnchangedContent)
+	content, err := os.ReadFile(filepath.Join(siteDir, "content", "posts", "unfinished-thoughts.md"))
+	require.NoError(t, err)
+	require.Contains(t, string(content), "Draft content, revised.")
+}
+
+func TestIncrementalGalleriesData(t *testing.T) {
+	t.Parallel()
+	siteDir := t.TempDir()
+	run := func(fixture integrationFixture) {
+		info := parseFixture(t, fixture)
+		generator := NewGenerator(siteDir, "", nil, false, false, false, false, *info,
+			Options{Incremental: true, PageOptions: hugopage.PageOptions{GalleryOutput: hugopage.GalleryOutputData}})
+		require.NoError(t, generator.writeContent(context.Background(), siteDir, *info))
+	}
+
+	run(integrationFixture{name: "classic"})
+	dataPath := filepath.Join(siteDir, "data", "galleries", "10.yaml")
+	require.FileExists(t, dataPath)
+	manifest, err := os.ReadFile(filepath.Join(siteDir, _manifestFileName))
+	require.NoError(t, err)
+	require.Contains(t, string(manifest), `"data/galleries/10.yaml"`)
+
+	// The galleries data is removed with the trashed post
+	run(integrationFixture{name: "classic", replacements: []string{
+		"<wp:post_name><![CDATA[a-trip-to-the-mountains]]></wp:post_name>\n    <wp:status><![CDATA[publish]]></wp:status>",
+		"<wp:post_name><![CDATA[a-trip-to-the-mountains]]></wp:post_name>\n    <wp:status><![CDATA[trash]]></wp:status>",
+	}})
+	require.NoFileExists(t, filepath.Join(siteDir, "content", "posts", "a-trip-to-the-mountains.md"))
+	require.NoFileExists(t, dataPath)
+}
@@ -0,0 +1,234 @@
+package hugogenerator
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+// Run `go test ./internal/hugogenerator/ -run TestIntegration -update` to regenerate the golden files
+var _updateGoldens = flag.Bool("update", false, "regenerate the golden files of the integration tests")
+
+const _integrationTestdataDir = "testdata/integration"
+
+// Each fixture is an anonymized WordPress export in testdata/integration/<name>.xml
+// and the expected generated tree is in testdata/integration/<name>/
+type integrationFixture struct {
+	name            string
+	customPostTypes []string
+	options         Options
+	// replacements patch the export before it is parsed, as pairs of old and new strings each replaced once,
+	// e.g. to revise a post or to add an author to the fixture
+	replacements []string
+}
+
+var _integrationFixtures = []integrationFixture{
+	{name: "classic"},
+	{name: "gutenberg"},
+	{name: "multilingual"},
+	{name: "messy_html"},
+	{name: "minimal"},
+	{name: "custom_post_types", customPostTypes: []string{"recipe", "avada_portfolio", "product"}},
+	{name: "woocommerce", customPostTypes: []string{"product", "product_variation"}, options: Options{WooCommerce: true}},
+	// The media stay on the WordPress site, under the subdirectory of the blog
+	{name: "subdirectory", options: Options{NoMedia: true}},
+}
+
+func TestIntegrationFixtures(t *testing.T) {
+	t.Parallel()
+	for _, fixture := range _integrationFixtures {
+		t.Run(fixture.name, func(t *testing.T) {
+			t.Parallel()
+			siteDir := generateFixtureSite(t, fixture, fixture.options)
+			goldenDir := filepath.Join(_integrationTestdataDir, fixture.name)
+			if *_updateGoldens {
+				updateGoldenFiles(t, siteDir, goldenDir)
+				return
+			}
+			compareWithGoldenFiles(t, siteDir, goldenDir)
+		})
+	}
+}
+
+func TestIntegrationIsDeterministic(t *testing.T) {
+	t.Parallel()
+	for _, fixture := range _integrationFixtures {
+		t.Run(fixture.name, func(t *testing.T) {
+			t.Parallel()
+			first := readTree(t, generateReproducibleSite(t, fixture))
+			second := readTree(t, generateReproducibleSite(t, fixture))
+			require.Equal(t, first, second)
+		})
+	}
+}
+
+// generateReproducibleSite writes the content, the data files and the Nginx config of the fixture
+func generateReproducibleSite(t *testing.T, fixture integrationFixture) string {
+	t.Helper()
+	websiteInfo := parseFixture(t, fixture)
+	siteDir := t.TempDir()
+	generator := NewGenerator(siteDir, "", nil, false, false, false, true, *websiteInfo, fixture.options)
+	require.NoError(t, generator.writeContent(context.Background(), siteDir, *websiteInfo))
+	require.NoError(t, setupLibraryData(siteDir, *websiteInfo))
+	require.NoError(t, setupAuthorsData(siteDir, *websiteInfo, nil, false))
+	require.NoError(t, os.WriteFile(filepath.Join(siteDir, "nginx.conf"), []byte(generator.ngnixConfig.Generate()), 0o600))
+	return siteDir
+}
+
+// generateFixtureSite writes the content of the fixture into a temporary site dir.
+// The Hugo site skeleton is not created, since that requires hugo and git.
+func generateFixtureSite(t *testing.T, fixture integrationFixture, options Options) string {
+	t.Helper()
+	websiteInfo := parseFixture(t, fixture)
+	siteDir := t.TempDir()
+	generator := NewGenerator(siteDir, "", nil, false, false, false, false, *websiteInfo, options)
+	require.NoError(t, generator.writeContent(context.Background(), siteDir, *websiteInfo))
+	return siteDir
+}
+
+func parseFixture(t *testing.T, fixture integrationFixture) *wpparser.WebsiteInfo {
+	t.Helper()
+	websiteInfo, err := wpparser.NewParser().Parse(strings.NewReader(readFixture(t, fixture)), nil, fixture.customPostTypes)
+	require.NoError(t, err)
+	return websiteInfo
+}
+
+// readFixture returns the export of the fixture, patched with its replacements
+func readFixture(t testing.TB, fixture integrationFixture) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(_integrationTestdataDir, fixture.name+".xml"))
+	require.NoError(t, err)
+	require.Zero(t, len(fixture.replacements)%2, "the replacements are pairs of old and new strings")
+	export := string(data)
+	for i := 0; i < len(fixture.replacements); i += 2 {
+		require.Contains(t, export, fixture.replacements[i], "the replacement does not match the %s fixture", fixture.name)
+		export = strings.Replace(export, fixture.replacements[i], fixture.replacements[i+1], 1)
+	}
+	return export
+}
+
+func readTree(t *testing.T, rootDir string) map[string]string {
+	t.Helper()
+	files := make(map[string]string)
+	err := filepath.WalkDir(rootDir, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(filePath)
+		if err != nil {
+			return err
+		}
+		relativePath, err := filepath.Rel(rootDir, filePath)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(relativePath)] = string(data)
+		return nil
+	})
+	require.NoError(t, err)
+	return files
+}
+
+func updateGoldenFiles(t *testing.T, siteDir string, goldenDir string) {
+	t.Helper()
+	require.NoError(t, os.RemoveAll(goldenDir))
+	for relativePath, content := range readTree(t, siteDir) {
+		goldenPath := filepath.Join(goldenDir, filepath.FromSlash(relativePath))
+		require.NoError(t, os.MkdirAll(filepath.Dir(goldenPath), 0o755))
+		require.NoError(t, os.WriteFile(goldenPath, []byte(content), 0o644))
+	}
+}
+
+func compareWithGoldenFiles(t *testing.T, siteDir string, goldenDir string) {
+	t.Helper()
+	generated := readTree(t, siteDir)
+	expected := readTree(t, goldenDir)
+
+	expectedPaths := make([]string, 0, len(expected))
+	for relativePath := range expected {
+		expectedPaths = append(expectedPaths, relativePath)
+	}
+	generatedPaths := make([]string, 0, len(generated))
+	for relativePath := range generated {
+		generatedPaths = append(generatedPaths, relativePath)
+	}
+	require.ElementsMatch(t, expectedPaths, generatedPaths, "generated files differ from %s, run with -update to regenerate", goldenDir)
+	for relativePath, content := range expected {
+		require.Equal(t, content, generated[relativePath], "generated %s differs from the golden file", relativePath)
+	}
+}
+
+// BenchmarkConversion parses and converts the fixtures, and larger exports made of copies of the classic fixture
+func BenchmarkConversion(b *testing.B) {
+	// The logs would dominate the timings
+	logLevel := zerolog.GlobalLevel()
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	b.Cleanup(func() {
+		zerolog.SetGlobalLevel(logLevel)
+	})
+	type benchmarkExport struct {
+		fixture integrationFixture
+		export  string
+	}
+	exports := make([]benchmarkExport, 0, len(_integrationFixtures)+2)
+	for _, fixture := range _integrationFixtures {
+		exports = append(exports, benchmarkExport{fixture, readFixture(b, fixture)})
+	}
+	for _, copies := range []int{10, 100} {
+		fixture := integrationFixture{name: fmt.Sprintf("classic_x%d", copies)}
+		exports = append(exports, benchmarkExport{fixture, replicateItems(b, exports[0].export, copies)})
+	}
+
+	for _, export := range exports {
+		b.Run(export.fixture.name, func(b *testing.B) {
+			b.SetBytes(int64(len(export.export)))
+			for b.Loop() {
+				info, err := wpparser.NewParser().Parse(strings.NewReader(export.export), nil, export.fixture.customPostTypes)
+				require.NoError(b, err)
+				siteDir := b.TempDir()
+				generator := NewGenerator(siteDir, "", nil, false, false, false, false, *info, export.fixture.options)
+				require.NoError(b, generator.writeContent(context.Background(), siteDir, *info))
+			}
+		})
+	}
+}
+
+var (
+	_postIDRegEx   = regexp.MustCompile(`<wp:post_id>(\d+)</wp:post_id>`)
+	_postNameRegEx = regexp.MustCompile(`<wp:post_name><!\[CDATA\[([^\]]*)\]\]></wp:post_name>`)
+)
+
+// replicateItems returns the export with its items repeated, each copy with other post IDs and slugs
+func replicateItems(b *testing.B, export string, copies int) string {
+	b.Helper()
+	start, end := strings.Index(export, "<item>"), strings.LastIndex(export, "</item>")
+	require.True(b, start >= 0 && end > start)
+	end += len("</item>")
+	items := export[start:end]
+
+	var sb strings.Builder
+	sb.WriteString(export[:end])
+	for i := 1; i < copies; i++ {
+		items := _postIDRegEx.ReplaceAllStringFunc(items, func(match string) string {
+			id, err := strconv.Atoi(_postIDRegEx.FindStringSubmatch(match)[1])
+			require.NoError(b, err)
+			return fmt.Sprintf("<wp:post_id>%d</wp:post_id>", id+i*10_000)
+		})
+		items = _postNameRegEx.ReplaceAllString(items, fmt.Sprintf("<wp:post_name><![CDATA[${1}-%d]]></wp:post_name>", i))
+		sb.WriteString("\n")
+		sb.WriteString(items)
+	}
+	sb.WriteString(export[end:])
+	return sb.String()
+}
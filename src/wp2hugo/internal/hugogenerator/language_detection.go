@@ -0,0 +1,67 @@
+package hugogenerator
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/abadojack/whatlanggo"
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/rs/zerolog/log"
+)
+
+// The detection is only trusted with enough text, the trigrams of a short text match several languages
+const _minLanguageDetectionChars = 200
+
+// Confidence of the detection, from 0 to 1, below which the language is not emitted
+const _minLanguageDetectionConfidence = 0.9
+
+// The code, the embeds and the shortcodes are not in the language of the text
+var (
+	_languageDetectionSkippedRegEx   = regexp.MustCompile(`(?is)<(pre|code|script|style)\b.*?</(pre|code|script|style)>`)
+	_languageDetectionShortcodeRegEx = regexp.MustCompile(`\[/?[a-zA-Z][^\]]*\]`)
+)
+
+// DetectedLanguage is a language detected from the text of the content, see Options.DetectLanguages
+type DetectedLanguage struct {
+	Link       string
+	Language   string
+	Confidence float64
+}
+
+// detectLanguage returns the language detected from the title and the text of the content, e.g. "fr", when it differs
+// from the language of the site, and records it in the Report. It returns "" when the language of the content is set,
+// e.g. by Polylang or WPML, when the text is too short, or the detection is not confident enough.
+func (g Generator) detectLanguage(page wpparser.CommonFields) string {
+	if !g.options.DetectLanguages || page.GetFileInfo().Language() != nil {
+		return ""
+	}
+	text := _languageDetectionSkippedRegEx.ReplaceAllString(page.Content, " ")
+	text = _languageDetectionShortcodeRegEx.ReplaceAllString(text, " ")
+	text = page.Title + "\n" + html.UnescapeString(_tagRegEx.ReplaceAllString(text, " "))
+	text = strings.Join(strings.Fields(text), " ")
+	if utf8.RuneCountInString(text) < _minLanguageDetectionChars {
+		return ""
+	}
+	info := whatlanggo.Detect(text)
+	language := info.Lang.Iso6391()
+	if language == "" || info.Confidence < _minLanguageDetectionConfidence {
+		log.Debug().
+			Str("link", page.Link).
+			Str("language", info.Lang.String()).
+			Float64("confidence", info.Confidence).
+			Msg("Language detection not confident enough, skipped")
+		return ""
+	}
+	siteLanguage, _, _ := strings.Cut(strings.ReplaceAll(strings.ToLower(g.wpInfo.Language()), "_", "-"), "-")
+	if language == siteLanguage {
+		return ""
+	}
+	g.report.DetectedLanguages = append(g.report.DetectedLanguages, DetectedLanguage{
+		Link:       page.Link,
+		Language:   language,
+		Confidence: info.Confidence,
+	})
+	return language
+}
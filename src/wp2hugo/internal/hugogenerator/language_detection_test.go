@@ -0,0 +1,46 @@
+package hugogenerator
+
+import (
+	"testing"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	t.Parallel()
+	websiteInfo := parseFixture(t, integrationFixture{name: "classic"})
+	generator := NewGenerator(t.TempDir(), "", nil, false, false, false, false, *websiteInfo,
+		Options{DetectLanguages: true})
+
+	french := wpparser.CommonFields{
+		Link:  "https://example.com/2024/03/une-randonnee/",
+		Title: "Une randonnée dans les montagnes",
+		Content: `<p>Nous sommes partis très tôt le matin pour profiter de la fraîcheur. Le sentier montait doucement ` +
+			`à travers la forêt, puis les arbres ont laissé la place aux prairies et aux rochers.</p>` +
+			`<pre><code>git clone https://example.com/the/repository.git</code></pre>` +
+			`<p>Arrivés au refuge, nous avons mangé une soupe chaude en regardant le coucher du soleil sur les sommets.</p>`,
+	}
+	require.Equal(t, "fr", generator.detectLanguage(french))
+	require.Len(t, generator.report.DetectedLanguages, 1)
+	require.Equal(t, french.Link, generator.report.DetectedLanguages[0].Link)
+
+	english := french
+	english.Content = `<p>We left very early in the morning to enjoy the cool air. The trail climbed gently through ` +
+		`the forest, then the trees gave way to meadows and rocks. At the hut, we had a hot soup while watching the ` +
+		`sun set over the summits.</p>`
+	english.Title = "A hike in the mountains"
+	require.Empty(t, generator.detectLanguage(english), "the language of the site")
+
+	short := french
+	short.Content = "<p>Une belle journée.</p>"
+	require.Empty(t, generator.detectLanguage(short), "too short to be confident")
+
+	translated := french
+	translated.Link = "https://example.com/2024/03/une-randonnee/?lang=fr"
+	require.Empty(t, generator.detectLanguage(translated), "language set by the multilingual plugin")
+	require.Len(t, generator.report.DetectedLanguages, 1)
+
+	generator.options.DetectLanguages = false
+	require.Empty(t, generator.detectLanguage(french))
+}
@@ -0,0 +1,56 @@
+package hugogenerator
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/hugogenerator/hugopage"
+)
+
+// AssetReferenceStyle decides how the content references the images downloaded into Options.AssetsDir
+type AssetReferenceStyle string
+
+const (
+	// AssetReferencesPath keeps the "/wp-content/..." links, resolved with resources.Get
+	// by Hugo's embedded image render hook, which is enabled in the config
+	AssetReferencesPath AssetReferenceStyle = "path"
+	// AssetReferencesShortcode replaces the images and figures with the resource shortcode
+	AssetReferencesShortcode AssetReferenceStyle = "shortcode"
+)
+
+const _staticDir = "static"
+
+// Images which can be processed by Hugo's asset pipeline
+var _assetImageExtensions = []string{".jpg", ".jpeg", ".png", ".gif", ".webp", ".avif", ".bmp", ".tif", ".tiff", ".svg"}
+
+func ParseAssetReferenceStyle(style string) (AssetReferenceStyle, error) {
+	switch AssetReferenceStyle(style) {
+	case AssetReferencesPath, AssetReferencesShortcode:
+		return AssetReferenceStyle(style), nil
+	case "":
+		return AssetReferencesPath, nil
+	default:
+		return "", fmt.Errorf("unknown asset reference style %q, expected one of %s, %s",
+			style, AssetReferencesPath, AssetReferencesShortcode)
+	}
+}
+
+// getImageMediaDir returns the dir of the site where an image of the content is downloaded
+func (g Generator) getImageMediaDir(link string) string {
+	if g.options.AssetsDir == "" || !isAssetImage(link) {
+		return _staticDir
+	}
+	return g.options.AssetsDir
+}
+
+func isAssetImage(link string) bool {
+	if hugopage.IsDataURI(link) {
+		mimeType, _, _ := strings.Cut(strings.TrimPrefix(link, _dataURIPrefix), ";")
+		_, ok := _dataURIImageExtensions[strings.ToLower(mimeType)]
+		return ok
+	}
+	link, _, _ = strings.Cut(link, "?")
+	return slices.Contains(_assetImageExtensions, strings.ToLower(path.Ext(link)))
+}
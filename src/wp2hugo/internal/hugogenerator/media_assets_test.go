@@ -0,0 +1,33 @@
+package hugogenerator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAssetReferenceStyle(t *testing.T) {
+	t.Parallel()
+	style, err := ParseAssetReferenceStyle("")
+	require.NoError(t, err)
+	require.Equal(t, AssetReferencesPath, style)
+
+	style, err = ParseAssetReferenceStyle("shortcode")
+	require.NoError(t, err)
+	require.Equal(t, AssetReferencesShortcode, style)
+
+	_, err = ParseAssetReferenceStyle("bundle")
+	require.Error(t, err)
+}
+
+func TestGetImageMediaDir(t *testing.T) {
+	t.Parallel()
+	g := Generator{}
+	require.Equal(t, "static", g.getImageMediaDir("/wp-content/uploads/a.jpg"))
+
+	g.options.AssetsDir = "assets"
+	require.Equal(t, "assets", g.getImageMediaDir("/wp-content/uploads/a.JPG"))
+	require.Equal(t, "assets", g.getImageMediaDir("/wp-content/uploads/a.png?resize=300"))
+	require.Equal(t, "assets", g.getImageMediaDir("data:image/png;base64,iVBORw0KGgo="))
+	require.Equal(t, "static", g.getImageMediaDir("/wp-content/uploads/a.mp4"))
+}
@@ -0,0 +1,86 @@
+package hugogenerator
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	_dataURIPrefix = "data:"
+	// Externalized inline images are written under this path, relative to the site's static dir
+	_inlineImagesDir = "/wp-content/uploads/wp2hugo-inline"
+)
+
+// Extension to use for the base64-embedded image mimetypes that we externalize
+var _dataURIImageExtensions = map[string]string{
+	"image/png":     "png",
+	"image/jpeg":    "jpg",
+	"image/jpg":     "jpg",
+	"image/gif":     "gif",
+	"image/webp":    "webp",
+	"image/svg+xml": "svg",
+	"image/bmp":     "bmp",
+	"image/avif":    "avif",
+}
+
+// externalizeDataURIImage decodes a base64 `data:image/...` URI, writes it as a real file
+// in the media dir of the site, usually the static dir, and returns the replacement for the link.
+// The filename is derived from the content hash, so the same image embedded in several
+// posts results in a single file.
+func externalizeDataURIImage(outputMediaDirPath string, mediaDir string, link string) (map[string]string, error) {
+	mimeType, data, err := decodeDataURI(link)
+	if err != nil {
+		return nil, err
+	}
+	extension, ok := _dataURIImageExtensions[mimeType]
+	if !ok {
+		return nil, fmt.Errorf("unsupported inline image type: %s", mimeType)
+	}
+
+	hash := sha256.Sum256(data)
+	relativeLink := fmt.Sprintf("%s/%s.%s", _inlineImagesDir, hex.EncodeToString(hash[:])[:16], extension)
+	outputFilePath := path.Join(outputMediaDirPath, mediaDir, relativeLink)
+	if _, err := download(outputFilePath, bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+
+	log.Debug().
+		Str("mimeType", mimeType).
+		Int("size", len(data)).
+		Str("path", relativeLink).
+		Msg("Externalized base64-embedded image")
+	return map[string]string{link: relativeLink}, nil
+}
+
+// decodeDataURI returns the mimetype and the decoded content of a base64 data URI
+// like `data:image/png;base64,iVBORw0KGgo...`
+func decodeDataURI(link string) (string, []byte, error) {
+	header, payload, found := strings.Cut(strings.TrimPrefix(link, _dataURIPrefix), ",")
+	if !found {
+		return "", nil, fmt.Errorf("malformed data URI: missing ','")
+	}
+	params := strings.Split(header, ";")
+	if params[len(params)-1] != "base64" {
+		return "", nil, fmt.Errorf("data URI is not base64-encoded")
+	}
+	mimeType := strings.ToLower(params[0])
+
+	// Markdown and HTML sources may wrap the payload or percent-encode the padding
+	payload = strings.Join(strings.Fields(payload), "")
+	payload = strings.ReplaceAll(payload, "%3D", "=")
+	data, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		data, err = base64.RawStdEncoding.DecodeString(strings.TrimRight(payload, "="))
+		if err != nil {
+			return "", nil, fmt.Errorf("error decoding data URI: %w", err)
+		}
+	}
+	return mimeType, data, nil
+}
@@ -0,0 +1,43 @@
+package hugogenerator
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// 1x1 transparent PNG
+const _onePixelPNG = "iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAQAAAC1HAwCAAAAC0lEQVR42mNkYAAAAAYAAjCB0C8AAAAASUVORK5CYII="
+
+func TestExternalizeDataURIImage(t *testing.T) {
+	t.Parallel()
+	outputDir := t.TempDir()
+	link := "data:image/png;base64," + _onePixelPNG
+
+	replacements, err := externalizeDataURIImage(outputDir, _staticDir, link)
+	require.NoError(t, err)
+	require.Len(t, replacements, 1)
+	relativeLink := replacements[link]
+	require.Regexp(t, `^/wp-content/uploads/wp2hugo-inline/[0-9a-f]{16}\.png$`, relativeLink)
+
+	data, err := os.ReadFile(path.Join(outputDir, "static", relativeLink))
+	require.NoError(t, err)
+	require.Equal(t, []byte("\x89PNG"), data[:4])
+
+	// Same content, same file name
+	replacements2, err := externalizeDataURIImage(outputDir, _staticDir, link)
+	require.NoError(t, err)
+	require.Equal(t, relativeLink, replacements2[link])
+}
+
+func TestExternalizeDataURIImageErrors(t *testing.T) {
+	t.Parallel()
+	_, err := externalizeDataURIImage(t.TempDir(), _staticDir, "data:image/png,not-base64")
+	require.Error(t, err)
+	_, err = externalizeDataURIImage(t.TempDir(), _staticDir, "data:text/plain;base64,aGVsbG8=")
+	require.Error(t, err)
+	_, err = externalizeDataURIImage(t.TempDir(), _staticDir, "data:image/png;base64,!!!")
+	require.Error(t, err)
+}
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"io"
 	"net/url"
-	"os"
 	"path"
 	"regexp"
 
@@ -14,46 +13,57 @@ import (
 
 func writeFavicon(outputDirPath string, faviconData io.Reader) error {
 	log.Debug().Msg("Writing favicon")
-	return download(path.Join(outputDirPath, "favicon.ico"), faviconData)
+	_, err := download(path.Join(outputDirPath, "favicon.ico"), faviconData)
+	return err
 }
 
 // Match %dd
 var _hexPattern = regexp.MustCompile(`%[0-9a-fA-F]{2}`)
 
-func download(destFilePath string, reader io.Reader) error {
+// download writes the reader into destFilePath, and returns the number of bytes written
+func download(destFilePath string, reader io.Reader) (int64, error) {
 	log.Debug().
 		Str("destFilePath", destFilePath).
 		Msg("Downloading from URL")
 	if err := utils.CreateDirIfNotExist(path.Dir(destFilePath)); err != nil {
-		return err
+		return 0, err
 	}
-	fileName := path.Base(destFilePath)
-	if _hexPattern.MatchString(fileName) {
-		tmp1, err := url.PathUnescape(fileName)
-		if err != nil {
-			return fmt.Errorf("error unescaping filename %s: %w", fileName, err)
-		}
-		log.Info().
-			Str("fileName", fileName).
-			Str("newFileName", tmp1).
-			Msg("Unescaped filename")
-		fileName = tmp1
-		destFilePath = path.Join(path.Dir(destFilePath), fileName)
+	destFilePath, err := getDownloadFilePath(destFilePath)
+	if err != nil {
+		return 0, err
 	}
 
-	file, err := os.OpenFile(destFilePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	file, err := _siteFiles.Create(destFilePath)
 	if err != nil {
-		return fmt.Errorf("error opening file %s: %w", destFilePath, err)
+		return 0, fmt.Errorf("error opening file %s: %w", destFilePath, err)
 	}
 
-	_, err = io.Copy(file, reader)
+	written, err := io.Copy(file, reader)
 	if err != nil {
-		return fmt.Errorf("error writing to file %s: %w", destFilePath, err)
+		return written, fmt.Errorf("error writing to file %s: %w", destFilePath, err)
 	}
 
 	if err := file.Close(); err != nil {
-		return fmt.Errorf("error closing file %s: %w", destFilePath, err)
+		return written, fmt.Errorf("error closing file %s: %w", destFilePath, err)
 	}
 
-	return err
+	return written, nil
+}
+
+// getDownloadFilePath returns the path the file is actually written to by download,
+// with the percent-encoded characters of the filename unescaped
+func getDownloadFilePath(destFilePath string) (string, error) {
+	fileName := path.Base(destFilePath)
+	if !_hexPattern.MatchString(fileName) {
+		return destFilePath, nil
+	}
+	tmp1, err := url.PathUnescape(fileName)
+	if err != nil {
+		return "", fmt.Errorf("error unescaping filename %s: %w", fileName, err)
+	}
+	log.Info().
+		Str("fileName", fileName).
+		Str("newFileName", tmp1).
+		Msg("Unescaped filename")
+	return path.Join(path.Dir(destFilePath), tmp1), nil
 }
@@ -0,0 +1,109 @@
+package hugogenerator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/hugogenerator/hugopage"
+	"github.com/rs/zerolog/log"
+)
+
+// SVGImagePolicy decides what becomes of the SVG images of the content, when downloading the media
+type SVGImagePolicy string
+
+const (
+	// SVGImagesDownload downloads them as files, like the other images
+	SVGImagesDownload SVGImagePolicy = "download"
+	// SVGImagesInline inlines the SVG images up to Options.SVGInlineMaxBytes into the content, e.g. the icons,
+	// once sanitized. The larger ones are downloaded.
+	SVGImagesInline SVGImagePolicy = "inline"
+)
+
+// DefaultSVGInlineMaxBytes is the size of the largest SVG images inlined, big enough for most icons
+const DefaultSVGInlineMaxBytes = 4096
+
+var errSVGInlineWithoutDownloads = errors.New("SVG images are inlined when downloading the media, enable the media downloads")
+
+func ParseSVGImagePolicy(policy string) (SVGImagePolicy, error) {
+	switch SVGImagePolicy(policy) {
+	case SVGImagesDownload, SVGImagesInline:
+		return SVGImagePolicy(policy), nil
+	case "":
+		return SVGImagesDownload, nil
+	default:
+		return "", fmt.Errorf("unknown SVG image policy %q, expected one of %s, %s",
+			policy, SVGImagesDownload, SVGImagesInline)
+	}
+}
+
+func (g Generator) validateSVGImages() error {
+	if g.options.SVGImages != SVGImagesInline {
+		return nil
+	}
+	if !g.downloadMedia {
+		return errSVGInlineWithoutDownloads
+	}
+	if g.options.SVGInlineMaxBytes <= 0 {
+		return fmt.Errorf("SVG inline max size must be positive, got %d", g.options.SVGInlineMaxBytes)
+	}
+	return nil
+}
+
+func isSVGLink(link string) bool {
+	return strings.EqualFold(path.Ext(strings.Split(link, "?")[0]), ".svg")
+}
+
+// inlineSVGImage inlines the SVG image of the content if it is small enough, see Options.SVGImages.
+// It returns false if the image still has to be downloaded: too large, not found, or referenced by a figure.
+func (g Generator) inlineSVGImage(ctx context.Context, p *hugopage.Page, link string, prefixes []string,
+	pageURL *url.URL,
+) (bool, error) {
+	// Resolved like in downloadMedia, which reports the media which are not found
+	mediaLink := link
+	if strings.HasPrefix(mediaLink, "//") {
+		mediaLink = pageURL.Scheme + ":" + mediaLink
+	}
+	for _, prefix := range prefixes {
+		mediaLink = strings.TrimPrefix(mediaLink, prefix)
+	}
+	if !strings.HasPrefix(mediaLink, "/") {
+		return false, nil
+	}
+	media, err := g.mediaProvider.GetReader(ctx, g.wpInfo.Link().Scheme+"://"+g.wpInfo.Link().Host+mediaLink)
+	if err != nil {
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		return false, nil
+	}
+	data, err := io.ReadAll(io.LimitReader(media, g.options.SVGInlineMaxBytes+1))
+	if err != nil {
+		return false, nil
+	}
+	if int64(len(data)) > g.options.SVGInlineMaxBytes {
+		log.Debug().
+			Str("link", link).
+			Int64("maxBytes", g.options.SVGInlineMaxBytes).
+			Msg("SVG image too large to inline, downloading it")
+		return false, nil
+	}
+	stillReferenced, err := p.InlineSVGImage(link, string(data))
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Str("link", link).
+			Str("pageLink", pageURL.String()).
+			Msg("Invalid SVG image, downloading it")
+		return false, nil
+	}
+	log.Debug().
+		Str("link", link).
+		Str("pageLink", pageURL.String()).
+		Msg("SVG image inlined")
+	return !stillReferenced, nil
+}
@@ -0,0 +1,89 @@
+package hugogenerator
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type mapMediaProvider map[string]string
+
+func (m mapMediaProvider) GetReader(_ context.Context, url string) (io.Reader, error) {
+	if content, ok := m[url]; ok {
+		return strings.NewReader(content), nil
+	}
+	return nil, errors.New("404 Not Found: " + url)
+}
+
+func TestSVGImages(t *testing.T) {
+	t.Parallel()
+	const (
+		iconLink         = "/wp-content/uploads/2024/03/check.svg"
+		illustrationLink = "/wp-content/uploads/2024/03/mountains.svg"
+	)
+	icon := `<?xml version="1.0" encoding="UTF-8"?>
+<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 24 24" onload="alert(1)">
+  <script>alert(2)</script>
+  <path d="M9 16.2 4.8 12l-1.4 1.4L9 19 21 7l-1.4-1.4z"/>
+</svg>
+`
+	illustration := `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 640 360">` +
+		strings.Repeat(`<path d="M0 360 L320 40 L640 360 Z" fill="#6b7280"/>`, 200) + `</svg>`
+	mediaProvider := mapMediaProvider{
+		"https://example.org" + iconLink:         icon,
+		"https://example.org" + illustrationLink: illustration,
+	}
+
+	fixture := integrationFixture{name: "classic", replacements: []string{
+		"<p>This is a <em>classic editor</em> page.</p>",
+		`<p><img src="https://example.org` + iconLink + `" alt="Done"> This is a <em>classic editor</em> page.</p>
+<p><img src="` + illustrationLink + `" alt="Mountains"></p>`,
+	}}
+
+	generate := func(policy SVGImagePolicy) (string, string) {
+		info := parseFixture(t, fixture)
+		siteDir := t.TempDir()
+		generator := NewGenerator(siteDir, "", mediaProvider, true, false, true, false, *info,
+			Options{SVGImages: policy, SVGInlineMaxBytes: DefaultSVGInlineMaxBytes})
+		require.NoError(t, generator.validateSVGImages())
+		require.NoError(t, generator.writeContent(context.Background(), siteDir, *info))
+		content, err := os.ReadFile(filepath.Join(siteDir, "content", "pages", "about", "_index.md"))
+		require.NoError(t, err)
+		return siteDir, string(content)
+	}
+
+	siteDir, content := generate(SVGImagesDownload)
+	require.Contains(t, content, "![Done]("+iconLink+")")
+	require.FileExists(t, filepath.Join(siteDir, "static", iconLink))
+
+	// The icon is inlined and sanitized, the illustration is too large
+	siteDir, content = generate(SVGImagesInline)
+	require.Contains(t, content, `<svg role="img" aria-label="Done" xmlns="http://www.w3.org/2000/svg" viewBox="0 0 24 24"> `+
+		`<path d="M9 16.2 4.8 12l-1.4 1.4L9 19 21 7l-1.4-1.4z"/> </svg> This is a`)
+	require.NotContains(t, content, "alert")
+	require.NotContains(t, content, iconLink)
+	require.NoFileExists(t, filepath.Join(siteDir, "static", iconLink))
+	require.Contains(t, content, "![Mountains]("+illustrationLink+")")
+	require.FileExists(t, filepath.Join(siteDir, "static", illustrationLink))
+}
+
+func TestParseSVGImagePolicy(t *testing.T) {
+	t.Parallel()
+	policy, err := ParseSVGImagePolicy("")
+	require.NoError(t, err)
+	require.Equal(t, SVGImagesDownload, policy)
+	policy, err = ParseSVGImagePolicy("inline")
+	require.NoError(t, err)
+	require.Equal(t, SVGImagesInline, policy)
+	_, err = ParseSVGImagePolicy("embed")
+	require.Error(t, err)
+
+	generator := Generator{options: Options{SVGImages: SVGImagesInline, SVGInlineMaxBytes: DefaultSVGInlineMaxBytes}}
+	require.ErrorIs(t, generator.validateSVGImages(), errSVGInlineWithoutDownloads)
+}
@@ -0,0 +1,133 @@
+package hugogenerator
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/jpeg" // Register the decoders of the converted formats
+	_ "image/png"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+const _defaultWebPQuality = 80
+
+var errWebPEncodingNotSupported = errors.New("WebP encoding is not supported by this build, rebuild with -tags webp")
+
+// Only JPEG and PNG are converted: WebP and AVIF are already compact, SVGs are not raster
+// images and GIFs are mostly used for animations
+var _webpConvertibleExtensions = []string{".jpg", ".jpeg", ".png"}
+
+func isWebPConvertible(filePath string) bool {
+	extension := strings.ToLower(path.Ext(filePath))
+	for _, convertible := range _webpConvertibleExtensions {
+		if extension == convertible {
+			return true
+		}
+	}
+	return false
+}
+
+func validateWebPQuality(quality int) error {
+	if quality < 1 || quality > 100 {
+		return fmt.Errorf("invalid WebP quality %d, expected a value between 1 and 100", quality)
+	}
+	return nil
+}
+
+// isAnimatedPNG reports whether the PNG data is an APNG, the Go decoder only reads its first frame.
+// Animated PNGs have an "acTL" chunk before the first "IDAT" chunk.
+func isAnimatedPNG(data []byte) bool {
+	const signatureLength = 8
+	for offset := signatureLength; offset+8 <= len(data); {
+		switch string(data[offset+4 : offset+8]) {
+		case "acTL":
+			return true
+		case "IDAT":
+			return false
+		}
+		// Length, type, data and CRC
+		offset += 12 + int(binary.BigEndian.Uint32(data[offset:]))
+	}
+	return false
+}
+
+// convertImageToWebP writes a WebP version of the downloaded JPEG or PNG image next to it,
+// as "photo.jpg.webp" so that "photo.jpg" and "photo.png" do not collide.
+// It returns the path of the WebP file, or an empty string if the image was not converted.
+func (g Generator) convertImageToWebP(filePath string) (string, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", fmt.Errorf("error reading image %s: %w", filePath, err)
+	}
+	if isAnimatedPNG(data) {
+		log.Info().
+			Str("filePath", filePath).
+			Msg("Not converting animated PNG to WebP")
+		return "", nil
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("error decoding image %s: %w", filePath, err)
+	}
+	var webpData bytes.Buffer
+	if err := encodeWebP(&webpData, img, g.options.WebPQuality); err != nil {
+		return "", fmt.Errorf("error encoding image %s to WebP: %w", filePath, err)
+	}
+	if webpData.Len() >= len(data) {
+		log.Debug().
+			Str("filePath", filePath).
+			Int("size", len(data)).
+			Int("webpSize", webpData.Len()).
+			Msg("WebP version is not smaller, keeping the original image")
+		return "", nil
+	}
+
+	webpFilePath := filePath + ".webp"
+	if err := writeFile(webpFilePath, webpData.Bytes()); err != nil {
+		return "", err
+	}
+	g.report.addImageConversion(int64(len(data)), int64(webpData.Len()))
+
+	// The media library data still references the original images
+	if !g.options.KeepOriginalImages && !g.downloadAll {
+		if err := os.Remove(filePath); err != nil {
+			return "", fmt.Errorf("error removing original image %s: %w", filePath, err)
+		}
+	}
+	return webpFilePath, nil
+}
+
+// maybeConvertImageToWebP converts the downloaded image and points the replacements
+// of the media link to the WebP version
+func (g Generator) maybeConvertImageToWebP(outputFilePath string, urlReplacement map[string]string, links ...string) error {
+	if !g.options.ConvertImagesToWebP || !isWebPConvertible(outputFilePath) {
+		return nil
+	}
+	filePath, err := getDownloadFilePath(outputFilePath)
+	if err != nil {
+		return err
+	}
+	webpFilePath, err := g.convertImageToWebP(filePath)
+	if err != nil || webpFilePath == "" {
+		return err
+	}
+
+	// Links to the resized thumbnails are already replaced by the full-res image link
+	target := links[0]
+	if replacement, ok := urlReplacement[target]; ok {
+		target = replacement
+	}
+	webpLink := strings.Split(target, "?")[0] + ".webp"
+	urlReplacement[target] = webpLink
+	for _, link := range links {
+		urlReplacement[link] = webpLink
+	}
+	return nil
+}
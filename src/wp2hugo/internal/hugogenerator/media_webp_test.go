@@ -0,0 +1,66 @@
+package hugogenerator
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsWebPConvertible(t *testing.T) {
+	t.Parallel()
+	require.True(t, isWebPConvertible("/static/wp-content/uploads/photo.jpg"))
+	require.True(t, isWebPConvertible("/static/wp-content/uploads/photo.JPEG"))
+	require.True(t, isWebPConvertible("/static/wp-content/uploads/screenshot.png"))
+	require.False(t, isWebPConvertible("/static/wp-content/uploads/photo.webp"))
+	require.False(t, isWebPConvertible("/static/wp-content/uploads/photo.avif"))
+	require.False(t, isWebPConvertible("/static/wp-content/uploads/logo.svg"))
+	require.False(t, isWebPConvertible("/static/wp-content/uploads/animation.gif"))
+}
+
+func TestIsAnimatedPNG(t *testing.T) {
+	t.Parallel()
+	staticPNG := testPNG(t)
+	require.False(t, isAnimatedPNG(staticPNG))
+	require.True(t, isAnimatedPNG(withPNGChunk(staticPNG, "acTL", []byte{0, 0, 0, 2, 0, 0, 0, 0})))
+	require.False(t, isAnimatedPNG(staticPNG[:10]))
+}
+
+func TestValidateWebPQuality(t *testing.T) {
+	t.Parallel()
+	require.NoError(t, validateWebPQuality(1))
+	require.NoError(t, validateWebPQuality(100))
+	require.Error(t, validateWebPQuality(0))
+	require.Error(t, validateWebPQuality(101))
+}
+
+func testPNG(t *testing.T) []byte {
+	t.Helper()
+	// Noisy, like a photo, so that the lossy WebP version is smaller
+	random := rand.New(rand.NewPCG(1, 2))
+	img := image.NewRGBA(image.Rect(0, 0, 128, 128))
+	for x := 0; x < 128; x++ {
+		for y := 0; y < 128; y++ {
+			img.Set(x, y, color.RGBA{R: uint8(x * 2), G: uint8(y * 2), B: uint8(random.IntN(256)), A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+// withPNGChunk inserts a chunk right after the IHDR chunk
+func withPNGChunk(data []byte, chunkType string, chunkData []byte) []byte {
+	const ihdrEnd = 8 + 12 + 13
+	chunk := binary.BigEndian.AppendUint32(nil, uint32(len(chunkData)))
+	chunk = append(chunk, chunkType...)
+	chunk = append(chunk, chunkData...)
+	chunk = binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(chunk[4:]))
+	return append(append(append([]byte{}, data[:ihdrEnd]...), chunk...), data[ihdrEnd:]...)
+}
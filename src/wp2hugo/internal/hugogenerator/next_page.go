@@ -0,0 +1,140 @@
+package hugogenerator
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/hugogenerator/hugopage"
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/rs/zerolog/log"
+)
+
+// NextPagePolicy decides what becomes of the content paginated with <!--nextpage--> tags,
+// since Hugo does not paginate a single page
+type NextPagePolicy string
+
+const (
+	// NextPageCollapse keeps the content on one page, with a thematic break in place of the tags
+	NextPageCollapse NextPagePolicy = "collapse"
+	// NextPageSplit writes one page per page of the content, at their WordPress URLs, e.g. /slug/2/,
+	// linked with page links like the ones WordPress renders
+	NextPageSplit NextPagePolicy = "split"
+)
+
+func ParseNextPagePolicy(policy string) (NextPagePolicy, error) {
+	switch NextPagePolicy(policy) {
+	case NextPageCollapse, NextPageSplit:
+		return NextPagePolicy(policy), nil
+	case "":
+		return NextPageCollapse, nil
+	default:
+		return "", fmt.Errorf("unknown nextpage policy %q, expected one of %s, %s",
+			policy, NextPageCollapse, NextPageSplit)
+	}
+}
+
+// pagePart is one of the pages of the content split at its <!--nextpage--> tags,
+// or the whole content, numbered 1 of 1
+type pagePart struct {
+	path   string
+	page   wpparser.CommonFields
+	number int
+	count  int
+	// Language detected from the text of the whole content, see Options.DetectLanguages
+	language string
+}
+
+// getPageParts returns the pages to write for the content, and records the paginated content in the Report
+func (g Generator) getPageParts(pagePath string, page wpparser.CommonFields) []pagePart {
+	whole := []pagePart{{path: pagePath, page: page, number: 1, count: 1}}
+	if !hugopage.HasNextPages(page.Content) {
+		return whole
+	}
+	policy := g.options.NextPage
+	if policy == "" {
+		policy = NextPageCollapse
+	}
+	g.report.PaginatedContent = append(g.report.PaginatedContent, PaginatedContent{Link: page.Link, Policy: policy})
+	if policy != NextPageSplit {
+		return whole
+	}
+
+	contents := hugopage.SplitNextPages(page.Content)
+	links := make([]string, len(contents))
+	for i := range contents {
+		links[i] = getPartLink(page.Link, i+1)
+	}
+	parts := make([]pagePart, 0, len(contents))
+	for i, content := range contents {
+		part := pagePart{path: pagePath, page: page, number: i + 1, count: len(contents)}
+		part.page.Content = content + "\n" + getPageLinks(links, i, g.options.SourceIsMarkdown)
+		if i > 0 {
+			part.path = getPartPath(pagePath, i+1)
+			part.page.Link = links[i]
+		}
+		parts = append(parts, part)
+	}
+	log.Debug().
+		Str("link", page.Link).
+		Int("pages", len(parts)).
+		Msg("Content split at its nextpage tags")
+	return parts
+}
+
+// getPartLink returns the WordPress URL of the page of the content, e.g. /slug/2/, or ?p=10&page=2 with the plain permalinks
+func getPartLink(link string, number int) string {
+	if number == 1 {
+		return link
+	}
+	parsedURL, err := url.Parse(link)
+	if err != nil {
+		return strings.TrimSuffix(link, "/") + "/" + strconv.Itoa(number) + "/"
+	}
+	if parsedURL.RawQuery != "" {
+		query := parsedURL.Query()
+		query.Set("page", strconv.Itoa(number))
+		parsedURL.RawQuery = query.Encode()
+	} else {
+		parsedURL.Path = strings.TrimSuffix(parsedURL.Path, "/") + "/" + strconv.Itoa(number) + "/"
+	}
+	return parsedURL.String()
+}
+
+// getPartPath returns the path of the page of the content next to its first page, e.g. posts/slug-page-2.md,
+// or pages/about-page-2.md for the page bundle pages/about/_index.md
+func getPartPath(pagePath string, number int) string {
+	dir, name := path.Split(strings.TrimSuffix(pagePath, ".md"))
+	// The language suffix, e.g. slug.fr.md
+	stem, language, hasLanguage := strings.Cut(name, ".")
+	if stem == "index" || stem == "_index" {
+		dir, stem = path.Split(strings.TrimSuffix(dir, "/"))
+	}
+	baseFileName := fmt.Sprintf("%s-page-%d", stem, number)
+	if hasLanguage {
+		baseFileName += "." + language
+	}
+	return getFilePath(dir, baseFileName)
+}
+
+// getPageLinks returns the page links appended to the page at index, like the ones rendered by wp_link_pages
+func getPageLinks(links []string, index int, sourceIsMarkdown bool) string {
+	items := make([]string, 0, len(links))
+	for i, link := range links {
+		number := strconv.Itoa(i + 1)
+		switch {
+		case i == index:
+			items = append(items, number)
+		case sourceIsMarkdown:
+			items = append(items, fmt.Sprintf("[%s](%s)", number, link))
+		default:
+			items = append(items, fmt.Sprintf(`<a href="%s">%s</a>`, link, number))
+		}
+	}
+	if sourceIsMarkdown {
+		return "\nPages: " + strings.Join(items, " ") + "\n"
+	}
+	return "<p>Pages: " + strings.Join(items, " ") + "</p>"
+}
@@ -0,0 +1,71 @@
+package hugogenerator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextPage(t *testing.T) {
+	t.Parallel()
+	info := parseFixture(t, integrationFixture{name: "classic", replacements: []string{
+		"<blockquote><p>The mountains are calling.</p></blockquote>",
+		"<!--nextpage--><blockquote><p>The mountains are calling.</p></blockquote>",
+	}})
+	generate := func(policy NextPagePolicy) (string, Report) {
+		siteDir := t.TempDir()
+		generator := NewGenerator(siteDir, "", nil, false, false, false, false, *info, Options{NextPage: policy})
+		require.NoError(t, generator.writeContent(context.Background(), siteDir, *info))
+		return siteDir, generator.Report()
+	}
+
+	siteDir, report := generate(NextPageCollapse)
+	require.Equal(t, []PaginatedContent{{Link: "https://example.org/2024/03/05/a-trip-to-the-mountains/", Policy: NextPageCollapse}},
+		report.PaginatedContent)
+	content, err := os.ReadFile(filepath.Join(siteDir, "content", "posts", "a-trip-to-the-mountains.md"))
+	require.NoError(t, err)
+	require.NotContains(t, string(content), "nextpage")
+	require.Contains(t, string(content), "The mountains are calling.")
+	require.NotContains(t, string(content), "parts:")
+	require.NoFileExists(t, filepath.Join(siteDir, "content", "posts", "a-trip-to-the-mountains-page-2.md"))
+
+	siteDir, report = generate(NextPageSplit)
+	require.Len(t, report.PaginatedContent, 1)
+	content, err = os.ReadFile(filepath.Join(siteDir, "content", "posts", "a-trip-to-the-mountains.md"))
+	require.NoError(t, err)
+	require.Contains(t, string(content), "url: /2024/03/05/a-trip-to-the-mountains/\n")
+	require.Contains(t, string(content), "part: 1\nparts: 2\n")
+	require.Contains(t, string(content), "Pages: 1 [2](/2024/03/05/a-trip-to-the-mountains/2/)")
+	require.NotContains(t, string(content), "The mountains are calling.")
+	require.NotContains(t, string(content), "build:")
+
+	content, err = os.ReadFile(filepath.Join(siteDir, "content", "posts", "a-trip-to-the-mountains-page-2.md"))
+	require.NoError(t, err)
+	require.Contains(t, string(content), "url: /2024/03/05/a-trip-to-the-mountains/2/\n")
+	require.Contains(t, string(content), "build:\n  list: never\n")
+	require.Contains(t, string(content), "part: 2\nparts: 2\n")
+	require.Contains(t, string(content), "The mountains are calling.")
+	require.Contains(t, string(content), "Pages: [1](/2024/03/05/a-trip-to-the-mountains/) 2")
+}
+
+func TestGetPartLinkAndPath(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, "https://example.org/slug/", getPartLink("https://example.org/slug/", 1))
+	require.Equal(t, "https://example.org/slug/3/", getPartLink("https://example.org/slug/", 3))
+	require.Equal(t, "https://example.org/slug/2/", getPartLink("https://example.org/slug", 2))
+	require.Equal(t, "https://example.org/?p=10&page=2", getPartLink("https://example.org/?p=10", 2))
+
+	dir := t.TempDir()
+	require.Equal(t, filepath.Join(dir, "slug-page-2.md"), getPartPath(filepath.Join(dir, "slug.md"), 2))
+	require.Equal(t, filepath.Join(dir, "slug-page-2.fr.md"), getPartPath(filepath.Join(dir, "slug.fr.md"), 2))
+	require.Equal(t, filepath.Join(dir, "about-page-3.md"), getPartPath(filepath.Join(dir, "about", "_index.md"), 3))
+
+	policy, err := ParseNextPagePolicy("")
+	require.NoError(t, err)
+	require.Equal(t, NextPageCollapse, policy)
+	_, err = ParseNextPagePolicy("paginate")
+	require.Error(t, err)
+}
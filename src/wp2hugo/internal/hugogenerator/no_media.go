@@ -0,0 +1,33 @@
+package hugogenerator
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/hugogenerator/hugopage"
+)
+
+var errNoMediaWithDownloads = errors.New("no media mode never downloads the media, it can't be combined with the media downloads, the WebP conversion or the assets dir")
+
+// validateNoMedia checks that nothing expects the media in the site, see Options.NoMedia
+func (g Generator) validateNoMedia() error {
+	if !g.options.NoMedia {
+		return nil
+	}
+	if g.downloadMedia || g.downloadAll || g.options.ConvertImagesToWebP || g.options.AssetsDir != "" {
+		return errNoMediaWithDownloads
+	}
+	return nil
+}
+
+// countRemoteMedia records the media links of the page left pointing to the WordPress site, see Options.NoMedia
+func (g Generator) countRemoteMedia(p *hugopage.Page) {
+	if !g.options.NoMedia {
+		return
+	}
+	for _, link := range p.WPMediaLinks() {
+		if !hugopage.IsDataURI(link) && !strings.HasPrefix(link, "/") {
+			g.report.RemoteMediaLinks++
+		}
+	}
+}
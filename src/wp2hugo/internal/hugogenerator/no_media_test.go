@@ -0,0 +1,33 @@
+package hugogenerator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNoMedia(t *testing.T) {
+	t.Parallel()
+	websiteInfo := parseFixture(t, integrationFixture{name: "classic"})
+	siteDir := t.TempDir()
+	generator := NewGenerator(siteDir, "", nil, false, false, false, false, *websiteInfo, Options{NoMedia: true})
+	require.NoError(t, generator.writeContent(context.Background(), siteDir, *websiteInfo))
+
+	content, err := os.ReadFile(filepath.Join(siteDir, "content", "posts", "a-trip-to-the-mountains.md"))
+	require.NoError(t, err)
+	require.Contains(t, string(content), "  image: https://example.org/wp-content/uploads/2024/03/summit.jpg\n")
+	require.Contains(t, string(content), `src="https://example.org/wp-content/uploads/2024/03/summit-640x480.jpg"`)
+	require.NotContains(t, string(content), `"/wp-content/`)
+	require.True(t, generator.Report().NoMedia)
+	require.Positive(t, generator.Report().RemoteMediaLinks)
+}
+
+func TestNoMediaWithDownloads(t *testing.T) {
+	t.Parallel()
+	generator := NewGenerator(t.TempDir(), "", nil, true, false, false, false,
+		*parseFixture(t, integrationFixture{name: "classic"}), Options{NoMedia: true})
+	require.ErrorIs(t, generator.Generate(context.Background()), errNoMediaWithDownloads)
+}
@@ -0,0 +1,25 @@
+package hugogenerator
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// createOutputDir creates the output dir, with its missing parents, and returns its absolute path.
+// Only a permission error is worth a dedicated message, the other errors are reported as is.
+func createOutputDir(outputDirPath string) (string, error) {
+	absPath, err := filepath.Abs(outputDirPath)
+	if err != nil {
+		return "", fmt.Errorf("error resolving output directory '%s': %w", outputDirPath, err)
+	}
+	if err := os.MkdirAll(absPath, 0o755); err != nil {
+		if errors.Is(err, fs.ErrPermission) {
+			return "", fmt.Errorf("no permission to create output directory '%s', choose a writable --output: %w", absPath, err)
+		}
+		return "", fmt.Errorf("error creating output directory '%s': %w", absPath, err)
+	}
+	return absPath, nil
+}
@@ -0,0 +1,53 @@
+package hugogenerator
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateOutputDir(t *testing.T) {
+	t.Parallel()
+	outputDirPath := path.Join(t.TempDir(), "not", "yet", "existing", "output")
+	absPath, err := createOutputDir(outputDirPath)
+	require.NoError(t, err)
+	require.Equal(t, outputDirPath, absPath)
+	require.True(t, utils.DirExists(absPath))
+
+	// Existing dir and writing into it
+	absPath, err = createOutputDir(outputDirPath)
+	require.NoError(t, err)
+	siteDir := path.Join(absPath, "site")
+	require.NoError(t, utils.CreateDirIfNotExist(path.Join(siteDir, "content", "posts")))
+	require.NoError(t, writeFile(path.Join(siteDir, "content", "posts", "hello.md"), []byte("Hello")))
+
+	// A file in the way
+	_, err = createOutputDir(path.Join(siteDir, "content", "posts", "hello.md", "output"))
+	require.ErrorContains(t, err, "error creating output directory")
+}
+
+func TestCreateOutputDirRelative(t *testing.T) {
+	t.Parallel()
+	absPath, err := createOutputDir(".")
+	require.NoError(t, err)
+	require.True(t, filepath.IsAbs(absPath))
+	workingDir, err := os.Getwd()
+	require.NoError(t, err)
+	require.Equal(t, workingDir, absPath)
+}
+
+func TestCreateOutputDirPermissionDenied(t *testing.T) {
+	t.Parallel()
+	if os.Geteuid() == 0 {
+		t.Skip("root ignores the dir permissions")
+	}
+	readOnlyDir := t.TempDir()
+	require.NoError(t, os.Chmod(readOnlyDir, 0o500))
+	t.Cleanup(func() { _ = os.Chmod(readOnlyDir, 0o755) })
+	_, err := createOutputDir(path.Join(readOnlyDir, "output"))
+	require.ErrorContains(t, err, "no permission to create output directory")
+}
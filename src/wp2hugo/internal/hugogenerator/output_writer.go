@@ -0,0 +1,84 @@
+package hugogenerator
+
+import (
+	"archive/zip"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/utils"
+)
+
+// outputWriter writes the files of the Hugo site, the paths are relative to its root, with forward slashes
+type outputWriter interface {
+	// Create creates, or truncates, the file at filePath, its dir must have been created
+	Create(filePath string) (io.WriteCloser, error)
+	// Mkdir creates the dir at dirPath along with its missing parents
+	Mkdir(dirPath string) error
+}
+
+// dirOutputWriter writes the files under its dir, at their path as is when the dir is empty
+type dirOutputWriter struct {
+	dir string
+}
+
+// _siteFiles writes the files of the generator, at their path on disk
+var _siteFiles outputWriter = dirOutputWriter{}
+
+func (w dirOutputWriter) Create(filePath string) (io.WriteCloser, error) {
+	return os.OpenFile(w.path(filePath), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+}
+
+func (w dirOutputWriter) Mkdir(dirPath string) error {
+	return utils.CreateDirIfNotExist(w.path(dirPath))
+}
+
+func (w dirOutputWriter) path(filePath string) string {
+	return filepath.Join(w.dir, filepath.FromSlash(filePath))
+}
+
+// zipOutputWriter writes the files as the entries of a zip archive, one at a time: the entry being written
+// is completed by the creation of the next one, or by Close
+type zipOutputWriter struct {
+	archive *zip.Writer
+}
+
+func newZipOutputWriter(w io.Writer) zipOutputWriter {
+	return zipOutputWriter{archive: zip.NewWriter(w)}
+}
+
+func (w zipOutputWriter) Create(filePath string) (io.WriteCloser, error) {
+	entry, err := w.archive.CreateHeader(&zip.FileHeader{
+		Name:     filePath,
+		Method:   zip.Deflate,
+		Modified: time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return zipEntryWriter{entry}, nil
+}
+
+// Mkdir adds the dir entry, the archive has no missing parents: the entries don't need the dirs of their path
+func (w zipOutputWriter) Mkdir(dirPath string) error {
+	// Dir entries end with a slash
+	_, err := w.archive.CreateHeader(&zip.FileHeader{
+		Name:     dirPath + "/",
+		Modified: time.Now(),
+	})
+	return err
+}
+
+// Close writes the central directory of the archive, the underlying writer is left open
+func (w zipOutputWriter) Close() error {
+	return w.archive.Close()
+}
+
+type zipEntryWriter struct {
+	io.Writer
+}
+
+func (zipEntryWriter) Close() error {
+	return nil
+}
@@ -0,0 +1,167 @@
+package hugogenerator
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/rs/zerolog/log"
+)
+
+// PageBuilderContent is the content built with a page builder, e.g. Elementor, whose layout is not converted
+type PageBuilderContent struct {
+	Link    string
+	Builder string
+	// The content was empty, it was extracted from the layout data of Elementor as best as possible
+	Extracted bool
+	// The content is empty, nothing could be extracted
+	Empty bool
+}
+
+const (
+	_elementorBuilder = "Elementor"
+	// Layout of the page, a JSON tree of sections, columns and widgets
+	_elementorDataKey = "_elementor_data"
+)
+
+// The tags and the Gutenberg block comments, which are left of a placeholder content
+var _tagRegEx = regexp.MustCompile(`<[^>]*>`)
+
+var _headingTagRegEx = regexp.MustCompile(`^h[1-6]$`)
+
+// getPageBuilderContent records the content built with a page builder in the Report. Its content is often empty,
+// or a placeholder, the layout being in the postmeta: the content is then extracted from the layout of Elementor.
+func (g Generator) getPageBuilderContent(page wpparser.CommonFields) wpparser.CommonFields {
+	builder, ok := wpparser.GetPageBuilder(page)
+	if !ok {
+		return page
+	}
+	content := PageBuilderContent{Link: page.Link, Builder: builder}
+	if isBlankContent(page.Content) {
+		if builder == _elementorBuilder {
+			if extracted := extractElementorContent(page.CustomMetaData); extracted != "" {
+				page.Content = extracted
+				content.Extracted = true
+			}
+		}
+		content.Empty = !content.Extracted
+	}
+	log.Debug().
+		Str("link", page.Link).
+		Str("builder", builder).
+		Bool("extracted", content.Extracted).
+		Msg("Content built with a page builder")
+	g.report.PageBuilderContent = append(g.report.PageBuilderContent, content)
+	return page
+}
+
+// recordEmptyContent records the content with an empty body in the Report, unless it is built with a page builder,
+// see getPageBuilderContent
+func (g Generator) recordEmptyContent(page wpparser.CommonFields) {
+	if _, ok := wpparser.GetPageBuilder(page); ok || strings.TrimSpace(page.Content) != "" {
+		return
+	}
+	g.report.EmptyContent = append(g.report.EmptyContent, page.Link)
+}
+
+func isBlankContent(content string) bool {
+	text := html.UnescapeString(_tagRegEx.ReplaceAllString(content, ""))
+	return strings.TrimSpace(strings.ReplaceAll(text, "\u00a0", " ")) == ""
+}
+
+type elementorElement struct {
+	ElType     string             `json:"elType"`
+	WidgetType string             `json:"widgetType"`
+	Settings   json.RawMessage    `json:"settings"`
+	Elements   []elementorElement `json:"elements"`
+}
+
+// Settings of the widgets the text is extracted from, the empty settings are exported as [] instead of {}
+type elementorSettings struct {
+	// Heading
+	Title      string `json:"title"`
+	HeaderSize string `json:"header_size"`
+	// Text editor and HTML
+	Editor string `json:"editor"`
+	HTML   string `json:"html"`
+	// Button
+	Text string `json:"text"`
+	Link struct {
+		URL string `json:"url"`
+	} `json:"link"`
+	// Image
+	Image struct {
+		URL string `json:"url"`
+		Alt string `json:"alt"`
+	} `json:"image"`
+}
+
+// extractElementorContent returns the HTML of the headings, texts, images and buttons of the Elementor layout,
+// in order, without the layout itself, empty if the postmeta has no layout
+func extractElementorContent(customMetaData []wpparser.CustomMetaDatum) string {
+	for _, metadatum := range customMetaData {
+		if metadatum.Key != _elementorDataKey {
+			continue
+		}
+		var elements []elementorElement
+		if err := json.Unmarshal([]byte(metadatum.Value), &elements); err != nil {
+			log.Warn().
+				Err(err).
+				Msg("Error decoding the Elementor layout")
+			return ""
+		}
+		var b strings.Builder
+		writeElementorElements(&b, elements)
+		return strings.TrimSpace(b.String())
+	}
+	return ""
+}
+
+func writeElementorElements(b *strings.Builder, elements []elementorElement) {
+	for _, element := range elements {
+		if element.ElType == "widget" {
+			writeElementorWidget(b, element)
+		}
+		writeElementorElements(b, element.Elements)
+	}
+}
+
+func writeElementorWidget(b *strings.Builder, widget elementorElement) {
+	var settings elementorSettings
+	// The settings of other types, e.g. [] or a number instead of a string, are skipped
+	_ = json.Unmarshal(widget.Settings, &settings)
+	switch widget.WidgetType {
+	case "heading":
+		if settings.Title == "" {
+			return
+		}
+		tag := settings.HeaderSize
+		if !_headingTagRegEx.MatchString(tag) {
+			tag = "h2"
+		}
+		fmt.Fprintf(b, "<%s>%s</%s>\n", tag, withLink(settings.Title, settings.Link.URL), tag)
+	case "text-editor":
+		b.WriteString(settings.Editor + "\n")
+	case "html":
+		b.WriteString(settings.HTML + "\n")
+	case "image":
+		if settings.Image.URL != "" {
+			fmt.Fprintf(b, "<p>%s</p>\n", withLink(fmt.Sprintf(`<img src="%s" alt="%s" />`,
+				html.EscapeString(settings.Image.URL), html.EscapeString(settings.Image.Alt)), settings.Link.URL))
+		}
+	case "button":
+		if settings.Text != "" {
+			fmt.Fprintf(b, "<p>%s</p>\n", withLink(settings.Text, settings.Link.URL))
+		}
+	}
+}
+
+func withLink(content string, link string) string {
+	if link == "" {
+		return content
+	}
+	return fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(link), content)
+}
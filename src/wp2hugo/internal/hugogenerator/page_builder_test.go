@@ -0,0 +1,68 @@
+package hugogenerator
+
+import (
+	"testing"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/stretchr/testify/require"
+)
+
+const _elementorData = `[{"id":"1","elType":"section","settings":[],"elements":[{"id":"2","elType":"column","settings":{"_column_size":100},"elements":[
+{"id":"3","elType":"widget","widgetType":"heading","settings":{"title":"Welcome","header_size":"h1"},"elements":[]},
+{"id":"4","elType":"widget","widgetType":"text-editor","settings":{"editor":"<p>Some <strong>text<\/strong>.<\/p>"},"elements":[]},
+{"id":"5","elType":"widget","widgetType":"image","settings":{"image":{"url":"https:\/\/example.com\/wp-content\/uploads\/a.jpg","id":7,"alt":"A"}},"elements":[]},
+{"id":"6","elType":"widget","widgetType":"button","settings":{"text":"Contact","link":{"url":"https:\/\/example.com\/contact\/","is_external":""}},"elements":[]},
+{"id":"7","elType":"widget","widgetType":"spacer","settings":{"space":{"size":50}},"elements":[]}]}]}]`
+
+func TestExtractElementorContent(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, `<h1>Welcome</h1>
+<p>Some <strong>text</strong>.</p>
+<p><img src="https://example.com/wp-content/uploads/a.jpg" alt="A" /></p>
+<p><a href="https://example.com/contact/">Contact</a></p>`,
+		extractElementorContent([]wpparser.CustomMetaDatum{{Key: "_elementor_data", Value: _elementorData}}))
+	require.Empty(t, extractElementorContent([]wpparser.CustomMetaDatum{{Key: "_elementor_data", Value: "not JSON"}}))
+	require.Empty(t, extractElementorContent(nil))
+}
+
+func TestPageBuilderContent(t *testing.T) {
+	t.Parallel()
+	info := parseFixture(t, integrationFixture{name: "classic"})
+	generator := NewGenerator(t.TempDir(), "", nil, false, false, false, false, *info, Options{})
+	elementorMeta := []wpparser.CustomMetaDatum{{Key: "_elementor_edit_mode", Value: "builder"}, {Key: "_elementor_data", Value: _elementorData}}
+
+	// The placeholder content is replaced with the one of the layout
+	page := generator.getPageBuilderContent(wpparser.CommonFields{Link: "https://example.com/home/",
+		Content: "<!-- wp:paragraph -->\n<p>&nbsp;</p>\n<!-- /wp:paragraph -->", CustomMetaData: elementorMeta})
+	require.Contains(t, page.Content, "<h1>Welcome</h1>")
+
+	// The content is kept, only flagged
+	page = generator.getPageBuilderContent(wpparser.CommonFields{Link: "https://example.com/about/",
+		Content: "<p>About</p>", CustomMetaData: elementorMeta})
+	require.Equal(t, "<p>About</p>", page.Content)
+	generator.getPageBuilderContent(wpparser.CommonFields{Link: "https://example.com/services/",
+		Content: "[vc_row][vc_column][/vc_column][/vc_row]"})
+	generator.getPageBuilderContent(wpparser.CommonFields{Link: "https://example.com/empty/",
+		CustomMetaData: elementorMeta[:1]})
+	// Not built with a page builder
+	generator.getPageBuilderContent(wpparser.CommonFields{Link: "https://example.com/post/", Content: "<p>Post</p>"})
+
+	require.Equal(t, []PageBuilderContent{
+		{Link: "https://example.com/home/", Builder: "Elementor", Extracted: true},
+		{Link: "https://example.com/about/", Builder: "Elementor"},
+		{Link: "https://example.com/services/", Builder: "WPBakery Page Builder"},
+		{Link: "https://example.com/empty/", Builder: "Elementor", Empty: true},
+	}, generator.Report().PageBuilderContent)
+}
+
+func TestEmptyContent(t *testing.T) {
+	t.Parallel()
+	info := parseFixture(t, integrationFixture{name: "classic"})
+	generator := NewGenerator(t.TempDir(), "", nil, false, false, false, false, *info, Options{})
+	generator.recordEmptyContent(wpparser.CommonFields{Link: "https://example.com/empty/", Content: " \n"})
+	generator.recordEmptyContent(wpparser.CommonFields{Link: "https://example.com/post/", Content: "<p>Post</p>"})
+	// In PageBuilderContent instead
+	generator.recordEmptyContent(wpparser.CommonFields{Link: "https://example.com/builder/",
+		CustomMetaData: []wpparser.CustomMetaDatum{{Key: "_elementor_edit_mode", Value: "builder"}}})
+	require.Equal(t, []string{"https://example.com/empty/"}, generator.Report().EmptyContent)
+}
@@ -0,0 +1,82 @@
+package hugogenerator
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/utils"
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+)
+
+// ReadPathOverrides reads a YAML file mapping the post IDs to the output path of their content,
+// relative to the content dir, e.g. `"42": about/index.md`
+func ReadPathOverrides(filePath string) (map[string]string, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading path overrides: %w", err)
+	}
+	var overrides map[string]string
+	if err := yaml.Unmarshal(data, &overrides); err != nil {
+		return nil, fmt.Errorf("error parsing path overrides '%s': %w", filePath, err)
+	}
+	return overrides, nil
+}
+
+// validateContentPath verifies that the path, relative to the content dir, stays within it
+func validateContentPath(contentPath string) error {
+	if contentPath == "" {
+		return fmt.Errorf("path is empty")
+	}
+	if strings.HasPrefix(contentPath, "/") || !filepath.IsLocal(filepath.FromSlash(contentPath)) {
+		return fmt.Errorf("path must be a relative path within the content dir: %s", contentPath)
+	}
+	return nil
+}
+
+func validatePathOverrides(overrides map[string]string) error {
+	for postID, overridePath := range overrides {
+		if err := validateContentPath(strings.TrimSpace(overridePath)); err != nil {
+			return fmt.Errorf("post %s: %w", postID, err)
+		}
+	}
+	return nil
+}
+
+// getPathOverride returns the output path of the content if overridden by Options.PathOverrides,
+// or else by the _wp2hugo_path postmeta, or "" if not overridden.
+// A path without the .md extension is a page bundle dir, e.g. "about" writes about/index.md.
+func (g Generator) getPathOverride(contentDir string, page wpparser.CommonFields) (string, error) {
+	overridePath, ok := g.options.PathOverrides[page.PostID]
+	if !ok {
+		if overridePath, ok = page.GetPathOverride(); !ok {
+			return "", nil
+		}
+	}
+	overridePath = strings.TrimSpace(overridePath)
+	if err := validateContentPath(overridePath); err != nil {
+		log.Warn().
+			Str("postID", page.PostID).
+			Err(err).
+			Msg("Ignoring the path override")
+		return "", nil
+	}
+	if path.Ext(overridePath) != ".md" {
+		overridePath = path.Join(overridePath, "index.md")
+	}
+	pagePath := path.Join(contentDir, path.Clean(overridePath))
+	if utils.FileExists(pagePath) {
+		log.Warn().
+			Str("postID", page.PostID).
+			Str("pagePath", pagePath).
+			Msg("The path override replaces an existing file")
+	}
+	if err := utils.CreateDirIfNotExist(path.Dir(pagePath)); err != nil {
+		return "", err
+	}
+	return pagePath, nil
+}
@@ -0,0 +1,84 @@
+package hugogenerator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPathOverrides(t *testing.T) {
+	t.Parallel()
+	siteDir := generateFixtureSite(t, integrationFixture{name: "classic"}, Options{
+		PathOverrides: map[string]string{
+			"10": "travel/mountains.md",
+			"20": "about",
+		},
+	})
+
+	content, err := os.ReadFile(filepath.Join(siteDir, "content", "travel", "mountains.md"))
+	require.NoError(t, err)
+	require.Contains(t, string(content), "title: A trip to the mountains\n")
+	require.NoFileExists(t, filepath.Join(siteDir, "content", "posts", "a-trip-to-the-mountains.md"))
+
+	content, err = os.ReadFile(filepath.Join(siteDir, "content", "about", "index.md"))
+	require.NoError(t, err)
+	require.Contains(t, string(content), "title: About\n")
+	require.NoFileExists(t, filepath.Join(siteDir, "content", "pages", "about", "_index.md"))
+}
+
+func TestPathOverrideFromPostMeta(t *testing.T) {
+	t.Parallel()
+	contentDir := t.TempDir()
+	generator := Generator{options: Options{PathOverrides: map[string]string{"2": "special.md"}}}
+	page := func(postID string, overridePath string) wpparser.CommonFields {
+		return wpparser.CommonFields{
+			PostID:         postID,
+			CustomMetaData: []wpparser.CustomMetaDatum{{Key: wpparser.PathOverrideKey, Value: overridePath}},
+		}
+	}
+
+	pagePath, err := generator.getPathOverride(contentDir, page("1", " landing/spring "))
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(contentDir, "landing", "spring", "index.md"), pagePath)
+	require.DirExists(t, filepath.Join(contentDir, "landing", "spring"))
+
+	// The options take precedence over the postmeta
+	pagePath, err = generator.getPathOverride(contentDir, page("2", "other.md"))
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(contentDir, "special.md"), pagePath)
+
+	// Paths escaping the content dir are ignored
+	for _, overridePath := range []string{"../outside.md", "/etc/passwd.md", "a/../../outside.md"} {
+		pagePath, err = generator.getPathOverride(contentDir, page("3", overridePath))
+		require.NoError(t, err)
+		require.Empty(t, pagePath, overridePath)
+	}
+
+	pagePath, err = generator.getPathOverride(contentDir, wpparser.CommonFields{PostID: "4"})
+	require.NoError(t, err)
+	require.Empty(t, pagePath)
+}
+
+func TestValidatePathOverrides(t *testing.T) {
+	t.Parallel()
+	require.NoError(t, validatePathOverrides(map[string]string{"1": "about/index.md", "2": "docs/./faq.md"}))
+	require.Error(t, validatePathOverrides(map[string]string{"1": "../about.md"}))
+	require.Error(t, validatePathOverrides(map[string]string{"1": "/about.md"}))
+	require.Error(t, validatePathOverrides(map[string]string{"1": " "}))
+}
+
+func TestReadPathOverrides(t *testing.T) {
+	t.Parallel()
+	filePath := filepath.Join(t.TempDir(), "overrides.yaml")
+	require.NoError(t, os.WriteFile(filePath, []byte("\"42\": about/index.md\n7: contact\n"), 0o600))
+
+	overrides, err := ReadPathOverrides(filePath)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"42": "about/index.md", "7": "contact"}, overrides)
+
+	_, err = ReadPathOverrides(filepath.Join(t.TempDir(), "missing.yaml"))
+	require.Error(t, err)
+}
@@ -0,0 +1,152 @@
+package hugogenerator
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/rs/zerolog/log"
+)
+
+// MissingDatePolicy decides which date to emit for content that was never published,
+// typically drafts, since some themes require a date
+type MissingDatePolicy string
+
+const (
+	// MissingDateOmit emits no date at all
+	MissingDateOmit MissingDatePolicy = "omit"
+	// MissingDateLastModified uses the last modification date instead
+	MissingDateLastModified MissingDatePolicy = "lastmod"
+	// MissingDatePostID places the content right after the dated content with the closest lower post ID,
+	// since post IDs are allocated in creation order
+	MissingDatePostID MissingDatePolicy = "post-id"
+)
+
+func ParseMissingDatePolicy(policy string) (MissingDatePolicy, error) {
+	switch MissingDatePolicy(policy) {
+	case MissingDateOmit, MissingDateLastModified, MissingDatePostID:
+		return MissingDatePolicy(policy), nil
+	case "":
+		return MissingDateOmit, nil
+	default:
+		return "", fmt.Errorf("unknown missing date policy %q, expected one of %s, %s, %s",
+			policy, MissingDateOmit, MissingDateLastModified, MissingDatePostID)
+	}
+}
+
+// DateSource decides which publish date of the content drives the `date` front matter, and the DatePath
+type DateSource string
+
+const (
+	// DateSourceGMT uses the GMT publish date, emitted in UTC
+	DateSourceGMT DateSource = "gmt"
+	// DateSourceLocal uses the local publish date, as displayed by WordPress, emitted in the UTC offset of the site,
+	// so that the content is grouped by its local day, e.g. a post published at 01:30 in UTC+05:30
+	DateSourceLocal DateSource = "local"
+)
+
+func ParseDateSource(source string) (DateSource, error) {
+	switch DateSource(source) {
+	case DateSourceGMT, DateSourceLocal:
+		return DateSource(source), nil
+	case "":
+		return DateSourceGMT, nil
+	default:
+		return "", fmt.Errorf("unknown date source %q, expected one of %s, %s",
+			source, DateSourceGMT, DateSourceLocal)
+	}
+}
+
+type postIDDate struct {
+	postID int
+	date   time.Time
+}
+
+// getPostIDDates returns the publish dates of all the content, ordered by post ID
+func getPostIDDates(info wpparser.WebsiteInfo) []postIDDate {
+	var all []wpparser.CommonFields
+	for _, post := range info.Posts() {
+		all = append(all, post.CommonFields)
+	}
+	for _, page := range info.Pages() {
+		all = append(all, page.CommonFields)
+	}
+	for _, customPost := range info.CustomPosts() {
+		all = append(all, customPost.CommonFields)
+	}
+
+	result := make([]postIDDate, 0, len(all))
+	for _, fields := range all {
+		postID, err := strconv.Atoi(fields.PostID)
+		if err != nil || fields.PublishDate == nil {
+			continue
+		}
+		result = append(result, postIDDate{postID: postID, date: *fields.PublishDate})
+	}
+	// Stable, so that content sharing a post ID keeps the export order
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].postID < result[j].postID
+	})
+	return result
+}
+
+// getPublishDate returns the publish date of the content according to the date source,
+// or the fallback date according to the missing date policy, or nil
+func (g Generator) getPublishDate(page wpparser.CommonFields) *time.Time {
+	if g.options.DateSource == DateSourceLocal && page.PublishDateLocal != nil {
+		return page.PublishDateLocal
+	}
+	if page.PublishDate != nil {
+		return page.PublishDate
+	}
+
+	var fallback *time.Time
+	switch g.options.MissingDatePolicy {
+	case MissingDateLastModified:
+		fallback = page.LastModifiedDate
+	case MissingDatePostID:
+		fallback = getDateFromPostID(g.postIDDates, page.PostID)
+	default:
+		return nil
+	}
+
+	if fallback == nil {
+		log.Warn().
+			Str("title", page.Title).
+			Str("postID", page.PostID).
+			Str("policy", string(g.options.MissingDatePolicy)).
+			Msg("No publish date and no fallback date, omitting the date")
+		return nil
+	}
+	log.Warn().
+		Str("title", page.Title).
+		Str("postID", page.PostID).
+		Str("policy", string(g.options.MissingDatePolicy)).
+		Time("date", *fallback).
+		Msg("No publish date, using a fallback date")
+	return fallback
+}
+
+// getDateFromPostID derives a date from the dated content with the closest post IDs,
+// one second apart per post ID so that the ordering is preserved
+func getDateFromPostID(postIDDates []postIDDate, postIDStr string) *time.Time {
+	postID, err := strconv.Atoi(postIDStr)
+	if err != nil || len(postIDDates) == 0 {
+		return nil
+	}
+	index := sort.Search(len(postIDDates), func(i int) bool {
+		return postIDDates[i].postID >= postID
+	})
+
+	var date time.Time
+	if index > 0 {
+		previous := postIDDates[index-1]
+		date = previous.date.Add(time.Duration(postID-previous.postID) * time.Second)
+	} else {
+		next := postIDDates[index]
+		date = next.date.Add(-time.Duration(next.postID-postID) * time.Second)
+	}
+	return &date
+}
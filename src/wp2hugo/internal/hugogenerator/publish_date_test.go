@@ -0,0 +1,98 @@
+package hugogenerator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMissingDatePolicy(t *testing.T) {
+	t.Parallel()
+	policy, err := ParseMissingDatePolicy("")
+	require.NoError(t, err)
+	require.Equal(t, MissingDateOmit, policy)
+	policy, err = ParseMissingDatePolicy("post-id")
+	require.NoError(t, err)
+	require.Equal(t, MissingDatePostID, policy)
+	_, err = ParseMissingDatePolicy("today")
+	require.Error(t, err)
+}
+
+func TestGetDateFromPostID(t *testing.T) {
+	t.Parallel()
+	date1 := time.Date(2024, time.March, 5, 10, 0, 0, 0, time.UTC)
+	date2 := time.Date(2024, time.April, 1, 10, 0, 0, 0, time.UTC)
+	postIDDates := []postIDDate{{postID: 10, date: date1}, {postID: 20, date: date2}}
+
+	require.Equal(t, date1.Add(5*time.Second), *getDateFromPostID(postIDDates, "15"))
+	require.Equal(t, date2.Add(10*time.Second), *getDateFromPostID(postIDDates, "30"))
+	require.Equal(t, date1.Add(-5*time.Second), *getDateFromPostID(postIDDates, "5"))
+	require.Nil(t, getDateFromPostID(postIDDates, "not-a-number"))
+	require.Nil(t, getDateFromPostID(nil, "15"))
+}
+
+func TestGetPublishDateFallbacks(t *testing.T) {
+	t.Parallel()
+	lastModified := time.Date(2024, time.June, 1, 12, 30, 0, 0, time.UTC)
+	draft := wpparser.CommonFields{PostID: "15", Title: "Draft", LastModifiedDate: &lastModified}
+	postIDDates := []postIDDate{{postID: 10, date: time.Date(2024, time.March, 5, 10, 0, 0, 0, time.UTC)}}
+
+	require.Nil(t, Generator{options: Options{}, postIDDates: postIDDates}.getPublishDate(draft))
+	require.Equal(t, &lastModified,
+		Generator{options: Options{MissingDatePolicy: MissingDateLastModified}}.getPublishDate(draft))
+	require.Equal(t, time.Date(2024, time.March, 5, 10, 0, 5, 0, time.UTC),
+		*Generator{options: Options{MissingDatePolicy: MissingDatePostID}, postIDDates: postIDDates}.getPublishDate(draft))
+
+	// Without a last modification date either
+	require.Nil(t, Generator{options: Options{MissingDatePolicy: MissingDateLastModified}}.getPublishDate(
+		wpparser.CommonFields{PostID: "15"}))
+}
+
+func TestMissingDateWithDatePath(t *testing.T) {
+	t.Parallel()
+	// The draft of the fixture has no publish date, it should neither panic nor be organized by date
+	siteDir := generateFixtureSite(t, integrationFixture{name: "classic"},
+		Options{DatePath: ":year/:month", MissingDatePolicy: MissingDateOmit})
+	require.FileExists(t, siteDir+"/content/posts/unfinished-thoughts.md")
+
+	siteDir = generateFixtureSite(t, integrationFixture{name: "classic"},
+		Options{DatePath: ":year/:month", MissingDatePolicy: MissingDateLastModified})
+	require.FileExists(t, siteDir+"/content/posts/2024/03/unfinished-thoughts.md")
+}
+
+func TestDateSourceLocal(t *testing.T) {
+	t.Parallel()
+	// The trip was published at 01:30 on March 6th in UTC+05:30, still March 5th in UTC
+	info := parseFixture(t, integrationFixture{name: "classic", replacements: []string{
+		"<pubDate>Tue, 05 Mar 2024 10:00:00 +0000</pubDate>", "<pubDate>Tue, 05 Mar 2024 20:00:00 +0000</pubDate>",
+		"<wp:post_date><![CDATA[2024-03-05 10:00:00]]>", "<wp:post_date><![CDATA[2024-03-06 01:30:00]]>",
+		"<wp:post_date_gmt><![CDATA[2024-03-05 10:00:00]]>", "<wp:post_date_gmt><![CDATA[2024-03-05 20:00:00]]>",
+	}})
+
+	generate := func(source DateSource) string {
+		siteDir := t.TempDir()
+		generator := NewGenerator(siteDir, "", nil, false, false, false, false, *info,
+			Options{DatePath: ":year/:month/:day", DateSource: source})
+		require.NoError(t, generator.writeContent(context.Background(), siteDir, *info))
+		return siteDir
+	}
+
+	content, err := os.ReadFile(filepath.Join(generate(DateSourceGMT), "content", "posts", "2024", "03", "05", "a-trip-to-the-mountains.md"))
+	require.NoError(t, err)
+	require.Contains(t, string(content), "date: \"2024-03-05T20:00:00+00:00\"\n")
+
+	content, err = os.ReadFile(filepath.Join(generate(DateSourceLocal), "content", "posts", "2024", "03", "06", "a-trip-to-the-mountains.md"))
+	require.NoError(t, err)
+	require.Contains(t, string(content), "date: \"2024-03-06T01:30:00+05:30\"\n")
+
+	source, err := ParseDateSource("")
+	require.NoError(t, err)
+	require.Equal(t, DateSourceGMT, source)
+	_, err = ParseDateSource("utc")
+	require.Error(t, err)
+}
@@ -0,0 +1,269 @@
+package hugogenerator
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/rs/zerolog/log"
+)
+
+// Report summarizes the conversion, it is logged once the site has been generated
+type Report struct {
+	// Format and producer of the WordPress export, useful to diagnose odd exports
+	WXRVersion       string
+	WordPressVersion string
+
+	// Number of items of each post type in the export, converted or not, e.g. to pick the post types to convert only
+	PostTypes map[string]int
+	// Number of items of the post types which are not converted, internal to WordPress, e.g. the revisions, or unknown
+	SkippedPostTypes map[string]int
+	// Plugins which produced some of the content, detected from its postmeta and shortcodes, with what to enable or review
+	Plugins []wpparser.DetectedPlugin
+
+	// Downloaded images converted to WebP, see Options.ConvertImagesToWebP
+	ConvertedImages  int
+	ImageBytesBefore int64
+	ImageBytesAfter  int64
+
+	// Content written since the previous run, see Options.Incremental
+	AddedContent     int
+	ChangedContent   int
+	UnchangedContent int
+	RemovedContent   int
+
+	// Number of pages of each status routed into the drafts dir, see Options.DraftsDir
+	DraftsDirContent map[wpparser.PublishStatus]int
+
+	// Items which failed to parse or convert and were skipped, see Options.FailFast
+	SkippedItems []wpparser.SkippedItem
+
+	// Content images which failed to download, see Options.BrokenImages
+	BrokenImages []BrokenImage
+
+	// Absolute media links left as is, pointing to the WordPress site or elsewhere, see Options.NoMedia
+	NoMedia          bool
+	RemoteMediaLinks int
+
+	// Number of URLs rewritten by each host rewrite, keyed by its rule, e.g. "example.org=cdn.example.net",
+	// see Options.HostRewrites
+	RewrittenHosts map[string]int
+
+	// Content paginated with <!--nextpage--> tags, to review, see Options.NextPage
+	PaginatedContent []PaginatedContent
+
+	// Links of the content written with an empty body, e.g. an export whose content went missing, to review.
+	// The content built with a page builder is in PageBuilderContent instead.
+	EmptyContent []string
+
+	// Languages detected heuristically from the text of the content, to review, see Options.DetectLanguages
+	DetectedLanguages []DetectedLanguage
+
+	// Content built with a page builder, whose layout is not converted, to review
+	PageBuilderContent []PageBuilderContent
+
+	// Terms named the same in the categories and the tags, merged or renamed, see Options.TermCollisions
+	TermCollisions []TermCollision
+
+	// Number of pages each shortcode was stripped from, see hugopage.PageOptions.StripShortcodes
+	StrippedShortcodes map[string]int
+
+	// Timings and sizes of the conversion, a baseline for its performance, only logged at the debug level.
+	// The export is parsed before the generation, see Generator.AddParsedExport.
+	ParseDuration    time.Duration
+	ExportBytes      int64
+	ContentDuration  time.Duration
+	ConvertedContent int
+	ContentBytes     int64
+	DownloadedMedia  int
+	DownloadedBytes  int64
+}
+
+// PaginatedContent is the content paginated with <!--nextpage--> tags, and what became of it
+type PaginatedContent struct {
+	Link   string
+	Policy NextPagePolicy
+}
+
+func (r *Report) addImageConversion(sizeBefore int64, sizeAfter int64) {
+	r.ConvertedImages++
+	r.ImageBytesBefore += sizeBefore
+	r.ImageBytesAfter += sizeAfter
+}
+
+func (r *Report) addConvertedContent(contentBytes int) {
+	r.ConvertedContent++
+	r.ContentBytes += int64(contentBytes)
+}
+
+func (r *Report) addDownloadedMedia(bytes int64) {
+	r.DownloadedMedia++
+	r.DownloadedBytes += bytes
+}
+
+func (r *Report) addStrippedShortcodes(names []string) {
+	if len(names) == 0 {
+		return
+	}
+	if r.StrippedShortcodes == nil {
+		r.StrippedShortcodes = make(map[string]int)
+	}
+	for _, name := range names {
+		r.StrippedShortcodes[name]++
+	}
+}
+
+func (r *Report) log() {
+	log.Info().
+		Str("wxrVersion", r.WXRVersion).
+		Str("wordPressVersion", r.WordPressVersion).
+		Msg("WordPress export")
+	for _, postType := range slices.Sorted(maps.Keys(r.PostTypes)) {
+		log.Info().
+			Str("postType", postType).
+			Int("items", r.PostTypes[postType]).
+			Msg("Post type in the export")
+	}
+	for _, postType := range slices.Sorted(maps.Keys(r.SkippedPostTypes)) {
+		log.Info().
+			Str("postType", postType).
+			Int("items", r.SkippedPostTypes[postType]).
+			Msg("Post type skipped")
+	}
+	for _, plugin := range r.Plugins {
+		event := log.Info()
+		if plugin.ConvertsPoorly {
+			event = log.Warn()
+		}
+		event.
+			Str("plugin", plugin.Name).
+			Int("items", plugin.Items).
+			Str("evidence", plugin.Evidence).
+			Str("hint", plugin.Hint).
+			Msg("Plugin detected in the export")
+	}
+	if r.AddedContent+r.ChangedContent+r.UnchangedContent+r.RemovedContent > 0 {
+		log.Info().
+			Int("added", r.AddedContent).
+			Int("changed", r.ChangedContent).
+			Int("unchanged", r.UnchangedContent).
+			Int("removed", r.RemovedContent).
+			Msg("Content updated since the previous run")
+	}
+	if r.ConvertedImages > 0 {
+		saved := r.ImageBytesBefore - r.ImageBytesAfter
+		log.Info().
+			Int("convertedImages", r.ConvertedImages).
+			Int64("bytesBefore", r.ImageBytesBefore).
+			Int64("bytesAfter", r.ImageBytesAfter).
+			Str("saved", fmt.Sprintf("%.1f MiB (%.0f%%)", float64(saved)/(1<<20),
+				100*float64(saved)/float64(r.ImageBytesBefore))).
+			Msg("Images converted to WebP")
+	}
+	if r.NoMedia {
+		log.Info().
+			Int("remoteMediaLinks", r.RemoteMediaLinks).
+			Msg("Media not downloaded, the media links point to the WordPress site")
+	}
+	for _, rule := range slices.Sorted(maps.Keys(r.RewrittenHosts)) {
+		log.Info().
+			Str("rule", rule).
+			Int("urls", r.RewrittenHosts[rule]).
+			Msg("Host rewritten")
+	}
+	for _, status := range slices.Sorted(maps.Keys(r.DraftsDirContent)) {
+		log.Info().
+			Str("status", string(status)).
+			Int("pages", r.DraftsDirContent[status]).
+			Msg("Content routed into the drafts dir")
+	}
+	for _, item := range r.SkippedItems {
+		log.Warn().
+			Str("postID", item.PostID).
+			Str("postType", item.PostType).
+			Str("title", item.Title).
+			Str("link", item.Link).
+			Str("error", item.Error).
+			Msg("Item skipped because of an error")
+	}
+	for _, image := range r.BrokenImages {
+		log.Warn().
+			Str("page", image.Page).
+			Str("link", image.Link).
+			Str("issue", image.Issue).
+			Msg("Broken image")
+	}
+	for _, content := range r.PaginatedContent {
+		log.Warn().
+			Str("link", content.Link).
+			Str("nextPage", string(content.Policy)).
+			Msg("Content paginated with <!--nextpage-->, review its conversion")
+	}
+	for _, link := range r.EmptyContent {
+		log.Warn().
+			Str("link", link).
+			Msg("Content with an empty body")
+	}
+	if len(r.EmptyContent) > 0 {
+		log.Warn().
+			Int("pages", len(r.EmptyContent)).
+			Msg("Content with an empty body, check that the content of the export is in <content:encoded>")
+	}
+	for _, detected := range r.DetectedLanguages {
+		log.Info().
+			Str("link", detected.Link).
+			Str("language", detected.Language).
+			Float64("confidence", detected.Confidence).
+			Msg("Language detected heuristically from the text, review it")
+	}
+	for _, content := range r.PageBuilderContent {
+		event := log.Warn().
+			Str("link", content.Link).
+			Str("builder", content.Builder)
+		switch {
+		case content.Empty:
+			event.Msg("Content built with a page builder is empty, its content is in the layout which is not converted")
+		case content.Extracted:
+			event.Msg("Content built with a page builder extracted from its layout, review its conversion")
+		default:
+			event.Msg("Content built with a page builder, its layout is not converted, review its conversion")
+		}
+	}
+	for _, collision := range r.TermCollisions {
+		log.Info().
+			Str("term", collision.Name).
+			Str("policy", string(collision.Policy)).
+			Str("from", collision.From).
+			Str("to", collision.To).
+			Msg("Term named the same in the categories and the tags")
+	}
+	for _, name := range slices.Sorted(maps.Keys(r.StrippedShortcodes)) {
+		log.Info().
+			Str("shortcode", name).
+			Int("pages", r.StrippedShortcodes[name]).
+			Msg("Stripped shortcode")
+	}
+	r.logMetrics()
+}
+
+func (r *Report) logMetrics() {
+	perSecond := func(count float64, duration time.Duration) float64 {
+		if duration <= 0 {
+			return 0
+		}
+		return count / duration.Seconds()
+	}
+	log.Debug().
+		Dur("parseDuration", r.ParseDuration).
+		Int64("exportBytes", r.ExportBytes).
+		Float64("exportBytesPerSec", perSecond(float64(r.ExportBytes), r.ParseDuration)).
+		Dur("contentDuration", r.ContentDuration).
+		Int("convertedContent", r.ConvertedContent).
+		Float64("contentPerSec", perSecond(float64(r.ConvertedContent), r.ContentDuration)).
+		Int64("contentBytes", r.ContentBytes).
+		Int("downloadedMedia", r.DownloadedMedia).
+		Int64("downloadedBytes", r.DownloadedBytes).
+		Msg("Conversion metrics")
+}
@@ -0,0 +1,49 @@
+package hugogenerator
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type staticMediaProvider struct{}
+
+func (staticMediaProvider) GetReader(_ context.Context, _ string) (io.Reader, error) {
+	return strings.NewReader("media"), nil
+}
+
+func TestReportMetrics(t *testing.T) {
+	t.Parallel()
+	info := parseFixture(t, integrationFixture{name: "classic"})
+	siteDir := t.TempDir()
+	generator := NewGenerator(siteDir, "", staticMediaProvider{}, true, false, false, false, *info, Options{})
+	generator.AddParsedExport(1_000, 0)
+	require.NoError(t, generator.writeContent(context.Background(), siteDir, *info))
+
+	report := generator.Report()
+	require.Equal(t, int64(1_000), report.ExportBytes)
+	require.Positive(t, report.ContentDuration)
+	require.Equal(t, len(generator.getWrittenContent(*info)), report.ConvertedContent)
+	contentBytes := 0
+	for _, content := range generator.getWrittenContent(*info) {
+		contentBytes += len(content.Content)
+	}
+	require.Equal(t, int64(contentBytes), report.ContentBytes)
+	require.Positive(t, report.DownloadedMedia)
+	require.Equal(t, int64(len("media")*report.DownloadedMedia), report.DownloadedBytes)
+}
+
+func TestReportPlugins(t *testing.T) {
+	t.Parallel()
+	info := parseFixture(t, integrationFixture{name: "woocommerce", customPostTypes: []string{"product", "product_variation"}})
+	generator := NewGenerator(t.TempDir(), "", nil, false, false, false, false, *info, Options{})
+
+	report := generator.Report()
+	require.Len(t, report.Plugins, 1)
+	require.Equal(t, "WooCommerce", report.Plugins[0].Name)
+	require.Contains(t, report.Plugins[0].Hint, "--woocommerce")
+	require.False(t, report.Plugins[0].ConvertsPoorly)
+}
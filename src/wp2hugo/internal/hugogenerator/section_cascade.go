@@ -0,0 +1,74 @@
+package hugogenerator
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/utils"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+)
+
+// ReadSectionCascades reads a YAML file mapping the content sections, e.g. "posts" or "products",
+// to the front matter cascaded to all their pages, e.g. `posts: {type: blog}`
+func ReadSectionCascades(filePath string) (map[string]map[string]any, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading section cascades: %w", err)
+	}
+	var cascades map[string]map[string]any
+	if err := yaml.Unmarshal(data, &cascades); err != nil {
+		return nil, fmt.Errorf("error parsing section cascades '%s': %w", filePath, err)
+	}
+	return cascades, nil
+}
+
+func validateSectionCascades(cascades map[string]map[string]any) error {
+	for section := range cascades {
+		if err := validateContentPath(strings.Trim(strings.TrimSpace(section), "/")); err != nil {
+			return fmt.Errorf("section cascade %q: %w", section, err)
+		}
+	}
+	return nil
+}
+
+// writeSectionCascades writes the _index.md of the sections with a `cascade` front matter,
+// e.g. content/posts/_index.md. It runs once the content is written, so that the page bundles
+// sanitization does not turn these _index.md into leaf bundles.
+func (g Generator) writeSectionCascades(siteDir string) error {
+	sections := make([]string, 0, len(g.options.SectionCascades))
+	for section := range g.options.SectionCascades {
+		sections = append(sections, section)
+	}
+	slices.Sort(sections)
+	for _, contentDir := range g.contentDirs(siteDir) {
+		for _, section := range sections {
+			sectionDir := path.Join(contentDir, strings.Trim(strings.TrimSpace(section), "/"))
+			if !utils.DirExists(sectionDir) {
+				log.Debug().
+					Str("sectionDir", sectionDir).
+					Msg("No content in the section, skipping its cascade")
+				continue
+			}
+			indexPath := path.Join(sectionDir, "_index.md")
+			if utils.FileExists(indexPath) {
+				log.Warn().
+					Str("indexPath", indexPath).
+					Msg("Section already has an _index.md, add the cascade to its front matter manually")
+				continue
+			}
+			frontMatter, err := utils.GetYAML(map[string]any{"cascade": g.options.SectionCascades[section]})
+			if err != nil {
+				return err
+			}
+			if err := writeFile(indexPath, fmt.Appendf(nil, "---\n%s---\n", frontMatter)); err != nil {
+				return err
+			}
+			log.Info().Msgf("Section cascade written: %s", indexPath)
+		}
+	}
+	return nil
+}
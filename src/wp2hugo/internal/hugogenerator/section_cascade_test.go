@@ -0,0 +1,58 @@
+package hugogenerator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSectionCascades(t *testing.T) {
+	t.Parallel()
+	siteDir := generateFixtureSite(t, integrationFixture{name: "classic"}, Options{
+		SectionCascades: map[string]map[string]any{
+			"posts":         {"type": "blog", "params": map[string]any{"showToc": true}},
+			"/pages/":       {"layout": "page"},
+			"pages/about":   {"layout": "team"},
+			"announcements": {"type": "news"},
+		},
+	})
+
+	content, err := os.ReadFile(filepath.Join(siteDir, "content", "posts", "_index.md"))
+	require.NoError(t, err)
+	require.Equal(t, "---\ncascade:\n  params:\n    showToc: true\n  type: blog\n---\n", string(content))
+
+	content, err = os.ReadFile(filepath.Join(siteDir, "content", "pages", "_index.md"))
+	require.NoError(t, err)
+	require.Equal(t, "---\ncascade:\n  layout: page\n---\n", string(content))
+
+	// The existing _index.md of the About page is left untouched
+	content, err = os.ReadFile(filepath.Join(siteDir, "content", "pages", "about", "_index.md"))
+	require.NoError(t, err)
+	require.NotContains(t, string(content), "cascade:")
+
+	// Sections without content are not created
+	require.NoDirExists(t, filepath.Join(siteDir, "content", "announcements"))
+}
+
+func TestValidateSectionCascades(t *testing.T) {
+	t.Parallel()
+	require.NoError(t, validateSectionCascades(map[string]map[string]any{"posts": {}, "/products/": {}}))
+	require.Error(t, validateSectionCascades(map[string]map[string]any{"../posts": {}}))
+	require.Error(t, validateSectionCascades(map[string]map[string]any{"": {}}))
+}
+
+func TestReadSectionCascades(t *testing.T) {
+	t.Parallel()
+	filePath := filepath.Join(t.TempDir(), "cascades.yaml")
+	require.NoError(t, os.WriteFile(filePath, []byte("posts:\n  type: blog\n"), 0o600))
+
+	cascades, err := ReadSectionCascades(filePath)
+	require.NoError(t, err)
+	require.Equal(t, map[string]map[string]any{"posts": {"type": "blog"}}, cascades)
+
+	require.NoError(t, os.WriteFile(filePath, []byte("posts: blog\n"), 0o600))
+	_, err = ReadSectionCascades(filePath)
+	require.Error(t, err)
+}
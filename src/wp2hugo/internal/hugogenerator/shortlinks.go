@@ -0,0 +1,35 @@
+package hugogenerator
+
+import (
+	"net/url"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+)
+
+// postLinks maps the post IDs to the URL of the migrated content, or of the media for the attachments
+type postLinks map[string]string
+
+func (l postLinks) GetPostLink(postID string) (string, bool) {
+	link, ok := l[postID]
+	return link, ok
+}
+
+// getPostLinks returns the URLs of the written content and the attachments by post ID,
+// which the shortlinks of the content, e.g. /?p=123, are replaced with
+func (g Generator) getPostLinks(info wpparser.WebsiteInfo) postLinks {
+	links := make(postLinks)
+	for _, attachment := range info.Attachments() {
+		if attachmentURL := attachment.GetAttachmentURL(); attachmentURL != nil {
+			links[attachment.PostID] = *attachmentURL
+		}
+	}
+	for _, content := range g.getWrittenContent(info) {
+		contentURL, err := url.Parse(content.Link)
+		// The content is reachable with its shortlink only, e.g. with the plain permalinks
+		if err != nil || contentURL.RawQuery != "" {
+			continue
+		}
+		links[content.PostID] = g.options.URLPrefix + contentURL.Path
+	}
+	return links
+}
@@ -0,0 +1,29 @@
+package hugogenerator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPostLinks(t *testing.T) {
+	t.Parallel()
+	websiteInfo := parseFixture(t, integrationFixture{name: "classic"})
+
+	options := Options{}
+	options.URLPrefix = "/blog"
+	generator := NewGenerator(t.TempDir(), "", nil, false, false, false, false, *websiteInfo, options)
+	link, ok := generator.postLinks.GetPostLink("10")
+	require.True(t, ok)
+	require.Equal(t, "/blog/2024/03/05/a-trip-to-the-mountains/", link)
+	link, ok = generator.postLinks.GetPostLink("20")
+	require.True(t, ok)
+	require.Equal(t, "/blog/about/", link)
+	// The attachments resolve to their media
+	link, ok = generator.postLinks.GetPostLink("11")
+	require.True(t, ok)
+	require.Equal(t, "https://example.org/wp-content/uploads/2024/03/summit.jpg", link)
+	// Only reachable with its shortlink
+	_, ok = generator.postLinks.GetPostLink("30")
+	require.False(t, ok)
+}
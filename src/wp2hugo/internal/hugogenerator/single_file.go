@@ -0,0 +1,264 @@
+package hugogenerator
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/adrg/frontmatter"
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/hugogenerator/hugopage"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	errIncrementalSingleFile = errors.New("incremental runs update the site of the previous run, they can't write a single file")
+	errSingleFileZipArchive  = errors.New("the single file and the zip archive are two kinds of output, only one can be written")
+	errSingleFileMedia       = errors.New("the single file has no media, its media links point to the WordPress site, it can't be combined with the media downloads, the WebP conversion or the assets dir")
+	errSingleFileSplitPages  = errors.New("the single file has the paginated posts on one page, it can't be combined with split pages")
+)
+
+// ATX headings up to h5, so that they can be nested one level deeper
+var _headingRegEx = regexp.MustCompile(`^#{1,5}(\s|$)`)
+
+// Order of the content types in the single file, the custom post types follow in alphabetical order
+var _singleFileTypeOrder = []string{"post", "page"}
+
+// singleFileEntry is a converted content of the single file, read back from the site
+type singleFileEntry struct {
+	index contentIndexEntry
+	date  string
+	meta  []string
+	body  string
+}
+
+// generateSingleFile generates the site into a temporary dir, and then concatenates its content
+// into the Markdown document Options.SingleFile, a section per content, e.g. for reading or grepping a whole blog.
+// The media links are kept pointing to the WordPress site, like with Options.NoMedia.
+func (g Generator) generateSingleFile(ctx context.Context) error {
+	switch {
+	case g.options.Incremental:
+		return errIncrementalSingleFile
+	case g.options.OutputZip != "":
+		return errSingleFileZipArchive
+	case g.downloadMedia || g.downloadAll || g.options.ConvertImagesToWebP || g.options.AssetsDir != "":
+		return errSingleFileMedia
+	case g.options.NextPage == NextPageSplit:
+		return errSingleFileSplitPages
+	}
+	fileDir, err := createOutputDir(filepath.Dir(g.options.SingleFile))
+	if err != nil {
+		return err
+	}
+	filePath := filepath.Join(fileDir, filepath.Base(g.options.SingleFile))
+	tempDir, err := os.MkdirTemp("", "wp2hugo-site-")
+	if err != nil {
+		return fmt.Errorf("error creating temporary dir: %w", err)
+	}
+	defer func() {
+		if err := os.RemoveAll(tempDir); err != nil {
+			log.Warn().
+				Err(err).
+				Str("dir", tempDir).
+				Msg("error removing temporary dir")
+		}
+	}()
+
+	g.outputDirPath = tempDir
+	g.options.SingleFile = ""
+	g.options.NoMedia = true
+	g.report.NoMedia = true
+	g.options.SiteName = g.getSiteName()
+	if g.index == nil {
+		// The written content is listed by the content index, which is only written with Options.Index
+		g.index = &contentIndex{}
+	}
+	if err := g.Generate(ctx); err != nil {
+		return err
+	}
+	return g.writeSingleFile(path.Join(tempDir, g.options.SiteName), filePath)
+}
+
+// writeSingleFile concatenates the content written into siteDir, listed by the content index, into filePath:
+// the posts by date, then the pages and the custom posts, each one under a heading with its front matter
+// summarized below, and a table of contents with Options.SingleFileTOC
+func (g Generator) writeSingleFile(siteDir string, filePath string) error {
+	entries := make([]singleFileEntry, 0, len(g.index.entries))
+	for _, indexEntry := range g.index.entries {
+		indexEntry.Path = getWrittenPath(siteDir, indexEntry.Path)
+		entry, err := readSingleFileEntry(siteDir, indexEntry)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, entry)
+	}
+	slices.SortFunc(entries, compareSingleFileEntries)
+
+	var sb strings.Builder
+	sb.WriteString("# " + cmp.Or(strings.TrimSpace(g.wpInfo.Title()), "WordPress export") + "\n")
+	if g.options.SingleFileTOC && len(entries) > 0 {
+		sb.WriteString("\n## Contents\n\n")
+		for _, entry := range entries {
+			item := fmt.Sprintf("- [%s](#%s)", escapeLinkText(getSingleFileTitle(entry)), getSingleFileAnchor(entry))
+			if entry.date != "" {
+				item += " " + entry.date
+			}
+			sb.WriteString(item + "\n")
+		}
+	}
+	for _, entry := range entries {
+		sb.WriteString("\n---\n\n")
+		sb.WriteString(fmt.Sprintf("<a id=\"%s\"></a>\n\n", getSingleFileAnchor(entry)))
+		sb.WriteString("## " + getSingleFileTitle(entry) + "\n\n")
+		for _, meta := range entry.meta {
+			sb.WriteString("- " + meta + "\n")
+		}
+		if body := strings.TrimSpace(entry.body); body != "" {
+			sb.WriteString("\n" + demoteHeadings(body) + "\n")
+		}
+	}
+	if err := os.WriteFile(filePath, []byte(sb.String()), 0o644); err != nil {
+		return fmt.Errorf("error writing single file: %w", err)
+	}
+	log.Info().
+		Str("location", filePath).
+		Int("entries", len(entries)).
+		Msg("Content written into the single file")
+	return nil
+}
+
+// readSingleFileEntry reads the content written at the path of the index entry,
+// its front matter becomes the lines listed below its heading
+func readSingleFileEntry(siteDir string, indexEntry contentIndexEntry) (singleFileEntry, error) {
+	data, err := os.ReadFile(path.Join(siteDir, indexEntry.Path))
+	if err != nil {
+		return singleFileEntry{}, fmt.Errorf("error reading content for the single file: %w", err)
+	}
+	var matter map[string]any
+	body, err := frontmatter.Parse(bytes.NewReader(data), &matter)
+	if err != nil {
+		return singleFileEntry{}, fmt.Errorf("error parsing the front matter of '%s': %w", indexEntry.Path, err)
+	}
+	entry := singleFileEntry{
+		index: indexEntry,
+		date:  getFrontMatterDate(matter["date"]),
+		body:  string(body),
+	}
+	if entry.date != "" {
+		entry.meta = append(entry.meta, "Date: "+entry.date)
+	}
+	if author := getFrontMatterList(matter["author"]); author != "" {
+		entry.meta = append(entry.meta, "Author: "+author)
+	}
+	if categories := getFrontMatterList(matter[hugopage.CategoryName]); categories != "" {
+		entry.meta = append(entry.meta, "Categories: "+categories)
+	}
+	if tags := getFrontMatterList(matter[hugopage.TagName]); tags != "" {
+		entry.meta = append(entry.meta, "Tags: "+tags)
+	}
+	if indexEntry.Status != "" && indexEntry.Status != "publish" {
+		entry.meta = append(entry.meta, "Status: "+indexEntry.Status)
+	}
+	if indexEntry.OriginalURL != "" {
+		entry.meta = append(entry.meta, "Original URL: <"+indexEntry.OriginalURL+">")
+	}
+	return entry, nil
+}
+
+// compareSingleFileEntries orders the posts first, then the pages and the custom posts, by date and then by path,
+// the content without a date last
+func compareSingleFileEntries(a, b singleFileEntry) int {
+	if c := cmp.Compare(getSingleFileTypeRank(a.index.Type), getSingleFileTypeRank(b.index.Type)); c != 0 {
+		return c
+	}
+	if c := strings.Compare(a.index.Type, b.index.Type); c != 0 {
+		return c
+	}
+	if (a.date == "") != (b.date == "") {
+		if a.date == "" {
+			return 1
+		}
+		return -1
+	}
+	return cmp.Or(strings.Compare(a.date, b.date), strings.Compare(a.index.Path, b.index.Path))
+}
+
+func getSingleFileTypeRank(postType string) int {
+	if rank := slices.Index(_singleFileTypeOrder, postType); rank >= 0 {
+		return rank
+	}
+	return len(_singleFileTypeOrder)
+}
+
+// getSingleFileAnchor returns the id of the section of the content, stable across runs
+func getSingleFileAnchor(entry singleFileEntry) string {
+	return cmp.Or(entry.index.Type, "content") + "-" + entry.index.PostID
+}
+
+func getSingleFileTitle(entry singleFileEntry) string {
+	title := strings.Join(strings.Fields(entry.index.Title), " ")
+	if title == "" {
+		return "(untitled)"
+	}
+	return title
+}
+
+// demoteHeadings nests the headings of the content under the heading of its section, e.g. "## Contact"
+// to "### Contact", the code blocks are left as is
+func demoteHeadings(markdown string) string {
+	lines := strings.Split(markdown, "\n")
+	inCodeBlock := false
+	for i, line := range lines {
+		if strings.HasPrefix(line, "```") || strings.HasPrefix(line, "~~~") {
+			inCodeBlock = !inCodeBlock
+			continue
+		}
+		if !inCodeBlock && _headingRegEx.MatchString(line) {
+			lines[i] = "#" + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func escapeLinkText(text string) string {
+	return strings.NewReplacer(`[`, `\[`, `]`, `\]`).Replace(text)
+}
+
+// getFrontMatterDate returns the day of the date front matter, e.g. "2024-03-05"
+func getFrontMatterDate(value any) string {
+	switch date := value.(type) {
+	case time.Time:
+		return date.Format(time.DateOnly)
+	case string:
+		if parsed, err := time.Parse(time.RFC3339, date); err == nil {
+			return parsed.Format(time.DateOnly)
+		}
+		return date
+	default:
+		return ""
+	}
+}
+
+// getFrontMatterList returns the values of a front matter string or list, comma-separated
+func getFrontMatterList(value any) string {
+	switch values := value.(type) {
+	case string:
+		return values
+	case []any:
+		strs := make([]string, 0, len(values))
+		for _, v := range values {
+			strs = append(strs, fmt.Sprint(v))
+		}
+		return strings.Join(strs, ", ")
+	default:
+		return ""
+	}
+}
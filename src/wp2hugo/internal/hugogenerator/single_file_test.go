@@ -0,0 +1,69 @@
+package hugogenerator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteSingleFile(t *testing.T) {
+	t.Parallel()
+	websiteInfo := parseFixture(t, integrationFixture{name: "classic"})
+	siteDir := t.TempDir()
+	generator := NewGenerator(siteDir, "", nil, false, false, false, false, *websiteInfo,
+		Options{NoMedia: true, SingleFileTOC: true})
+	generator.index = &contentIndex{}
+	require.NoError(t, generator.writeContent(context.Background(), siteDir, *websiteInfo))
+
+	filePath := filepath.Join(t.TempDir(), "blog.md")
+	require.NoError(t, generator.writeSingleFile(siteDir, filePath))
+	data, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	document := string(data)
+
+	require.True(t, strings.HasPrefix(document, "# "))
+	require.NotContains(t, document, "\n---\ntitle:", "the front matter is summarized, not copied")
+	require.Contains(t, document, "## Contents\n\n- [A trip to the mountains](#post-10) 2024-03-05\n")
+	require.Contains(t, document, "<a id=\"post-10\"></a>\n\n## A trip to the mountains\n\n- Date: 2024-03-05\n")
+	require.Contains(t, document, "- Original URL: <https://example.org/2024/03/05/a-trip-to-the-mountains/>\n")
+	// The posts come before the pages
+	require.Less(t, strings.Index(document, "<a id=\"post-10\">"), strings.Index(document, "<a id=\"page-20\">"))
+	require.Contains(t, document, "\n### Contact\n", "the headings of the content are nested under its heading")
+	require.Equal(t, len(generator.index.entries), strings.Count(document, "<a id="))
+}
+
+func TestDemoteHeadings(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, "### Contact\n\n```sh\n# comment\n```\n\n###### Deepest\n#hashtag",
+		demoteHeadings("## Contact\n\n```sh\n# comment\n```\n\n###### Deepest\n#hashtag"))
+}
+
+func TestSingleFileOptions(t *testing.T) {
+	t.Parallel()
+	websiteInfo := parseFixture(t, integrationFixture{name: "classic"})
+	testCases := []struct {
+		name          string
+		downloadMedia bool
+		options       Options
+		expected      error
+	}{
+		{name: "incremental", options: Options{Incremental: true, SiteName: "site"}, expected: errIncrementalSingleFile},
+		{name: "zip archive", options: Options{OutputZip: filepath.Join(t.TempDir(), "site.zip")}, expected: errSingleFileZipArchive},
+		{name: "media downloads", downloadMedia: true, expected: errSingleFileMedia},
+		{name: "split pages", options: Options{NextPage: NextPageSplit}, expected: errSingleFileSplitPages},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			options := testCase.options
+			options.SingleFile = filepath.Join(t.TempDir(), "blog.md")
+			generator := NewGenerator(t.TempDir(), "", nil, testCase.downloadMedia, false, false, false, *websiteInfo, options)
+			require.ErrorIs(t, generator.Generate(context.Background()), testCase.expected)
+			require.NoFileExists(t, options.SingleFile)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package hugogenerator
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"gopkg.in/yaml.v3"
+)
+
+// Params of the site icon and the custom logo in the Hugo config, as dotted paths under `params`,
+// defaulting to the ones of PaperMod, the theme of the generated site
+// Ref: https://github.com/adityatelange/hugo-PaperMod/wiki/Variables
+const (
+	DefaultFaviconParam = "assets.favicon"
+	DefaultLogoParam    = "label.icon"
+)
+
+func validateConfigParam(param string) error {
+	for key := range strings.SplitSeq(param, ".") {
+		if strings.TrimSpace(key) == "" {
+			return fmt.Errorf("invalid config param %q, expected a dotted path under params, e.g. %s", param, DefaultLogoParam)
+		}
+	}
+	return nil
+}
+
+// getSiteBranding returns the links of the site icon and the custom logo, keyed by their config param,
+// after downloading them with downloadMedia. The sites without them are skipped.
+func (g Generator) getSiteBranding(ctx context.Context, siteDir string, info wpparser.WebsiteInfo) (map[string]string, error) {
+	branding := make(map[string]string)
+	attachments := map[string]func() (wpparser.AttachmentInfo, bool){
+		g.options.FaviconParam: info.GetSiteIcon,
+		g.options.LogoParam:    info.GetCustomLogo,
+	}
+	for _, param := range slices.Sorted(maps.Keys(attachments)) {
+		attachment, ok := attachments[param]()
+		if !ok {
+			continue
+		}
+		link, err := g.getMediaLink(ctx, siteDir, info, *attachment.GetAttachmentURL())
+		if err != nil {
+			return nil, err
+		}
+		branding[param] = link
+	}
+	return branding, nil
+}
+
+// setConfigParams returns the config with the params set, keeping the order of its keys.
+// The params are dotted paths under `params`, e.g. "label.icon", replacing the value there if any.
+func setConfigParams(config *_HugoConfig, params map[string]string) (*yaml.Node, error) {
+	var node yaml.Node
+	if err := node.Encode(config); err != nil {
+		return nil, fmt.Errorf("error encoding config: %w", err)
+	}
+	for _, param := range slices.Sorted(maps.Keys(params)) {
+		setMappingValue(&node, append([]string{"params"}, strings.Split(param, ".")...), params[param])
+	}
+	return &node, nil
+}
+
+func setMappingValue(node *yaml.Node, keys []string, value string) {
+	var child *yaml.Node
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == keys[0] {
+			child = node.Content[i+1]
+			break
+		}
+	}
+	if child == nil {
+		child = &yaml.Node{}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: keys[0]}, child)
+	}
+	if len(keys) == 1 {
+		*child = yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+		return
+	}
+	if child.Kind != yaml.MappingNode {
+		*child = yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	}
+	setMappingValue(child, keys[1:], value)
+}
@@ -0,0 +1,61 @@
+package hugogenerator
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSiteBranding(t *testing.T) {
+	t.Parallel()
+	// No site icon nor custom logo
+	info := parseFixture(t, integrationFixture{name: "classic"})
+	generator := NewGenerator(t.TempDir(), "", nil, false, false, false, false, *info, Options{})
+	branding, err := generator.getSiteBranding(context.Background(), t.TempDir(), *info)
+	require.NoError(t, err)
+	require.Empty(t, branding)
+
+	info = parseFixture(t, integrationFixture{name: "classic", replacements: []string{
+		"<wp:attachment_url><![CDATA[https://example.org/wp-content/uploads/2024/03/summit.jpg]]></wp:attachment_url>",
+		"<wp:attachment_url><![CDATA[https://example.org/wp-content/uploads/2024/03/summit.jpg]]></wp:attachment_url>" +
+			"<wp:postmeta><wp:meta_key><![CDATA[_wp_attachment_context]]></wp:meta_key><wp:meta_value><![CDATA[site-icon]]></wp:meta_value></wp:postmeta>",
+	}})
+	_, ok := info.GetCustomLogo()
+	require.False(t, ok)
+
+	generator = NewGenerator(t.TempDir(), "", nil, false, false, false, false, *info, Options{})
+	branding, err = generator.getSiteBranding(context.Background(), t.TempDir(), *info)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{DefaultFaviconParam: "/wp-content/uploads/2024/03/summit.jpg"}, branding)
+
+	generator = NewGenerator(t.TempDir(), "", nil, false, false, false, false, *info, Options{FaviconParam: "favicon"})
+	branding, err = generator.getSiteBranding(context.Background(), t.TempDir(), *info)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"favicon": "/wp-content/uploads/2024/03/summit.jpg"}, branding)
+
+	require.Error(t, validateConfigParam("label..icon"))
+	require.NoError(t, validateConfigParam("logo"))
+}
+
+func TestSetConfigParams(t *testing.T) {
+	t.Parallel()
+	var config _HugoConfig
+	config.Title = "Example"
+	config.Params.Description = "An example"
+	config.Params.Assets.Favicon = "/favicon.ico"
+	node, err := setConfigParams(&config, map[string]string{
+		DefaultFaviconParam: "/icon.png",
+		DefaultLogoParam:    "/logo.png",
+	})
+	require.NoError(t, err)
+	data, err := utils.GetYAML(node)
+	require.NoError(t, err)
+	require.Contains(t, string(data), "  assets:\n    favicon: /icon.png\n    disableHLJS: false\n")
+	require.Contains(t, string(data), "  label:\n    icon: /logo.png\n")
+	// The order of the keys is kept
+	require.Less(t, strings.Index(string(data), "title:"), strings.Index(string(data), "params:"))
+	require.Less(t, strings.Index(string(data), "description:"), strings.Index(string(data), "label:"))
+}
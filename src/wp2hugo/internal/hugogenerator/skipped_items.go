@@ -0,0 +1,54 @@
+package hugogenerator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"syscall"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/rs/zerolog/log"
+)
+
+// errContentConversion marks the errors converting a content, as opposed to writing it,
+// e.g. a malformed block or a failing front matter hook
+var errContentConversion = errors.New("error converting content")
+
+// conversionError wraps the error of a conversion step, see skipContent
+func conversionError(err error) error {
+	return fmt.Errorf("%w: %w", errContentConversion, err)
+}
+
+// skipContent records the content as skipped because of the conversion error, unless Options.FailFast is set.
+// The other errors are returned, e.g. a full disk or a cancelled conversion, they would fail the next content too.
+func (g Generator) skipContent(page wpparser.CommonFields, err error) error {
+	if g.options.FailFast || !errors.Is(err, errContentConversion) || isIOError(err) ||
+		errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	postType := ""
+	if page.PostType != nil {
+		postType = *page.PostType
+	}
+	log.Warn().
+		Err(err).
+		Str("postID", page.PostID).
+		Str("link", page.Link).
+		Msg("Skipping content which failed to convert")
+	g.report.SkippedItems = append(g.report.SkippedItems, wpparser.SkippedItem{
+		PostID:   page.PostID,
+		PostType: postType,
+		Title:    page.Title,
+		Link:     page.Link,
+		Error:    err.Error(),
+	})
+	return nil
+}
+
+// isIOError reports whether the error comes from the file system, even if a conversion step returned it
+func isIOError(err error) bool {
+	var pathErr *fs.PathError
+	var errno syscall.Errno
+	return errors.As(err, &pathErr) || errors.As(err, &errno)
+}
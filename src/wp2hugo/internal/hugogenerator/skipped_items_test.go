@@ -0,0 +1,69 @@
+package hugogenerator
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSkippedContent(t *testing.T) {
+	t.Parallel()
+	info := parseFixture(t, integrationFixture{name: "classic"})
+	failingHook := func(post *wpparser.PostInfo, _ map[string]any) error {
+		if post.PostID == "10" {
+			return errors.New("malformed post")
+		}
+		return nil
+	}
+
+	siteDir := t.TempDir()
+	generator := NewGenerator(siteDir, "", nil, false, false, false, false, *info, Options{FrontMatterHook: failingHook})
+	require.NoError(t, generator.writeContent(context.Background(), siteDir, *info))
+	skippedItems := generator.Report().SkippedItems
+	require.Len(t, skippedItems, 1)
+	require.Equal(t, "10", skippedItems[0].PostID)
+	require.Equal(t, "post", skippedItems[0].PostType)
+	require.Equal(t, "https://example.org/2024/03/05/a-trip-to-the-mountains/", skippedItems[0].Link)
+	require.Contains(t, skippedItems[0].Error, "malformed post")
+	// The rest of the content is written
+	require.FileExists(t, filepath.Join(siteDir, "content", "pages", "about", "_index.md"))
+
+	// Not skipped when cancelled, nor on the errors which are not about the content, e.g. a full disk
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(t, generator.skipContent(info.Posts()[0].CommonFields, ctx.Err()), context.Canceled)
+	diskFull := &fs.PathError{Op: "write", Path: "index.md", Err: syscall.ENOSPC}
+	require.ErrorIs(t, generator.skipContent(info.Posts()[0].CommonFields, diskFull), syscall.ENOSPC)
+	require.ErrorIs(t, generator.skipContent(info.Posts()[0].CommonFields, conversionError(diskFull)), syscall.ENOSPC,
+		"wrapped conversion error")
+	require.Len(t, generator.Report().SkippedItems, 1)
+}
+
+func TestSkippedContentParts(t *testing.T) {
+	t.Parallel()
+	info := parseFixture(t, integrationFixture{name: "classic", replacements: []string{
+		"<blockquote><p>The mountains are calling.</p></blockquote>",
+		"<!--nextpage--><blockquote><p>The mountains are calling.</p></blockquote>",
+	}})
+	failingHook := func(_ *wpparser.PostInfo, metadata map[string]any) error {
+		if metadata["part"] == 2 {
+			return errors.New("malformed part")
+		}
+		return nil
+	}
+
+	siteDir := t.TempDir()
+	generator := NewGenerator(siteDir, "", nil, false, false, false, false, *info,
+		Options{NextPage: NextPageSplit, FrontMatterHook: failingHook})
+	require.NoError(t, generator.writeContent(context.Background(), siteDir, *info))
+	require.Len(t, generator.Report().SkippedItems, 1)
+	// The first part, written before the second one failed, is removed too
+	require.NoFileExists(t, filepath.Join(siteDir, "content", "posts", "a-trip-to-the-mountains.md"))
+	require.NoFileExists(t, filepath.Join(siteDir, "content", "posts", "a-trip-to-the-mountains-page-2.md"))
+}
@@ -0,0 +1,54 @@
+package hugogenerator
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/hugogenerator/hugopage"
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/rs/zerolog/log"
+)
+
+// Front matter keys with a meaning for Hugo, or written by wp2hugo
+// Ref: https://gohugo.io/content-management/front-matter/#fields
+var _reservedFrontMatterKeys = []string{
+	"aliases", "build", "cascade", "date", "description", "draft", "expiryDate", "headless", "isCJKLanguage",
+	"lastmod", "layout", "linkTitle", "markup", "menus", "outputs", "params", "publishDate", "resources",
+	"sitemap", "slug", "summary", "title", "translationKey", "type", "url", "weight",
+	"author", "comment_count", "comments", "cover", "guid", "images", "lang", "parent_post_id", "post_id", "robots",
+}
+
+// ParseTaxonomyKeys parses a CSV list of taxonomy=key pairs, e.g. "categories=category,tags=keywords"
+func ParseTaxonomyKeys(mapping string) (map[string]string, error) {
+	taxonomyKeys := make(map[string]string)
+	if strings.TrimSpace(mapping) == "" {
+		return taxonomyKeys, nil
+	}
+	for pair := range strings.SplitSeq(mapping, ",") {
+		taxonomy, key, ok := strings.Cut(pair, "=")
+		taxonomy, key = strings.TrimSpace(taxonomy), strings.TrimSpace(key)
+		if !ok || taxonomy == "" || key == "" {
+			return nil, fmt.Errorf("invalid taxonomy key mapping %q, expected taxonomy=key, e.g. tags=keywords", pair)
+		}
+		if _, ok := taxonomyKeys[taxonomy]; ok {
+			return nil, fmt.Errorf("taxonomy %q is mapped more than once", taxonomy)
+		}
+		taxonomyKeys[taxonomy] = key
+	}
+	return taxonomyKeys, nil
+}
+
+// warnReservedTaxonomyKeys warns about the taxonomies emitted as a reserved front matter key,
+// which Hugo would not read as a taxonomy
+func warnReservedTaxonomyKeys(info wpparser.WebsiteInfo, options hugopage.PageOptions) {
+	taxonomies := append([]string{hugopage.CategoryName, hugopage.TagName}, info.TaxonomyNames()...)
+	for _, taxonomy := range taxonomies {
+		if key := options.TaxonomyKey(taxonomy); slices.Contains(_reservedFrontMatterKeys, key) {
+			log.Warn().
+				Str("taxonomy", taxonomy).
+				Str("key", key).
+				Msg("Taxonomy front matter key is reserved, rename it with --taxonomy-keys")
+		}
+	}
+}
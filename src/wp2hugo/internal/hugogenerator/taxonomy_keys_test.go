@@ -0,0 +1,28 @@
+package hugogenerator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTaxonomyKeys(t *testing.T) {
+	t.Parallel()
+
+	taxonomyKeys, err := ParseTaxonomyKeys(" categories=category, tags = keywords,product_cat=product_categories")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"categories":  "category",
+		"tags":        "keywords",
+		"product_cat": "product_categories",
+	}, taxonomyKeys)
+
+	taxonomyKeys, err = ParseTaxonomyKeys("")
+	require.NoError(t, err)
+	require.Empty(t, taxonomyKeys)
+
+	for _, mapping := range []string{"tags", "tags=", "=keywords", "tags=a,tags=b"} {
+		_, err = ParseTaxonomyKeys(mapping)
+		require.Error(t, err, mapping)
+	}
+}
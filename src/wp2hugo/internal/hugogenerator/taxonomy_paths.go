@@ -0,0 +1,79 @@
+package hugogenerator
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/hugogenerator/hugopage"
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+)
+
+// ParseTaxonomyPaths parses a CSV list of taxonomy=path pairs, e.g. "categories=topics,tags=labels/tags"
+func ParseTaxonomyPaths(mapping string) (map[string]string, error) {
+	taxonomyPaths := make(map[string]string)
+	if strings.TrimSpace(mapping) == "" {
+		return taxonomyPaths, nil
+	}
+	for pair := range strings.SplitSeq(mapping, ",") {
+		taxonomy, taxonomyPath, ok := strings.Cut(pair, "=")
+		taxonomy, taxonomyPath = strings.TrimSpace(taxonomy), strings.Trim(strings.TrimSpace(taxonomyPath), "/")
+		if !ok || taxonomy == "" || taxonomyPath == "" {
+			return nil, fmt.Errorf("invalid taxonomy path mapping %q, expected taxonomy=path, e.g. categories=topics", pair)
+		}
+		if _, ok := taxonomyPaths[taxonomy]; ok {
+			return nil, fmt.Errorf("taxonomy %q is mapped more than once", taxonomy)
+		}
+		taxonomyPaths[taxonomy] = taxonomyPath
+	}
+	return taxonomyPaths, nil
+}
+
+// getTaxonomyPath returns the URL path of the taxonomy, without slashes, e.g. "topics" for /topics/,
+// which defaults to its front matter key, like Hugo does
+func (options Options) getTaxonomyPath(taxonomy string) string {
+	if taxonomyPath, ok := options.TaxonomyPaths[taxonomy]; ok {
+		return taxonomyPath
+	}
+	return options.TaxonomyKey(taxonomy)
+}
+
+// validateTaxonomyPaths verifies that the taxonomies of the export don't share a URL path,
+// including the default ones of the taxonomies which are not mapped, and that the paths stay within the site
+func validateTaxonomyPaths(info wpparser.WebsiteInfo, options Options) error {
+	for _, taxonomy := range slices.Sorted(maps.Keys(options.TaxonomyPaths)) {
+		if err := validateContentPath(options.TaxonomyPaths[taxonomy]); err != nil {
+			return fmt.Errorf("taxonomy %s: %w", taxonomy, err)
+		}
+	}
+	taxonomies := append([]string{hugopage.CategoryName, hugopage.TagName}, info.TaxonomyNames()...)
+	taxonomies = append(taxonomies, slices.Sorted(maps.Keys(options.TaxonomyPaths))...)
+	slices.Sort(taxonomies)
+	byPath := make(map[string]string)
+	for _, taxonomy := range slices.Compact(taxonomies) {
+		taxonomyPath := options.getTaxonomyPath(taxonomy)
+		if other, ok := byPath[taxonomyPath]; ok {
+			return fmt.Errorf("taxonomies %s and %s both have the path /%s/, map one of them with --taxonomy-paths",
+				other, taxonomy, taxonomyPath)
+		}
+		byPath[taxonomyPath] = taxonomy
+	}
+	return nil
+}
+
+// getTaxonomyPermalinks returns the permalinks of the taxonomy and term pages of the mapped taxonomies,
+// keyed by the taxonomy name of the Hugo config, aka the front matter key
+func getTaxonomyPermalinks(options Options) (map[string]string, map[string]string) {
+	if len(options.TaxonomyPaths) == 0 {
+		return nil, nil
+	}
+	taxonomyPermalinks := make(map[string]string, len(options.TaxonomyPaths))
+	termPermalinks := make(map[string]string, len(options.TaxonomyPaths))
+	for taxonomy, taxonomyPath := range options.TaxonomyPaths {
+		key := options.TaxonomyKey(taxonomy)
+		taxonomyPermalinks[key] = "/" + taxonomyPath + "/"
+		termPermalinks[key] = "/" + taxonomyPath + "/:slug/"
+	}
+	return taxonomyPermalinks, termPermalinks
+}
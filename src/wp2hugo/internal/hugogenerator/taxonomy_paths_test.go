@@ -0,0 +1,54 @@
+package hugogenerator
+
+import (
+	"testing"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/hugogenerator/hugopage"
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTaxonomyPaths(t *testing.T) {
+	t.Parallel()
+
+	taxonomyPaths, err := ParseTaxonomyPaths(" categories=/topics/, tags = labels/tags")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"categories": "topics", "tags": "labels/tags"}, taxonomyPaths)
+
+	for _, mapping := range []string{"tags", "tags=/", "=topics", "tags=a,tags=b"} {
+		_, err = ParseTaxonomyPaths(mapping)
+		require.Error(t, err, mapping)
+	}
+}
+
+func TestValidateTaxonomyPaths(t *testing.T) {
+	t.Parallel()
+	info := wpparser.WebsiteInfo{}
+
+	require.NoError(t, validateTaxonomyPaths(info, Options{TaxonomyPaths: map[string]string{"categories": "topics"}}))
+	// Swapped paths don't collide
+	require.NoError(t, validateTaxonomyPaths(info, Options{TaxonomyPaths: map[string]string{"categories": "tags", "tags": "categories"}}))
+
+	require.Error(t, validateTaxonomyPaths(info, Options{TaxonomyPaths: map[string]string{"categories": "tags"}}))
+	require.Error(t, validateTaxonomyPaths(info, Options{TaxonomyPaths: map[string]string{"categories": "topics", "tags": "topics"}}))
+	require.Error(t, validateTaxonomyPaths(info, Options{TaxonomyPaths: map[string]string{"categories": "../topics"}}))
+	// The default path follows the front matter key
+	require.Error(t, validateTaxonomyPaths(info, Options{
+		TaxonomyPaths: map[string]string{"categories": "keywords"},
+		PageOptions:   hugopage.PageOptions{TaxonomyKeys: map[string]string{"tags": "keywords"}},
+	}))
+}
+
+func TestTaxonomyPermalinks(t *testing.T) {
+	t.Parallel()
+	taxonomyPermalinks, termPermalinks := getTaxonomyPermalinks(Options{
+		TaxonomyPaths: map[string]string{"categories": "topics"},
+		PageOptions:   hugopage.PageOptions{TaxonomyKeys: map[string]string{"categories": "category"}},
+	})
+	require.Equal(t, map[string]string{"category": "/topics/"}, taxonomyPermalinks)
+	require.Equal(t, map[string]string{"category": "/topics/:slug/"}, termPermalinks)
+
+	taxonomyPermalinks, termPermalinks = getTaxonomyPermalinks(Options{})
+	require.Nil(t, taxonomyPermalinks)
+	require.Nil(t, termPermalinks)
+}
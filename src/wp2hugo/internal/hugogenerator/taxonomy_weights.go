@@ -0,0 +1,86 @@
+package hugogenerator
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/hugogenerator/hugopage"
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+)
+
+// orderedTerm is a taxonomy term, in the order of the export
+type orderedTerm struct {
+	// Name of the term in the front matter, Hugo urlizes it into the term page path
+	name  string
+	order *int
+	meta  map[string]string
+}
+
+// getTermWeights returns the weight of each term, from 1, following their custom order if they have one,
+// or else their order in the export. The terms without a custom order come after the ones with one.
+func getTermWeights(terms []orderedTerm) map[string]int {
+	terms = slices.Clone(terms)
+	slices.SortStableFunc(terms, func(a, b orderedTerm) int {
+		switch {
+		case a.order == nil && b.order == nil:
+			return 0
+		case a.order == nil:
+			return 1
+		case b.order == nil:
+			return -1
+		default:
+			return cmp.Compare(*a.order, *b.order)
+		}
+	})
+	weights := make(map[string]int, len(terms))
+	for _, term := range terms {
+		if _, ok := weights[term.name]; !ok {
+			weights[term.name] = len(weights) + 1
+		}
+	}
+	return weights
+}
+
+// getOrderedTerms returns the terms of the categories, the tags and the custom taxonomies,
+// keyed by taxonomy, once renamed, see Options.TermCollisions
+func getOrderedTerms(info wpparser.WebsiteInfo, renames termRenames) map[string][]orderedTerm {
+	terms := make(map[string][]orderedTerm)
+	add := func(taxonomy string, name string, order *int, meta map[string]string) {
+		term := renames.get(taxonomy, name)
+		terms[term.taxonomy] = append(terms[term.taxonomy], orderedTerm{term.name, order, meta})
+	}
+	for _, category := range info.Categories() {
+		add(hugopage.CategoryName, category.Name, category.Order, category.Meta)
+	}
+	for _, tag := range info.Tags() {
+		add(hugopage.TagName, tag.Name, tag.Order, tag.Meta)
+	}
+	for _, taxonomy := range info.Taxonomies() {
+		terms[taxonomy.Taxonomy] = append(terms[taxonomy.Taxonomy], orderedTerm{taxonomy.Name, taxonomy.Order, taxonomy.Meta})
+	}
+	return terms
+}
+
+// getUsedTerms returns the terms of the written content keyed by taxonomy, the other terms have no term page
+func (g Generator) getUsedTerms(info wpparser.WebsiteInfo) map[string]map[string]bool {
+	used := make(map[string]map[string]bool)
+	add := func(taxonomy string, name string) {
+		if used[taxonomy] == nil {
+			used[taxonomy] = make(map[string]bool)
+		}
+		used[taxonomy][name] = true
+	}
+	for _, content := range g.getWrittenContent(info) {
+		categories, tags := g.termRenames.apply(content.Categories, content.Tags)
+		for _, category := range categories {
+			add(hugopage.CategoryName, category)
+		}
+		for _, tag := range tags {
+			add(hugopage.TagName, tag)
+		}
+		for _, taxonomy := range content.Taxonomies {
+			add(taxonomy.Taxonomy, taxonomy.Name)
+		}
+	}
+	return used
+}
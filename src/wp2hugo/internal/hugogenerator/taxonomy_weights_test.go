@@ -0,0 +1,46 @@
+package hugogenerator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/hugogenerator/hugopage"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaxonomyWeights(t *testing.T) {
+	t.Parallel()
+	siteDir := generateFixtureSite(t, integrationFixture{name: "classic"}, Options{
+		PageOptions:     hugopage.PageOptions{TaxonomyKeys: map[string]string{hugopage.TagName: "keywords"}},
+		TaxonomyWeights: true,
+	})
+
+	// Same order as the export
+	content, err := os.ReadFile(filepath.Join(siteDir, "content", "categories", "general", "_index.md"))
+	require.NoError(t, err)
+	require.Equal(t, "---\nweight: 1\n---\n", string(content))
+	content, err = os.ReadFile(filepath.Join(siteDir, "content", "categories", "travel", "_index.md"))
+	require.NoError(t, err)
+	require.Equal(t, "---\nweight: 2\n---\n", string(content))
+
+	content, err = os.ReadFile(filepath.Join(siteDir, "content", "keywords", "photos", "_index.md"))
+	require.NoError(t, err)
+	require.Equal(t, "---\nweight: 1\n---\n", string(content))
+
+	require.NoDirExists(t, filepath.Join(generateFixtureSite(t, integrationFixture{name: "classic"}, Options{}), "content", "categories"))
+}
+
+func TestGetTermWeights(t *testing.T) {
+	t.Parallel()
+	order := func(value int) *int {
+		return &value
+	}
+	require.Equal(t, map[string]int{"shirts": 1, "hats": 2, "shoes": 3, "socks": 4},
+		getTermWeights([]orderedTerm{
+			{"shoes", nil, nil},
+			{"hats", order(5), nil},
+			{"socks", nil, nil},
+			{"shirts", order(-1), nil},
+		}))
+}
@@ -0,0 +1,137 @@
+package hugogenerator
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/hugogenerator/hugopage"
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+)
+
+// TermCollisionPolicy decides what becomes of the terms named the same in the categories and the tags,
+// e.g. a "Go" category and a "Go" tag, which Hugo renders as two term pages of the same title
+type TermCollisionPolicy string
+
+const (
+	// TermCollisionsSeparate keeps both terms, like WordPress
+	TermCollisionsSeparate TermCollisionPolicy = "separate"
+	// TermCollisionsMerge moves the term of the other taxonomy into the term of Options.TermCollisionTarget
+	TermCollisionsMerge TermCollisionPolicy = "merge"
+	// TermCollisionsSuffix renames the term of the other taxonomy with its taxonomy, e.g. "go-tag"
+	TermCollisionsSuffix TermCollisionPolicy = "suffix"
+)
+
+func ParseTermCollisionPolicy(policy string) (TermCollisionPolicy, error) {
+	switch TermCollisionPolicy(policy) {
+	case TermCollisionsSeparate, TermCollisionsMerge, TermCollisionsSuffix:
+		return TermCollisionPolicy(policy), nil
+	case "":
+		return TermCollisionsSeparate, nil
+	default:
+		return "", fmt.Errorf("unknown term collision policy %q, expected one of %s, %s, %s",
+			policy, TermCollisionsSeparate, TermCollisionsMerge, TermCollisionsSuffix)
+	}
+}
+
+// Singular of the taxonomies, suffixed to the renamed terms
+var _termSuffixes = map[string]string{
+	hugopage.CategoryName: "category",
+	hugopage.TagName:      "tag",
+}
+
+func validateTermCollisionTarget(target string) error {
+	if _, ok := _termSuffixes[target]; !ok {
+		return fmt.Errorf("unknown term collision target %q, expected one of %s, %s",
+			target, hugopage.CategoryName, hugopage.TagName)
+	}
+	return nil
+}
+
+// TermCollision is a term named the same in the categories and the tags, and what became of it
+type TermCollision struct {
+	Name   string
+	Policy TermCollisionPolicy
+	// Term of the other taxonomy, and the term it became, e.g. "tags/go" and "categories/go"
+	From string
+	To   string
+}
+
+// termRename is the term a term of the content becomes
+type termRename struct {
+	taxonomy string
+	name     string
+}
+
+// termRenames maps the taxonomy, CategoryName or TagName, and the name of the renamed terms to their new term
+type termRenames map[string]map[string]termRename
+
+// getTermRenames returns the renames of the terms named the same in the categories and the tags of the written content,
+// following Options.TermCollisions, and records them in the Report
+func (g Generator) getTermRenames(info wpparser.WebsiteInfo) termRenames {
+	policy := g.options.TermCollisions
+	if policy != TermCollisionsMerge && policy != TermCollisionsSuffix {
+		return nil
+	}
+	target := g.options.TermCollisionTarget
+	if validateTermCollisionTarget(target) != nil {
+		return nil
+	}
+	other := hugopage.TagName
+	if target == hugopage.TagName {
+		other = hugopage.CategoryName
+	}
+
+	used := g.getUsedTerms(info)
+	names := make([]string, 0)
+	for name := range used[other] {
+		if used[target][name] {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	slices.Sort(names)
+	renames := termRenames{other: make(map[string]termRename, len(names))}
+	for _, name := range names {
+		rename := termRename{taxonomy: target, name: name}
+		if policy == TermCollisionsSuffix {
+			rename = termRename{taxonomy: other, name: name + "-" + _termSuffixes[other]}
+		}
+		renames[other][name] = rename
+		g.report.TermCollisions = append(g.report.TermCollisions, TermCollision{
+			Name:   name,
+			Policy: policy,
+			From:   other + "/" + name,
+			To:     rename.taxonomy + "/" + rename.name,
+		})
+	}
+	return renames
+}
+
+// apply returns the categories and the tags of a page once their terms are renamed, without duplicates
+func (r termRenames) apply(categories []string, tags []string) ([]string, []string) {
+	if len(r) == 0 {
+		return categories, tags
+	}
+	terms := make(map[string][]string, 2)
+	add := func(taxonomy string, names []string) {
+		for _, name := range names {
+			term := r.get(taxonomy, name)
+			if !slices.Contains(terms[term.taxonomy], term.name) {
+				terms[term.taxonomy] = append(terms[term.taxonomy], term.name)
+			}
+		}
+	}
+	add(hugopage.CategoryName, categories)
+	add(hugopage.TagName, tags)
+	return terms[hugopage.CategoryName], terms[hugopage.TagName]
+}
+
+// get returns the term the term becomes, itself unless it is renamed
+func (r termRenames) get(taxonomy string, name string) termRename {
+	if rename, ok := r[taxonomy][name]; ok {
+		return rename
+	}
+	return termRename{taxonomy: taxonomy, name: name}
+}
@@ -0,0 +1,65 @@
+package hugogenerator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTermCollisions(t *testing.T) {
+	t.Parallel()
+	// A "Travel" tag on top of the "Travel" category of the post, and on the draft
+	info := parseFixture(t, integrationFixture{name: "classic", replacements: []string{
+		`<category domain="post_tag" nicename="photos"><![CDATA[Photos]]></category>`,
+		`<category domain="post_tag" nicename="photos"><![CDATA[Photos]]></category><category domain="post_tag" nicename="travel"><![CDATA[Travel]]></category>`,
+		`<category domain="category" nicename="general"><![CDATA[General]]></category>`,
+		`<category domain="category" nicename="general"><![CDATA[General]]></category><category domain="post_tag" nicename="travel"><![CDATA[Travel]]></category>`,
+	}})
+	generate := func(options Options) (string, string, Report) {
+		siteDir := t.TempDir()
+		generator := NewGenerator(siteDir, "", nil, false, false, false, false, *info, options)
+		require.NoError(t, generator.writeContent(context.Background(), siteDir, *info))
+		post, err := os.ReadFile(filepath.Join(siteDir, "content", "posts", "a-trip-to-the-mountains.md"))
+		require.NoError(t, err)
+		draft, err := os.ReadFile(filepath.Join(siteDir, "content", "posts", "unfinished-thoughts.md"))
+		require.NoError(t, err)
+		return string(post), string(draft), generator.Report()
+	}
+
+	post, draft, report := generate(Options{})
+	require.Contains(t, post, "categories:\n  - travel\n")
+	require.Contains(t, post, "tags:\n  - photos\n  - travel\n")
+	require.Contains(t, draft, "tags:\n  - travel\n")
+	require.Empty(t, report.TermCollisions)
+
+	post, draft, report = generate(Options{TermCollisions: TermCollisionsMerge})
+	require.Contains(t, post, "categories:\n  - travel\n")
+	require.Contains(t, post, "tags:\n  - photos\n")
+	require.Contains(t, draft, "categories:\n  - general\n  - travel\n")
+	require.NotContains(t, draft, "tags:")
+	require.Equal(t, []TermCollision{{Name: "travel", Policy: TermCollisionsMerge, From: "tags/travel", To: "categories/travel"}},
+		report.TermCollisions)
+
+	post, draft, report = generate(Options{TermCollisions: TermCollisionsMerge, TermCollisionTarget: "tags"})
+	require.NotContains(t, post, "categories:")
+	require.Contains(t, post, "tags:\n  - photos\n  - travel\n")
+	require.Contains(t, draft, "categories:\n  - general\n")
+	require.Equal(t, "categories/travel", report.TermCollisions[0].From)
+	require.Equal(t, "tags/travel", report.TermCollisions[0].To)
+
+	post, draft, report = generate(Options{TermCollisions: TermCollisionsSuffix})
+	require.Contains(t, post, "categories:\n  - travel\n")
+	require.Contains(t, post, "tags:\n  - photos\n  - travel-tag\n")
+	require.Contains(t, draft, "tags:\n  - travel-tag\n")
+	require.Equal(t, "tags/travel-tag", report.TermCollisions[0].To)
+
+	policy, err := ParseTermCollisionPolicy("")
+	require.NoError(t, err)
+	require.Equal(t, TermCollisionsSeparate, policy)
+	_, err = ParseTermCollisionPolicy("rename")
+	require.Error(t, err)
+	require.Error(t, validateTermCollisionTarget("keywords"))
+}
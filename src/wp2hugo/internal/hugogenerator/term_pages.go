@@ -0,0 +1,194 @@
+package hugogenerator
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/utils"
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/rs/zerolog/log"
+)
+
+// Term meta storing the attachment ID of the term image, lowest priority last:
+// WooCommerce ("thumbnail_id") and the Categories Images plugin ("z_taxonomy_image_id")
+var _termImageIDMetaKeys = []string{"thumbnail_id", "z_taxonomy_image_id", "image_id"}
+
+// Term meta storing the URL of the term image, used when it has no attachment ID
+var _termImageURLMetaKeys = []string{"z_taxonomy_image", "image"}
+
+// Term meta which is not emitted as is: the WooCommerce display settings, and the order of the terms,
+// emitted as their weight with Options.TaxonomyWeights
+var _skippedTermMetaKeys = []string{"display_type", "order", "term_order", "tax_position", "product_count_product_cat", "product_count_product_tag"}
+
+// writeTermPages writes the _index.md of the term pages, e.g. content/categories/news/_index.md,
+// with the `weight` of the term, see Options.TaxonomyWeights, and its term meta, see Options.TermMeta
+func (g Generator) writeTermPages(ctx context.Context, siteDir string, info wpparser.WebsiteInfo) error {
+	used := g.getUsedTerms(info)
+	termsByTaxonomy := getOrderedTerms(info, g.termRenames)
+	taxonomies := make([]string, 0, len(termsByTaxonomy))
+	for taxonomy := range termsByTaxonomy {
+		taxonomies = append(taxonomies, taxonomy)
+	}
+	slices.Sort(taxonomies)
+	for _, taxonomy := range taxonomies {
+		weights := getTermWeights(termsByTaxonomy[taxonomy])
+		metas := make(map[string]map[string]string, len(weights))
+		for _, term := range termsByTaxonomy[taxonomy] {
+			if _, ok := metas[term.name]; !ok {
+				metas[term.name] = term.meta
+			}
+		}
+		names := make([]string, 0, len(weights))
+		for name := range weights {
+			if used[taxonomy][name] {
+				names = append(names, name)
+			}
+		}
+		slices.Sort(names)
+		taxonomyDir := path.Join(siteDir, "content", g.options.TaxonomyKey(taxonomy))
+		for _, name := range names {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			metadata := make(map[string]any)
+			if g.options.TaxonomyWeights {
+				metadata["weight"] = weights[name]
+			}
+			if g.options.TermMeta {
+				if err := g.addTermMeta(ctx, siteDir, info, metadata, metas[name]); err != nil {
+					return err
+				}
+			}
+			if len(metadata) == 0 {
+				continue
+			}
+			termDir := path.Join(taxonomyDir, wpparser.NormalizeCategoryName(name))
+			if err := writeTermPage(termDir, metadata); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func writeTermPage(termDir string, metadata map[string]any) error {
+	indexPath := path.Join(termDir, "_index.md")
+	if utils.FileExists(indexPath) {
+		log.Warn().
+			Str("indexPath", indexPath).
+			Msg("Term already has an _index.md, add the weight and term meta to its front matter manually")
+		return nil
+	}
+	frontMatter, err := utils.GetYAML(metadata)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(termDir, 0o755); err != nil {
+		return fmt.Errorf("error creating term dir: %w", err)
+	}
+	if err := writeFile(indexPath, fmt.Appendf(nil, "---\n%s---\n", frontMatter)); err != nil {
+		return err
+	}
+	log.Debug().Msgf("Term page written: %s", indexPath)
+	return nil
+}
+
+// addTermMeta adds the term image as the `cover` front matter, like the featured image of the posts,
+// and the other public term meta as is, e.g. a term color, unless its key is a reserved front matter key. The term image is downloaded with downloadMedia.
+func (g Generator) addTermMeta(ctx context.Context, siteDir string, info wpparser.WebsiteInfo, metadata map[string]any,
+	meta map[string]string,
+) error {
+	imageKeys := make(map[string]bool)
+	imageURL := ""
+	for _, key := range _termImageIDMetaKeys {
+		if meta[key] == "" {
+			continue
+		}
+		imageKeys[key] = true
+		if imageURL != "" {
+			continue
+		}
+		if imageInfo, err := g.imageURLProvider.GetImageInfo(meta[key]); err != nil {
+			log.Warn().
+				Err(err).
+				Str("imageID", meta[key]).
+				Msg("Term image not found")
+		} else {
+			imageURL = imageInfo.ImageURL
+		}
+	}
+	for _, key := range _termImageURLMetaKeys {
+		if meta[key] == "" {
+			continue
+		}
+		imageKeys[key] = true
+		if imageURL == "" {
+			imageURL = meta[key]
+		}
+	}
+	if imageURL != "" {
+		link, err := g.getMediaLink(ctx, siteDir, info, imageURL)
+		if err != nil {
+			return err
+		}
+		metadata["cover"] = map[string]string{"image": link}
+	}
+
+	for key, value := range meta {
+		// Private meta, e.g. _edit_lock, is internal to WordPress
+		if strings.HasPrefix(key, "_") || imageKeys[key] || slices.Contains(_skippedTermMetaKeys, key) {
+			continue
+		}
+		if _, ok := metadata[key]; ok {
+			continue
+		}
+		if slices.Contains(_reservedFrontMatterKeys, key) {
+			// E.g. a `url` term meta would move the term page
+			log.Warn().
+				Str("key", key).
+				Msg("Term meta key is a reserved front matter key, not emitted")
+			continue
+		}
+		metadata[key] = value
+	}
+	return nil
+}
+
+// getMediaLink returns the link of a media outside of the content, e.g. a term image or the site icon,
+// relative if it is on the WordPress host, after downloading it with downloadMedia
+func (g Generator) getMediaLink(ctx context.Context, siteDir string, info wpparser.WebsiteInfo, imageURL string) (string, error) {
+	parsedURL, err := url.Parse(imageURL)
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Str("imageURL", imageURL).
+			Msg("Invalid term image URL")
+		return imageURL, nil
+	}
+	if parsedURL.Host != "" && strings.TrimPrefix(parsedURL.Host, "www.") != strings.TrimPrefix(info.Link().Host, "www.") {
+		return imageURL, nil
+	}
+	if g.options.NoMedia {
+		return imageURL, nil
+	}
+	link := parsedURL.Path
+	if !g.downloadMedia {
+		return link, nil
+	}
+
+	hostname := strings.TrimPrefix(info.Link().Host, "www.")
+	prefixes := []string{"https://" + hostname, "http://" + hostname, "https://www." + hostname, "http://www." + hostname}
+	replacements, _, err := downloadMedia(ctx, imageURL, siteDir, _staticDir, prefixes, g, info.Link())
+	if err != nil {
+		return "", err
+	}
+	if replacement, ok := replacements[link]; ok {
+		return replacement, nil
+	}
+	return link, nil
+}
@@ -0,0 +1,36 @@
+package hugogenerator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTermMeta(t *testing.T) {
+	t.Parallel()
+	siteDir := generateFixtureSite(t, integrationFixture{name: "classic"}, Options{TermMeta: true})
+
+	content, err := os.ReadFile(filepath.Join(siteDir, "content", "categories", "travel", "_index.md"))
+	require.NoError(t, err)
+	require.Equal(t, "---\ncolor: '#336699'\ncover:\n  image: /wp-content/uploads/2024/03/summit.jpg\n---\n", string(content))
+	// No term meta
+	require.NoFileExists(t, filepath.Join(siteDir, "content", "categories", "general", "_index.md"))
+
+	siteDir = generateFixtureSite(t, integrationFixture{name: "classic"}, Options{TermMeta: true, TaxonomyWeights: true})
+	content, err = os.ReadFile(filepath.Join(siteDir, "content", "categories", "travel", "_index.md"))
+	require.NoError(t, err)
+	require.Equal(t, "---\ncolor: '#336699'\ncover:\n  image: /wp-content/uploads/2024/03/summit.jpg\nweight: 2\n---\n", string(content))
+
+	// The term meta with a reserved front matter key would change the term page, e.g. its URL
+	siteDir = generateFixtureSite(t, integrationFixture{name: "classic", replacements: []string{
+		"<wp:termmeta><wp:meta_key><![CDATA[color]]>",
+		"<wp:termmeta><wp:meta_key><![CDATA[url]]></wp:meta_key><wp:meta_value><![CDATA[/elsewhere/]]></wp:meta_value></wp:termmeta>" +
+			"<wp:termmeta><wp:meta_key><![CDATA[title]]></wp:meta_key><wp:meta_value><![CDATA[Other]]></wp:meta_value></wp:termmeta>" +
+			"<wp:termmeta><wp:meta_key><![CDATA[color]]>",
+	}}, Options{TermMeta: true})
+	content, err = os.ReadFile(filepath.Join(siteDir, "content", "categories", "travel", "_index.md"))
+	require.NoError(t, err)
+	require.Equal(t, "---\ncolor: '#336699'\ncover:\n  image: /wp-content/uploads/2024/03/summit.jpg\n---\n", string(content))
+}
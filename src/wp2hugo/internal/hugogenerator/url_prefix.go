@@ -0,0 +1,14 @@
+package hugogenerator
+
+import (
+	"strings"
+)
+
+// normalizeURLPrefix turns "blog", "/blog/" etc. into "/blog"
+func normalizeURLPrefix(urlPrefix string) string {
+	urlPrefix = strings.Trim(strings.TrimSpace(urlPrefix), "/")
+	if urlPrefix == "" {
+		return ""
+	}
+	return "/" + urlPrefix
+}
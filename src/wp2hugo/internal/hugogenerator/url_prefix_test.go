@@ -0,0 +1,54 @@
+package hugogenerator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeURLPrefix(t *testing.T) {
+	t.Parallel()
+	require.Empty(t, normalizeURLPrefix(""))
+	require.Empty(t, normalizeURLPrefix("/"))
+	require.Equal(t, "/blog", normalizeURLPrefix("blog"))
+	require.Equal(t, "/blog", normalizeURLPrefix("/blog/"))
+	require.Equal(t, "/en/blog", normalizeURLPrefix("/en/blog"))
+}
+
+func TestURLPrefixNamespacesContent(t *testing.T) {
+	t.Parallel()
+	options := Options{}
+	options.URLPrefix = "/blog"
+	siteDir := generateFixtureSite(t, integrationFixture{name: "classic"}, options)
+
+	data, err := os.ReadFile(filepath.Join(siteDir, "content", "blog", "posts", "a-trip-to-the-mountains.md"))
+	require.NoError(t, err)
+	post := string(data)
+	require.Contains(t, post, "url: /blog/2024/03/05/a-trip-to-the-mountains/\n")
+	// Internal links follow the prefix, media stay at the root of the static dir
+	require.Contains(t, post, "[pictures](/blog/2024/01/02/gear/)")
+	require.Contains(t, post, `src="/wp-content/uploads/2024/03/summit.jpg"`)
+
+	_, err = os.Stat(filepath.Join(siteDir, "content", "blog", "pages", "about", "_index.md"))
+	require.NoError(t, err)
+	_, err = os.Stat(filepath.Join(siteDir, "content", "posts"))
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestURLPrefixRedirects(t *testing.T) {
+	t.Parallel()
+	websiteInfo := parseFixture(t, integrationFixture{name: "classic"})
+
+	options := Options{}
+	options.URLPrefix = "blog/"
+	generator := NewGenerator(t.TempDir(), "", nil, false, false, false, true, *websiteInfo, options)
+	for _, post := range websiteInfo.Posts() {
+		generator.maybeAddNginxRedirect(post.CommonFields)
+	}
+	config := generator.ngnixConfig.Generate()
+	// The source is the original WordPress URL
+	require.Contains(t, config, "p=10")
+	require.Contains(t, config, "/blog/2024/03/05/a-trip-to-the-mountains/")
+}
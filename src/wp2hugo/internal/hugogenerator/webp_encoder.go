@@ -0,0 +1,16 @@
+//go:build webp
+
+package hugogenerator
+
+import (
+	"image"
+	"io"
+
+	"github.com/gen2brain/webp"
+)
+
+const _webpEncodingSupported = true
+
+func encodeWebP(w io.Writer, img image.Image, quality int) error {
+	return webp.Encode(w, img, webp.Options{Quality: quality})
+}
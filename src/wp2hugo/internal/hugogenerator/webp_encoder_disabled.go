@@ -0,0 +1,15 @@
+//go:build !webp
+
+package hugogenerator
+
+import (
+	"image"
+	"io"
+)
+
+// WebP encoding pulls in a sizeable dependency, it is only built with `-tags webp`
+const _webpEncodingSupported = false
+
+func encodeWebP(_ io.Writer, _ image.Image, _ int) error {
+	return errWebPEncodingNotSupported
+}
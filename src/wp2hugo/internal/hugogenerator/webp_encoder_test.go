@@ -0,0 +1,54 @@
+//go:build webp
+
+package hugogenerator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaybeConvertImageToWebP(t *testing.T) {
+	t.Parallel()
+	for _, keepOriginal := range []bool{false, true} {
+		dir := t.TempDir()
+		filePath := filepath.Join(dir, "static", "wp-content", "uploads", "photo.png")
+		require.NoError(t, os.MkdirAll(filepath.Dir(filePath), 0o755))
+		require.NoError(t, os.WriteFile(filePath, testPNG(t), 0o644))
+
+		options := Options{ConvertImagesToWebP: true, KeepOriginalImages: keepOriginal}
+		g := NewGenerator(dir, "", nil, true, false, false, false, wpparser.WebsiteInfo{}, options)
+		urlReplacements := map[string]string{}
+		require.NoError(t, g.maybeConvertImageToWebP(filePath, urlReplacements,
+			"/wp-content/uploads/photo.png", "https://example.com/wp-content/uploads/photo.png"))
+
+		require.Equal(t, map[string]string{
+			"/wp-content/uploads/photo.png":                    "/wp-content/uploads/photo.png.webp",
+			"https://example.com/wp-content/uploads/photo.png": "/wp-content/uploads/photo.png.webp",
+		}, urlReplacements)
+		require.FileExists(t, filePath+".webp")
+		_, err := os.Stat(filePath)
+		require.Equal(t, keepOriginal, err == nil)
+
+		report := g.Report()
+		require.Equal(t, 1, report.ConvertedImages)
+		require.Less(t, report.ImageBytesAfter, report.ImageBytesBefore)
+	}
+}
+
+func TestMaybeConvertImageToWebPSkipsAnimatedPNG(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "animation.png")
+	require.NoError(t, os.WriteFile(filePath, withPNGChunk(testPNG(t), "acTL", []byte{0, 0, 0, 2, 0, 0, 0, 0}), 0o644))
+
+	g := NewGenerator(dir, "", nil, true, false, false, false, wpparser.WebsiteInfo{}, Options{ConvertImagesToWebP: true})
+	urlReplacements := map[string]string{}
+	require.NoError(t, g.maybeConvertImageToWebP(filePath, urlReplacements, "/animation.png"))
+	require.Empty(t, urlReplacements)
+	require.FileExists(t, filePath)
+	require.NoFileExists(t, filePath+".webp")
+}
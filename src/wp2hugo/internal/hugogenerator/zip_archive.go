@@ -0,0 +1,138 @@
+package hugogenerator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/rs/zerolog/log"
+)
+
+var errIncrementalZipArchive = errors.New("incremental runs update the site of the previous run, they can't write a zip archive")
+
+// generateZipArchive generates the site into a temporary dir, and then exports it through a zipOutputWriter into
+// Options.OutputZip. The generator doesn't write through the zipOutputWriter directly: it reads back and moves the
+// files it writes, e.g. the config set up by "hugo new site", the fonts appended to the theme, data/comments.yaml,
+// the page bundles and the media converted to WebP, which a zip archive being streamed can't do. The temporary dir,
+// under $TMPDIR, needs room for the whole site along with the downloaded media. It is removed once archived,
+// and when the generation fails, so nothing of the site is left next to the archive.
+func (g Generator) generateZipArchive(ctx context.Context) error {
+	if g.options.Incremental {
+		return errIncrementalZipArchive
+	}
+	zipDir, err := createOutputDir(filepath.Dir(g.options.OutputZip))
+	if err != nil {
+		return err
+	}
+	zipPath := filepath.Join(zipDir, filepath.Base(g.options.OutputZip))
+	log.Info().
+		Str("location", zipPath).
+		Msg("Archiving the Hugo site into the zip archive")
+	tempDir, err := os.MkdirTemp("", "wp2hugo-site-")
+	if err != nil {
+		return fmt.Errorf("error creating temporary dir: %w", err)
+	}
+	defer func() {
+		if err := os.RemoveAll(tempDir); err != nil {
+			log.Warn().
+				Err(err).
+				Str("dir", tempDir).
+				Msg("error removing temporary dir")
+		}
+	}()
+
+	g.outputDirPath = tempDir
+	g.options.OutputZip = ""
+	// Fixed before the generation, the archive entries are under the site dir
+	g.options.SiteName = g.getSiteName()
+	if err := g.Generate(ctx); err != nil {
+		return err
+	}
+	if err := writeZipArchive(ctx, path.Join(tempDir, g.options.SiteName), zipPath); err != nil {
+		return err
+	}
+	log.Info().
+		Str("location", zipPath).
+		Msg("Hugo site has been archived")
+	return nil
+}
+
+// writeZipArchive archives siteDir into zipPath, under the site dir name.
+// The files are streamed one at a time, the archive is removed if it can't be completed.
+func writeZipArchive(ctx context.Context, siteDir string, zipPath string) (err error) {
+	file, err := os.OpenFile(zipPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return fmt.Errorf("error creating zip archive: %w", err)
+	}
+	defer func() {
+		if closeErr := file.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("error closing zip archive: %w", closeErr)
+		}
+		if err != nil {
+			_ = os.Remove(zipPath)
+		}
+	}()
+
+	archive := newZipOutputWriter(file)
+	if err := exportSite(ctx, siteDir, archive); err != nil {
+		return err
+	}
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf("error writing zip archive: %w", err)
+	}
+	return nil
+}
+
+// exportSite writes the files of siteDir through w, under the site dir name
+func exportSite(ctx context.Context, siteDir string, w outputWriter) error {
+	rootDir := path.Dir(siteDir)
+	err := filepath.WalkDir(siteDir, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return exportFile(w, rootDir, filePath, entry)
+	})
+	if err != nil {
+		return fmt.Errorf("error exporting '%s': %w", siteDir, err)
+	}
+	return nil
+}
+
+func exportFile(w outputWriter, rootDir string, filePath string, entry fs.DirEntry) error {
+	relativePath, err := filepath.Rel(rootDir, filePath)
+	if err != nil {
+		return err
+	}
+	relativePath = filepath.ToSlash(relativePath)
+	if entry.IsDir() {
+		return w.Mkdir(relativePath)
+	}
+	if !entry.Type().IsRegular() {
+		log.Warn().
+			Str("path", filePath).
+			Msg("Skipping the file which is not a regular file in the export")
+		return nil
+	}
+	r, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	dest, err := w.Create(relativePath)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dest, r); err != nil {
+		_ = dest.Close()
+		return err
+	}
+	return dest.Close()
+}
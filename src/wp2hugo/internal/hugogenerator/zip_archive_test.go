@@ -0,0 +1,113 @@
+package hugogenerator
+
+import (
+	"archive/zip"
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteZipArchive(t *testing.T) {
+	t.Parallel()
+	siteDir := path.Join(t.TempDir(), "my-site")
+	require.NoError(t, os.MkdirAll(path.Join(siteDir, "content", "posts"), 0o755))
+	require.NoError(t, os.WriteFile(path.Join(siteDir, "hugo.yaml"), []byte("title: My site\n"), 0o644))
+	require.NoError(t, os.WriteFile(path.Join(siteDir, "content", "posts", "hello.md"), []byte("Hello"), 0o644))
+
+	zipPath := path.Join(t.TempDir(), "site.zip")
+	require.NoError(t, writeZipArchive(context.Background(), siteDir, zipPath))
+
+	archive, err := zip.OpenReader(zipPath)
+	require.NoError(t, err)
+	defer archive.Close()
+	names := make([]string, 0, len(archive.File))
+	for _, file := range archive.File {
+		names = append(names, file.Name)
+	}
+	require.Equal(t, []string{"my-site/", "my-site/content/", "my-site/content/posts/",
+		"my-site/content/posts/hello.md", "my-site/hugo.yaml"}, names)
+
+	r, err := archive.Open("my-site/content/posts/hello.md")
+	require.NoError(t, err)
+	defer r.Close()
+	content, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.Equal(t, "Hello", string(content))
+}
+
+func TestZipArchiveOfConvertedSite(t *testing.T) {
+	t.Parallel()
+	info := parseFixture(t, integrationFixture{name: "classic"})
+	siteDir := path.Join(t.TempDir(), "site")
+	generator := NewGenerator(siteDir, "", nil, false, false, false, false, *info, Options{})
+	require.NoError(t, generator.writeContent(context.Background(), siteDir, *info))
+
+	zipPath := path.Join(t.TempDir(), "site.zip")
+	require.NoError(t, writeZipArchive(context.Background(), siteDir, zipPath))
+
+	archive, err := zip.OpenReader(zipPath)
+	require.NoError(t, err)
+	defer archive.Close()
+	// Every file of the site is archived, with the same content
+	numFiles := 0
+	require.NoError(t, filepath.WalkDir(siteDir, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		numFiles++
+		relativePath, err := filepath.Rel(filepath.Dir(siteDir), filePath)
+		require.NoError(t, err)
+		expected, err := os.ReadFile(filePath)
+		require.NoError(t, err)
+		r, err := archive.Open(filepath.ToSlash(relativePath))
+		require.NoError(t, err)
+		defer r.Close()
+		content, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.Equal(t, string(expected), string(content), relativePath)
+		return nil
+	}))
+	require.Positive(t, numFiles)
+	_, err = archive.Open("site/content/posts/a-trip-to-the-mountains.md")
+	require.NoError(t, err)
+}
+
+func TestWriteZipArchiveCancelled(t *testing.T) {
+	t.Parallel()
+	siteDir := t.TempDir()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	zipPath := path.Join(t.TempDir(), "site.zip")
+	require.ErrorIs(t, writeZipArchive(ctx, siteDir, zipPath), context.Canceled)
+	require.NoFileExists(t, zipPath)
+}
+
+func TestIncrementalZipArchive(t *testing.T) {
+	t.Parallel()
+	generator := NewGenerator(t.TempDir(), "", nil, false, false, false, false, *parseFixture(t, integrationFixture{name: "classic"}),
+		Options{Incremental: true, SiteName: "site", OutputZip: path.Join(t.TempDir(), "site.zip")})
+	require.ErrorIs(t, generator.Generate(context.Background()), errIncrementalZipArchive)
+}
+
+func TestExportSiteToDir(t *testing.T) {
+	t.Parallel()
+	siteDir := path.Join(t.TempDir(), "my-site")
+	require.NoError(t, os.MkdirAll(path.Join(siteDir, "content", "posts"), 0o755))
+	require.NoError(t, os.MkdirAll(path.Join(siteDir, "static"), 0o755))
+	require.NoError(t, os.WriteFile(path.Join(siteDir, "content", "posts", "hello.md"), []byte("Hello"), 0o644))
+
+	exportDir := t.TempDir()
+	require.NoError(t, exportSite(context.Background(), siteDir, dirOutputWriter{dir: exportDir}))
+	content, err := os.ReadFile(path.Join(exportDir, "my-site", "content", "posts", "hello.md"))
+	require.NoError(t, err)
+	require.Equal(t, "Hello", string(content))
+	// The empty dirs are exported too
+	require.DirExists(t, path.Join(exportDir, "my-site", "static"))
+}
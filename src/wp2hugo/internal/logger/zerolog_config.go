@@ -17,14 +17,37 @@ const (
 	_defaultColoredOutput = false
 )
 
-// ConfigureLogging configures ZeroLog's logging config with good defaults
+// LogFormat is the format of the log output
+type LogFormat string
+
+const (
+	// LogFormatConsole is the human-friendly colored output
+	LogFormatConsole LogFormat = "console"
+	// LogFormatJSON is the structured output, one JSON object per line
+	LogFormatJSON LogFormat = "json"
+)
+
+// ConfigureLogging configures ZeroLog's logging config with good defaults.
+// The log level is read from the LOG_LEVEL environment variable.
 func ConfigureLogging(colorLogOutput bool) {
+	logFormat := LogFormatJSON
+	if colorLogOutput {
+		logFormat = LogFormatConsole
+	}
+	logLevel, err := GetDefaultLogLevel()
+	if err != nil {
+		panic(err)
+	}
+	Configure(logLevel, logFormat)
+}
+
+// Configure configures ZeroLog's logging config with the given level and format
+func Configure(logLevel zerolog.Level, logFormat LogFormat) {
 	// UNIX Time is faster and smaller than most timestamps
 	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
-	logLevel := getLogLevel()
 	zerolog.SetGlobalLevel(logLevel)
 
-	if colorLogOutput {
+	if logFormat == LogFormatConsole {
 		// Pretty printing is a bit inefficient for production
 		output := zerolog.ConsoleWriter{Out: os.Stderr}
 		output.FormatTimestamp = func(t any) string {
@@ -53,25 +76,42 @@ func ConfigureLogging(colorLogOutput bool) {
 	}
 }
 
-func getLogLevel() zerolog.Level {
+// GetDefaultLogLevel returns the log level from the LOG_LEVEL environment variable, debug if unset
+func GetDefaultLogLevel() (zerolog.Level, error) {
 	logLevelStr := strings.TrimSpace(os.Getenv("LOG_LEVEL"))
 	if len(logLevelStr) == 0 {
-		return _defaultLogLevel
+		return _defaultLogLevel, nil
 	}
-	switch strings.ToUpper(logLevelStr) {
+	return ParseLogLevel(logLevelStr)
+}
+
+// ParseLogLevel parses trace, debug, info, warn, error or fatal, case-insensitively
+func ParseLogLevel(logLevelStr string) (zerolog.Level, error) {
+	switch strings.ToUpper(strings.TrimSpace(logLevelStr)) {
 	case "TRACE":
-		return zerolog.TraceLevel
+		return zerolog.TraceLevel, nil
 	case "DEBUG":
-		return zerolog.DebugLevel
+		return zerolog.DebugLevel, nil
 	case "INFO":
-		return zerolog.InfoLevel
+		return zerolog.InfoLevel, nil
 	case "ERROR":
-		return zerolog.ErrorLevel
+		return zerolog.ErrorLevel, nil
 	case "WARN":
-		return zerolog.WarnLevel
+		return zerolog.WarnLevel, nil
 	case "FATAL":
-		return zerolog.FatalLevel
+		return zerolog.FatalLevel, nil
+	default:
+		return zerolog.NoLevel, fmt.Errorf("unexpected log level: %q", logLevelStr)
+	}
+}
+
+// ParseLogFormat parses "console" or "json", case-insensitively
+func ParseLogFormat(logFormatStr string) (LogFormat, error) {
+	switch logFormat := LogFormat(strings.ToLower(strings.TrimSpace(logFormatStr))); logFormat {
+	case LogFormatConsole, LogFormatJSON:
+		return logFormat, nil
 	default:
-		panic("Unexpected log level: " + logLevelStr)
+		return "", fmt.Errorf("unexpected log format: %q, expected %q or %q",
+			logFormatStr, LogFormatConsole, LogFormatJSON)
 	}
 }
@@ -114,8 +114,16 @@ func (m MediaCache) GetReader(ctx context.Context, url string) (io.Reader, error
 	for retries < 5 && !stop {
 		// Send at most 1 request per second
 		// to avoid hammering servers and getting rate-limited.
-		time.Sleep(time.Duration(timeout) * time.Second)
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("error fetching media %s: %w", url, ctx.Err())
+		case <-time.After(time.Duration(timeout) * time.Second):
+		}
 		resp, httpErr = http.DefaultClient.Do(req)
+		if ctx.Err() != nil {
+			// Cancelled, not worth retrying
+			break
+		}
 		timeout, stop = waitOrStop(resp)
 		retries++
 		timeout *= retries
@@ -140,6 +148,9 @@ func (m MediaCache) GetReader(ctx context.Context, url string) (io.Reader, error
 	}
 	_, err = io.Copy(file, resp.Body)
 	if err != nil {
+		// A partial download, e.g. cancelled, must not be found in the cache by the next run
+		_ = file.Close()
+		_ = os.Remove(path.Join(m.cacheDirPath, key))
 		return nil, fmt.Errorf("error writing media to cache %s: %w", url, err)
 	}
 
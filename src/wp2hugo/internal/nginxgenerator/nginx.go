@@ -4,6 +4,8 @@ import (
 	_ "embed"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 )
 
@@ -44,8 +46,9 @@ func (c *Config) AddRedirect(source string, destination string) error {
 
 func (c *Config) generateRedirects() string {
 	var sb strings.Builder
-	for sourceQueryString, destination := range c.redirects {
-		fmt.Fprintf(&sb, _redirectHomepageQueryStringTemplate, sourceQueryString, destination)
+	// Sorted, so that the generated config does not change between runs
+	for _, sourceQueryString := range slices.Sorted(maps.Keys(c.redirects)) {
+		fmt.Fprintf(&sb, _redirectHomepageQueryStringTemplate, sourceQueryString, c.redirects[sourceQueryString])
 	}
 	return sb.String()
 }
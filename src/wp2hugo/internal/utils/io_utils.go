@@ -16,7 +16,7 @@ func GetYAML(input any) ([]byte, error) {
 	if err != nil {
 		return nil, fmt.Errorf("error marshalling to YAML: %w", err)
 	}
-	return output.Bytes(), nil
+	return unescapeAstralRunes(output.Bytes()), nil
 }
 
 func CreateDirIfNotExist(dirPath string) error {
@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"bytes"
+	"reflect"
+	"regexp"
+	"strconv"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// The YAML encoder escapes the characters outside the Basic Multilingual Plane,
+// e.g. "Hello 👋" is written as "Hello \U0001F44B"
+var _yamlAstralEscapeRegEx = regexp.MustCompile(`(\\+)U([0-9A-Fa-f]{8})`)
+
+// unescapeAstralRunes writes the escaped emoji and other 4-byte UTF-8 characters
+// back as they are, so that the generated front matter stays readable.
+// The output is only used if it decodes to the same value.
+func unescapeAstralRunes(data []byte) []byte {
+	if !bytes.Contains(data, []byte(`\U`)) {
+		return data
+	}
+	result := _yamlAstralEscapeRegEx.ReplaceAllFunc(data, func(match []byte) []byte {
+		numBackslashes := bytes.LastIndexByte(match, '\\') + 1
+		// An even number of backslashes is an escaped backslash followed by a literal "U"
+		if numBackslashes%2 == 0 {
+			return match
+		}
+		code, err := strconv.ParseUint(string(match[numBackslashes+1:]), 16, 32)
+		if err != nil || !utf8.ValidRune(rune(code)) {
+			return match
+		}
+		return utf8.AppendRune(bytes.Clone(match[:numBackslashes-1]), rune(code))
+	})
+
+	var expected, actual any
+	if yaml.Unmarshal(data, &expected) != nil || yaml.Unmarshal(result, &actual) != nil ||
+		!reflect.DeepEqual(expected, actual) {
+		return data
+	}
+	return result
+}
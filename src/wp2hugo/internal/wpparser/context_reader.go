@@ -0,0 +1,19 @@
+package wpparser
+
+import (
+	"context"
+	"io"
+)
+
+// contextReader stops reading once the context is cancelled, which aborts the parsing of large exports
+type contextReader struct {
+	ctx    context.Context
+	reader io.Reader
+}
+
+func (c contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.reader.Read(p)
+}
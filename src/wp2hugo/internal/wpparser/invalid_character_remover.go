@@ -1,10 +1,32 @@
 package wpparser
 
 import (
+	"bufio"
 	"bytes"
 	"io"
 )
 
+// UTF-8 byte order mark, written by some hosts and editors before the XML declaration
+var _utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// skipXMLPrologueNoise skips the byte order marks and the whitespace before the XML declaration,
+// which strict XML parsers reject, e.g. "XML declaration allowed only at the start of the document"
+func skipXMLPrologueNoise(reader io.Reader) io.Reader {
+	bufReader := bufio.NewReader(reader)
+	for {
+		// Fewer bytes are returned at the end of the data
+		prefix, _ := bufReader.Peek(len(_utf8BOM))
+		switch {
+		case bytes.HasPrefix(prefix, _utf8BOM):
+			_, _ = bufReader.Discard(len(_utf8BOM))
+		case len(prefix) > 0 && bytes.IndexByte([]byte(" \t\r\n"), prefix[0]) >= 0:
+			_, _ = bufReader.Discard(1)
+		default:
+			return bufReader
+		}
+	}
+}
+
 type InvalidatorCharacterRemover struct {
 	reader io.Reader
 }
@@ -12,9 +34,8 @@ type InvalidatorCharacterRemover struct {
 func (i InvalidatorCharacterRemover) Read(p []byte) (int, error) {
 	tmp := make([]byte, len(p))
 	n, err := i.reader.Read(tmp)
-	if err != nil {
-		return n, err
-	}
+	// Readers may return the last bytes along with io.EOF, e.g. the gzip reader
+	tmp = tmp[:n]
 	// Characters from 1 to 31 seem to be disallowed in XML
 	// One gets errors like "XML syntax error on line <>: illegal character code U+0001"
 	// Ref:
@@ -28,5 +49,5 @@ func (i InvalidatorCharacterRemover) Read(p []byte) (int, error) {
 		tmp = bytes.ReplaceAll(tmp, []byte{byte(i)}, []byte(""))
 	}
 	copy(p, tmp)
-	return len(tmp), nil
+	return len(tmp), err
 }
@@ -0,0 +1,37 @@
+package wpparser
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseExportWithLeadingBOM(t *testing.T) {
+	t.Parallel()
+
+	for name, prefix := range map[string]string{
+		"BOM":                "\uFEFF",
+		"whitespace":         "\n\n  \t",
+		"BOM and whitespace": "\uFEFF\r\n ",
+		"whitespace and BOM": " \n\uFEFF",
+	} {
+		info, err := NewParser().Parse(strings.NewReader(prefix+_wxr10Export), nil, nil)
+		require.NoError(t, err, name)
+		require.Equal(t, "Example", info.Title(), name)
+		require.Len(t, info.Posts(), 1, name)
+	}
+}
+
+func TestSkipXMLPrologueNoise(t *testing.T) {
+	t.Parallel()
+
+	data, err := io.ReadAll(skipXMLPrologueNoise(strings.NewReader("\uFEFF \r\n\uFEFF<?xml version=\"1.0\"?>\n<rss/>")))
+	require.NoError(t, err)
+	require.Equal(t, "<?xml version=\"1.0\"?>\n<rss/>", string(data))
+
+	data, err = io.ReadAll(skipXMLPrologueNoise(strings.NewReader(" \n")))
+	require.NoError(t, err)
+	require.Empty(t, data)
+}
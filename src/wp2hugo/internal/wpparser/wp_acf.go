@@ -0,0 +1,37 @@
+package wpparser
+
+import (
+	"regexp"
+
+	"github.com/mmcdole/gofeed/rss"
+)
+
+// ACFField is an Advanced Custom Fields field definition, exported by WordPress as an
+// item of type "acf-field".
+// Ref: https://www.advancedcustomfields.com/resources/
+type ACFField struct {
+	Key   string // e.g. "field_5f3c1a2b3c4d5", referenced from the "_fieldname" postmeta
+	Name  string // e.g. "price", the postmeta key holding the value
+	Label string
+	Type  string // e.g. "text", "repeater", "relationship"
+}
+
+// The field settings are stored as a PHP-serialized array in the post content, for example
+// a:10:{s:4:"type";s:8:"repeater";s:12:"instructions";s:0:"";...}
+var _acfFieldTypeRegEx = regexp.MustCompile(`s:4:"type";s:\d+:"([^"]*)"`)
+
+func getACFField(item *rss.Item, fields CommonFields) *ACFField {
+	key := getWPField(item, "post_name")
+	if key == "" {
+		return nil
+	}
+	field := ACFField{
+		Key:   key,
+		Name:  fields.Excerpt,
+		Label: fields.Title,
+	}
+	if matches := _acfFieldTypeRegEx.FindStringSubmatch(fields.Content); matches != nil {
+		field.Type = matches[1]
+	}
+	return &field
+}
@@ -0,0 +1,87 @@
+package wpparser
+
+import (
+	ext "github.com/mmcdole/gofeed/extensions"
+	"github.com/mmcdole/gofeed/rss"
+	"github.com/rs/zerolog/log"
+)
+
+// AuthorInfo is a user listed in the <wp:author> entries of the export channel
+type AuthorInfo struct {
+	ID          string `yaml:"id"`
+	Login       string `yaml:"login"` // Matches the <dc:creator> of the posts
+	Email       string `yaml:"email"`
+	DisplayName string `yaml:"display_name"`
+	FirstName   string `yaml:"first_name"`
+	LastName    string `yaml:"last_name"`
+	Slug        string `yaml:"-"` // Derived from the display name, unique across authors
+}
+
+func getAuthors(inputs []ext.Extension) []AuthorInfo {
+	authors := make([]AuthorInfo, 0, len(inputs))
+	for _, input := range inputs {
+		author := AuthorInfo{
+			ID:          getChildValue(input, "author_id"),
+			Login:       getChildValue(input, "author_login"),
+			Email:       getChildValue(input, "author_email"),
+			DisplayName: getChildValue(input, "author_display_name"),
+			FirstName:   getChildValue(input, "author_first_name"),
+			LastName:    getChildValue(input, "author_last_name"),
+		}
+		if author.Login == "" {
+			log.Warn().
+				Any("input", input).
+				Msg("author_login is missing, ignoring author")
+			continue
+		}
+		authors = append(authors, author)
+	}
+	setAuthorSlugs(authors)
+	return authors
+}
+
+// getAuthorsFromItems lists the authors of the items, for WXR 1.0 exports
+// which predate the <wp:author> entries
+func getAuthorsFromItems(items []*rss.Item) []AuthorInfo {
+	authors := make([]AuthorInfo, 0)
+	seen := make(map[string]bool)
+	for _, item := range items {
+		login := getAuthor(item)
+		if login == "" || seen[login] {
+			continue
+		}
+		seen[login] = true
+		authors = append(authors, AuthorInfo{Login: login, DisplayName: login})
+	}
+	setAuthorSlugs(authors)
+	return authors
+}
+
+// setAuthorSlugs derives the slugs from the display names.
+// Authors sharing a display name (or a slug) are disambiguated with their login.
+func setAuthorSlugs(authors []AuthorInfo) {
+	authorsPerSlug := make(map[string]int, len(authors))
+	for i := range authors {
+		name := authors[i].DisplayName
+		if name == "" {
+			name = authors[i].Login
+		}
+		authors[i].Slug = titleToFilename(name)
+		if authors[i].Slug == "" {
+			authors[i].Slug = titleToFilename(authors[i].Login)
+		}
+		authorsPerSlug[authors[i].Slug]++
+	}
+	for i := range authors {
+		if authorsPerSlug[authors[i].Slug] > 1 {
+			authors[i].Slug = authors[i].Slug + "-" + titleToFilename(authors[i].Login)
+		}
+	}
+}
+
+func getChildValue(input ext.Extension, name string) string {
+	if len(input.Children[name]) == 0 {
+		return ""
+	}
+	return input.Children[name][0].Value
+}
@@ -0,0 +1,55 @@
+package wpparser
+
+import (
+	"testing"
+
+	ext "github.com/mmcdole/gofeed/extensions"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAuthors(t *testing.T) {
+	t.Parallel()
+
+	authors := getAuthors([]ext.Extension{
+		newAuthorExtension("1", "jdoe", "John Doe"),
+		newAuthorExtension("2", "john.doe", "John Doe"),
+		newAuthorExtension("3", "amelie", "Amélie Poulain"),
+		newAuthorExtension("4", "bob", ""),
+		newAuthorExtension("5", "", "No Login"),
+	})
+
+	slugs := make(map[string]string, len(authors))
+	for _, author := range authors {
+		slugs[author.Login] = author.Slug
+	}
+	require.Equal(t, map[string]string{
+		"jdoe":     "john-doe-jdoe",
+		"john.doe": "john-doe-john-doe",
+		"amelie":   "amelie-poulain",
+		"bob":      "bob",
+	}, slugs)
+	require.Equal(t, "john.doe@example.org", authors[1].Email)
+}
+
+func TestGetAuthorSlug(t *testing.T) {
+	t.Parallel()
+
+	info := WebsiteInfo{authors: getAuthors([]ext.Extension{newAuthorExtension("1", "jdoe", "John Doe")})}
+	slug, ok := info.GetAuthorSlug("jdoe")
+	require.True(t, ok)
+	require.Equal(t, "john-doe", slug)
+
+	_, ok = info.GetAuthorSlug("unknown")
+	require.False(t, ok)
+}
+
+func newAuthorExtension(id string, login string, displayName string) ext.Extension {
+	return ext.Extension{
+		Children: map[string][]ext.Extension{
+			"author_id":           {{Value: id}},
+			"author_login":        {{Value: login}},
+			"author_email":        {{Value: login + "@example.org"}},
+			"author_display_name": {{Value: displayName}},
+		},
+	}
+}
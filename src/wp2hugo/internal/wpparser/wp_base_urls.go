@@ -0,0 +1,60 @@
+package wpparser
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/mmcdole/gofeed/rss"
+	"github.com/rs/zerolog/log"
+)
+
+// The base URLs of the export: <wp:base_site_url> is the URL of the WordPress install, or of the network
+// for a multisite, and <wp:base_blog_url> the URL of the blog, e.g. "https://example.org/blog"
+// when WordPress lives in a subdirectory
+const (
+	_baseSiteURLKey = "base_site_url"
+	_baseBlogURLKey = "base_blog_url"
+)
+
+// BaseSiteURL returns the <wp:base_site_url> of the export, nil if it is missing
+func (w *WebsiteInfo) BaseSiteURL() *url.URL {
+	return w.baseSiteURL
+}
+
+// BaseBlogURL returns the <wp:base_blog_url> of the export, nil if it is missing
+func (w *WebsiteInfo) BaseBlogURL() *url.URL {
+	return w.baseBlogURL
+}
+
+// getBaseURL returns the absolute URL of the <wp:key> element of the channel, nil if missing or invalid
+func getBaseURL(feed *rss.Feed, key string) *url.URL {
+	values := feed.Extensions["wp"][key]
+	if len(values) == 0 || strings.TrimSpace(values[0].Value) == "" {
+		return nil
+	}
+	value := strings.TrimSpace(values[0].Value)
+	baseURL, err := url.Parse(value)
+	if err != nil || baseURL.Scheme == "" || baseURL.Host == "" {
+		log.Warn().
+			Str("key", "wp:"+key).
+			Str("value", value).
+			Msg("Invalid base URL in the export, ignoring it")
+		return nil
+	}
+	return baseURL
+}
+
+// getSiteLink returns the canonical URL of the blog: its base blog URL, or else the link of the feed.
+// They differ e.g. when the link of the feed is filtered by a plugin.
+func getSiteLink(feedLink *url.URL, baseBlogURL *url.URL) *url.URL {
+	if baseBlogURL == nil {
+		return feedLink
+	}
+	if feedLink != nil && strings.TrimSuffix(feedLink.String(), "/") != strings.TrimSuffix(baseBlogURL.String(), "/") {
+		log.Info().
+			Str("feedLink", feedLink.String()).
+			Str("baseBlogURL", baseBlogURL.String()).
+			Msg("The link of the feed differs from the base blog URL, using the base blog URL")
+	}
+	return baseBlogURL
+}
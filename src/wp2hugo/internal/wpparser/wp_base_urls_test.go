@@ -0,0 +1,60 @@
+package wpparser
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const _baseURLsExport = `<?xml version="1.0" encoding="UTF-8" ?>
+<rss version="2.0" xmlns:wp="http://wordpress.org/export/1.2/">
+<channel>
+  <title>Example</title>
+  <link>https://example.org</link>
+  <wp:wxr_version>1.2</wp:wxr_version>
+  %s
+</channel>
+</rss>`
+
+func TestBaseURLs(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name                string
+		baseURLs            string
+		expectedLink        string
+		expectedBaseSiteURL string
+	}{
+		{
+			name: "subdirectory",
+			baseURLs: `<wp:base_site_url>https://example.org/wordpress</wp:base_site_url>
+  <wp:base_blog_url>https://example.org/blog</wp:base_blog_url>`,
+			expectedLink:        "https://example.org/blog",
+			expectedBaseSiteURL: "https://example.org/wordpress",
+		},
+		{
+			name:         "missing",
+			expectedLink: "https://example.org",
+		},
+		{
+			name:         "invalid",
+			baseURLs:     `<wp:base_blog_url>/blog</wp:base_blog_url>`,
+			expectedLink: "https://example.org",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			info, err := NewParser().Parse(strings.NewReader(fmt.Sprintf(_baseURLsExport, testCase.baseURLs)), nil, nil)
+			require.NoError(t, err)
+			require.Equal(t, testCase.expectedLink, info.Link().String())
+			if testCase.expectedBaseSiteURL == "" {
+				require.Nil(t, info.BaseSiteURL())
+				return
+			}
+			require.Equal(t, testCase.expectedBaseSiteURL, info.BaseSiteURL().String())
+			require.Equal(t, testCase.expectedLink, info.BaseBlogURL().String())
+		})
+	}
+}
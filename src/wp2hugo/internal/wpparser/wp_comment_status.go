@@ -0,0 +1,23 @@
+package wpparser
+
+import (
+	"strings"
+
+	"github.com/mmcdole/gofeed/rss"
+)
+
+// Values of wp:comment_status and wp:ping_status
+const (
+	CommentStatusOpen   = "open"
+	CommentStatusClosed = "closed"
+)
+
+// CommentsOpen reports whether the content accepts comments on WordPress
+func (i CommonFields) CommentsOpen() bool {
+	return i.CommentStatus == CommentStatusOpen
+}
+
+// getDiscussionStatus returns the wp:comment_status or wp:ping_status of the item, empty if not exported
+func getDiscussionStatus(item *rss.Item, key string) string {
+	return strings.ToLower(strings.TrimSpace(getWPField(item, key)))
+}
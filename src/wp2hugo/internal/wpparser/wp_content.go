@@ -0,0 +1,99 @@
+package wpparser
+
+import (
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+
+	ext "github.com/mmcdole/gofeed/extensions"
+	"github.com/mmcdole/gofeed/rss"
+	"github.com/rs/zerolog/log"
+)
+
+// getContent returns the full body of the item from <content:encoded>, see setContentFromNamespaces for the content
+// namespace declared with another URI. <description> is at best a teaser, often truncated, it is only used when
+// the body is empty.
+func getContent(item *rss.Item) string {
+	if strings.TrimSpace(item.Content) != "" {
+		log.Trace().
+			Str("link", item.Link).
+			Msg("Content from content:encoded")
+		return item.Content
+	}
+	if strings.TrimSpace(item.Description) != "" {
+		log.Debug().
+			Str("link", item.Link).
+			Msg("Empty content:encoded, falling back to the description")
+		return item.Description
+	}
+	return item.Content
+}
+
+// The URIs of the content module, gofeed only takes "http://purl.org/rss/1.0/modules/content/" for the content,
+// the old exporters declare it over https or without the trailing slash
+var _contentNamespaceRegEx = regexp.MustCompile(`^https?://purl\.org/rss/1\.0/modules/content/?$`)
+
+var (
+	_rssStartTagRegEx           = regexp.MustCompile(`<rss\b[^>]*>`)
+	_namespaceDeclarationRegEx  = regexp.MustCompile(`\sxmlns:([\w.-]+)\s*=\s*["']([^"']*)["']`)
+	_maxRecordedRSSStartTagSize = 64 * 1024
+)
+
+// rssStartTagRecorder keeps the start tag of the <rss> element as it is read, for the namespaces it declares,
+// which gofeed replaces with their prefix
+type rssStartTagRecorder struct {
+	reader io.Reader
+	data   []byte
+	done   bool
+}
+
+func (r *rssStartTagRecorder) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if !r.done {
+		r.data = append(r.data, p[:n]...)
+		if match := _rssStartTagRegEx.FindIndex(r.data); match != nil {
+			r.data, r.done = r.data[match[0]:match[1]], true
+		} else if len(r.data) > _maxRecordedRSSStartTagSize {
+			r.data, r.done = nil, true
+		}
+	}
+	return n, err
+}
+
+// getContentNamespacePrefixes returns the prefixes of the content module declared by the start tag of the <rss>
+// element, see _contentNamespaceRegEx
+func getContentNamespacePrefixes(rssStartTag []byte) []string {
+	var prefixes []string
+	for _, declaration := range _namespaceDeclarationRegEx.FindAllSubmatch(rssStartTag, -1) {
+		if _contentNamespaceRegEx.Match(declaration[2]) {
+			prefixes = append(prefixes, string(declaration[1]))
+		}
+	}
+	return prefixes
+}
+
+// setContentFromNamespaces sets the empty content of the items from the encoded element of the content module
+// declared with another URI, e.g. <c:encoded> with xmlns:c="https://purl.org/rss/1.0/modules/content",
+// which gofeed keeps as an extension
+func setContentFromNamespaces(items []*rss.Item, prefixes []string) {
+	for _, item := range items {
+		if strings.TrimSpace(item.Content) != "" {
+			continue
+		}
+		for _, prefix := range prefixes {
+			index := slices.IndexFunc(item.Extensions[prefix]["encoded"], func(extension ext.Extension) bool {
+				return strings.TrimSpace(extension.Value) != ""
+			})
+			if index < 0 {
+				continue
+			}
+			log.Debug().
+				Str("link", item.Link).
+				Str("element", prefix+":encoded").
+				Msg("Content from the content namespace declared with another URI")
+			item.Content = item.Extensions[prefix]["encoded"][index].Value
+			break
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package wpparser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// The description of the first post is a truncated teaser, the second post only has a description
+const _teaserExport = `<?xml version="1.0" encoding="UTF-8" ?>
+<rss version="2.0"
+  xmlns:content="http://purl.org/rss/1.0/modules/content/"
+  xmlns:dc="http://purl.org/dc/elements/1.1/"
+  xmlns:wp="http://wordpress.org/export/1.2/">
+<channel>
+  <title>Example</title>
+  <link>https://example.org</link>
+  <wp:wxr_version>1.2</wp:wxr_version>
+  <item>
+    <title>Full post</title>
+    <link>https://example.org/full-post/</link>
+    <dc:creator><![CDATA[jdoe]]></dc:creator>
+    <description><![CDATA[The beginning of the post [&hellip;]]]></description>
+    <content:encoded><![CDATA[<p>The beginning of the post, and the rest of it.</p>]]></content:encoded>
+    <wp:post_id>1</wp:post_id>
+    <wp:post_date>2024-01-01 10:00:00</wp:post_date>
+    <wp:post_name>full-post</wp:post_name>
+    <wp:status>publish</wp:status>
+    <wp:post_parent>0</wp:post_parent>
+    <wp:post_type>post</wp:post_type>
+  </item>
+  <item>
+    <title>Description only</title>
+    <link>https://example.org/description-only/</link>
+    <dc:creator><![CDATA[jdoe]]></dc:creator>
+    <description><![CDATA[<p>Only a description.</p>]]></description>
+    <content:encoded><![CDATA[]]></content:encoded>
+    <wp:post_id>2</wp:post_id>
+    <wp:post_date>2024-01-02 10:00:00</wp:post_date>
+    <wp:post_name>description-only</wp:post_name>
+    <wp:status>publish</wp:status>
+    <wp:post_parent>0</wp:post_parent>
+    <wp:post_type>post</wp:post_type>
+  </item>
+</channel>
+</rss>`
+
+func TestContentIsFromContentEncoded(t *testing.T) {
+	t.Parallel()
+	info, err := NewParser().Parse(strings.NewReader(_teaserExport), nil, nil)
+	require.NoError(t, err)
+	require.Len(t, info.Posts(), 2)
+
+	require.Equal(t, "<p>The beginning of the post, and the rest of it.</p>", info.Posts()[0].Content)
+	require.Equal(t, "The beginning of the post [&hellip;]", info.Posts()[0].Description)
+	require.Equal(t, "<p>Only a description.</p>", info.Posts()[1].Content)
+}
+
+// Exported by an old exporter, WXR 1.0, with the content namespace declared with another URI and prefix
+const _wxr10ContentNamespaceExport = `<?xml version="1.0" encoding="UTF-8" ?>
+<rss version="2.0"
+  xmlns:c="https://purl.org/rss/1.0/modules/content"
+  xmlns:excerpt="http://wordpress.org/export/1.0/excerpt/"
+  xmlns:dc="http://purl.org/dc/elements/1.1/"
+  xmlns:wp="http://wordpress.org/export/1.0/">
+<channel>
+  <title>Example</title>
+  <link>https://example.org</link>
+  <wp:wxr_version>1.0</wp:wxr_version>
+  <item>
+    <title>Old post</title>
+    <link>https://example.org/old-post/</link>
+    <dc:creator><![CDATA[jdoe]]></dc:creator>
+    <description></description>
+    <excerpt:encoded><![CDATA[The excerpt]]></excerpt:encoded>
+    <c:encoded><![CDATA[<p>The body of the old post.</p>]]></c:encoded>
+    <wp:post_id>1</wp:post_id>
+    <wp:post_date>2009-01-01 10:00:00</wp:post_date>
+    <wp:post_name>old-post</wp:post_name>
+    <wp:status>publish</wp:status>
+    <wp:post_parent>0</wp:post_parent>
+    <wp:post_type>post</wp:post_type>
+  </item>
+</channel>
+</rss>`
+
+// Exported by a WXR 1.2 exporter which doesn't declare the content namespace
+const _wxr12UndeclaredContentNamespaceExport = `<?xml version="1.0" encoding="UTF-8" ?>
+<rss version="2.0"
+  xmlns:excerpt="http://wordpress.org/export/1.2/excerpt/"
+  xmlns:dc="http://purl.org/dc/elements/1.1/"
+  xmlns:wp="http://wordpress.org/export/1.2/">
+<channel>
+  <title>Example</title>
+  <link>https://example.org</link>
+  <wp:wxr_version>1.2</wp:wxr_version>
+  <item>
+    <title>New post</title>
+    <link>https://example.org/new-post/</link>
+    <dc:creator><![CDATA[jdoe]]></dc:creator>
+    <description></description>
+    <content:encoded><![CDATA[<!-- wp:paragraph --><p>The body of the new post.</p><!-- /wp:paragraph -->]]></content:encoded>
+    <excerpt:encoded><![CDATA[]]></excerpt:encoded>
+    <wp:post_id>2</wp:post_id>
+    <wp:post_date>2024-01-01 10:00:00</wp:post_date>
+    <wp:post_name>new-post</wp:post_name>
+    <wp:status>publish</wp:status>
+    <wp:post_parent>0</wp:post_parent>
+    <wp:post_type>post</wp:post_type>
+  </item>
+</channel>
+</rss>`
+
+func TestContentNamespaceVariations(t *testing.T) {
+	t.Parallel()
+	info, err := NewParser().Parse(strings.NewReader(_wxr10ContentNamespaceExport), nil, nil)
+	require.NoError(t, err)
+	require.Len(t, info.Posts(), 1)
+	require.Equal(t, "<p>The body of the old post.</p>", info.Posts()[0].Content)
+
+	// Over http, with the trailing slash, but with another prefix
+	info, err = NewParser().Parse(strings.NewReader(strings.Replace(_wxr10ContentNamespaceExport,
+		`xmlns:c="https://purl.org/rss/1.0/modules/content"`, `xmlns:c="http://purl.org/rss/1.0/modules/content/"`, 1)), nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, "<p>The body of the old post.</p>", info.Posts()[0].Content)
+
+	// The encoded element of another namespace is not the content
+	info, err = NewParser().Parse(strings.NewReader(strings.NewReplacer(
+		`xmlns:c="https://purl.org/rss/1.0/modules/content"`, `xmlns:c="https://example.org/plugin/"`,
+		"<description></description>", "<description>The teaser</description>").Replace(_wxr10ContentNamespaceExport)), nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, "The teaser", info.Posts()[0].Content)
+
+	info, err = NewParser().Parse(strings.NewReader(_wxr12UndeclaredContentNamespaceExport), nil, nil)
+	require.NoError(t, err)
+	require.Len(t, info.Posts(), 1)
+	require.Equal(t, "<!-- wp:paragraph --><p>The body of the new post.</p><!-- /wp:paragraph -->", info.Posts()[0].Content)
+}
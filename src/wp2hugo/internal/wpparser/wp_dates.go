@@ -0,0 +1,101 @@
+package wpparser
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	ext "github.com/mmcdole/gofeed/extensions"
+	"github.com/rs/zerolog/log"
+)
+
+// Layouts of the dates found in the exports, the first one being the WordPress default.
+// Dates without a timezone are parsed as UTC.
+var _timeLayouts = []string{
+	time.DateTime,
+	"2006-01-02T15:04:05",
+	time.RFC3339,
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02T15:04:05-0700",
+	time.DateOnly,
+}
+
+// WordPress writes this GMT date for the content which was never published, e.g. drafts
+const _zeroDate = "0000-00-00 00:00:00"
+
+func parseTime(value string) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range _timeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("error parsing time %q: unknown layout", value)
+}
+
+// getDate returns the date of the GMT field, or else of the local time field, e.g.
+// "post_date_gmt" and "post_date". It returns nil, with a warning, if neither can be parsed.
+func getDate(link string, fields map[string][]ext.Extension, gmtKey string, localKey string) *time.Time {
+	for _, key := range []string{gmtKey, localKey} {
+		values := fields[key]
+		if len(values) == 0 || strings.TrimSpace(values[0].Value) == "" || values[0].Value == _zeroDate {
+			continue
+		}
+		t, err := parseTime(values[0].Value)
+		if err != nil {
+			log.Warn().
+				Str("link", link).
+				Str(key, values[0].Value).
+				Err(err).
+				Msg("Error parsing date")
+			continue
+		}
+		return t
+	}
+	return nil
+}
+
+// WordPress' UTC offsets range from -12:00 to +14:00
+const _maxUTCOffset = 14 * time.Hour
+
+// getLocalDate returns the date of the local time field, e.g. "post_date", as displayed by WordPress,
+// in the UTC offset of the site at that time, derived from the GMT date. It returns the local time as UTC
+// if there is no GMT date, e.g. for the drafts, and nil if the field can't be parsed.
+func getLocalDate(link string, fields map[string][]ext.Extension, localKey string, gmtDate *time.Time) *time.Time {
+	values := fields[localKey]
+	if len(values) == 0 {
+		return nil
+	}
+	return parseLocalDate(link, localKey, values[0].Value, gmtDate)
+}
+
+// parseLocalDate returns the local time value in the UTC offset of the site, see getLocalDate
+func parseLocalDate(link string, localKey string, value string, gmtDate *time.Time) *time.Time {
+	if strings.TrimSpace(value) == "" || value == _zeroDate {
+		return nil
+	}
+	local, err := parseTime(value)
+	if err != nil {
+		log.Warn().
+			Str("link", link).
+			Str(localKey, value).
+			Err(err).
+			Msg("Error parsing date")
+		return nil
+	}
+	if gmtDate == nil {
+		return local
+	}
+	offset := local.Sub(*gmtDate).Round(time.Minute)
+	if offset.Abs() > _maxUTCOffset {
+		log.Warn().
+			Str("link", link).
+			Str(localKey, value).
+			Time("gmtDate", *gmtDate).
+			Msg("Local date too far from the GMT date, ignoring its UTC offset")
+		return local
+	}
+	date := gmtDate.In(time.FixedZone("", int(offset.Seconds())))
+	return &date
+}
@@ -0,0 +1,74 @@
+package wpparser
+
+import (
+	"testing"
+	"time"
+
+	ext "github.com/mmcdole/gofeed/extensions"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTime(t *testing.T) {
+	t.Parallel()
+	expected := time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)
+	for _, value := range []string{"2024-03-05 10:00:00", "2024-03-05T10:00:00", " 2024-03-05T10:00:00Z ", "2024-03-05 12:00:00+02:00", "2024-03-05T12:00:00+0200"} {
+		parsed, err := parseTime(value)
+		require.NoError(t, err, value)
+		require.True(t, expected.Equal(*parsed), value)
+	}
+
+	_, err := parseTime("5 March 2024")
+	require.Error(t, err)
+}
+
+func TestGetDate(t *testing.T) {
+	t.Parallel()
+	newFields := func(gmt string, local string) map[string][]ext.Extension {
+		return map[string][]ext.Extension{
+			"post_date_gmt": {{Value: gmt}},
+			"post_date":     {{Value: local}},
+		}
+	}
+
+	date := getDate("", newFields("2024-03-05 09:00:00", "2024-03-05 10:00:00"), "post_date_gmt", "post_date")
+	require.Equal(t, time.Date(2024, 3, 5, 9, 0, 0, 0, time.UTC), *date)
+
+	// Drafts have no GMT date
+	date = getDate("", newFields(_zeroDate, "2024-03-05 10:00:00"), "post_date_gmt", "post_date")
+	require.Equal(t, time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC), *date)
+
+	date = getDate("", newFields("invalid", "2024-03-05T10:00:00"), "post_date_gmt", "post_date")
+	require.Equal(t, time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC), *date)
+
+	require.Nil(t, getDate("", newFields("", "invalid"), "post_date_gmt", "post_date"))
+	require.Nil(t, getDate("", nil, "post_date_gmt", "post_date"))
+}
+
+func TestGetLocalDate(t *testing.T) {
+	t.Parallel()
+	fields := map[string][]ext.Extension{"post_date": {{Value: "2024-03-06 01:30:00"}}}
+	gmtDate := time.Date(2024, 3, 5, 20, 0, 0, 0, time.UTC)
+
+	// Published at 01:30 in UTC+05:30, which is still the day before in UTC
+	date := getLocalDate("", fields, "post_date", &gmtDate)
+	require.True(t, gmtDate.Equal(*date))
+	require.Equal(t, "2024-03-06T01:30:00+05:30", date.Format(time.RFC3339))
+	require.Equal(t, 6, date.Day())
+
+	// UTC-05:00, the local day is the day before the GMT one
+	gmtDate = time.Date(2024, 3, 6, 3, 0, 0, 0, time.UTC)
+	date = getLocalDate("", map[string][]ext.Extension{"post_date": {{Value: "2024-03-05 22:00:00"}}}, "post_date", &gmtDate)
+	require.Equal(t, "2024-03-05T22:00:00-05:00", date.Format(time.RFC3339))
+
+	// No GMT date, e.g. drafts
+	date = getLocalDate("", fields, "post_date", nil)
+	require.Equal(t, time.Date(2024, 3, 6, 1, 30, 0, 0, time.UTC), *date)
+
+	// Inconsistent dates
+	gmtDate = time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	date = getLocalDate("", fields, "post_date", &gmtDate)
+	require.Equal(t, time.Date(2024, 3, 6, 1, 30, 0, 0, time.UTC), *date)
+
+	require.Nil(t, getLocalDate("", map[string][]ext.Extension{"post_date": {{Value: _zeroDate}}}, "post_date", &gmtDate))
+	require.Nil(t, getLocalDate("", nil, "post_date", &gmtDate))
+}
@@ -0,0 +1,201 @@
+package wpparser
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ExportSummary is the structure of a WordPress export, to understand it before configuring its conversion
+type ExportSummary struct {
+	Title            string `json:"title"`
+	Link             string `json:"link"`
+	Language         string `json:"language,omitempty"`
+	WXRVersion       string `json:"wxr_version,omitempty"`
+	WordPressVersion string `json:"wordpress_version,omitempty"`
+
+	// Number of items of each post type in the export, converted or not, and of the ones which are not converted
+	PostTypes        map[string]int `json:"post_types"`
+	SkippedPostTypes map[string]int `json:"skipped_post_types,omitempty"`
+	// Number of posts, pages and custom posts of each status, e.g. "publish" or "draft"
+	Statuses map[PublishStatus]int `json:"statuses"`
+
+	Posts       int `json:"posts"`
+	Pages       int `json:"pages"`
+	CustomPosts int `json:"custom_posts"`
+	Attachments int `json:"attachments"`
+
+	// Number of terms of the categories, the tags and each custom taxonomy, e.g. "product_cat"
+	Categories int            `json:"categories"`
+	Tags       int            `json:"tags"`
+	Taxonomies map[string]int `json:"taxonomies,omitempty"`
+
+	Authors []AuthorSummary `json:"authors"`
+
+	// Range of the publish dates of the posts, pages and custom posts, nil if none is published
+	FirstPublishDate *time.Time `json:"first_publish_date,omitempty"`
+	LastPublishDate  *time.Time `json:"last_publish_date,omitempty"`
+
+	// Plugins detected from the postmeta keys and the shortcodes, see DetectPlugins
+	Plugins []DetectedPlugin `json:"plugins,omitempty"`
+	// Number of posts, pages and custom posts of each postmeta key
+	MetaKeys map[string]int `json:"meta_keys,omitempty"`
+
+	// Items which failed to parse and were skipped
+	SkippedItems int `json:"skipped_items"`
+}
+
+// AuthorSummary is an author of the export, with the number of posts, pages and custom posts they authored
+type AuthorSummary struct {
+	Login       string `json:"login"`
+	DisplayName string `json:"display_name"`
+	Content     int    `json:"content"`
+}
+
+// Summary returns the structure of the export: the counts of its content, taxonomies and authors,
+// the range of its publish dates and the plugins detected from its postmeta and shortcodes
+func (w *WebsiteInfo) Summary() ExportSummary {
+	summary := ExportSummary{
+		Title:            w.title,
+		Language:         w.language,
+		WXRVersion:       w.wxrVersion,
+		WordPressVersion: w.WordPressVersion(),
+		PostTypes:        w.postTypeCounts,
+		SkippedPostTypes: w.skippedPostTypeCounts,
+		Statuses:         make(map[PublishStatus]int),
+		Posts:            len(w.posts),
+		Pages:            len(w.pages),
+		CustomPosts:      len(w.customPosts),
+		Attachments:      len(w.attachments),
+		Categories:       len(w.categories),
+		Tags:             len(w.tags),
+		MetaKeys:         make(map[string]int),
+		SkippedItems:     len(w.skippedItems),
+	}
+	if w.link != nil {
+		summary.Link = w.link.String()
+	}
+	for _, taxonomy := range w.taxonomies {
+		if summary.Taxonomies == nil {
+			summary.Taxonomies = make(map[string]int)
+		}
+		summary.Taxonomies[taxonomy.Taxonomy]++
+	}
+
+	authoredContent := make(map[string]int)
+	for _, fields := range w.contentFields() {
+		summary.Statuses[fields.PublishStatus]++
+		authoredContent[fields.Author]++
+		if date := fields.PublishDate; date != nil {
+			if summary.FirstPublishDate == nil || date.Before(*summary.FirstPublishDate) {
+				summary.FirstPublishDate = date
+			}
+			if summary.LastPublishDate == nil || date.After(*summary.LastPublishDate) {
+				summary.LastPublishDate = date
+			}
+		}
+		for _, key := range getMetaKeys(fields.CustomMetaData) {
+			summary.MetaKeys[key]++
+		}
+	}
+	summary.Plugins = w.DetectPlugins()
+
+	summary.Authors = make([]AuthorSummary, 0, len(w.authors))
+	for _, author := range w.authors {
+		summary.Authors = append(summary.Authors, AuthorSummary{
+			Login:       author.Login,
+			DisplayName: author.DisplayName,
+			Content:     authoredContent[author.Login],
+		})
+	}
+	return summary
+}
+
+// contentFields returns the fields of the posts, pages and custom posts
+func (w *WebsiteInfo) contentFields() []CommonFields {
+	fields := make([]CommonFields, 0, len(w.posts)+len(w.pages)+len(w.customPosts))
+	for _, post := range w.posts {
+		fields = append(fields, post.CommonFields)
+	}
+	for _, page := range w.pages {
+		fields = append(fields, page.CommonFields)
+	}
+	for _, customPost := range w.customPosts {
+		fields = append(fields, customPost.CommonFields)
+	}
+	return fields
+}
+
+// getMetaKeys returns the distinct keys of the postmeta
+func getMetaKeys(metadata []CustomMetaDatum) []string {
+	var keys []string
+	for _, metadatum := range metadata {
+		if !slices.Contains(keys, metadatum.Key) {
+			keys = append(keys, metadatum.Key)
+		}
+	}
+	return keys
+}
+
+// WriteText writes the summary as text, for reading it in a terminal.
+// The postmeta keys are left out, they are only in the JSON.
+func (s ExportSummary) WriteText(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Site: %s (%s)\n", s.Title, s.Link)
+	if s.Language != "" {
+		fmt.Fprintf(&b, "Language: %s\n", s.Language)
+	}
+	fmt.Fprintf(&b, "Export: WXR %s, WordPress %s\n", valueOrUnknown(s.WXRVersion), valueOrUnknown(s.WordPressVersion))
+	fmt.Fprintf(&b, "Content: %d posts, %d pages, %d custom posts, %d attachments\n", s.Posts, s.Pages, s.CustomPosts, s.Attachments)
+	if s.FirstPublishDate != nil {
+		fmt.Fprintf(&b, "Published: %s to %s\n", s.FirstPublishDate.Format(time.DateOnly), s.LastPublishDate.Format(time.DateOnly))
+	}
+	writeCounts(&b, "Post types", s.PostTypes)
+	writeCounts(&b, "Skipped post types", s.SkippedPostTypes)
+	writeCounts(&b, "Statuses", s.Statuses)
+	fmt.Fprintf(&b, "Taxonomies:\n  category: %d\n  post_tag: %d\n", s.Categories, s.Tags)
+	for _, taxonomy := range slices.Sorted(maps.Keys(s.Taxonomies)) {
+		fmt.Fprintf(&b, "  %s: %d\n", taxonomy, s.Taxonomies[taxonomy])
+	}
+	fmt.Fprintf(&b, "Authors:\n")
+	for _, author := range s.Authors {
+		fmt.Fprintf(&b, "  %s (%s): %d\n", author.Login, author.DisplayName, author.Content)
+	}
+	if len(s.Plugins) > 0 {
+		fmt.Fprintf(&b, "Plugins:\n")
+	}
+	for _, plugin := range s.Plugins {
+		fmt.Fprintf(&b, "  %s: %d items, %s\n", plugin.Name, plugin.Items, plugin.Evidence)
+		if plugin.Hint != "" {
+			fmt.Fprintf(&b, "    %s\n", plugin.Hint)
+		}
+	}
+	if s.SkippedItems > 0 {
+		fmt.Fprintf(&b, "Skipped items: %d\n", s.SkippedItems)
+	}
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("error writing the export summary: %w", err)
+	}
+	return nil
+}
+
+// writeCounts writes the counts sorted by key, e.g. the number of items of each post type
+func writeCounts[K ~string](b *strings.Builder, title string, counts map[K]int) {
+	if len(counts) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "%s:\n", title)
+	for _, key := range slices.Sorted(maps.Keys(counts)) {
+		fmt.Fprintf(b, "  %s: %d\n", key, counts[key])
+	}
+}
+
+func valueOrUnknown(value string) string {
+	if value == "" {
+		return "unknown"
+	}
+	return value
+}
@@ -0,0 +1,52 @@
+package wpparser
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummary(t *testing.T) {
+	t.Parallel()
+	export := exportWithItems(
+		item("2", "page").withCreator("asmith").withStatus("draft").withDate("2012-03-04 10:00:00").
+			withPostmeta("_yoast_wpseo_title", "About").withPostmeta("price", "10").withPostmeta("_price", "field_5f3c1a2b3c4d5"),
+		item("3", "recipe").withCreator("asmith").withDate("2008-05-06 10:00:00").withPostmeta("_yoast_wpseo_metadesc", "Pie"),
+		item("4", "revision").withCreator("asmith").withStatus("inherit").withDate("2013-01-01 10:00:00"))
+	info, err := NewParser().Parse(strings.NewReader(export), nil, []string{"recipe"})
+	require.NoError(t, err)
+
+	summary := info.Summary()
+	require.Equal(t, "Example", summary.Title)
+	require.Equal(t, "2.9.2", summary.WordPressVersion)
+	require.Equal(t, map[string]int{"post": 1, "page": 1, "recipe": 1, "revision": 1}, summary.PostTypes)
+	require.Equal(t, map[string]int{"revision": 1}, summary.SkippedPostTypes)
+	require.Equal(t, map[PublishStatus]int{PublishStatusPublish: 2, PublishStatusDraft: 1}, summary.Statuses)
+	require.Equal(t, 1, summary.Posts)
+	require.Equal(t, 1, summary.Pages)
+	require.Equal(t, 1, summary.CustomPosts)
+	require.Equal(t, []AuthorSummary{
+		{Login: "jdoe", DisplayName: "jdoe", Content: 1},
+		{Login: "asmith", DisplayName: "asmith", Content: 2},
+	}, summary.Authors)
+	// The skipped revisions are left out of the date range
+	require.Equal(t, time.Date(2008, 5, 6, 10, 0, 0, 0, time.UTC), summary.FirstPublishDate.UTC())
+	require.Equal(t, time.Date(2012, 3, 4, 10, 0, 0, 0, time.UTC), summary.LastPublishDate.UTC())
+	require.Equal(t, []string{"Advanced Custom Fields", "Yoast SEO"}, []string{summary.Plugins[0].Name, summary.Plugins[1].Name})
+	require.Equal(t, "postmeta _price", summary.Plugins[0].Evidence)
+	require.Equal(t, 2, summary.Plugins[1].Items)
+	require.Equal(t, map[string]int{"_yoast_wpseo_title": 1, "_yoast_wpseo_metadesc": 1, "price": 1, "_price": 1}, summary.MetaKeys)
+
+	var buf bytes.Buffer
+	require.NoError(t, summary.WriteText(&buf))
+	require.Contains(t, buf.String(), "Content: 1 posts, 1 pages, 1 custom posts, 0 attachments\n")
+	require.Contains(t, buf.String(), "Published: 2008-05-06 to 2012-03-04\n")
+	require.Contains(t, buf.String(), "Statuses:\n  draft: 1\n  publish: 2\n")
+	require.Contains(t, buf.String(), "  asmith (asmith): 2\n")
+	require.Contains(t, buf.String(), "Plugins:\n  Advanced Custom Fields: 1 items, postmeta _price\n    its fields are decoded")
+	require.Contains(t, buf.String(), "  Yoast SEO: 2 items, postmeta _yoast_wpseo_title\n")
+	require.NotContains(t, buf.String(), "_yoast_wpseo_metadesc")
+}
@@ -0,0 +1,57 @@
+package wpparser
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/mmcdole/gofeed/rss"
+	"github.com/rs/zerolog/log"
+)
+
+// WordPress eXtended RSS (WXR) versions written by the WordPress exporter
+const (
+	// WXR 1.0 exports do not list the authors in the channel, nor the excerpts for older ones
+	_wxrVersion10 = "1.0"
+	_wxrVersion11 = "1.1"
+	_wxrVersion12 = "1.2"
+)
+
+var _knownWXRVersions = []string{_wxrVersion10, _wxrVersion11, _wxrVersion12}
+
+// WordPress identifies itself as "https://wordpress.org/?v=6.5.2" in the <generator> element
+var _wordPressGeneratorRegEx = regexp.MustCompile(`^https?://wordpress\.(?:org|com)/\?v=(.+)$`)
+
+func getWXRVersion(feed *rss.Feed) string {
+	values := feed.Extensions["wp"]["wxr_version"]
+	if len(values) == 0 || strings.TrimSpace(values[0].Value) == "" {
+		log.Warn().Msg("wp:wxr_version is missing, parsing the file as the latest known WXR version")
+		return ""
+	}
+	version := strings.TrimSpace(values[0].Value)
+	if !slices.Contains(_knownWXRVersions, version) {
+		log.Warn().
+			Str("wxrVersion", version).
+			Strs("knownVersions", _knownWXRVersions).
+			Msg("Unknown WXR version, parsing the file as the latest known WXR version")
+	}
+	return version
+}
+
+// getWordPressVersion returns the WordPress version from the <generator> element, if any
+func getWordPressVersion(generator string) string {
+	matches := _wordPressGeneratorRegEx.FindStringSubmatch(strings.TrimSpace(generator))
+	if matches == nil {
+		return ""
+	}
+	return matches[1]
+}
+
+// getExcerpt returns the <excerpt:encoded> of the item, missing from some WXR 1.0 exports
+func getExcerpt(item *rss.Item) string {
+	values := item.Extensions["excerpt"]["encoded"]
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0].Value
+}
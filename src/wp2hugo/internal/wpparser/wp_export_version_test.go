@@ -0,0 +1,58 @@
+package wpparser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// A WXR 1.0 export, without the <wp:author> entries nor the excerpt namespace
+const _wxr10Export = `<?xml version="1.0" encoding="UTF-8" ?>
+<rss version="2.0"
+  xmlns:content="http://purl.org/rss/1.0/modules/content/"
+  xmlns:dc="http://purl.org/dc/elements/1.1/"
+  xmlns:wp="http://wordpress.org/export/1.0/">
+<channel>
+  <title>Example</title>
+  <link>https://example.org</link>
+  <pubDate>Mon, 01 Jul 2024 08:49:45 +0000</pubDate>
+  <generator>http://wordpress.org/?v=2.9.2</generator>
+  <wp:wxr_version>1.0</wp:wxr_version>
+  <item>
+    <title>Hello world</title>
+    <link>https://example.org/hello-world/</link>
+    <dc:creator><![CDATA[jdoe]]></dc:creator>
+    <content:encoded><![CDATA[Hello]]></content:encoded>
+    <wp:post_id>1</wp:post_id>
+    <wp:post_date>2010-01-01 10:00:00</wp:post_date>
+    <wp:post_name>hello-world</wp:post_name>
+    <wp:status>publish</wp:status>
+    <wp:post_parent>0</wp:post_parent>
+    <wp:post_type>post</wp:post_type>
+  </item>
+</channel>
+</rss>`
+
+func TestParseWXR10Export(t *testing.T) {
+	t.Parallel()
+
+	info, err := NewParser().Parse(strings.NewReader(_wxr10Export), nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, "1.0", info.WXRVersion())
+	require.Equal(t, "http://wordpress.org/?v=2.9.2", info.Generator())
+	require.Equal(t, "2.9.2", info.WordPressVersion())
+
+	require.Len(t, info.Posts(), 1)
+	require.Empty(t, info.Posts()[0].Excerpt)
+	require.Equal(t, []AuthorInfo{{Login: "jdoe", DisplayName: "jdoe", Slug: "jdoe"}}, info.Authors())
+}
+
+func TestGetWordPressVersion(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "6.5.5", getWordPressVersion("https://wordpress.org/?v=6.5.5"))
+	require.Equal(t, "6.6-alpha-58000", getWordPressVersion(" https://wordpress.com/?v=6.6-alpha-58000 "))
+	require.Empty(t, getWordPressVersion("Blogger"))
+	require.Empty(t, getWordPressVersion(""))
+}
@@ -0,0 +1,164 @@
+package wpparser
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ParseFile parses the WordPress export at filePath, which may be gzipped, e.g. "export.xml.gz".
+// The parsing stops with the context error once ctx is cancelled.
+func (p *Parser) ParseFile(ctx context.Context, filePath string, authors []string, customPostTypes []string) (*WebsiteInfo, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error opening export file: %w", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	var reader io.Reader = file
+	if strings.HasSuffix(strings.ToLower(filePath), ".gz") {
+		gzipReader, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, fmt.Errorf("error decompressing export file '%s': %w", filePath, err)
+		}
+		defer func() {
+			_ = gzipReader.Close()
+		}()
+		reader = gzipReader
+	}
+	info, err := p.Parse(contextReader{ctx: ctx, reader: reader}, authors, customPostTypes)
+	if err != nil && ctx.Err() != nil {
+		// The feed parser may not wrap the read errors
+		return nil, ctx.Err()
+	}
+	return info, err
+}
+
+// Merge combines the files of a WordPress export split into several files, e.g. by a splitter plugin.
+// The site information comes from the first file, the content present in several files is kept once:
+// the posts, pages and custom posts keep their most recently modified version, the rest its first one.
+func Merge(infos ...*WebsiteInfo) (*WebsiteInfo, error) {
+	if len(infos) == 0 {
+		return nil, errors.New("no export to merge")
+	}
+	merged := *infos[0]
+	merged.reusableBlocks = maps.Clone(merged.reusableBlocks)
+	merged.acfFields = maps.Clone(merged.acfFields)
+	merged.customPostTypes = slices.Clone(merged.customPostTypes)
+	merged.postTypeCounts = make(map[string]int, len(merged.postTypeCounts))
+	maps.Copy(merged.postTypeCounts, infos[0].postTypeCounts)
+	merged.skippedPostTypeCounts = make(map[string]int, len(merged.skippedPostTypeCounts))
+	maps.Copy(merged.skippedPostTypeCounts, infos[0].skippedPostTypeCounts)
+	merged.skippedItems = slices.Clone(merged.skippedItems)
+	for _, info := range infos[1:] {
+		if info.link.Host != merged.link.Host {
+			log.Warn().
+				Str("link", info.link.String()).
+				Str("mergedLink", merged.link.String()).
+				Msg("Merging exports of different websites")
+		}
+		merged.categories = appendMissing(merged.categories, info.categories, func(c CategoryInfo) string { return c.ID })
+		merged.tags = appendMissing(merged.tags, info.tags, func(t TagInfo) string { return t.ID })
+		merged.taxonomies = appendMissing(merged.taxonomies, info.taxonomies, func(t TaxonomyInfo) string { return t.Taxonomy + "/" + t.Slug })
+		merged.authors = appendMissing(merged.authors, info.authors, func(a AuthorInfo) string { return a.Login })
+		merged.attachments = appendMissing(merged.attachments, info.attachments, func(a AttachmentInfo) string { return a.PostID })
+		merged.pages = appendLatest(merged.pages, info.pages, func(p PageInfo) CommonFields { return p.CommonFields })
+		merged.posts = appendLatest(merged.posts, info.posts, func(p PostInfo) CommonFields { return p.CommonFields })
+		merged.customPosts = appendLatest(merged.customPosts, info.customPosts, func(p CustomPostInfo) CommonFields { return p.CommonFields })
+		merged.navigationLinks = appendMissing(merged.navigationLinks, info.navigationLinks, func(l NavigationLink) string { return l.URL })
+		merged.customPostTypes = appendMissing(merged.customPostTypes, info.customPostTypes, func(t string) string { return t })
+		for blockID, block := range info.reusableBlocks {
+			if _, ok := merged.reusableBlocks[blockID]; !ok {
+				merged.reusableBlocks[blockID] = block
+			}
+		}
+		// Items of the split export, some of them may be repeated across the files
+		for postType, count := range info.postTypeCounts {
+			merged.postTypeCounts[postType] += count
+		}
+		merged.skippedItems = append(merged.skippedItems, info.skippedItems...)
+		for postType, count := range info.skippedPostTypeCounts {
+			merged.skippedPostTypeCounts[postType] += count
+		}
+		for fieldKey, field := range info.acfFields {
+			if _, ok := merged.acfFields[fieldKey]; !ok {
+				merged.acfFields[fieldKey] = field
+			}
+		}
+	}
+	merged.postIDToAttachmentCache = getPostIDToAttachmentsMap(merged.attachments)
+	log.Info().
+		Int("numFiles", len(infos)).
+		Int("numAttachments", len(merged.attachments)).
+		Int("numPages", len(merged.pages)).
+		Int("numPosts", len(merged.posts)).
+		Int("numCustomPosts", len(merged.customPosts)).
+		Msgf("Merged WebsiteInfo: %s", merged.title)
+	return &merged, nil
+}
+
+// appendLatest appends the content whose post ID is not in the result yet, in order.
+// The content present in both, e.g. in overlapping exports, is replaced in place if it was modified more recently.
+func appendLatest[T any](result []T, elements []T, fields func(T) CommonFields) []T {
+	indexes := make(map[string]int, len(result))
+	for i, element := range result {
+		indexes[fields(element).PostID] = i
+	}
+	// Copied, since the content is replaced in place
+	result = slices.Clone(result)
+	for _, element := range elements {
+		postID := fields(element).PostID
+		i, ok := indexes[postID]
+		if !ok {
+			indexes[postID] = len(result)
+			result = append(result, element)
+			continue
+		}
+		kept := fields(result[i])
+		isNewer := isModifiedAfter(fields(element).LastModifiedDate, kept.LastModifiedDate)
+		log.Warn().
+			Str("postID", postID).
+			Str("title", kept.Title).
+			Bool("keepingLaterFile", isNewer).
+			Msg("Content present in several export files, keeping its most recently modified version")
+		if isNewer {
+			result[i] = element
+		}
+	}
+	return result
+}
+
+// isModifiedAfter reports whether a was modified after b, the content without a modification date is the oldest
+func isModifiedAfter(a *time.Time, b *time.Time) bool {
+	if a == nil {
+		return false
+	}
+	return b == nil || a.After(*b)
+}
+
+// appendMissing appends the elements whose key is not in the result yet, in order
+func appendMissing[T any](result []T, elements []T, key func(T) string) []T {
+	keys := make(map[string]bool, len(result))
+	for _, element := range result {
+		keys[key(element)] = true
+	}
+	result = slices.Clip(result)
+	for _, element := range elements {
+		if k := key(element); !keys[k] {
+			keys[k] = true
+			result = append(result, element)
+		}
+	}
+	return result
+}
@@ -0,0 +1,93 @@
+package wpparser
+
+import (
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+	first, err := NewParser().Parse(strings.NewReader(_wxr10Export), nil, nil)
+	require.NoError(t, err)
+	// The second file of the split export repeats the first post
+	secondExport := strings.Replace(_wxr10Export, "</item>", `</item>
+  <item>
+    <title>Second post</title>
+    <link>https://example.org/second-post/</link>
+    <dc:creator><![CDATA[asmith]]></dc:creator>
+    <content:encoded><![CDATA[Second]]></content:encoded>
+    <wp:post_id>2</wp:post_id>
+    <wp:post_date>2010-01-02 10:00:00</wp:post_date>
+    <wp:post_name>second-post</wp:post_name>
+    <wp:status>publish</wp:status>
+    <wp:post_parent>0</wp:post_parent>
+    <wp:post_type>post</wp:post_type>
+  </item>`, 1)
+	second, err := NewParser().Parse(strings.NewReader(secondExport), nil, nil)
+	require.NoError(t, err)
+
+	merged, err := Merge(first, second)
+	require.NoError(t, err)
+	require.Len(t, merged.Posts(), 2)
+	require.Equal(t, "1", merged.Posts()[0].PostID)
+	require.Equal(t, "2", merged.Posts()[1].PostID)
+	require.Len(t, merged.Authors(), 2)
+	require.Equal(t, "Example", merged.Title())
+	// The inputs are left untouched
+	require.Len(t, first.Posts(), 1)
+
+	_, err = Merge()
+	require.Error(t, err)
+}
+
+func TestMergeDuplicatePostIDs(t *testing.T) {
+	t.Parallel()
+	modified := func(title string, modifiedDate string) string {
+		export := strings.Replace(_wxr10Export, "<title>Hello world</title>", "<title>"+title+"</title>", 1)
+		return strings.Replace(export, "<wp:post_date>", "<wp:post_modified_gmt>"+modifiedDate+"</wp:post_modified_gmt>\n    <wp:post_date>", 1)
+	}
+	older, err := NewParser().Parse(strings.NewReader(modified("Hello world", "2010-01-01 10:00:00")), nil, nil)
+	require.NoError(t, err)
+	newer, err := NewParser().Parse(strings.NewReader(modified("Hello world, edited", "2010-02-01 10:00:00")), nil, nil)
+	require.NoError(t, err)
+
+	merged, err := Merge(older, newer)
+	require.NoError(t, err)
+	require.Len(t, merged.Posts(), 1)
+	require.Equal(t, "Hello world, edited", merged.Posts()[0].Title)
+
+	// Whatever the order of the files
+	merged, err = Merge(newer, older)
+	require.NoError(t, err)
+	require.Len(t, merged.Posts(), 1)
+	require.Equal(t, "Hello world, edited", merged.Posts()[0].Title)
+	// The inputs are left untouched
+	require.Equal(t, "Hello world", older.Posts()[0].Title)
+}
+
+func TestParseGzippedFile(t *testing.T) {
+	t.Parallel()
+	filePath := filepath.Join(t.TempDir(), "export.xml.gz")
+	file, err := os.Create(filePath)
+	require.NoError(t, err)
+	w := gzip.NewWriter(file)
+	_, err = w.Write([]byte(_wxr10Export))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	require.NoError(t, file.Close())
+
+	info, err := NewParser().ParseFile(context.Background(), filePath, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, info.Posts(), 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = NewParser().ParseFile(ctx, filePath, nil, nil)
+	require.ErrorIs(t, err, context.Canceled)
+}
@@ -1,6 +1,8 @@
 package wpparser
 
 import (
+	"cmp"
+	"crypto/sha256"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -12,6 +14,7 @@ import (
 	"strings"
 	"time"
 	"unicode"
+	"unicode/utf8"
 
 	ext "github.com/mmcdole/gofeed/extensions"
 	"github.com/mmcdole/gofeed/rss"
@@ -24,13 +27,20 @@ import (
 
 const _filenameSizeLimit = 200
 
+// Number of hex characters of the hash suffix of the truncated filenames
+const _truncatedFilenameHashLength = 8
+
 var (
 	errTrashItem = errors.New("item is in trash")
 	// \p{L} matches any letter from any language while \w matches only ASCII letters
 	nonAlphanumericRegex = regexp.MustCompile(`[^\p{L}]+`)
 )
 
-type Parser struct{}
+type Parser struct {
+	// FailFast aborts the parsing on the first item which fails to parse,
+	// by default the item is skipped and listed in WebsiteInfo.SkippedItems
+	FailFast bool
+}
 
 func NewParser() *Parser {
 	return &Parser{}
@@ -57,7 +67,9 @@ type CommonFields struct {
 	Author           string
 	Title            string
 	Link             string     // Note that this is the absolute link for example https://example.com/about
+	Slug             string     // "post_name", URL-encoded when not ASCII, empty for the drafts which were never published
 	PublishDate      *time.Time // This can be nil since an item might have never been published
+	PublishDateLocal *time.Time // The same date in the UTC offset of the site, from "post_date"
 	LastModifiedDate *time.Time
 	PublishStatus    PublishStatus // "publish", "draft", "pending" etc. may be make this a custom type
 	GUID             *rss.GUID
@@ -68,6 +80,17 @@ type CommonFields struct {
 	// 2. "0" seems to be reserved for no parent, we replace that with nil
 	PostParentID *string // ID of the parent post, if any
 
+	// The content requires a password on WordPress, the password itself is not kept
+	PasswordProtected bool
+
+	// The post is pinned to the top of the home page, "wp:is_sticky", false if not exported
+	Sticky bool
+
+	// "open" or "closed", empty if not exported. The comments themselves are in Comments.
+	CommentStatus string
+	// "open" or "closed", closed if not exported
+	PingStatus string
+
 	Description string // how to use this?
 	Content     string
 	Excerpt     string // may be empty
@@ -111,12 +134,20 @@ func titleToFilename(title string) string {
 	if len(str1) > 1 {
 		str1 = strings.TrimSuffix(str1, "-")
 	}
+	// E.g. titles made only of emoji
+	if str1 == "-" {
+		str1 = ""
+	}
 
 	if len(str1) > _filenameSizeLimit {
 		log.Warn().
 			Str("title", title).
 			Msgf("Filename is too long, truncating to %d characters", _filenameSizeLimit)
 		str1 = str1[:_filenameSizeLimit]
+		// Do not cut a multibyte character in half
+		for !utf8.ValidString(str1) {
+			str1 = str1[:len(str1)-1]
+		}
 	}
 
 	return str1
@@ -144,6 +175,9 @@ func findSlugAndParams(parts []string) (string, string) {
 type FileInfo struct {
 	filename string
 	language *string
+
+	// Set when the filename was truncated by Truncate
+	originalFilename string
 }
 
 func (f FileInfo) FileNameWithLanguage() string {
@@ -162,6 +196,38 @@ func (f FileInfo) Language() *string {
 	return f.language
 }
 
+// Truncate shortens the filename to maxLength bytes, keeping a hash of the whole filename
+// as a suffix so that truncated filenames sharing a prefix stay distinct.
+// A maxLength of 0 keeps the filename as is.
+func (f FileInfo) Truncate(maxLength int) FileInfo {
+	if maxLength <= 0 || len(f.filename) <= maxLength {
+		return f
+	}
+	suffix := fmt.Sprintf("-%x", sha256.Sum256([]byte(f.filename)))[:_truncatedFilenameHashLength+1]
+	prefix := truncateSlug(f.filename, maxLength-len(suffix))
+	log.Warn().
+		Str("filename", f.filename).
+		Msgf("Filename is too long, truncating to %d characters", maxLength)
+	return FileInfo{
+		filename:         strings.TrimSuffix(prefix, "-") + suffix,
+		language:         f.language,
+		originalFilename: f.filename,
+	}
+}
+
+// IsTruncated reports whether Truncate shortened the filename
+func (f FileInfo) IsTruncated() bool {
+	return f.originalFilename != ""
+}
+
+// OriginalFileName returns the filename before Truncate
+func (f FileInfo) OriginalFileName() string {
+	if f.IsTruncated() {
+		return f.originalFilename
+	}
+	return f.filename
+}
+
 func (i CommonFields) GetFileInfo() FileInfo {
 	// Split canonical link path on /
 	parts := strings.Split(strings.TrimRight(i.Link, "/"), "/")
@@ -174,6 +240,8 @@ func (i CommonFields) GetFileInfo() FileInfo {
 		file, params = findSlugAndParams(parts)
 	}
 
+	file = stripEmojiFromSlug(file)
+
 	// Remove leading and trailing "-"
 	if len(file) > 1 {
 		file = strings.TrimPrefix(file, "-")
@@ -184,6 +252,13 @@ func (i CommonFields) GetFileInfo() FileInfo {
 	if len(file) == 0 {
 		file = titleToFilename((i.Title))
 	}
+	if len(file) == 0 {
+		log.Warn().
+			Str("title", i.Title).
+			Str("postID", i.PostID).
+			Msg("Unable to derive a filename from the link or the title, using the post ID")
+		file = "untitled-" + i.PostID
+	}
 
 	// Append language suffix if found in link
 	langRegex := regexp.MustCompile(`(?:\?|&)lang=([^&$]+)`)
@@ -199,6 +274,19 @@ func (i CommonFields) GetFileInfo() FileInfo {
 	}
 }
 
+// IsPublic reports whether the content is visible to anyone on the WordPress site
+func (i CommonFields) IsPublic() bool {
+	if i.PasswordProtected {
+		return false
+	}
+	switch i.PublishStatus {
+	case PublishStatusDraft, PublishStatusPending, PublishStatusPrivate:
+		return false
+	default:
+		return true
+	}
+}
+
 func (i CommonFields) GetAttachmentURL() *string {
 	return i.attachmentURL
 }
@@ -245,13 +333,22 @@ type CustomMetaDatum struct {
 // authors is a list of author names. If it is empty, all authors are considered.
 func (p *Parser) Parse(xmlData io.Reader, authors []string, customPostTypes []string) (*WebsiteInfo, error) {
 	fp := rss.Parser{}
-	feed, err := fp.Parse(InvalidatorCharacterRemover{reader: xmlData})
+	rssStartTag := &rssStartTagRecorder{reader: skipXMLPrologueNoise(xmlData)}
+	feed, err := fp.Parse(InvalidatorCharacterRemover{reader: rssStartTag})
 	if err != nil {
 		log.Warn().
 			Err(err).
 			Msgf("error parsing XML")
 		return nil, fmt.Errorf("error parsing XML: %w", err)
 	}
+	setContentFromNamespaces(feed.Items, getContentNamespacePrefixes(rssStartTag.data))
+	for _, postType := range customPostTypes {
+		if slices.Contains(_skippedPostTypes, postType) {
+			log.Warn().
+				Str("postType", postType).
+				Msg("Post type is internal to WordPress and never converted, ignoring it")
+		}
+	}
 	nonEmptyAuthors := make([]string, 0, len(authors))
 	for _, a := range authors {
 		a = strings.TrimSpace(a)
@@ -275,19 +372,41 @@ func (p *Parser) getWebsiteInfo(feed *rss.Feed, authors []string, customPostType
 	categories := getCategories(feed.Extensions["wp"]["category"])
 	tags := getTags(feed.Extensions["wp"]["tag"])
 	taxonomies := getTaxonomies(feed.Extensions["wp"]["term"])
+	wxrVersion := getWXRVersion(feed)
+	authorInfos := getAuthors(feed.Extensions["wp"]["author"])
+	if wxrVersion == _wxrVersion10 && len(authorInfos) == 0 {
+		authorInfos = getAuthorsFromItems(feed.Items)
+	}
 
 	attachments := make([]AttachmentInfo, 0)
 	pages := make([]PageInfo, 0)
 	posts := make([]PostInfo, 0)
 	customPosts := make([]CustomPostInfo, 0)
+	reusableBlocks := make(map[string]string)
+	acfFields := make(map[string]ACFField)
 	var navigationLinks []NavigationLink
+	postTypeCounts := make(map[string]int)
+	skippedPostTypeCounts := make(map[string]int)
+	var skippedItems []SkippedItem
 
 	for _, item := range feed.Items {
-		wpPostType := item.Extensions["wp"]["post_type"][0].Value
+		wpPostType := getWPField(item, "post_type")
+		postTypeCounts[wpPostType]++
+		if slices.Contains(_skippedPostTypes, wpPostType) {
+			// Even if listed in the custom post types
+			skippedPostTypeCounts[wpPostType]++
+			log.Debug().
+				Str("title", item.Title).
+				Str("type", wpPostType).
+				Msg("Skipping item of an internal type")
+			continue
+		}
 		switch wpPostType {
 		case "attachment":
 			if attachment, err := getAttachmentInfo(item, taxonomies); err != nil && !errors.Is(err, errTrashItem) {
-				return nil, err
+				if err := p.skipItem(&skippedItems, item, err); err != nil {
+					return nil, err
+				}
 			} else if attachment != nil && hasValidAuthor(authors, attachment.CommonFields) {
 				attachments = append(attachments, *attachment)
 				log.Debug().
@@ -297,7 +416,9 @@ func (p *Parser) getWebsiteInfo(feed *rss.Feed, authors []string, customPostType
 			}
 		case "page":
 			if page, err := getPageInfo(item, taxonomies); err != nil && !errors.Is(err, errTrashItem) {
-				return nil, err
+				if err := p.skipItem(&skippedItems, item, err); err != nil {
+					return nil, err
+				}
 			} else if page != nil {
 				if page.Content == "" && hasValidAuthor(authors, page.CommonFields) {
 					log.Warn().
@@ -312,7 +433,9 @@ func (p *Parser) getWebsiteInfo(feed *rss.Feed, authors []string, customPostType
 			}
 		case "post":
 			if post, err := getPostInfo(item, taxonomies); err != nil && !errors.Is(err, errTrashItem) {
-				return nil, err
+				if err := p.skipItem(&skippedItems, item, err); err != nil {
+					return nil, err
+				}
 			} else if post != nil && hasValidAuthor(authors, post.CommonFields) {
 				if post.Content == "" {
 					log.Warn().
@@ -326,18 +449,44 @@ func (p *Parser) getWebsiteInfo(feed *rss.Feed, authors []string, customPostType
 				posts = append(posts, *post)
 			}
 		case "wp_navigation":
-			var err error
-			navigationLinks, err = getNavigationLinks(item.Content)
+			links, err := getNavigationLinks(item.Content)
 			if err != nil {
-				return nil, fmt.Errorf("error getting navigation links: %w", err)
+				if err := p.skipItem(&skippedItems, item, fmt.Errorf("error getting navigation links: %w", err)); err != nil {
+					return nil, err
+				}
+			} else {
+				navigationLinks = links
+			}
+		case "wp_block":
+			// Gutenberg reusable blocks, referenced from other posts via <!-- wp:block {"ref":123} /-->
+			if block, err := getCommonFields(item, taxonomies); err != nil && !errors.Is(err, errTrashItem) {
+				if err := p.skipItem(&skippedItems, item, err); err != nil {
+					return nil, err
+				}
+			} else if block != nil {
+				reusableBlocks[block.PostID] = block.Content
+				log.Debug().
+					Str("postID", block.PostID).
+					Str("postType", wpPostType).
+					Msg("processing reusable block")
+			}
+		case "acf-field":
+			// Advanced Custom Fields definitions, used to resolve the fields stored in postmeta
+			if fields, err := getCommonFields(item, taxonomies); err != nil && !errors.Is(err, errTrashItem) {
+				if err := p.skipItem(&skippedItems, item, err); err != nil {
+					return nil, err
+				}
+			} else if fields != nil {
+				if field := getACFField(item, *fields); field != nil {
+					acfFields[field.Key] = *field
+				}
 			}
-		case "amp_validated_url", "nav_menu_item", "custom_css", "wp_global_styles":
-			// Ignoring these for now
-			continue
 		default:
 			if slices.Contains(customPostTypes, wpPostType) {
 				if customPost, err := getCustomPostInfo(item, taxonomies); err != nil && !errors.Is(err, errTrashItem) {
-					return nil, err
+					if err := p.skipItem(&skippedItems, item, err); err != nil {
+						return nil, err
+					}
 				} else if customPost != nil {
 					if customPost.Content == "" {
 						log.Warn().
@@ -351,6 +500,7 @@ func (p *Parser) getWebsiteInfo(feed *rss.Feed, authors []string, customPostType
 						Msg("processing post")
 				}
 			} else {
+				skippedPostTypeCounts[wpPostType]++
 				log.Info().
 					Str("title", item.Title).
 					Str("type", wpPostType).
@@ -364,24 +514,38 @@ func (p *Parser) getWebsiteInfo(feed *rss.Feed, authors []string, customPostType
 		return nil, fmt.Errorf("error parsing feed link: %w", err)
 	}
 
+	baseBlogURL := getBaseURL(feed, _baseBlogURLKey)
 	websiteInfo := WebsiteInfo{
 		title:       feed.Title,
-		link:        linkURL,
+		link:        getSiteLink(linkURL, baseBlogURL),
 		Description: feed.Description,
 		pubDate:     feed.PubDateParsed,
 		language:    feed.Language,
 
+		baseSiteURL: getBaseURL(feed, _baseSiteURLKey),
+		baseBlogURL: baseBlogURL,
+
+		wxrVersion: wxrVersion,
+		generator:  feed.Generator,
+
 		categories: categories,
 		tags:       tags,
 		taxonomies: taxonomies,
+		authors:    authorInfos,
 
 		attachments:     attachments,
 		pages:           pages,
 		posts:           posts,
 		customPosts:     customPosts,
 		navigationLinks: navigationLinks,
+		reusableBlocks:  reusableBlocks,
+		acfFields:       acfFields,
 
 		customPostTypes: customPostTypes,
+		postTypeCounts:  postTypeCounts,
+
+		skippedPostTypeCounts: skippedPostTypeCounts,
+		skippedItems:          skippedItems,
 
 		postIDToAttachmentCache: getPostIDToAttachmentsMap(attachments),
 	}
@@ -391,8 +555,13 @@ func (p *Parser) getWebsiteInfo(feed *rss.Feed, authors []string, customPostType
 		Int("numPosts", len(websiteInfo.posts)).
 		Int("numCustomPosts", len(websiteInfo.customPosts)).
 		Int("numNavigationLinks", len(websiteInfo.navigationLinks)).
+		Int("numReusableBlocks", len(websiteInfo.reusableBlocks)).
+		Int("numACFFields", len(websiteInfo.acfFields)).
 		Int("numCategories", len(categories)).
 		Int("numTags", len(tags)).
+		Int("numAuthors", len(authorInfos)).
+		Str("wxrVersion", wxrVersion).
+		Str("generator", feed.Generator).
 		Msgf("WebsiteInfo: %s", websiteInfo.title)
 	return &websiteInfo, nil
 }
@@ -410,30 +579,11 @@ func getAttachmentInfo(item *rss.Item, taxonomies []TaxonomyInfo) (*AttachmentIn
 }
 
 func getCommonFields(item *rss.Item, taxonomies []TaxonomyInfo) (*CommonFields, error) {
-	var lastModifiedDate *time.Time
-	values := item.Extensions["wp"]["post_modified_gmt"]
-	if len(values) > 0 {
-		var err error
-		lastModifiedDate, err = parseTime(values[0].Value)
-		if err != nil {
-			log.Warn().
-				Str("link", item.Link).
-				Str("date", item.Extensions["wp"]["post_modified_gmt"][0].Value).
-				Err(err).
-				Msg("Error parsing last modified date")
-		}
-	}
+	lastModifiedDate := getDate(item.Link, item.Extensions["wp"], "post_modified_gmt", "post_modified")
 
-	publishStatus := PublishStatus(item.Extensions["wp"]["status"][0].Value)
-	switch publishStatus {
-	case PublishStatusAttachment, PublishStatusDraft, PublishStatusFuture, PublishStatusInherit, PublishStatusPending,
-		PublishStatusPrivate, PublishStatusPublish, PublishStatusStatic:
-		// OK
-	case PublishStatusTrash:
-		return nil, fmt.Errorf("%w, ignored: %s", errTrashItem, item.Title)
-	default:
-		log.Warn().Msgf("Unknown publish status: '%s' for '%s'. Mapping to draft.", publishStatus, item.Title)
-		publishStatus = PublishStatusDraft
+	publishStatus, err := getPublishStatus(getWPField(item, "status"), item.Title)
+	if err != nil {
+		return nil, err
 	}
 	pageCategories := make([]string, 0, len(item.Categories))
 	pageTags := make([]string, 0, len(item.Categories))
@@ -505,17 +655,10 @@ func getCommonFields(item *rss.Item, taxonomies []TaxonomyInfo) (*CommonFields,
 	}
 
 	pubDate := item.PubDateParsed
-	if pubDate == nil && len(item.Extensions["wp"]["post_date"]) > 0 {
-		tmp, err := time.Parse("2006-01-02 15:04:05", item.Extensions["wp"]["post_date"][0].Value)
-		if err != nil {
-			log.Warn().
-				Str("link", item.Link).
-				Str("date", item.Extensions["wp"]["post_date"][0].Value).
-				Msg("Error parsing date")
-		} else {
-			pubDate = &tmp
-		}
+	if pubDate == nil {
+		pubDate = getDate(item.Link, item.Extensions["wp"], "post_date_gmt", "post_date")
 	}
+	pubDateLocal := getLocalDate(item.Link, item.Extensions["wp"], "post_date", pubDate)
 
 	var postType *string
 	if len(item.Extensions["wp"]["post_type"]) > 0 {
@@ -525,7 +668,7 @@ func getCommonFields(item *rss.Item, taxonomies []TaxonomyInfo) (*CommonFields,
 	}
 
 	var postParent *string
-	tmp := item.Extensions["wp"]["post_parent"][0].Value
+	tmp := getWPField(item, "post_parent")
 	if tmp != "0" && tmp != "" {
 		log.Debug().
 			Str("link", item.Link).
@@ -540,27 +683,18 @@ func getCommonFields(item *rss.Item, taxonomies []TaxonomyInfo) (*CommonFields,
 	if len(item.Extensions["wp"]["comment"]) > 0 {
 		for _, comment := range item.Extensions["wp"]["comment"] {
 			// Don't append spams and unapproved comments
-			if comment.Children["comment_approved"][0].Value == "1" {
-				var commentPubDate *time.Time
-				tmp, err := time.Parse("2006-01-02 15:04:05", comment.Children["comment_date"][0].Value)
-				if err != nil {
-					log.Warn().
-						Str("date", item.Extensions["wp"]["post_date"][0].Value).
-						Msg("Error parsing date")
-				} else {
-					commentPubDate = &tmp
-				}
-
+			if getChildValue(comment, "comment_approved") == "1" {
+				commentPubDate := getDate(item.Link, comment.Children, "comment_date_gmt", "comment_date")
 				comments = append(comments, CommentInfo{
-					ID:          comment.Children["comment_id"][0].Value,
-					ParentID:    comment.Children["comment_parent"][0].Value,
-					AuthorName:  comment.Children["comment_author"][0].Value,
-					AuthorEmail: comment.Children["comment_author_email"][0].Value,
-					AuthorURL:   comment.Children["comment_author_url"][0].Value,
+					ID:          getChildValue(comment, "comment_id"),
+					ParentID:    getChildValue(comment, "comment_parent"),
+					AuthorName:  getChildValue(comment, "comment_author"),
+					AuthorEmail: getChildValue(comment, "comment_author_email"),
+					AuthorURL:   getChildValue(comment, "comment_author_url"),
 					PublishDate: commentPubDate,
-					Content:     comment.Children["comment_content"][0].Value,
+					Content:     getChildValue(comment, "comment_content"),
 					PostLink:    item.Link,
-					PostID:      item.Extensions["wp"]["post_id"][0].Value,
+					PostID:      getWPField(item, "post_id"),
 				})
 			}
 		}
@@ -568,20 +702,22 @@ func getCommonFields(item *rss.Item, taxonomies []TaxonomyInfo) (*CommonFields,
 
 	return &CommonFields{
 		Author:           getAuthor(item),
-		PostID:           item.Extensions["wp"]["post_id"][0].Value,
+		PostID:           getWPField(item, "post_id"),
 		Title:            item.Title,
 		Link:             item.Link,
+		Slug:             strings.TrimSpace(getWPField(item, "post_name")),
 		PublishDate:      pubDate,
+		PublishDateLocal: pubDateLocal,
 		GUID:             item.GUID,
 		LastModifiedDate: lastModifiedDate,
 		PublishStatus:    publishStatus,
 		PostFormat:       postFormat,
 		PostType:         postType,
 		PostParentID:     postParent,
-		Excerpt:          item.Extensions["excerpt"]["encoded"][0].Value,
+		Excerpt:          getExcerpt(item),
 
 		Description:     item.Description,
-		Content:         item.Content,
+		Content:         getContent(item),
 		Categories:      pageCategories,
 		CustomMetaData:  pageCustomMetaData,
 		Tags:            pageTags,
@@ -591,10 +727,30 @@ func getCommonFields(item *rss.Item, taxonomies []TaxonomyInfo) (*CommonFields,
 
 		attachmentURL: attachmentURL,
 
-		Comments: comments,
+		PasswordProtected: getWPField(item, "post_password") != "",
+		Sticky:            getWPField(item, "is_sticky") == "1",
+
+		CommentStatus: getDiscussionStatus(item, "comment_status"),
+		PingStatus:    cmp.Or(getDiscussionStatus(item, "ping_status"), CommentStatusClosed),
+		Comments:      comments,
 	}, nil
 }
 
+// getPublishStatus returns the status of the item, mapping the unknown ones to drafts, or errTrashItem for the trash
+func getPublishStatus(status string, title string) (PublishStatus, error) {
+	publishStatus := PublishStatus(status)
+	switch publishStatus {
+	case PublishStatusAttachment, PublishStatusDraft, PublishStatusFuture, PublishStatusInherit, PublishStatusPending,
+		PublishStatusPrivate, PublishStatusPublish, PublishStatusStatic:
+		return publishStatus, nil
+	case PublishStatusTrash:
+		return "", fmt.Errorf("%w, ignored: %s", errTrashItem, title)
+	default:
+		log.Warn().Msgf("Unknown publish status: '%s' for '%s'. Mapping to draft.", publishStatus, title)
+		return PublishStatusDraft, nil
+	}
+}
+
 func hasValidAuthor(authors []string, fields CommonFields) bool {
 	if len(authors) == 0 {
 		return true
@@ -616,12 +772,22 @@ func getAuthor(item *rss.Item) string {
 	if len(author) > 0 {
 		return author
 	}
-	if item.Extensions["dc"] != nil && item.Extensions["dc"]["creator"] != nil {
+	if len(item.Extensions["dc"]["creator"]) > 0 {
 		return item.Extensions["dc"]["creator"][0].Value
 	}
 	return ""
 }
 
+// getWPField returns the value of the wp field of the item, e.g. "wp:post_type", empty if not exported.
+// Some plugins trim the fields of their exports, so that none of them can be assumed.
+func getWPField(item *rss.Item, key string) string {
+	values := item.Extensions["wp"][key]
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0].Value
+}
+
 func getNavigationLinks(content string) ([]NavigationLink, error) {
 	// Extract all HTML comments
 	htmlCommentExtractor := regexp.MustCompile(`<!--(.*?)-->`)
@@ -702,12 +868,20 @@ func isTag(tag *rss.Category) bool {
 }
 
 func isTaxonomy(taxonomy *rss.Category, taxonomies []TaxonomyInfo) *TaxonomyInfo {
+	var domainTaxonomy *TaxonomyInfo
 	for _, tax := range taxonomies {
-		if tax.Taxonomy == taxonomy.Domain {
+		if tax.Taxonomy != taxonomy.Domain {
+			continue
+		}
+		if tax.Name == taxonomy.Value {
 			return &tax
 		}
+		if domainTaxonomy == nil {
+			domainTaxonomy = &tax
+		}
 	}
-	return nil
+	// The term is not declared in the export, fallback to the first term of the taxonomy
+	return domainTaxonomy
 }
 
 // NormalizeCategoryName removes space from the category name and converts it to lowercase
@@ -769,9 +943,11 @@ func getCategories(inputs []ext.Extension) []CategoryInfo {
 		}
 		category := CategoryInfo{
 			// ID is usually int but for safety let's assume string
-			ID:       input.Children["term_id"][0].Value,
+			ID:       getChildValue(input, "term_id"),
 			Name:     categoryName,
 			NiceName: categoryNiceName,
+			Order:    getTermOrder(input),
+			Meta:     getTermMeta(input),
 			// We are ignoring "category_parent" for now as I have never used it
 		}
 		log.Trace().Msgf("category: %+v", category)
@@ -786,7 +962,7 @@ func getTags(inputs []ext.Extension) []TagInfo {
 		var tagName string
 		if len(input.Children["tag_name"]) == 0 {
 			// Fallback
-			tagName = input.Children["tag_slug"][0].Value
+			tagName = getChildValue(input, "tag_slug")
 			log.Warn().
 				Any("input", input).
 				Msg("tag_name is missing")
@@ -795,9 +971,11 @@ func getTags(inputs []ext.Extension) []TagInfo {
 		}
 		tag := TagInfo{
 			// ID is usually int but for safety let's assume string
-			ID:   input.Children["term_id"][0].Value,
-			Name: NormalizeCategoryName(tagName),
-			Slug: input.Children["tag_slug"][0].Value,
+			ID:    getChildValue(input, "term_id"),
+			Name:  NormalizeCategoryName(tagName),
+			Slug:  getChildValue(input, "tag_slug"),
+			Order: getTermOrder(input),
+			Meta:  getTermMeta(input),
 		}
 		log.Trace().Msgf("tag: %+v", tag)
 		categories = append(categories, tag)
@@ -838,6 +1016,8 @@ func buildTaxonomy(term ext.Extension) TaxonomyInfo {
 		Parent:   parent,
 		Name:     name,
 		Slug:     slug,
+		Order:    getTermOrder(term),
+		Meta:     getTermMeta(term),
 	}
 }
 
@@ -921,14 +1101,6 @@ func getThumbnailID(item *rss.Item) *string {
 	return nil
 }
 
-func parseTime(utcTime string) (*time.Time, error) {
-	t, err := time.Parse("2006-01-02 15:04:05", utcTime)
-	if err != nil {
-		return nil, fmt.Errorf("error parsing time: %w", err)
-	}
-	return &t, nil
-}
-
 // keys returns the keys of the map m.
 // The keys will be an indeterminate order.
 func keys[M ~map[K]V, K comparable, V any](m M) []K {
@@ -1,10 +1,13 @@
 package wpparser
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
 	ext "github.com/mmcdole/gofeed/extensions"
 	"github.com/mmcdole/gofeed/rss"
+	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/require"
 )
 
@@ -44,3 +47,198 @@ func newRSSItemWithStatus(status string) *rss.Item {
 		},
 	}
 }
+
+func TestIsPublic(t *testing.T) {
+	t.Parallel()
+
+	for status, expected := range map[string]bool{
+		string(PublishStatusPublish): true,
+		string(PublishStatusFuture):  true,
+		string(PublishStatusPrivate): false,
+		string(PublishStatusDraft):   false,
+		string(PublishStatusPending): false,
+	} {
+		fields, err := getCommonFields(newRSSItemWithStatus(status), nil)
+		require.NoError(t, err)
+		require.Equal(t, expected, fields.IsPublic(), status)
+	}
+
+	item := newRSSItemWithStatus(string(PublishStatusPublish))
+	item.Extensions["wp"]["post_password"] = []ext.Extension{{Value: "secret"}}
+	fields, err := getCommonFields(item, nil)
+	require.NoError(t, err)
+	require.True(t, fields.PasswordProtected)
+	require.False(t, fields.IsPublic())
+}
+
+func TestGetCommonFields_CommentStatus(t *testing.T) {
+	t.Parallel()
+
+	item := newRSSItemWithStatus(string(PublishStatusPublish))
+	item.Extensions["wp"]["comment_status"] = []ext.Extension{{Value: "open"}}
+	item.Extensions["wp"]["ping_status"] = []ext.Extension{{Value: " Closed "}}
+	fields, err := getCommonFields(item, nil)
+	require.NoError(t, err)
+	require.Equal(t, CommentStatusOpen, fields.CommentStatus)
+	require.Equal(t, CommentStatusClosed, fields.PingStatus)
+	require.True(t, fields.CommentsOpen())
+
+	// Not exported
+	fields, err = getCommonFields(newRSSItemWithStatus(string(PublishStatusPublish)), nil)
+	require.NoError(t, err)
+	require.Empty(t, fields.CommentStatus)
+	require.False(t, fields.CommentsOpen())
+}
+
+func TestGetCommonFields_TrimmedFields(t *testing.T) {
+	t.Parallel()
+
+	item := newRSSItemWithStatus(string(PublishStatusPublish))
+	delete(item.Extensions["wp"], "post_parent")
+	delete(item.Extensions, "excerpt")
+	fields, err := getCommonFields(item, nil)
+	require.NoError(t, err)
+	require.False(t, fields.Sticky)
+	require.Equal(t, CommentStatusClosed, fields.PingStatus)
+	require.Nil(t, fields.PostParentID)
+	require.Empty(t, fields.Slug)
+
+	item.Extensions["wp"]["is_sticky"] = []ext.Extension{{Value: "1"}}
+	item.Extensions["wp"]["post_name"] = []ext.Extension{{Value: " hello-world\n"}}
+	fields, err = getCommonFields(item, nil)
+	require.NoError(t, err)
+	require.True(t, fields.Sticky)
+	require.Equal(t, "hello-world", fields.Slug)
+}
+
+func BenchmarkParse(b *testing.B) {
+	// The logs would dominate the timings
+	logLevel := zerolog.GlobalLevel()
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	b.Cleanup(func() {
+		zerolog.SetGlobalLevel(logLevel)
+	})
+	for _, numPosts := range []int{10, 100, 1_000} {
+		export := syntheticExport(numPosts)
+		b.Run(fmt.Sprintf("posts_%d", numPosts), func(b *testing.B) {
+			b.SetBytes(int64(len(export)))
+			for b.Loop() {
+				_, err := NewParser().Parse(strings.NewReader(export), nil, nil)
+				require.NoError(b, err)
+			}
+		})
+	}
+}
+
+// wxrItem is an item of a WXR export, see item
+type wxrItem struct {
+	postID   string
+	postType string
+	creator  string
+	content  string
+	status   string
+	date     string
+	postmeta string
+}
+
+// item returns a published item of the post type, titled "Item <post ID>",
+// whose other fields are set with the with* methods
+func item(postID string, postType string) wxrItem {
+	return wxrItem{
+		postID:   postID,
+		postType: postType,
+		creator:  "jdoe",
+		content:  "Content",
+		status:   "publish",
+		date:     "2010-01-01 10:00:00",
+	}
+}
+
+func (i wxrItem) withCreator(creator string) wxrItem {
+	i.creator = creator
+	return i
+}
+
+func (i wxrItem) withContent(content string) wxrItem {
+	i.content = content
+	return i
+}
+
+func (i wxrItem) withStatus(status string) wxrItem {
+	i.status = status
+	return i
+}
+
+func (i wxrItem) withDate(date string) wxrItem {
+	i.date = date
+	return i
+}
+
+func (i wxrItem) withPostmeta(key string, value string) wxrItem {
+	i.postmeta += `
+    <wp:postmeta><wp:meta_key><![CDATA[` + key + `]]></wp:meta_key><wp:meta_value><![CDATA[` + value + `]]></wp:meta_value></wp:postmeta>`
+	return i
+}
+
+func (i wxrItem) String() string {
+	return `
+  <item>
+    <title>Item ` + i.postID + `</title>
+    <link>https://example.org/item-` + i.postID + `/</link>
+    <dc:creator><![CDATA[` + i.creator + `]]></dc:creator>
+    <content:encoded><![CDATA[` + i.content + `]]></content:encoded>
+    <wp:post_id>` + i.postID + `</wp:post_id>
+    <wp:post_date>` + i.date + `</wp:post_date>
+    <wp:post_date_gmt>` + i.date + `</wp:post_date_gmt>
+    <wp:post_name>item-` + i.postID + `</wp:post_name>
+    <wp:status>` + i.status + `</wp:status>
+    <wp:post_parent>0</wp:post_parent>
+    <wp:post_type>` + i.postType + `</wp:post_type>` + i.postmeta + `
+  </item>`
+}
+
+// exportWithItems returns _wxr10Export, which has a single post, with the items added
+func exportWithItems(items ...wxrItem) string {
+	var sb strings.Builder
+	for _, i := range items {
+		sb.WriteString(i.String())
+	}
+	return strings.Replace(_wxr10Export, "</channel>", sb.String()+"\n</channel>", 1)
+}
+
+// syntheticExport returns an export of numPosts posts, each with a category, a tag, a postmeta and a comment
+func syntheticExport(numPosts int) string {
+	var sb strings.Builder
+	sb.WriteString(`<?xml version="1.0" encoding="UTF-8" ?>
+<rss version="2.0" xmlns:excerpt="http://wordpress.org/export/1.2/excerpt/" xmlns:content="http://purl.org/rss/1.0/modules/content/"
+  xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:wp="http://wordpress.org/export/1.2/">
+<channel>
+  <title>Example</title>
+  <link>https://example.org</link>
+  <wp:wxr_version>1.2</wp:wxr_version>
+  <wp:category><wp:term_id>1</wp:term_id><wp:category_nicename><![CDATA[news]]></wp:category_nicename><wp:cat_name><![CDATA[News]]></wp:cat_name></wp:category>
+`)
+	for i := 1; i <= numPosts; i++ {
+		fmt.Fprintf(&sb, `  <item>
+    <title><![CDATA[Post %[1]d]]></title>
+    <link>https://example.org/post-%[1]d/</link>
+    <dc:creator><![CDATA[jdoe]]></dc:creator>
+    <guid isPermaLink="false">https://example.org/?p=%[1]d</guid>
+    <content:encoded><![CDATA[<p>Paragraph of post %[1]d with a <a href="https://example.org/post-1/">link</a>.</p><p>Another paragraph.</p>]]></content:encoded>
+    <excerpt:encoded><![CDATA[]]></excerpt:encoded>
+    <wp:post_id>%[1]d</wp:post_id>
+    <wp:post_date_gmt><![CDATA[2024-03-05 10:00:00]]></wp:post_date_gmt>
+    <wp:post_name><![CDATA[post-%[1]d]]></wp:post_name>
+    <wp:status><![CDATA[publish]]></wp:status>
+    <wp:post_parent>0</wp:post_parent>
+    <wp:post_type><![CDATA[post]]></wp:post_type>
+    <category domain="category" nicename="news"><![CDATA[News]]></category>
+    <category domain="post_tag" nicename="tag-%[1]d"><![CDATA[Tag %[1]d]]></category>
+    <wp:postmeta><wp:meta_key><![CDATA[_edit_last]]></wp:meta_key><wp:meta_value><![CDATA[1]]></wp:meta_value></wp:postmeta>
+    <wp:comment><wp:comment_id>%[1]d</wp:comment_id><wp:comment_author><![CDATA[A Reader]]></wp:comment_author><wp:comment_date_gmt><![CDATA[2024-03-06 09:00:00]]></wp:comment_date_gmt><wp:comment_content><![CDATA[Nice.]]></wp:comment_content><wp:comment_approved><![CDATA[1]]></wp:comment_approved></wp:comment>
+  </item>
+`, i)
+	}
+	sb.WriteString("</channel>\n</rss>\n")
+	return sb.String()
+}
@@ -0,0 +1,15 @@
+package wpparser
+
+// PathOverrideKey is the postmeta overriding the output path of the content, relative to the content dir,
+// e.g. "about/index.md". It is an escape hatch for the special pages of a migration.
+const PathOverrideKey = "_wp2hugo_path"
+
+// GetPathOverride returns the output path set in the PathOverrideKey postmeta, if any
+func (i CommonFields) GetPathOverride() (string, bool) {
+	for _, metadatum := range i.CustomMetaData {
+		if metadatum.Key == PathOverrideKey && metadatum.Value != "" {
+			return metadatum.Value, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,161 @@
+package wpparser
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// DetectedPlugin is a plugin, or a theme with a page builder, which produced some of the content of the export,
+// detected heuristically from the postmeta keys it stores and the shortcodes it renders, see DetectPlugins
+type DetectedPlugin struct {
+	Name string `json:"name"`
+	// What it was detected from, e.g. "postmeta _yoast_wpseo_title" or "shortcode [vc_row]"
+	Evidence string `json:"evidence"`
+	// Number of posts, pages and custom posts with its postmeta or shortcodes
+	Items int `json:"items"`
+	// What special handling to enable for its content, or what to review
+	Hint string `json:"hint,omitempty"`
+	// Its content is likely to convert poorly, e.g. the layouts of the page builders
+	ConvertsPoorly bool `json:"converts_poorly,omitempty"`
+}
+
+type pluginSignature struct {
+	name string
+	// Prefixes of the postmeta keys and the shortcode names, e.g. "vc_" for [vc_row] and [vc_column]
+	metaKeyPrefixes   []string
+	shortcodePrefixes []string
+	hint              string
+	convertsPoorly    bool
+}
+
+const _unhandledShortcodesHint = "its shortcodes are not converted, see --strip-shortcodes unhandled and --annotate-issues"
+
+var _pluginSignatures = []pluginSignature{
+	{name: "Yoast SEO", metaKeyPrefixes: []string{"_yoast_wpseo_"},
+		hint: "its SEO titles, descriptions and robots are converted, see --seo-title-separator and --noindex-exclusion"},
+	{name: "Rank Math", metaKeyPrefixes: []string{"rank_math_"},
+		hint: "its robots are converted, see --noindex-exclusion"},
+	{name: "All in One SEO", metaKeyPrefixes: []string{"_aioseo_", "_aioseop_"},
+		hint: "its sitemap exclusions are converted, see --noindex-exclusion"},
+	{name: "Jetpack", metaKeyPrefixes: []string{"_jetpack_", "jetpack_", "_wpcom_"},
+		hint: "the content written with Jetpack Markdown (_wpcom_is_markdown) is kept as Markdown with --source-is-markdown"},
+	{name: "Elementor", metaKeyPrefixes: []string{"_elementor_"},
+		hint:           "the layouts of its _elementor_data postmeta are not converted, only the HTML fallback of the content, review these pages",
+		convertsPoorly: true},
+	{name: "WPBakery Page Builder", metaKeyPrefixes: []string{"_wpb_"}, shortcodePrefixes: []string{"vc_"},
+		hint: _unhandledShortcodesHint, convertsPoorly: true},
+	{name: "Divi Builder", metaKeyPrefixes: []string{"_et_pb_"}, shortcodePrefixes: []string{"et_pb_"},
+		hint: _unhandledShortcodesHint, convertsPoorly: true},
+	{name: "Avada Fusion Builder", metaKeyPrefixes: []string{"_fusion"}, shortcodePrefixes: []string{"fusion_"},
+		hint: _unhandledShortcodesHint, convertsPoorly: true},
+	{name: "Shortcodes Ultimate", shortcodePrefixes: []string{"su_"},
+		hint: _unhandledShortcodesHint},
+	{name: "Contact Form 7", shortcodePrefixes: []string{"contact-form-7"},
+		hint: "its forms are not converted, they need a form service on the Hugo site"},
+	{name: "Gravity Forms", shortcodePrefixes: []string{"gravityform"},
+		hint: "its forms are not converted, they need a form service on the Hugo site"},
+	{name: "WooCommerce", metaKeyPrefixes: []string{"_sku", "_regular_price"},
+		hint: "its products are converted, with their price, SKU and variations with --woocommerce"},
+	{name: "Post Expirator", metaKeyPrefixes: []string{"_expiration-date"},
+		hint: "its unpublish dates are converted, see --expiry-date-meta"},
+	{name: "WPML", metaKeyPrefixes: []string{"_wpml_"},
+		hint: "the translations are converted from the ?lang= parameter of their links, see doc/translation.md"},
+}
+
+// Advanced Custom Fields references its field keys from the "_fieldname" postmeta, see getACFFields
+var _acfSignature = pluginSignature{name: "Advanced Custom Fields", hint: "its fields are decoded into front matter params with --acf-fields"}
+
+// The name of the opening shortcodes, e.g. "vc_row" for [vc_row width="full"]
+var _openingShortcodeRegEx = regexp.MustCompile(`\[([a-zA-Z][\w-]*)[\s\]/]`)
+
+// DetectPlugins returns the plugins which produced the posts, pages and custom posts, sorted by name.
+// The detection is heuristic, the plugins which store no postmeta and render no shortcodes are not detected.
+func (w *WebsiteInfo) DetectPlugins() []DetectedPlugin {
+	detected := make(map[string]*DetectedPlugin)
+	detect := func(signature pluginSignature, evidence string) {
+		plugin, ok := detected[signature.name]
+		if !ok {
+			plugin = &DetectedPlugin{Name: signature.name, Evidence: evidence, Hint: signature.hint,
+				ConvertsPoorly: signature.convertsPoorly}
+			detected[signature.name] = plugin
+		}
+		plugin.Items++
+	}
+	for _, fields := range w.contentFields() {
+		shortcodes := getShortcodeNames(fields.Content)
+		for _, signature := range _pluginSignatures {
+			if evidence, ok := signature.match(fields.CustomMetaData, shortcodes); ok {
+				detect(signature, evidence)
+			}
+		}
+		if key, ok := getACFFieldReference(fields.CustomMetaData); ok {
+			detect(_acfSignature, "postmeta "+key)
+		}
+	}
+	if _, ok := detected[_acfSignature.name]; !ok && len(w.acfFields) > 0 {
+		// Field groups defined, but no content using them
+		detected[_acfSignature.name] = &DetectedPlugin{Name: _acfSignature.name, Evidence: "post type acf-field", Hint: _acfSignature.hint}
+	}
+
+	plugins := make([]DetectedPlugin, 0, len(detected))
+	for _, plugin := range detected {
+		plugins = append(plugins, *plugin)
+	}
+	slices.SortFunc(plugins, func(a, b DetectedPlugin) int { return strings.Compare(a.Name, b.Name) })
+	return plugins
+}
+
+// GetPageBuilder returns the page builder the content was built with, e.g. "Elementor", detected like DetectPlugins,
+// its layout is not converted
+func GetPageBuilder(fields CommonFields) (string, bool) {
+	shortcodes := getShortcodeNames(fields.Content)
+	for _, signature := range _pluginSignatures {
+		if !signature.convertsPoorly {
+			continue
+		}
+		if _, ok := signature.match(fields.CustomMetaData, shortcodes); ok {
+			return signature.name, true
+		}
+	}
+	return "", false
+}
+
+// match returns the first postmeta key or shortcode of the signature, as the evidence of the plugin
+func (s pluginSignature) match(metadata []CustomMetaDatum, shortcodes []string) (string, bool) {
+	for _, metadatum := range metadata {
+		for _, prefix := range s.metaKeyPrefixes {
+			if strings.HasPrefix(metadatum.Key, prefix) {
+				return "postmeta " + metadatum.Key, true
+			}
+		}
+	}
+	for _, shortcode := range shortcodes {
+		for _, prefix := range s.shortcodePrefixes {
+			if strings.HasPrefix(shortcode, prefix) {
+				return "shortcode [" + shortcode + "]", true
+			}
+		}
+	}
+	return "", false
+}
+
+func getACFFieldReference(metadata []CustomMetaDatum) (string, bool) {
+	for _, metadatum := range metadata {
+		if strings.HasPrefix(metadatum.Key, "_") && strings.HasPrefix(metadatum.Value, "field_") {
+			return metadatum.Key, true
+		}
+	}
+	return "", false
+}
+
+// getShortcodeNames returns the distinct names of the shortcodes opened in the content, in order
+func getShortcodeNames(content string) []string {
+	var names []string
+	for _, match := range _openingShortcodeRegEx.FindAllStringSubmatch(content, -1) {
+		if !slices.Contains(names, match[1]) {
+			names = append(names, match[1])
+		}
+	}
+	return names
+}
@@ -0,0 +1,39 @@
+package wpparser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectPlugins(t *testing.T) {
+	t.Parallel()
+	export := exportWithItems(
+		item("2", "page").withContent(`<div>Fallback</div>`).
+			withPostmeta("_elementor_edit_mode", "builder").withPostmeta("_elementor_data", "[]"),
+		item("3", "page").withContent(`[vc_row][vc_column]Text[/vc_column][/vc_row] [contact-form-7 id="1"]`),
+		item("4", "page").withContent(`[vc_row/]`),
+		// Neither shortcodes of a plugin nor shortcodes
+		item("5", "page").withContent(`An [array] of [1] and [vc_row`))
+	info, err := NewParser().Parse(strings.NewReader(export), nil, nil)
+	require.NoError(t, err)
+
+	require.Equal(t, []DetectedPlugin{
+		{Name: "Contact Form 7", Evidence: "shortcode [contact-form-7]", Items: 1,
+			Hint: "its forms are not converted, they need a form service on the Hugo site"},
+		{Name: "Elementor", Evidence: "postmeta _elementor_edit_mode", Items: 1,
+			Hint:           "the layouts of its _elementor_data postmeta are not converted, only the HTML fallback of the content, review these pages",
+			ConvertsPoorly: true},
+		{Name: "WPBakery Page Builder", Evidence: "shortcode [vc_row]", Items: 2, Hint: _unhandledShortcodesHint, ConvertsPoorly: true},
+	}, info.DetectPlugins())
+
+	builder, ok := GetPageBuilder(info.Pages()[0].CommonFields)
+	require.True(t, ok)
+	require.Equal(t, "Elementor", builder)
+	builder, ok = GetPageBuilder(info.Pages()[1].CommonFields)
+	require.True(t, ok)
+	require.Equal(t, "WPBakery Page Builder", builder)
+	_, ok = GetPageBuilder(info.Pages()[3].CommonFields)
+	require.False(t, ok)
+}
@@ -0,0 +1,48 @@
+package wpparser
+
+import (
+	"slices"
+
+	"github.com/rs/zerolog/log"
+)
+
+// FilterPostIDs returns the website info with only the posts, pages and custom posts of the included post IDs,
+// or all of them if none is included, less the excluded post IDs, e.g. to convert a single post again.
+// The attachments, reusable blocks and ACF fields are kept, like with OnlyPostTypes.
+func (w *WebsiteInfo) FilterPostIDs(includedIDs []string, excludedIDs []string) *WebsiteInfo {
+	for _, postID := range slices.Concat(includedIDs, excludedIDs) {
+		if !w.hasContent(postID) {
+			log.Warn().
+				Str("postID", postID).
+				Msg("No post, page or custom post with the ID in the export")
+		}
+	}
+	isKept := func(fields CommonFields) bool {
+		return (len(includedIDs) == 0 || slices.Contains(includedIDs, fields.PostID)) &&
+			!slices.Contains(excludedIDs, fields.PostID)
+	}
+	filtered := *w
+	filtered.postIDsFiltered = true
+	filtered.posts = slices.DeleteFunc(slices.Clone(w.posts), func(p PostInfo) bool { return !isKept(p.CommonFields) })
+	filtered.pages = slices.DeleteFunc(slices.Clone(w.pages), func(p PageInfo) bool { return !isKept(p.CommonFields) })
+	filtered.customPosts = slices.DeleteFunc(slices.Clone(w.customPosts), func(p CustomPostInfo) bool { return !isKept(p.CommonFields) })
+	log.Info().
+		Strs("includedIDs", includedIDs).
+		Strs("excludedIDs", excludedIDs).
+		Int("numPages", len(filtered.pages)).
+		Int("numPosts", len(filtered.posts)).
+		Int("numCustomPosts", len(filtered.customPosts)).
+		Msg("Only converting the content of the post IDs")
+	return &filtered
+}
+
+// PostIDsFiltered reports whether the content was filtered with FilterPostIDs, it is then only a part of the site
+func (w *WebsiteInfo) PostIDsFiltered() bool {
+	return w.postIDsFiltered
+}
+
+func (w *WebsiteInfo) hasContent(postID string) bool {
+	return slices.ContainsFunc(w.posts, func(p PostInfo) bool { return p.PostID == postID }) ||
+		slices.ContainsFunc(w.pages, func(p PageInfo) bool { return p.PostID == postID }) ||
+		slices.ContainsFunc(w.customPosts, func(p CustomPostInfo) bool { return p.PostID == postID })
+}
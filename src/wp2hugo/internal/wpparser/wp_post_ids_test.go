@@ -0,0 +1,36 @@
+package wpparser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterPostIDs(t *testing.T) {
+	t.Parallel()
+	export := exportWithItems(item("2", "page"), item("3", "product"), item("4", "product"), item("5", "attachment"))
+	info, err := NewParser().Parse(strings.NewReader(export), nil, []string{"product"})
+	require.NoError(t, err)
+
+	included := info.FilterPostIDs([]string{"2", "3", "404"}, nil)
+	require.Empty(t, included.Posts())
+	require.Len(t, included.Pages(), 1)
+	require.Len(t, included.CustomPosts(), 1)
+	require.Equal(t, "3", included.CustomPosts()[0].PostID)
+	require.Len(t, included.Attachments(), 1)
+	// The website info is left untouched
+	require.Len(t, info.CustomPosts(), 2)
+
+	excluded := info.FilterPostIDs(nil, []string{"2", "3"})
+	require.Len(t, excluded.Posts(), 1)
+	require.Empty(t, excluded.Pages())
+	require.Len(t, excluded.CustomPosts(), 1)
+	require.Equal(t, "4", excluded.CustomPosts()[0].PostID)
+
+	// The excluded IDs take precedence, and the filter combines with the post types
+	both := info.OnlyPostTypes([]string{"product"}).FilterPostIDs([]string{"2", "3", "4"}, []string{"4"})
+	require.Empty(t, both.Pages())
+	require.Len(t, both.CustomPosts(), 1)
+	require.Equal(t, "3", both.CustomPosts()[0].PostID)
+}
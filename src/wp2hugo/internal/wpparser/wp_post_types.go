@@ -0,0 +1,55 @@
+package wpparser
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Post types internal to WordPress, which are never converted, even if listed in the custom post types:
+// the revisions of "all content" exports or database dumps, the menu items, which are read from wp_navigation,
+// and the site editor, customizer and plugin data
+var _skippedPostTypes = []string{
+	"revision", "nav_menu_item", "custom_css", "customize_changeset", "oembed_cache", "user_request",
+	"wp_global_styles", "wp_template", "wp_template_part", "wp_font_family", "wp_font_face", "amp_validated_url",
+}
+
+// SkippedPostTypeCounts returns the number of items of each post type which is not converted,
+// the internal post types and the unknown ones which are not imported as custom post types
+func (w *WebsiteInfo) SkippedPostTypeCounts() map[string]int {
+	return w.skippedPostTypeCounts
+}
+
+// PostTypeCounts returns the number of items of each post type in the export, including the ones which are not converted,
+// e.g. the attachments or the custom post types which are not imported, to discover the post types to target
+func (w *WebsiteInfo) PostTypeCounts() map[string]int {
+	return w.postTypeCounts
+}
+
+// OnlyPostTypes returns the website info with only the posts, pages and custom posts of these post types, e.g. "product".
+// The attachments, reusable blocks and ACF fields are kept, since the content of these post types may use them.
+func (w *WebsiteInfo) OnlyPostTypes(postTypes []string) *WebsiteInfo {
+	for _, postType := range postTypes {
+		if w.postTypeCounts[postType] == 0 {
+			log.Warn().
+				Str("postType", postType).
+				Strs("postTypes", slices.Sorted(maps.Keys(w.postTypeCounts))).
+				Msg("No item of the post type in the export")
+		}
+	}
+	isKept := func(fields CommonFields) bool {
+		return fields.PostType != nil && slices.Contains(postTypes, *fields.PostType)
+	}
+	filtered := *w
+	filtered.posts = slices.DeleteFunc(slices.Clone(w.posts), func(p PostInfo) bool { return !isKept(p.CommonFields) })
+	filtered.pages = slices.DeleteFunc(slices.Clone(w.pages), func(p PageInfo) bool { return !isKept(p.CommonFields) })
+	filtered.customPosts = slices.DeleteFunc(slices.Clone(w.customPosts), func(p CustomPostInfo) bool { return !isKept(p.CommonFields) })
+	log.Info().
+		Strs("postTypes", postTypes).
+		Int("numPages", len(filtered.pages)).
+		Int("numPosts", len(filtered.posts)).
+		Int("numCustomPosts", len(filtered.customPosts)).
+		Msg("Only converting the content of the post types")
+	return &filtered
+}
@@ -0,0 +1,52 @@
+package wpparser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOnlyPostTypes(t *testing.T) {
+	t.Parallel()
+	export := exportWithItems(item("2", "page"), item("3", "product"), item("4", "product"), item("5", "recipe"), item("6", "attachment"))
+	info, err := NewParser().Parse(strings.NewReader(export), nil, []string{"product"})
+	require.NoError(t, err)
+	// The post types which are not imported, e.g. "recipe", are counted too
+	require.Equal(t, map[string]int{"post": 1, "page": 1, "product": 2, "recipe": 1, "attachment": 1}, info.PostTypeCounts())
+
+	products := info.OnlyPostTypes([]string{"product"})
+	require.Empty(t, products.Posts())
+	require.Empty(t, products.Pages())
+	require.Len(t, products.CustomPosts(), 2)
+	require.Len(t, products.Attachments(), 1)
+	require.Equal(t, info.PostTypeCounts(), products.PostTypeCounts())
+	// The website info is left untouched
+	require.Len(t, info.Posts(), 1)
+	require.Len(t, info.CustomPosts(), 2)
+
+	postsAndPages := info.OnlyPostTypes([]string{"post", "page", "unknown"})
+	require.Len(t, postsAndPages.Posts(), 1)
+	require.Len(t, postsAndPages.Pages(), 1)
+	require.Empty(t, postsAndPages.CustomPosts())
+
+	// The items of the split exports add up
+	merged, err := Merge(info, info)
+	require.NoError(t, err)
+	require.Equal(t, 4, merged.PostTypeCounts()["product"])
+	require.Equal(t, 2, info.PostTypeCounts()["product"])
+	require.Equal(t, map[string]int{"recipe": 2}, merged.SkippedPostTypeCounts())
+}
+
+func TestSkippedPostTypes(t *testing.T) {
+	t.Parallel()
+	export := exportWithItems(item("2", "revision").withStatus("inherit"), item("3", "revision").withStatus("inherit"),
+		item("4", "nav_menu_item"), item("5", "recipe"))
+	// The internal post types never match the custom post types
+	info, err := NewParser().Parse(strings.NewReader(export), nil, []string{"revision"})
+	require.NoError(t, err)
+	require.Len(t, info.Posts(), 1)
+	require.Empty(t, info.CustomPosts())
+	require.Equal(t, map[string]int{"revision": 2, "nav_menu_item": 1, "recipe": 1}, info.SkippedPostTypeCounts())
+	require.Equal(t, 2, info.PostTypeCounts()["revision"])
+}
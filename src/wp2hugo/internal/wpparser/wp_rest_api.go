@@ -0,0 +1,540 @@
+package wpparser
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"maps"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mmcdole/gofeed/rss"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+)
+
+// RESTAPISource is a live WordPress site read through its REST API, /wp-json/wp/v2/, instead of an export
+type RESTAPISource struct {
+	// SiteURL is the URL of the site, e.g. https://example.com
+	SiteURL string
+	// Username and ApplicationPassword authenticate the requests, with an application password of the user,
+	// for reading the drafts and the private content, and the raw content instead of the rendered one.
+	// They are only sent over https, or to the local machine.
+	Username            string
+	ApplicationPassword string
+	// CacheDir keeps the pages of the collections fetched so far, a run interrupted, e.g. by the rate limiting,
+	// resumes from the ones fetched within the last day. It is removed once the whole site is fetched.
+	CacheDir string
+}
+
+// The index of the REST API, at /wp-json/
+type restAPIIndex struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	URL         string `json:"url"`
+	Home        string `json:"home"`
+}
+
+// A field of the REST API, rendered in the "view" context, and raw as well in the "edit" one
+type restAPIField struct {
+	Raw       *string `json:"raw"`
+	Rendered  string  `json:"rendered"`
+	Protected bool    `json:"protected"`
+}
+
+// value returns the raw field if known, or else the rendered one
+func (f restAPIField) value() string {
+	if f.Raw != nil {
+		return *f.Raw
+	}
+	return f.Rendered
+}
+
+// text returns the raw field if known, or else the rendered one without its entities, e.g. of a title
+func (f restAPIField) text() string {
+	if f.Raw != nil {
+		return *f.Raw
+	}
+	return html.UnescapeString(f.Rendered)
+}
+
+// A post, page, custom post or media item of the REST API
+type restAPIPost struct {
+	ID            int             `json:"id"`
+	Date          string          `json:"date"`
+	DateGMT       *string         `json:"date_gmt"`
+	ModifiedGMT   *string         `json:"modified_gmt"`
+	GUID          restAPIField    `json:"guid"`
+	Link          string          `json:"link"`
+	Slug          string          `json:"slug"`
+	Status        string          `json:"status"`
+	Type          string          `json:"type"`
+	Password      string          `json:"password"`
+	Title         restAPIField    `json:"title"`
+	Content       restAPIField    `json:"content"`
+	Excerpt       restAPIField    `json:"excerpt"`
+	Author        int             `json:"author"`
+	FeaturedMedia int             `json:"featured_media"`
+	Parent        int             `json:"parent"`
+	CommentStatus string          `json:"comment_status"`
+	PingStatus    string          `json:"ping_status"`
+	Sticky        bool            `json:"sticky"`
+	Format        string          `json:"format"`
+	Categories    []int           `json:"categories"`
+	Tags          []int           `json:"tags"`
+	Meta          json.RawMessage `json:"meta"`
+
+	// The media items only
+	Post        *int         `json:"post"`
+	SourceURL   string       `json:"source_url"`
+	Caption     restAPIField `json:"caption"`
+	Description restAPIField `json:"description"`
+}
+
+type restAPITerm struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+type restAPIUser struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	Slug      string `json:"slug"`
+	Username  string `json:"username"`
+	Email     string `json:"email"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+}
+
+type restAPIComment struct {
+	ID          int          `json:"id"`
+	Post        int          `json:"post"`
+	Parent      int          `json:"parent"`
+	AuthorName  string       `json:"author_name"`
+	AuthorEmail string       `json:"author_email"`
+	AuthorURL   string       `json:"author_url"`
+	DateGMT     string       `json:"date_gmt"`
+	Content     restAPIField `json:"content"`
+}
+
+type restAPIPostType struct {
+	RestBase string `json:"rest_base"`
+}
+
+// The terms and the users, keyed by their ID, the posts refer to them by ID
+type restAPILookups struct {
+	categories map[int]string
+	tags       map[int]string
+	authors    map[int]string
+	comments   map[int][]restAPIComment
+}
+
+// ParseRESTAPI fetches the posts, pages, custom posts, media, categories, tags, authors and approved comments
+// of a live WordPress site from its REST API into the WebsiteInfo, like Parse does for an export.
+// Without the credentials of RESTAPISource, only the published content is read, rendered by WordPress.
+func (p *Parser) ParseRESTAPI(ctx context.Context, source RESTAPISource, authors []string, customPostTypes []string,
+) (*WebsiteInfo, error) {
+	client, index, err := newRESTAPIClient(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+	log.Info().
+		Str("site", source.SiteURL).
+		Bool("authenticated", client.isAuthenticated()).
+		Msg("Reading the website from its REST API")
+
+	query := url.Values{}
+	postQuery := url.Values{}
+	if client.isAuthenticated() {
+		query.Set("context", "edit")
+		postQuery.Set("context", "edit")
+		postQuery.Set("status", "any")
+	}
+
+	categoryTerms, err := getAll[restAPITerm](ctx, client, "/wp/v2/categories", query)
+	if err != nil {
+		return nil, err
+	}
+	tagTerms, err := getAll[restAPITerm](ctx, client, "/wp/v2/tags", query)
+	if err != nil {
+		return nil, err
+	}
+	// The security plugins often block the users and the comments routes, the content is read without them
+	users, err := getAll[restAPIUser](ctx, client, "/wp/v2/users", query)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		log.Warn().
+			Err(err).
+			Msg("Error reading the users of the site, the content has no author")
+	}
+	comments, err := getAll[restAPIComment](ctx, client, "/wp/v2/comments", query)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		log.Warn().
+			Err(err).
+			Msg("Error reading the comments of the site, the content has no comment")
+	}
+
+	lookups := restAPILookups{
+		categories: make(map[int]string, len(categoryTerms)),
+		tags:       make(map[int]string, len(tagTerms)),
+		authors:    make(map[int]string, len(users)),
+		comments:   make(map[int][]restAPIComment),
+	}
+	categories := make([]CategoryInfo, 0, len(categoryTerms))
+	for _, term := range categoryTerms {
+		lookups.categories[term.ID] = term.Name
+		categories = append(categories, CategoryInfo{ID: strconv.Itoa(term.ID), Name: term.Name, NiceName: term.Slug})
+	}
+	tags := make([]TagInfo, 0, len(tagTerms))
+	for _, term := range tagTerms {
+		lookups.tags[term.ID] = term.Name
+		tags = append(tags, TagInfo{ID: strconv.Itoa(term.ID), Name: term.Name, Slug: term.Slug})
+	}
+	authorInfos := make([]AuthorInfo, 0, len(users))
+	for _, user := range users {
+		// The login is only readable when authenticated, the slug stands in for it
+		login := cmp.Or(user.Username, user.Slug)
+		lookups.authors[user.ID] = login
+		authorInfos = append(authorInfos, AuthorInfo{
+			ID:          strconv.Itoa(user.ID),
+			Login:       login,
+			Email:       user.Email,
+			DisplayName: user.Name,
+			FirstName:   user.FirstName,
+			LastName:    user.LastName,
+		})
+	}
+	setAuthorSlugs(authorInfos)
+	for _, comment := range comments {
+		lookups.comments[comment.Post] = append(lookups.comments[comment.Post], comment)
+	}
+
+	postTypeCounts := make(map[string]int)
+	var skippedItems []SkippedItem
+	getItems := func(route string, query url.Values) ([]CommonFields, error) {
+		items, err := getAll[restAPIPost](ctx, client, route, query)
+		if err != nil {
+			return nil, err
+		}
+		fields := make([]CommonFields, 0, len(items))
+		for _, item := range items {
+			postTypeCounts[item.Type]++
+			itemFields, err := getRESTAPICommonFields(item, lookups)
+			if errors.Is(err, errTrashItem) {
+				continue
+			} else if err != nil {
+				if p.FailFast {
+					return nil, err
+				}
+				skippedItem := SkippedItem{
+					PostID:   strconv.Itoa(item.ID),
+					PostType: item.Type,
+					Title:    item.Title.text(),
+					Link:     item.Link,
+					Error:    err.Error(),
+				}
+				log.Warn().
+					Err(err).
+					Str("postID", skippedItem.PostID).
+					Str("title", skippedItem.Title).
+					Msg("Skipping item which failed to parse")
+				skippedItems = append(skippedItems, skippedItem)
+				continue
+			}
+			fields = append(fields, *itemFields)
+		}
+		return fields, nil
+	}
+
+	attachments := make([]AttachmentInfo, 0)
+	mediaItems, err := getItems("/wp/v2/media", query)
+	if err != nil {
+		return nil, err
+	}
+	for _, fields := range mediaItems {
+		if hasValidAuthor(authors, fields) {
+			attachments = append(attachments, AttachmentInfo{fields})
+		}
+	}
+	pages := make([]PageInfo, 0)
+	pageItems, err := getItems("/wp/v2/pages", postQuery)
+	if err != nil {
+		return nil, err
+	}
+	for _, fields := range pageItems {
+		pages = append(pages, PageInfo{fields})
+	}
+	posts := make([]PostInfo, 0)
+	postItems, err := getItems("/wp/v2/posts", postQuery)
+	if err != nil {
+		return nil, err
+	}
+	for _, fields := range postItems {
+		if hasValidAuthor(authors, fields) {
+			posts = append(posts, PostInfo{fields})
+		}
+	}
+
+	customPosts := make([]CustomPostInfo, 0)
+	if len(customPostTypes) > 0 {
+		var postTypes map[string]restAPIPostType
+		if _, err := client.get(ctx, "/wp/v2/types", nil, &postTypes); err != nil {
+			return nil, err
+		}
+		for _, postType := range customPostTypes {
+			restAPIPostType, ok := postTypes[postType]
+			if !ok || restAPIPostType.RestBase == "" {
+				log.Debug().
+					Str("postType", postType).
+					Msg("Post type not exposed by the REST API, skipped")
+				continue
+			}
+			items, err := getItems("/wp/v2/"+restAPIPostType.RestBase, postQuery)
+			if err != nil {
+				return nil, err
+			}
+			for _, fields := range items {
+				customPosts = append(customPosts, CustomPostInfo{fields})
+			}
+		}
+	}
+
+	language := ""
+	if client.isAuthenticated() {
+		var settings struct {
+			Language string `json:"language"`
+		}
+		if _, err := client.get(ctx, "/wp/v2/settings", nil, &settings); err != nil {
+			log.Warn().
+				Err(err).
+				Msg("Error reading the settings of the site, its language is unknown")
+		} else {
+			language = strings.ReplaceAll(settings.Language, "_", "-")
+		}
+	}
+
+	linkURL, err := url.Parse(cmp.Or(index.Home, source.SiteURL))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing site URL: %w", err)
+	}
+	var baseSiteURL *url.URL
+	if siteURL, err := url.Parse(index.URL); err == nil && index.URL != "" {
+		baseSiteURL = siteURL
+	}
+	websiteInfo := WebsiteInfo{
+		title:       html.UnescapeString(index.Name),
+		link:        linkURL,
+		Description: html.UnescapeString(index.Description),
+		language:    language,
+
+		baseSiteURL: baseSiteURL,
+		baseBlogURL: linkURL,
+
+		categories: categories,
+		tags:       tags,
+		authors:    authorInfos,
+
+		attachments:    attachments,
+		pages:          pages,
+		posts:          posts,
+		customPosts:    customPosts,
+		reusableBlocks: make(map[string]string),
+		acfFields:      make(map[string]ACFField),
+
+		customPostTypes: customPostTypes,
+		postTypeCounts:  postTypeCounts,
+
+		skippedPostTypeCounts: make(map[string]int),
+		skippedItems:          skippedItems,
+
+		postIDToAttachmentCache: getPostIDToAttachmentsMap(attachments),
+	}
+	if source.CacheDir != "" {
+		if err := os.RemoveAll(source.CacheDir); err != nil {
+			log.Warn().
+				Err(err).
+				Str("cacheDir", source.CacheDir).
+				Msg("Error removing the REST API cache")
+		}
+	}
+	log.Info().
+		Int("numAttachments", len(websiteInfo.attachments)).
+		Int("numPages", len(websiteInfo.pages)).
+		Int("numPosts", len(websiteInfo.posts)).
+		Int("numCustomPosts", len(websiteInfo.customPosts)).
+		Int("numCategories", len(categories)).
+		Int("numTags", len(tags)).
+		Int("numAuthors", len(authorInfos)).
+		Msgf("WebsiteInfo: %s", websiteInfo.title)
+	return &websiteInfo, nil
+}
+
+func getRESTAPICommonFields(item restAPIPost, lookups restAPILookups) (*CommonFields, error) {
+	title := item.Title.text()
+	publishStatus, err := getPublishStatus(item.Status, title)
+	if err != nil {
+		return nil, err
+	}
+
+	var pubDate *time.Time
+	if item.DateGMT != nil && *item.DateGMT != "" {
+		if pubDate, err = parseTime(*item.DateGMT); err != nil {
+			return nil, err
+		}
+	}
+	pubDateLocal := parseLocalDate(item.Link, "date", item.Date, pubDate)
+	if pubDate == nil {
+		pubDate = pubDateLocal
+	}
+	var lastModifiedDate *time.Time
+	if item.ModifiedGMT != nil && *item.ModifiedGMT != "" {
+		if lastModifiedDate, err = parseTime(*item.ModifiedGMT); err != nil {
+			return nil, err
+		}
+	}
+
+	pageCategories := make([]string, 0, len(item.Categories))
+	for _, id := range item.Categories {
+		if name, ok := lookups.categories[id]; ok {
+			pageCategories = append(pageCategories, NormalizeCategoryName(name))
+		}
+	}
+	pageTags := make([]string, 0, len(item.Tags))
+	for _, id := range item.Tags {
+		if name, ok := lookups.tags[id]; ok {
+			pageTags = append(pageTags, NormalizeCategoryName(name))
+		}
+	}
+	var postFormat *string
+	if item.Format != "" && item.Format != "standard" {
+		postFormat = lo.ToPtr(item.Format)
+	}
+
+	var postParent *string
+	if item.Post != nil && *item.Post != 0 {
+		postParent = lo.ToPtr(strconv.Itoa(*item.Post))
+	} else if item.Parent != 0 {
+		postParent = lo.ToPtr(strconv.Itoa(item.Parent))
+	}
+	var featuredImageID *string
+	if item.FeaturedMedia != 0 {
+		featuredImageID = lo.ToPtr(strconv.Itoa(item.FeaturedMedia))
+	}
+
+	customMetaData, footnotes := getRESTAPIMeta(item)
+
+	content := item.Content.value()
+	excerpt := ""
+	// The rendered excerpt is generated from the content when there is no manual excerpt
+	if item.Excerpt.Raw != nil {
+		excerpt = *item.Excerpt.Raw
+	}
+	var attachmentURL *string
+	if item.Type == "attachment" {
+		// The description and the caption of the media, like the export
+		content = lo.FromPtr(item.Description.Raw)
+		excerpt = strings.TrimSpace(item.Caption.value())
+		if item.SourceURL != "" {
+			attachmentURL = lo.ToPtr(item.SourceURL)
+		}
+	}
+
+	postID := strconv.Itoa(item.ID)
+	comments := make([]CommentInfo, 0, len(lookups.comments[item.ID]))
+	for _, comment := range lookups.comments[item.ID] {
+		var commentPubDate *time.Time
+		if date, err := parseTime(comment.DateGMT); err == nil {
+			commentPubDate = date
+		}
+		comments = append(comments, CommentInfo{
+			ID:          strconv.Itoa(comment.ID),
+			ParentID:    strconv.Itoa(comment.Parent),
+			AuthorName:  comment.AuthorName,
+			AuthorEmail: comment.AuthorEmail,
+			AuthorURL:   comment.AuthorURL,
+			PublishDate: commentPubDate,
+			Content:     comment.Content.value(),
+			PostLink:    item.Link,
+			PostID:      postID,
+		})
+	}
+
+	return &CommonFields{
+		Author:           lookups.authors[item.Author],
+		PostID:           postID,
+		Title:            title,
+		Link:             item.Link,
+		Slug:             item.Slug,
+		PublishDate:      pubDate,
+		PublishDateLocal: pubDateLocal,
+		GUID:             &rss.GUID{Value: item.GUID.text()},
+		LastModifiedDate: lastModifiedDate,
+		PublishStatus:    publishStatus,
+		PostFormat:       postFormat,
+		PostType:         lo.ToPtr(item.Type),
+		PostParentID:     postParent,
+		Excerpt:          excerpt,
+
+		Content:         content,
+		Categories:      pageCategories,
+		CustomMetaData:  customMetaData,
+		Tags:            pageTags,
+		Footnotes:       footnotes,
+		FeaturedImageID: featuredImageID,
+
+		attachmentURL: attachmentURL,
+
+		PasswordProtected: item.Password != "" || item.Content.Protected,
+		Sticky:            item.Sticky,
+
+		CommentStatus: item.CommentStatus,
+		PingStatus:    cmp.Or(item.PingStatus, CommentStatusClosed),
+		Comments:      comments,
+	}, nil
+}
+
+// getRESTAPIMeta returns the meta of the item registered for the REST API, and its footnotes.
+// The meta is an empty array rather than an object when there is none.
+func getRESTAPIMeta(item restAPIPost) ([]CustomMetaDatum, []Footnote) {
+	var meta map[string]any
+	if err := json.Unmarshal(item.Meta, &meta); err != nil {
+		return nil, nil
+	}
+	keys := slices.Sorted(maps.Keys(meta))
+	customMetaData := make([]CustomMetaDatum, 0, len(keys))
+	var footnotes []Footnote
+	for _, key := range keys {
+		var value string
+		switch typed := meta[key].(type) {
+		case string:
+			value = typed
+		case bool, float64:
+			value = fmt.Sprint(typed)
+		default:
+			continue
+		}
+		if key == "footnotes" && value != "" {
+			if err := json.Unmarshal([]byte(value), &footnotes); err != nil {
+				log.Warn().
+					Str("link", item.Link).
+					Err(err).
+					Msg("error parsing footnotes")
+			}
+		}
+		customMetaData = append(customMetaData, CustomMetaDatum{Key: key, Value: value})
+	}
+	return customMetaData, footnotes
+}
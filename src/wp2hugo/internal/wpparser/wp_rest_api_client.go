@@ -0,0 +1,305 @@
+package wpparser
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Largest page size of the REST API collections
+const _restAPIPageSize = 100
+
+// Attempts of each request rate limited or failing with a temporary server error
+const _restAPIMaxAttempts = 5
+
+// Sites without pretty permalinks only serve the REST API at ?rest_route=
+const _restRouteParam = "rest_route"
+
+// The cached pages older than this are fetched again, the site has likely changed since the interrupted run
+const _restAPICacheTTL = 24 * time.Hour
+
+// restAPIClient fetches the routes of the REST API of a WordPress site, e.g. "/wp/v2/posts"
+type restAPIClient struct {
+	// URL of the index of the REST API, e.g. https://example.com/wp-json/, or https://example.com/?rest_route=/
+	apiURL    *url.URL
+	restRoute bool
+
+	username string
+	password string
+
+	// Dir of the pages of the collections fetched so far, see RESTAPISource.CacheDir
+	cacheDir string
+	// Whether a page was read from the cache dir, logged once
+	resumed bool
+}
+
+// newRESTAPIClient finds the REST API of the site, at /wp-json/ or else at ?rest_route=/
+func newRESTAPIClient(ctx context.Context, source RESTAPISource) (*restAPIClient, *restAPIIndex, error) {
+	siteURL, err := url.Parse(strings.TrimSpace(source.SiteURL))
+	if err != nil || siteURL.Scheme == "" || siteURL.Host == "" {
+		return nil, nil, fmt.Errorf("invalid site URL %q", source.SiteURL)
+	}
+	// The URL of the API itself is accepted too, e.g. https://example.com/wp-json/wp/v2/
+	sitePath, _, _ := strings.Cut(siteURL.Path, "/wp-json")
+	siteURL.Path = strings.TrimSuffix(sitePath, "/") + "/"
+	siteURL.RawQuery = ""
+	siteURL.Fragment = ""
+	if source.Username != "" && siteURL.Scheme != "https" && !isLoopbackHost(siteURL.Hostname()) {
+		return nil, nil, fmt.Errorf("%w: %s", errPlainHTTPCredentials, siteURL)
+	}
+
+	client := &restAPIClient{
+		apiURL:   siteURL.JoinPath("wp-json/"),
+		username: source.Username,
+		password: source.ApplicationPassword,
+		cacheDir: source.CacheDir,
+	}
+	var index restAPIIndex
+	if _, err := client.get(ctx, "/", nil, &index); err != nil {
+		log.Debug().
+			Err(err).
+			Str("apiURL", client.apiURL.String()).
+			Msg("REST API not found at /wp-json/, trying ?rest_route=")
+		client.apiURL = siteURL
+		client.restRoute = true
+		if _, err := client.get(ctx, "/", nil, &index); err != nil {
+			return nil, nil, fmt.Errorf("error finding the REST API of %s: %w", siteURL, err)
+		}
+	}
+	return client, &index, nil
+}
+
+var errPlainHTTPCredentials = errors.New("the application password would be sent in the clear, use the https:// URL of the site")
+
+// isLoopbackHost returns whether the requests to host stay on this machine, e.g. to a local WordPress install
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func (c *restAPIClient) isAuthenticated() bool {
+	return c.username != ""
+}
+
+func (c *restAPIClient) getURL(route string, query url.Values) string {
+	query = cloneQuery(query)
+	if c.restRoute {
+		query.Set(_restRouteParam, route)
+		routeURL := *c.apiURL
+		routeURL.RawQuery = query.Encode()
+		return routeURL.String()
+	}
+	routeURL := *c.apiURL
+	if route != "/" {
+		routeURL = *c.apiURL.JoinPath(strings.TrimPrefix(route, "/"))
+	}
+	routeURL.RawQuery = query.Encode()
+	return routeURL.String()
+}
+
+func cloneQuery(query url.Values) url.Values {
+	clone := make(url.Values, len(query)+1)
+	for key, values := range query {
+		clone[key] = append([]string(nil), values...)
+	}
+	return clone
+}
+
+// getAll fetches and decodes the items of every page of the collection, e.g. "/wp/v2/posts"
+func getAll[T any](ctx context.Context, c *restAPIClient, route string, query url.Values) ([]T, error) {
+	query = cloneQuery(query)
+	query.Set("per_page", strconv.Itoa(_restAPIPageSize))
+	var items []T
+	for page, totalPages := 1, 1; page <= totalPages; page++ {
+		query.Set("page", strconv.Itoa(page))
+		var pageItems []T
+		pages, err := c.getPage(ctx, route, query, &pageItems)
+		if err != nil {
+			return nil, err
+		}
+		totalPages = pages
+		items = append(items, pageItems...)
+		log.Debug().
+			Str("route", route).
+			Int("page", page).
+			Int("totalPages", totalPages).
+			Msg("REST API page fetched")
+	}
+	return items, nil
+}
+
+// A page of a collection, as cached in the cache dir
+type restAPICachedPage struct {
+	FetchedAt  time.Time       `json:"fetched_at"`
+	TotalPages int             `json:"total_pages"`
+	Items      json.RawMessage `json:"items"`
+}
+
+// getPage fetches the page of the collection, or reads it from the cache dir unless it is older than _restAPICacheTTL,
+// and returns the number of pages. The cache is only readable by the user, the pages of an authenticated client
+// include the drafts and the private content: they are cached under the credentials too, so that another user,
+// or no user, doesn't read them.
+func (c *restAPIClient) getPage(ctx context.Context, route string, query url.Values, items any) (int, error) {
+	requestURL := c.getURL(route, query)
+	cachePath := ""
+	if c.cacheDir != "" {
+		key := sha256.Sum256([]byte(requestURL + "\x00" + c.username + "\x00" + c.password))
+		cachePath = path.Join(c.cacheDir, hex.EncodeToString(key[:])+".json")
+		if data, err := os.ReadFile(cachePath); err == nil {
+			var cached restAPICachedPage
+			if err := json.Unmarshal(data, &cached); err != nil || time.Since(cached.FetchedAt) > _restAPICacheTTL {
+				log.Debug().
+					Str("url", requestURL).
+					Msg("REST API page in cache is stale, fetching it again")
+			} else if json.Unmarshal(cached.Items, items) == nil {
+				if !c.resumed {
+					c.resumed = true
+					log.Info().
+						Str("cacheDir", c.cacheDir).
+						Time("fetchedAt", cached.FetchedAt).
+						Msg("Resuming from the REST API pages cached by a previous run")
+				}
+				log.Debug().
+					Str("url", requestURL).
+					Msg("REST API page found in cache")
+				return cached.TotalPages, nil
+			}
+		}
+	}
+
+	var raw json.RawMessage
+	header, err := c.get(ctx, route, query, &raw)
+	if err != nil {
+		return 0, err
+	}
+	if err := json.Unmarshal(raw, items); err != nil {
+		return 0, fmt.Errorf("error decoding %s: %w", requestURL, err)
+	}
+	totalPages := 1
+	if value := header.Get("X-WP-TotalPages"); value != "" {
+		if totalPages, err = strconv.Atoi(value); err != nil {
+			return 0, fmt.Errorf("error parsing the X-WP-TotalPages header %q of %s: %w", value, requestURL, err)
+		}
+	}
+	if cachePath != "" {
+		if err := os.MkdirAll(c.cacheDir, 0o700); err != nil {
+			return 0, fmt.Errorf("error creating REST API cache dir: %w", err)
+		}
+		data, err := json.Marshal(restAPICachedPage{FetchedAt: time.Now(), TotalPages: totalPages, Items: raw})
+		if err != nil {
+			return 0, err
+		}
+		if err := os.WriteFile(cachePath, data, 0o600); err != nil {
+			return 0, fmt.Errorf("error writing REST API cache: %w", err)
+		}
+	}
+	return totalPages, nil
+}
+
+// errRESTAPIRetry is a response worth retrying, once the server is ready again
+var errRESTAPIRetry = errors.New("temporary REST API error")
+
+// get fetches the route and decodes its JSON into out, it retries the requests which are rate limited
+// or fail with a temporary server error, after their Retry-After delay or an increasing backoff
+func (c *restAPIClient) get(ctx context.Context, route string, query url.Values, out any) (http.Header, error) {
+	requestURL := c.getURL(route, query)
+	var lastErr error
+	for attempt := 1; attempt <= _restAPIMaxAttempts; attempt++ {
+		header, err := c.fetch(ctx, requestURL, out)
+		if err == nil || !errors.Is(err, errRESTAPIRetry) {
+			return header, err
+		}
+		lastErr = err
+		if attempt == _restAPIMaxAttempts {
+			break
+		}
+		retryAfter := getRetryAfter(header, attempt)
+		log.Warn().
+			Str("url", requestURL).
+			Err(err).
+			Dur("retryAfter", retryAfter).
+			Msg("REST API request failed, retrying")
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(retryAfter):
+		}
+	}
+	return nil, lastErr
+}
+
+// fetch fetches the URL and decodes its JSON into out, the header is returned along with errRESTAPIRetry too
+func (c *restAPIClient) fetch(ctx context.Context, requestURL string, out any) (http.Header, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request for %s: %w", requestURL, err)
+	}
+	req.Header.Set("User-Agent", "ashishb/wp2hugo")
+	req.Header.Set("Accept", "application/json")
+	if c.isAuthenticated() {
+		req.SetBasicAuth(c.username, c.password)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("%w: error fetching %s: %w", errRESTAPIRetry, requestURL, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return nil, fmt.Errorf("error decoding %s: %w", requestURL, err)
+		}
+		return resp.Header, nil
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return resp.Header, fmt.Errorf("%w: %s: %s", errRESTAPIRetry, requestURL, resp.Status)
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return nil, fmt.Errorf("error fetching %s: %s, check the username and the application password: %s",
+			requestURL, resp.Status, readRESTAPIError(resp.Body))
+	default:
+		return nil, fmt.Errorf("error fetching %s: %s: %s", requestURL, resp.Status, readRESTAPIError(resp.Body))
+	}
+}
+
+// getRetryAfter returns the delay of the Retry-After header in seconds, or else 2 seconds more for each attempt
+func getRetryAfter(header http.Header, attempt int) time.Duration {
+	if seconds, err := strconv.Atoi(strings.TrimSpace(header.Get("Retry-After"))); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return time.Duration(attempt) * 2 * time.Second
+}
+
+// readRESTAPIError returns the message of the error response of the REST API, e.g. {"code": "...", "message": "..."}
+func readRESTAPIError(body io.Reader) string {
+	var restErr struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}
+	data, _ := io.ReadAll(io.LimitReader(body, 64*1024))
+	if err := json.Unmarshal(data, &restErr); err != nil || restErr.Code == "" {
+		return strings.TrimSpace(string(data))
+	}
+	return fmt.Sprintf("%s (%s)", restErr.Message, restErr.Code)
+}
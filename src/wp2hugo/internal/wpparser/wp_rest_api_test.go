@@ -0,0 +1,228 @@
+package wpparser
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// A site serving its REST API at /wp-json/, the second page of the posts is rate limited once
+func newRESTAPIServer(t *testing.T, rateLimited *atomic.Bool) *httptest.Server {
+	t.Helper()
+	var server *httptest.Server
+	routes := map[string]string{
+		"/wp-json/":                 `{"name": "Example &amp; co", "description": "A blog", "url": "SITE", "home": "SITE"}`,
+		"/wp-json/wp/v2/categories": `[{"id": 3, "name": "Travel Notes", "slug": "travel-notes"}]`,
+		"/wp-json/wp/v2/tags":       `[{"id": 4, "name": "Hiking", "slug": "hiking"}]`,
+		"/wp-json/wp/v2/users":      `[{"id": 1, "name": "Jane Doe", "slug": "jane"}]`,
+		"/wp-json/wp/v2/comments": `[{"id": 7, "post": 10, "parent": 0, "author_name": "Bob", "author_url": "",
+			"date_gmt": "2024-03-06T08:00:00", "content": {"rendered": "<p>Nice!</p>"}}]`,
+		"/wp-json/wp/v2/media": `[{"id": 11, "type": "attachment", "status": "inherit", "link": "SITE/summit/",
+			"slug": "summit", "date": "2024-03-05T11:00:00", "date_gmt": "2024-03-05T10:00:00", "author": 1,
+			"guid": {"rendered": "SITE/wp-content/uploads/summit.jpg"}, "title": {"rendered": "Summit"},
+			"post": 10, "source_url": "SITE/wp-content/uploads/summit.jpg", "caption": {"rendered": "<p>At the top</p>\n"}}]`,
+		"/wp-json/wp/v2/pages": `[{"id": 2, "type": "page", "status": "publish", "link": "SITE/about/", "slug": "about",
+			"date": "2024-01-01T10:00:00", "date_gmt": "2024-01-01T09:00:00", "author": 1, "parent": 0,
+			"guid": {"rendered": "SITE/?page_id=2"}, "title": {"rendered": "About"},
+			"content": {"rendered": "<p>About us</p>", "protected": false}, "meta": []}]`,
+		"/wp-json/wp/v2/types": `{"post": {"rest_base": "posts"}, "recipe": {"rest_base": "recipes"}}`,
+		"/wp-json/wp/v2/recipes": `[{"id": 20, "type": "recipe", "status": "publish", "link": "SITE/recipes/soup/",
+			"slug": "soup", "date": "2024-02-01T10:00:00", "date_gmt": "2024-02-01T09:00:00", "author": 1,
+			"guid": {"rendered": "SITE/?post_type=recipe&p=20"}, "title": {"rendered": "Soup"},
+			"content": {"rendered": "<p>Boil water.</p>"}}]`,
+	}
+	posts := []string{
+		`[{"id": 10, "type": "post", "status": "publish", "link": "SITE/2024/03/a-trip/", "slug": "a-trip",
+			"date": "2024-03-05T11:00:00", "date_gmt": "2024-03-05T10:00:00", "modified_gmt": "2024-03-07T10:00:00",
+			"author": 1, "featured_media": 11, "sticky": true, "format": "standard", "categories": [3], "tags": [4],
+			"comment_status": "open", "ping_status": "closed", "guid": {"rendered": "SITE/?p=10"},
+			"title": {"rendered": "A trip to the &#8220;mountains&#8221;"},
+			"content": {"rendered": "<p>We left early.</p>", "protected": false},
+			"excerpt": {"rendered": "<p>We left early. [&hellip;]</p>"},
+			"meta": {"footnotes": "[{\"id\":\"a1\",\"content\":\"A note\"}]", "_edit_lock": "1"}}]`,
+		`[{"id": 12, "type": "post", "status": "publish", "link": "SITE/2024/03/back-home/", "slug": "back-home",
+			"date": "2024-03-08T11:00:00", "date_gmt": "2024-03-08T10:00:00", "author": 1, "format": "aside",
+			"guid": {"rendered": "SITE/?p=12"}, "title": {"rendered": "Back home"},
+			"content": {"rendered": "", "protected": true}}]`,
+	}
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/wp-json/wp/v2/posts" {
+			page := r.URL.Query().Get("page")
+			if page == "2" && !rateLimited.Swap(true) {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.Header().Set("X-WP-TotalPages", "2")
+			if page == "2" {
+				_, _ = w.Write([]byte(replaceSite(posts[1], server.URL)))
+			} else {
+				_, _ = w.Write([]byte(replaceSite(posts[0], server.URL)))
+			}
+			return
+		}
+		body, ok := routes[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code": "rest_no_route", "message": "No route was found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(replaceSite(body, server.URL)))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func replaceSite(body string, siteURL string) string {
+	return strings.ReplaceAll(body, "SITE", siteURL)
+}
+
+func TestParseRESTAPI(t *testing.T) {
+	t.Parallel()
+	var rateLimited atomic.Bool
+	server := newRESTAPIServer(t, &rateLimited)
+	cacheDir := filepath.Join(t.TempDir(), "rest-api")
+
+	info, err := NewParser().ParseRESTAPI(context.Background(),
+		RESTAPISource{SiteURL: server.URL + "/wp-json/wp/v2/", CacheDir: cacheDir}, nil, []string{"product", "recipe"})
+	require.NoError(t, err)
+	require.True(t, rateLimited.Load(), "the rate limited page is retried")
+	require.NoDirExists(t, cacheDir, "the cache is removed once the site is fetched")
+
+	require.Equal(t, "Example & co", info.Title())
+	require.Equal(t, server.URL, info.Link().String())
+	require.Len(t, info.Categories(), 1)
+	require.Equal(t, "travel-notes", info.Categories()[0].NiceName)
+	require.Len(t, info.Authors(), 1)
+	require.Equal(t, "jane-doe", info.Authors()[0].Slug)
+
+	require.Len(t, info.Posts(), 2)
+	post := info.Posts()[0]
+	require.Equal(t, "10", post.PostID)
+	require.Equal(t, "jane", post.Author)
+	require.Equal(t, "A trip to the “mountains”", post.Title)
+	require.Equal(t, "a-trip", post.GetFileInfo().FileNameNoLanguage())
+	require.Equal(t, "2024-03-05T10:00:00Z", post.PublishDate.Format("2006-01-02T15:04:05Z07:00"))
+	require.Equal(t, "2024-03-05T11:00:00+01:00", post.PublishDateLocal.Format("2006-01-02T15:04:05Z07:00"))
+	require.Equal(t, []string{"travel-notes"}, post.Categories)
+	require.Equal(t, []string{"hiking"}, post.Tags)
+	require.Nil(t, post.PostFormat)
+	require.Equal(t, "11", *post.FeaturedImageID)
+	require.True(t, post.Sticky)
+	require.Equal(t, "<p>We left early.</p>", post.Content)
+	require.Empty(t, post.Excerpt, "the rendered excerpt is generated from the content")
+	require.Equal(t, []Footnote{{ID: "a1", Content: "A note"}}, post.Footnotes)
+	require.Len(t, post.Comments, 1)
+	require.Equal(t, "Bob", post.Comments[0].AuthorName)
+	require.Equal(t, post.Link, post.Comments[0].PostLink)
+
+	require.Equal(t, "aside", *info.Posts()[1].PostFormat)
+	require.True(t, info.Posts()[1].PasswordProtected)
+
+	require.Len(t, info.Pages(), 1)
+	require.Nil(t, info.Pages()[0].PostParentID)
+	require.Len(t, info.GetAttachmentsForPost("10"), 1)
+	attachment := info.GetAttachmentsForPost("10")[0]
+	require.Equal(t, server.URL+"/wp-content/uploads/summit.jpg", *attachment.GetAttachmentURL())
+	require.Equal(t, "<p>At the top</p>", attachment.Excerpt)
+	require.Equal(t, 2, info.Summary().PostTypes["post"])
+	// The products are not exposed by the REST API of the site
+	require.Len(t, info.CustomPosts(), 1)
+	require.Equal(t, "recipe", *info.CustomPosts()[0].PostType)
+}
+
+func TestParseRESTAPIResumes(t *testing.T) {
+	t.Parallel()
+	var rateLimited atomic.Bool
+	server := newRESTAPIServer(t, &rateLimited)
+	client, _, err := newRESTAPIClient(context.Background(), RESTAPISource{SiteURL: server.URL, CacheDir: t.TempDir()})
+	require.NoError(t, err)
+
+	pages, err := getAll[restAPIPost](context.Background(), client, "/wp/v2/pages", nil)
+	require.NoError(t, err)
+	require.Len(t, pages, 1)
+	entries, err := os.ReadDir(client.cacheDir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	info, err := entries[0].Info()
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), info.Mode().Perm(), "the pages may include the private content")
+
+	// The stale pages are fetched again
+	cachePath := filepath.Join(client.cacheDir, entries[0].Name())
+	readCachedPage := func() restAPICachedPage {
+		data, err := os.ReadFile(cachePath)
+		require.NoError(t, err)
+		var cached restAPICachedPage
+		require.NoError(t, json.Unmarshal(data, &cached))
+		return cached
+	}
+	stale := readCachedPage()
+	stale.FetchedAt = time.Now().Add(-_restAPICacheTTL - time.Minute)
+	data, err := json.Marshal(stale)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(cachePath, data, 0o600))
+	_, err = getAll[restAPIPost](context.Background(), client, "/wp/v2/pages", nil)
+	require.NoError(t, err)
+	require.WithinDuration(t, time.Now(), readCachedPage().FetchedAt, time.Minute)
+
+	// The next run reads the page fetched by the interrupted one from the cache
+	server.Close()
+	pages, err = getAll[restAPIPost](context.Background(), client, "/wp/v2/pages", nil)
+	require.NoError(t, err)
+	require.Len(t, pages, 1)
+}
+
+func TestRESTAPICredentials(t *testing.T) {
+	t.Parallel()
+	_, _, err := newRESTAPIClient(context.Background(),
+		RESTAPISource{SiteURL: "http://example.com", Username: "editor", ApplicationPassword: "abcd efgh"})
+	require.ErrorIs(t, err, errPlainHTTPCredentials)
+
+	// The pages cached for a user are not read by another one, nor anonymously
+	var rateLimited atomic.Bool
+	server := newRESTAPIServer(t, &rateLimited)
+	cacheDir := t.TempDir()
+	for _, username := range []string{"editor", "author", ""} {
+		client, _, err := newRESTAPIClient(context.Background(),
+			RESTAPISource{SiteURL: server.URL, Username: username, ApplicationPassword: "abcd efgh", CacheDir: cacheDir})
+		require.NoError(t, err)
+		_, err = getAll[restAPIPost](context.Background(), client, "/wp/v2/pages", nil)
+		require.NoError(t, err)
+	}
+	entries, err := os.ReadDir(cacheDir)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+}
+
+func TestRESTAPIRoute(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" || r.URL.Query().Get("rest_route") != "/" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"name": "Plain permalinks"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client, index, err := newRESTAPIClient(context.Background(), RESTAPISource{SiteURL: server.URL})
+	require.NoError(t, err)
+	require.True(t, client.restRoute)
+	require.Equal(t, "Plain permalinks", index.Name)
+	require.Equal(t, server.URL+"/?page=2&rest_route=%2Fwp%2Fv2%2Fposts",
+		client.getURL("/wp/v2/posts", map[string][]string{"page": {"2"}}))
+
+	_, _, err = newRESTAPIClient(context.Background(), RESTAPISource{SiteURL: "example.com"})
+	require.Error(t, err)
+}
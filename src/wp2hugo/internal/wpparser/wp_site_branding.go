@@ -0,0 +1,37 @@
+package wpparser
+
+// The site icon and the custom logo are options, which are not exported. Their attachments are, with the
+// _wp_attachment_context postmeta the Customizer records when cropping them.
+const _attachmentContextKey = "_wp_attachment_context"
+
+const (
+	AttachmentContextSiteIcon   = "site-icon"
+	AttachmentContextCustomLogo = "custom-logo"
+)
+
+// GetSiteIcon returns the attachment of the site icon, if any
+func (w *WebsiteInfo) GetSiteIcon() (AttachmentInfo, bool) {
+	return w.getAttachmentByContext(AttachmentContextSiteIcon)
+}
+
+// GetCustomLogo returns the attachment of the custom logo of the theme, if any
+func (w *WebsiteInfo) GetCustomLogo() (AttachmentInfo, bool) {
+	return w.getAttachmentByContext(AttachmentContextCustomLogo)
+}
+
+// getAttachmentByContext returns the last attachment cropped for the context, the current one
+// if it was replaced since, as the export lists the attachments by ID
+func (w *WebsiteInfo) getAttachmentByContext(context string) (AttachmentInfo, bool) {
+	for i := len(w.attachments) - 1; i >= 0; i-- {
+		attachment := w.attachments[i]
+		if attachment.GetAttachmentURL() == nil {
+			continue
+		}
+		for _, metadatum := range attachment.CustomMetaData {
+			if metadatum.Key == _attachmentContextKey && metadatum.Value == context {
+				return attachment, true
+			}
+		}
+	}
+	return AttachmentInfo{}, false
+}
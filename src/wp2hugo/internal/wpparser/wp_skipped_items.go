@@ -0,0 +1,41 @@
+package wpparser
+
+import (
+	"github.com/mmcdole/gofeed/rss"
+	"github.com/rs/zerolog/log"
+)
+
+// SkippedItem is an item of the export which failed to convert and was skipped, see Parser.FailFast
+type SkippedItem struct {
+	PostID   string
+	PostType string
+	Title    string
+	Link     string
+	Error    string
+}
+
+// SkippedItems returns the items of the export which failed to parse and were skipped
+func (w *WebsiteInfo) SkippedItems() []SkippedItem {
+	return w.skippedItems
+}
+
+// skipItem records the item as skipped because of the error, unless FailFast is set, then the error is returned
+func (p *Parser) skipItem(skippedItems *[]SkippedItem, item *rss.Item, err error) error {
+	if p.FailFast {
+		return err
+	}
+	skippedItem := SkippedItem{
+		PostID:   getWPField(item, "post_id"),
+		PostType: getWPField(item, "post_type"),
+		Title:    item.Title,
+		Link:     item.Link,
+		Error:    err.Error(),
+	}
+	log.Warn().
+		Err(err).
+		Str("postID", skippedItem.PostID).
+		Str("title", skippedItem.Title).
+		Msg("Skipping item which failed to parse")
+	*skippedItems = append(*skippedItems, skippedItem)
+	return nil
+}
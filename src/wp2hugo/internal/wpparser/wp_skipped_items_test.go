@@ -0,0 +1,35 @@
+package wpparser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSkippedItems(t *testing.T) {
+	t.Parallel()
+	export := exportWithItems(item("7", "wp_navigation").withContent(`<!-- wp:navigation-link {"label":"About",} /-->`))
+
+	// The malformed item is skipped, the rest of the export is kept
+	info, err := NewParser().Parse(strings.NewReader(export), nil, nil)
+	require.NoError(t, err)
+	require.Len(t, info.Posts(), 1)
+	require.Empty(t, info.NavigationLinks())
+	require.Len(t, info.SkippedItems(), 1)
+	skippedItem := info.SkippedItems()[0]
+	require.Equal(t, "7", skippedItem.PostID)
+	require.Equal(t, "wp_navigation", skippedItem.PostType)
+	require.Equal(t, "Item 7", skippedItem.Title)
+	require.Contains(t, skippedItem.Error, "error getting navigation links")
+
+	merged, err := Merge(info, info)
+	require.NoError(t, err)
+	require.Len(t, merged.SkippedItems(), 2)
+	require.Len(t, info.SkippedItems(), 1)
+
+	parser := NewParser()
+	parser.FailFast = true
+	_, err = parser.Parse(strings.NewReader(export), nil, nil)
+	require.ErrorContains(t, err, "error getting navigation links")
+}
@@ -0,0 +1,80 @@
+package wpparser
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// isEmojiRune reports whether the rune is part of an emoji sequence,
+// including the joiners, variation selectors and skin tone modifiers
+func isEmojiRune(r rune) bool {
+	switch {
+	case r == '\u200d', r == '\u20e3': // Zero-width joiner, keycap
+		return true
+	case r >= '\ufe00' && r <= '\ufe0f': // Variation selectors
+		return true
+	case r >= 0x1f3fb && r <= 0x1f3ff: // Skin tone modifiers
+		return true
+	case r >= 0xe0020 && r <= 0xe007f: // Tags, used in subdivision flags
+		return true
+	default:
+		return unicode.Is(unicode.So, r)
+	}
+}
+
+// stripEmojiFromSlug removes the emoji from a WordPress slug.
+// WordPress keeps them percent-encoded in the post name, e.g. "hello-%f0%9f%91%8b-world",
+// which makes for unwieldy and platform-dependent filenames.
+// Slugs without emoji are returned unchanged.
+func stripEmojiFromSlug(slug string) string {
+	decoded, err := url.PathUnescape(slug)
+	if err != nil || !strings.ContainsFunc(decoded, isEmojiRune) {
+		return slug
+	}
+
+	var sb strings.Builder
+	for _, r := range decoded {
+		switch {
+		case isEmojiRune(r):
+			sb.WriteByte('-')
+		case r < utf8.RuneSelf:
+			sb.WriteRune(r)
+		default:
+			// Keep the other non-ASCII characters percent-encoded, like WordPress does
+			for _, b := range []byte(string(r)) {
+				fmt.Fprintf(&sb, "%%%02x", b)
+			}
+		}
+	}
+	result := sb.String()
+	for strings.Contains(result, "--") {
+		result = strings.ReplaceAll(result, "--", "-")
+	}
+	return strings.Trim(result, "-")
+}
+
+// truncateSlug cuts the slug to maxLength bytes, without splitting a multibyte character
+// nor a percent-encoded byte, e.g. "%d7"
+func truncateSlug(slug string, maxLength int) string {
+	if len(slug) <= maxLength {
+		return slug
+	}
+	slug = slug[:max(maxLength, 0)]
+	if i := strings.LastIndexByte(slug, '%'); i >= 0 && i > len(slug)-3 {
+		slug = slug[:i]
+	}
+	// Percent-encoded characters span several "%xx" bytes, drop the incomplete trailing one
+	if decoded, err := url.PathUnescape(slug); err == nil {
+		for !utf8.ValidString(decoded) && strings.Contains(slug, "%") {
+			slug = slug[:strings.LastIndexByte(slug, '%')]
+			decoded, _ = url.PathUnescape(slug)
+		}
+	}
+	for !utf8.ValidString(slug) {
+		slug = slug[:len(slug)-1]
+	}
+	return slug
+}
@@ -0,0 +1,90 @@
+package wpparser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mmcdole/gofeed/rss"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTitleToFilenameWithEmoji(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, "hello-world", titleToFilename("Hello 👋 World"))
+	require.Equal(t, "family-trip", titleToFilename("👨‍👩‍👧 Family trip 🇫🇷"))
+	require.Empty(t, titleToFilename("🎉🎉"))
+
+	// Truncation does not split a multibyte character
+	filename := titleToFilename(strings.Repeat("é", _filenameSizeLimit))
+	require.Equal(t, strings.Repeat("e", _filenameSizeLimit), filename)
+	filename = titleToFilename(strings.Repeat("ж", _filenameSizeLimit))
+	require.Equal(t, strings.Repeat("ж", _filenameSizeLimit/2), filename)
+}
+
+func TestStripEmojiFromSlug(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, "hello-world", stripEmojiFromSlug("hello-%f0%9f%91%8b-world"))
+	require.Equal(t, "hello-world", stripEmojiFromSlug("hello-👋-world"))
+	require.Equal(t, "%d0%bf%d1%80%d0%b8", stripEmojiFromSlug("%d0%bf%d1%80%d0%b8-%e2%9d%a4%ef%b8%8f"))
+	// Slugs without emoji are left as is
+	require.Equal(t, "%D0%BF%D1%80%D0%B8", stripEmojiFromSlug("%D0%BF%D1%80%D0%B8"))
+	require.Equal(t, "hello-world", stripEmojiFromSlug("hello-world"))
+}
+
+func TestGetFileInfoWithEmoji(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		link     string
+		title    string
+		expected string
+	}{
+		{"https://example.com/hello-%f0%9f%91%8b-world/", "Hello 👋 World", "hello-world"},
+		{"https://example.com/?p=42", "Hello 👋 World", "hello-world"},
+		{"https://example.com/%f0%9f%8e%89/", "🎉", "untitled-42"},
+	}
+	for _, testCase := range testCases {
+		fields := CommonFields{
+			PostID: "42",
+			Title:  testCase.title,
+			Link:   testCase.link,
+			GUID:   &rss.GUID{Value: testCase.link},
+		}
+		// Stable across runs
+		require.Equal(t, testCase.expected, fields.GetFileInfo().FileNameNoLanguage(), testCase.link)
+		require.Equal(t, testCase.expected, fields.GetFileInfo().FileNameNoLanguage(), testCase.link)
+	}
+}
+
+func TestTruncateFileInfo(t *testing.T) {
+	t.Parallel()
+	longTitle := strings.Repeat("An absurdly long title ", 50)
+	fields := CommonFields{
+		PostID: "42",
+		Title:  longTitle,
+		Link:   "https://example.com/?p=42",
+		GUID:   &rss.GUID{Value: "https://example.com/?p=42"},
+	}
+	fileInfo := fields.GetFileInfo()
+	truncated := fileInfo.Truncate(64)
+	require.True(t, truncated.IsTruncated())
+	require.Len(t, truncated.FileNameNoLanguage(), 64)
+	require.Regexp(t, `^an-absurdly-long-title-an-absurdly-long-title-an-absurd-[0-9a-f]{8}$`, truncated.FileNameNoLanguage())
+	require.Equal(t, fileInfo.FileNameNoLanguage(), truncated.OriginalFileName())
+	// Stable across runs
+	require.Equal(t, truncated, fileInfo.Truncate(64))
+
+	short := CommonFields{PostID: "1", Title: "Short", Link: "https://example.com/short/"}.GetFileInfo()
+	require.False(t, short.Truncate(64).IsTruncated())
+	require.Equal(t, "short", short.Truncate(64).OriginalFileName())
+}
+
+func TestTruncateSlug(t *testing.T) {
+	t.Parallel()
+	// "при" is percent-encoded as 3 bytes per byte of its 2-byte characters
+	require.Equal(t, "%D0%BF", truncateSlug("%D0%BF%D1%80%D0%B8", 8))
+	require.Equal(t, "%D0%BF", truncateSlug("%D0%BF%D1%80%D0%B8", 10))
+	require.Equal(t, "%D0%BF%D1%80", truncateSlug("%D0%BF%D1%80%D0%B8", 12))
+	require.Equal(t, "при", truncateSlug("привет", 6))
+	require.Equal(t, "пр", truncateSlug("привет", 5))
+	require.Equal(t, "hello", truncateSlug("hello", 10))
+}
@@ -0,0 +1,25 @@
+package wpparser
+
+import (
+	ext "github.com/mmcdole/gofeed/extensions"
+)
+
+// getTermMeta returns the <wp:termmeta> of the term keyed by meta key, e.g. the term image or color
+// stored by the theme or plugins, or nil if it has none
+func getTermMeta(term ext.Extension) map[string]string {
+	var meta map[string]string
+	for _, termMeta := range term.Children["termmeta"] {
+		if len(termMeta.Children["meta_key"]) == 0 || termMeta.Children["meta_key"][0].Value == "" {
+			continue
+		}
+		if meta == nil {
+			meta = make(map[string]string)
+		}
+		value := ""
+		if len(termMeta.Children["meta_value"]) > 0 {
+			value = termMeta.Children["meta_value"][0].Value
+		}
+		meta[termMeta.Children["meta_key"][0].Value] = value
+	}
+	return meta
+}
@@ -0,0 +1,38 @@
+package wpparser
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	ext "github.com/mmcdole/gofeed/extensions"
+	"github.com/rs/zerolog/log"
+)
+
+// Term meta storing the custom order of the terms, lowest first:
+// WooCommerce ("order") and the term ordering plugins, e.g. Simple Taxonomy Ordering ("tax_position")
+var _termOrderMetaKeys = []string{"order", "term_order", "tax_position"}
+
+// getTermOrder returns the custom order of the term, from its <wp:termmeta>, or nil if it has none
+func getTermOrder(term ext.Extension) *int {
+	for _, meta := range term.Children["termmeta"] {
+		if len(meta.Children["meta_key"]) == 0 || len(meta.Children["meta_value"]) == 0 {
+			continue
+		}
+		key := meta.Children["meta_key"][0].Value
+		if !slices.Contains(_termOrderMetaKeys, key) {
+			continue
+		}
+		value := strings.TrimSpace(meta.Children["meta_value"][0].Value)
+		order, err := strconv.Atoi(value)
+		if err != nil {
+			log.Warn().
+				Str("key", key).
+				Str("value", value).
+				Msg("Ignoring the non-numeric term order")
+			continue
+		}
+		return &order
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package wpparser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const _termOrderExport = `<?xml version="1.0" encoding="UTF-8" ?>
+<rss version="2.0" xmlns:wp="http://wordpress.org/export/1.2/">
+<channel>
+  <title>Example</title>
+  <link>https://example.org</link>
+  <wp:wxr_version>1.2</wp:wxr_version>
+  <wp:category>
+    <wp:term_id>1</wp:term_id>
+    <wp:category_nicename>news</wp:category_nicename>
+    <wp:cat_name><![CDATA[News]]></wp:cat_name>
+    <wp:termmeta><wp:meta_key><![CDATA[tax_position]]></wp:meta_key><wp:meta_value><![CDATA[3]]></wp:meta_value></wp:termmeta>
+  </wp:category>
+  <wp:tag>
+    <wp:term_id>2</wp:term_id>
+    <wp:tag_slug>go</wp:tag_slug>
+    <wp:tag_name><![CDATA[Go]]></wp:tag_name>
+  </wp:tag>
+  <wp:term>
+    <wp:term_id>3</wp:term_id>
+    <wp:term_taxonomy>product_cat</wp:term_taxonomy>
+    <wp:term_slug>shirts</wp:term_slug>
+    <wp:term_name><![CDATA[Shirts]]></wp:term_name>
+    <wp:termmeta><wp:meta_key><![CDATA[display_type]]></wp:meta_key><wp:meta_value><![CDATA[]]></wp:meta_value></wp:termmeta>
+    <wp:termmeta><wp:meta_key><![CDATA[order]]></wp:meta_key><wp:meta_value><![CDATA[1]]></wp:meta_value></wp:termmeta>
+  </wp:term>
+</channel>
+</rss>`
+
+func TestGetTermOrder(t *testing.T) {
+	t.Parallel()
+	info, err := NewParser().Parse(strings.NewReader(_termOrderExport), nil, nil)
+	require.NoError(t, err)
+
+	require.Len(t, info.Categories(), 1)
+	require.Equal(t, 3, *info.Categories()[0].Order)
+	require.Len(t, info.Tags(), 1)
+	require.Nil(t, info.Tags()[0].Order)
+	require.Len(t, info.Taxonomies(), 1)
+	require.Equal(t, 1, *info.Taxonomies()[0].Order)
+}
+
+func TestGetTermMeta(t *testing.T) {
+	t.Parallel()
+	info, err := NewParser().Parse(strings.NewReader(_termOrderExport), nil, nil)
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]string{"tax_position": "3"}, info.Categories()[0].Meta)
+	require.Nil(t, info.Tags()[0].Meta)
+	require.Equal(t, map[string]string{"display_type": "", "order": "1"}, info.Taxonomies()[0].Meta)
+}
@@ -2,17 +2,27 @@ package wpparser
 
 import (
 	"net/url"
+	"slices"
 	"time"
 )
 
 type WebsiteInfo struct {
-	title       string
+	title string
+	// The canonical URL of the blog, see getSiteLink
 	link        *url.URL
 	Description string
 
+	// <wp:base_site_url> and <wp:base_blog_url>, if any
+	baseSiteURL *url.URL
+	baseBlogURL *url.URL
+
 	pubDate  *time.Time
 	language string
 
+	// Version of the WordPress eXtended RSS format, e.g. "1.2", and the <generator> of the export
+	wxrVersion string
+	generator  string
+
 	categories []CategoryInfo
 	tags       []TagInfo
 
@@ -25,12 +35,28 @@ type WebsiteInfo struct {
 	customPosts     []CustomPostInfo
 	taxonomies      []TaxonomyInfo
 
+	authors []AuthorInfo
+
+	// Gutenberg reusable blocks (wp_block post type), keyed by post ID
+	reusableBlocks map[string]string
+	// Advanced Custom Fields definitions (acf-field post type), keyed by field key
+	acfFields map[string]ACFField
+
 	// WordPress non-native post types slugs to import.
 	// By default, we handle avada_portfolio, avada_faq (Advada theme),
 	// product, product_variation (WooCommerce plugin).
 	// This is mapped to the <wp:post_type> field in the XML export
 	customPostTypes []string
 
+	// Number of items of each post type in the export, see PostTypeCounts
+	postTypeCounts map[string]int
+	// Number of items of each post type which is not converted, see SkippedPostTypeCounts
+	skippedPostTypeCounts map[string]int
+	// Items which failed to parse, see SkippedItems
+	skippedItems []SkippedItem
+	// Whether the content was filtered by post ID, see FilterPostIDs
+	postIDsFiltered bool
+
 	postIDToAttachmentCache map[string][]AttachmentInfo
 }
 
@@ -45,12 +71,18 @@ type CategoryInfo struct {
 	ID       string
 	Name     string
 	NiceName string
+	// Order is the custom order of the term, if stored in its term meta, see getTermOrder
+	Order *int
+	// Meta is the term meta, keyed by meta key
+	Meta map[string]string
 }
 
 type TagInfo struct {
-	ID   string
-	Name string
-	Slug string
+	ID    string
+	Name  string
+	Slug  string
+	Order *int
+	Meta  map[string]string
 }
 
 type TaxonomyInfo struct {
@@ -59,12 +91,16 @@ type TaxonomyInfo struct {
 	Slug     string
 	Parent   string
 	Name     string
+	Order    *int
+	Meta     map[string]string
 }
 
 func (w *WebsiteInfo) Title() string {
 	return w.title
 }
 
+// Link returns the URL of the blog, its base blog URL, or else the link of the feed.
+// Its host and path are the ones of the internal links and the media.
 func (w *WebsiteInfo) Link() *url.URL {
 	return w.link
 }
@@ -73,6 +109,34 @@ func (w *WebsiteInfo) Language() string {
 	return w.language
 }
 
+func (w *WebsiteInfo) WXRVersion() string {
+	return w.wxrVersion
+}
+
+func (w *WebsiteInfo) Generator() string {
+	return w.generator
+}
+
+// WordPressVersion returns the version of WordPress that produced the export, if known
+func (w *WebsiteInfo) WordPressVersion() string {
+	return getWordPressVersion(w.generator)
+}
+
+// Categories returns the categories in the order of the export
+func (w *WebsiteInfo) Categories() []CategoryInfo {
+	return w.categories
+}
+
+// Tags returns the tags in the order of the export
+func (w *WebsiteInfo) Tags() []TagInfo {
+	return w.tags
+}
+
+// Taxonomies returns the terms of the custom taxonomies in the order of the export
+func (w *WebsiteInfo) Taxonomies() []TaxonomyInfo {
+	return w.taxonomies
+}
+
 func (w *WebsiteInfo) Attachments() []AttachmentInfo {
 	return w.attachments
 }
@@ -97,10 +161,47 @@ func (w *WebsiteInfo) CustomPosts() []CustomPostInfo {
 	return w.customPosts
 }
 
+// GetReusableBlock returns the raw content of the reusable block with the given post ID
+func (w *WebsiteInfo) GetReusableBlock(blockID string) (string, bool) {
+	content, ok := w.reusableBlocks[blockID]
+	return content, ok
+}
+
+func (w *WebsiteInfo) Authors() []AuthorInfo {
+	return w.authors
+}
+
+// GetAuthorSlug returns the slug of the author with the given login
+func (w *WebsiteInfo) GetAuthorSlug(login string) (string, bool) {
+	for _, author := range w.authors {
+		if author.Login == login {
+			return author.Slug, true
+		}
+	}
+	return "", false
+}
+
+// GetACFField returns the Advanced Custom Fields definition with the given field key
+func (w *WebsiteInfo) GetACFField(fieldKey string) (ACFField, bool) {
+	field, ok := w.acfFields[fieldKey]
+	return field, ok
+}
+
 func (w *WebsiteInfo) CustomPostTypes() []string {
 	return w.customPostTypes
 }
 
+// TaxonomyNames returns the distinct names of the custom taxonomies of the terms, e.g. "product_cat"
+func (w *WebsiteInfo) TaxonomyNames() []string {
+	var names []string
+	for _, taxonomy := range w.taxonomies {
+		if !slices.Contains(names, taxonomy.Taxonomy) {
+			names = append(names, taxonomy.Taxonomy)
+		}
+	}
+	return names
+}
+
 func getPostIDToAttachmentsMap(attachments []AttachmentInfo) map[string][]AttachmentInfo {
 	result := make(map[string][]AttachmentInfo)
 	for _, attachment := range attachments {
@@ -0,0 +1,22 @@
+package wpparser
+
+// WooCommerce post types
+const (
+	ProductPostType          = "product"
+	ProductVariationPostType = "product_variation"
+)
+
+// GetProductVariations returns the WooCommerce variations of the product with the given post ID,
+// in the export order
+func (w *WebsiteInfo) GetProductVariations(productID string) []CommonFields {
+	var variations []CommonFields
+	for _, customPost := range w.customPosts {
+		if customPost.PostType == nil || *customPost.PostType != ProductVariationPostType {
+			continue
+		}
+		if customPost.PostParentID != nil && *customPost.PostParentID == productID {
+			variations = append(variations, customPost.CommonFields)
+		}
+	}
+	return variations
+}
@@ -0,0 +1,284 @@
+// Package wp2hugo converts WordPress exports into Hugo websites.
+//
+// ConvertFile and ConvertDir are the one-call entry points, the wp2hugo command is built on them,
+// ConvertSite reads a live site from its REST API instead of an export.
+// InspectFile, InspectDir and InspectSite only parse the content, to summarize its structure before configuring a conversion.
+// Cancelling their context aborts the conversion, including the media downloads in flight.
+package wp2hugo
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/hugogenerator"
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/hugogenerator/hugopage"
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/mediacache"
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/rs/zerolog/log"
+)
+
+type (
+	// GeneratorOptions controls the generation of the Hugo site
+	GeneratorOptions = hugogenerator.Options
+	// PageOptions controls the conversion of each page, it is embedded in GeneratorOptions
+	PageOptions = hugopage.PageOptions
+	// ContentReplacement is a replacement rule of GeneratorOptions.ContentReplacements
+	ContentReplacement = hugogenerator.ContentReplacement
+	// HostRewrite is a host swap of GeneratorOptions.HostRewrites
+	HostRewrite = hugogenerator.HostRewrite
+	// Report summarizes the conversion
+	Report = hugogenerator.Report
+	// PostInfo is the WordPress content passed to GeneratorOptions.FrontMatterHook
+	PostInfo = wpparser.PostInfo
+	// SkippedItem is an item which failed to convert, listed in the Report unless GeneratorOptions.FailFast is set
+	SkippedItem = wpparser.SkippedItem
+	// ExportSummary is the structure of the export returned by InspectFile and InspectDir
+	ExportSummary = wpparser.ExportSummary
+)
+
+// DefaultCustomPostTypes are always imported, on top of Options.CustomPostTypes:
+// the Avada theme portfolios and FAQs, and the WooCommerce products
+var DefaultCustomPostTypes = []string{"avada_portfolio", "avada_faq", "product", "product_variation"}
+
+// Options of the conversion
+type Options struct {
+	GeneratorOptions
+
+	// Authors only keeps the content of these authors, all the content is kept if empty
+	Authors []string
+	// CustomPostTypes to import, e.g. "recipe"
+	CustomPostTypes []string
+	// OnlyTypes only converts the content of these post types, e.g. "product", all the content is converted if empty.
+	// They are imported even if not in CustomPostTypes.
+	OnlyTypes []string
+	// IncludePostIDs only converts the content of these post IDs, e.g. "42", and ExcludePostIDs leaves out the content
+	// of these post IDs. They combine with the other filters, e.g. OnlyTypes, but not with GeneratorOptions.Incremental.
+	IncludePostIDs []string
+	ExcludePostIDs []string
+
+	// Font of the generated website, defaults to Lexend
+	Font string
+
+	// DownloadMedia downloads the media embedded in the content, DownloadAll downloads the whole media library.
+	// The downloads are cached in MediaCacheDir, defaults to "wp2hugo-cache" in the temporary dir.
+	DownloadMedia                  bool
+	DownloadAll                    bool
+	ContinueOnMediaDownloadFailure bool
+	MediaCacheDir                  string
+
+	// GenerateNginxConfig writes an nginx.conf redirecting the WordPress GUIDs to the Hugo URLs
+	GenerateNginxConfig bool
+
+	// RESTAPIUsername and RESTAPIApplicationPassword authenticate ConvertSite and InspectSite, with an application
+	// password of the user, to read the drafts and the private content, and the raw content instead of the rendered one
+	RESTAPIUsername            string
+	RESTAPIApplicationPassword string
+}
+
+const _defaultFont = "Lexend"
+
+// Export files globbed by ConvertDir
+var _exportFilePatterns = []string{"*.xml", "*.xml.gz"}
+
+// ConvertFile converts the WordPress export at inPath, which may be gzipped, into a Hugo site under outDir.
+// Once the content is being written, a cancellation returns the partial report along with the context error.
+func ConvertFile(ctx context.Context, inPath string, outDir string, opts Options) (*Report, error) {
+	return convert(ctx, []string{inPath}, outDir, opts)
+}
+
+// ConvertDir converts the WordPress export files of inDir (*.xml and *.xml.gz) together into a Hugo site
+// under outDir. This is meant for large exports split into several files.
+func ConvertDir(ctx context.Context, inDir string, outDir string, opts Options) (*Report, error) {
+	inPaths, err := getExportFiles(inDir)
+	if err != nil {
+		return nil, err
+	}
+	return convert(ctx, inPaths, outDir, opts)
+}
+
+// InspectFile parses the WordPress export at inPath, which may be gzipped, and summarizes its structure without converting it.
+// The content is filtered by the Authors, CustomPostTypes, OnlyTypes and post IDs options, like for the conversion.
+func InspectFile(ctx context.Context, inPath string, opts Options) (*ExportSummary, error) {
+	return inspect(ctx, []string{inPath}, opts)
+}
+
+// InspectDir parses the WordPress export files of inDir (*.xml and *.xml.gz) together, like ConvertDir,
+// and summarizes their structure without converting them
+func InspectDir(ctx context.Context, inDir string, opts Options) (*ExportSummary, error) {
+	inPaths, err := getExportFiles(inDir)
+	if err != nil {
+		return nil, err
+	}
+	return inspect(ctx, inPaths, opts)
+}
+
+// ConvertSite converts the live WordPress site at siteURL, e.g. https://example.com, read from its REST API
+// (/wp-json/wp/v2/), into a Hugo site under outDir. The pages of the API fetched by an interrupted run
+// are cached in MediaCacheDir, the next run resumes from them.
+func ConvertSite(ctx context.Context, siteURL string, outDir string, opts Options) (*Report, error) {
+	info, parseDuration, err := parseSite(ctx, siteURL, opts)
+	if err != nil {
+		return nil, err
+	}
+	return generate(ctx, info, 0, parseDuration, outDir, opts)
+}
+
+// InspectSite reads the live WordPress site at siteURL from its REST API, like ConvertSite,
+// and summarizes its structure without converting it
+func InspectSite(ctx context.Context, siteURL string, opts Options) (*ExportSummary, error) {
+	info, _, err := parseSite(ctx, siteURL, opts)
+	if err != nil {
+		return nil, err
+	}
+	summary := info.Summary()
+	return &summary, nil
+}
+
+// IsSiteURL reports whether the source is the URL of a live site, for ConvertSite, rather than a path to an export
+func IsSiteURL(source string) bool {
+	return strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+}
+
+func getExportFiles(inDir string) ([]string, error) {
+	var inPaths []string
+	for _, pattern := range _exportFilePatterns {
+		matches, err := filepath.Glob(filepath.Join(inDir, pattern))
+		if err != nil {
+			return nil, fmt.Errorf("error listing export files: %w", err)
+		}
+		inPaths = append(inPaths, matches...)
+	}
+	if len(inPaths) == 0 {
+		return nil, fmt.Errorf("no export file (%s) in '%s'", strings.Join(_exportFilePatterns, ", "), inDir)
+	}
+	// Same order as the splitter plugins number the files
+	slices.Sort(inPaths)
+	return inPaths, nil
+}
+
+func inspect(ctx context.Context, inPaths []string, opts Options) (*ExportSummary, error) {
+	info, _, _, err := parse(ctx, inPaths, opts)
+	if err != nil {
+		return nil, err
+	}
+	summary := info.Summary()
+	return &summary, nil
+}
+
+// getCustomPostTypes returns the custom post types to import, see Options.OnlyTypes
+func getCustomPostTypes(opts Options) []string {
+	customPostTypes := append(slices.Clone(DefaultCustomPostTypes), opts.CustomPostTypes...)
+	for _, postType := range opts.OnlyTypes {
+		if postType != "post" && postType != "page" && !slices.Contains(customPostTypes, postType) {
+			customPostTypes = append(customPostTypes, postType)
+		}
+	}
+	return customPostTypes
+}
+
+func getMediaCacheDir(opts Options) string {
+	if opts.MediaCacheDir == "" {
+		return path.Join(os.TempDir(), "wp2hugo-cache")
+	}
+	return opts.MediaCacheDir
+}
+
+// parseSite reads the site from its REST API, it returns the parsing duration
+func parseSite(ctx context.Context, siteURL string, opts Options) (*wpparser.WebsiteInfo, time.Duration, error) {
+	parser := wpparser.NewParser()
+	parser.FailFast = opts.FailFast
+	// Keyed by the site and the user, the content read differs with the authentication
+	cacheKey := sha256.Sum256([]byte(siteURL + "\x00" + opts.RESTAPIUsername))
+	source := wpparser.RESTAPISource{
+		SiteURL:             siteURL,
+		Username:            opts.RESTAPIUsername,
+		ApplicationPassword: opts.RESTAPIApplicationPassword,
+		CacheDir:            path.Join(getMediaCacheDir(opts), "rest-api", hex.EncodeToString(cacheKey[:8])),
+	}
+	parseStart := time.Now()
+	info, err := parser.ParseRESTAPI(ctx, source, opts.Authors, getCustomPostTypes(opts))
+	if err != nil {
+		return nil, 0, fmt.Errorf("error reading '%s': %w", siteURL, err)
+	}
+	return filterContent(info, opts), time.Since(parseStart), nil
+}
+
+// parse parses and merges the export files, it returns their total size and the parsing duration
+func parse(ctx context.Context, inPaths []string, opts Options) (*wpparser.WebsiteInfo, int64, time.Duration, error) {
+	customPostTypes := getCustomPostTypes(opts)
+	parser := wpparser.NewParser()
+	parser.FailFast = opts.FailFast
+	infos := make([]*wpparser.WebsiteInfo, 0, len(inPaths))
+	var exportBytes int64
+	parseStart := time.Now()
+	for _, inPath := range inPaths {
+		log.Debug().
+			Str("source", inPath).
+			Msg("Reading website export")
+		info, err := parser.ParseFile(ctx, inPath, opts.Authors, customPostTypes)
+		if err != nil {
+			return nil, 0, 0, fmt.Errorf("error parsing '%s': %w", inPath, err)
+		}
+		infos = append(infos, info)
+		if fileInfo, err := os.Stat(inPath); err == nil {
+			exportBytes += fileInfo.Size()
+		}
+	}
+	parseDuration := time.Since(parseStart)
+	info, err := wpparser.Merge(infos...)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	return filterContent(info, opts), exportBytes, parseDuration, nil
+}
+
+// filterContent leaves out the content filtered out by Options.OnlyTypes and the post IDs options
+func filterContent(info *wpparser.WebsiteInfo, opts Options) *wpparser.WebsiteInfo {
+	if len(opts.OnlyTypes) > 0 {
+		info = info.OnlyPostTypes(opts.OnlyTypes)
+	}
+	if len(opts.IncludePostIDs) > 0 || len(opts.ExcludePostIDs) > 0 {
+		info = info.FilterPostIDs(opts.IncludePostIDs, opts.ExcludePostIDs)
+	}
+	return info
+}
+
+func convert(ctx context.Context, inPaths []string, outDir string, opts Options) (*Report, error) {
+	info, exportBytes, parseDuration, err := parse(ctx, inPaths, opts)
+	if err != nil {
+		return nil, err
+	}
+	return generate(ctx, info, exportBytes, parseDuration, outDir, opts)
+}
+
+func generate(ctx context.Context, info *wpparser.WebsiteInfo, exportBytes int64, parseDuration time.Duration,
+	outDir string, opts Options,
+) (*Report, error) {
+	font := opts.Font
+	if font == "" {
+		font = _defaultFont
+	}
+	log.Debug().Msgf("Output: %s", outDir)
+	generator := hugogenerator.NewGenerator(outDir, font, mediacache.New(getMediaCacheDir(opts)),
+		opts.DownloadMedia, opts.DownloadAll, opts.ContinueOnMediaDownloadFailure, opts.GenerateNginxConfig,
+		*info, opts.GeneratorOptions)
+	generator.AddParsedExport(exportBytes, parseDuration)
+	if err := generator.Generate(ctx); err != nil {
+		if ctx.Err() != nil {
+			// The partial report of the content converted before the cancellation
+			report := generator.Report()
+			return &report, err
+		}
+		return nil, err
+	}
+	report := generator.Report()
+	return &report, nil
+}
@@ -0,0 +1,22 @@
+package wp2hugo
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertDirWithoutExportFile(t *testing.T) {
+	t.Parallel()
+	inDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(inDir, "notes.txt"), []byte("not an export"), 0o600))
+
+	_, err := ConvertDir(context.Background(), inDir, t.TempDir(), Options{})
+	require.ErrorContains(t, err, "no export file")
+
+	_, err = InspectDir(context.Background(), inDir, Options{})
+	require.ErrorContains(t, err, "no export file")
+}